@@ -5,15 +5,49 @@ import (
 	"fmt"
 	"strconv"
 	"time"
+
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
 )
 
+// NewDatabase opens the database selected with DB_CONNECTION
+func NewDatabase(configuration Config) *sql.DB {
+	if configuration.Get("DB_CONNECTION") == utils.DriverPostgres {
+		return NewPostgres(configuration)
+	}
+
+	return NewSQLite(configuration)
+}
+
+// DataSourceName builds the DSN of the database selected with DB_CONNECTION
+func DataSourceName(configuration Config) string {
+	databaseName := configuration.Get("DB_DATABASE")
+	if configuration.Get("DB_CONNECTION") != utils.DriverPostgres {
+		return fmt.Sprintf("./%v.db", databaseName)
+	}
+
+	sslMode := configuration.Get("DB_SSLMODE")
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	return fmt.Sprintf(
+		"host=%v port=%v user=%v password=%v dbname=%v sslmode=%v",
+		configuration.Get("DB_HOST"),
+		configuration.Get("DB_PORT"),
+		configuration.Get("DB_USERNAME"),
+		configuration.Get("DB_PASSWORD"),
+		databaseName,
+		sslMode,
+	)
+}
+
 func NewSQLite(configuration Config) *sql.DB {
 	// Setup DB
 	driver := configuration.Get("DB_CONNECTION")
-	databaseName := configuration.Get("DB_DATABASE")
 
-	dsn := fmt.Sprintf("./%v.db", databaseName)
-	db, err := sql.Open(driver, dsn)
+	db, err := sql.Open(driver, DataSourceName(configuration))
 	if err != nil {
 		panic(err)
 	}
@@ -48,3 +82,41 @@ func NewSQLite(configuration Config) *sql.DB {
 
 	return db
 }
+
+func NewPostgres(configuration Config) *sql.DB {
+	// Setup DB
+	db, err := sql.Open(utils.DriverPostgres, DataSourceName(configuration))
+	if err != nil {
+		panic(err)
+	}
+
+	err = db.Ping()
+	if err != nil {
+		panic(err)
+	}
+
+	// Limit connection with db pooling
+	setMaxIdleConns, err := strconv.Atoi(configuration.Get("POSTGRES_POOL_MIN"))
+	if err != nil {
+		panic(err)
+	}
+	setMaxOpenConns, err := strconv.Atoi(configuration.Get("POSTGRES_POOL_MAX"))
+	if err != nil {
+		panic(err)
+	}
+	setConnMaxIdleTime, err := strconv.Atoi(configuration.Get("POSTGRES_MAX_IDLE_TIME_SECOND"))
+	if err != nil {
+		panic(err)
+	}
+	setConnMaxLifetime, err := strconv.Atoi(configuration.Get("POSTGRES_MAX_LIFE_TIME_SECOND"))
+	if err != nil {
+		panic(err)
+	}
+
+	db.SetMaxIdleConns(setMaxIdleConns)
+	db.SetMaxOpenConns(setMaxOpenConns)
+	db.SetConnMaxIdleTime(time.Duration(setConnMaxIdleTime) * time.Second)
+	db.SetConnMaxLifetime(time.Duration(setConnMaxLifetime) * time.Second)
+
+	return db
+}
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rg-km/final-project-engineering-12/backend/middleware"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/service"
+)
+
+type AccountController struct {
+	AccountService service.AccountService
+}
+
+func NewAccountController(accountService *service.AccountService) *AccountController {
+	return &AccountController{
+		AccountService: *accountService,
+	}
+}
+
+func (controller *AccountController) Route(router *gin.Engine) *gin.Engine {
+	router.PUT("/api/users/password", middleware.Authenticated(controller.ChangePassword))
+	router.PUT("/api/users/email", middleware.Authenticated(controller.ChangeEmail))
+	router.POST("/api/users/email/confirm", controller.ConfirmEmailChange)
+
+	return router
+}
+
+// ChangePassword sets a new password for the logged in user, the current password has to be sent along
+func (controller *AccountController) ChangePassword(ctx *gin.Context) {
+	var request model.ChangePasswordRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   "Please Check Your Input",
+		})
+		return
+	}
+
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	err := controller.AccountService.ChangePassword(ctx.Request.Context(), principal.Id, request)
+	if err != nil {
+		ctx.JSON(accountErrorCode(err), model.WebResponse{
+			Code:   accountErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "password successfully changed",
+		Data:   nil,
+	})
+}
+
+// ChangeEmail emails a confirmation link to the new email of the logged in user, the email changes once the link is
+// opened
+func (controller *AccountController) ChangeEmail(ctx *gin.Context) {
+	var request model.ChangeEmailRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   "Please Check Your Input",
+		})
+		return
+	}
+
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	err := controller.AccountService.ChangeEmail(ctx.Request.Context(), principal.Id, request, ctx.GetHeader("Accept-Language"))
+	if err != nil {
+		ctx.JSON(accountErrorCode(err), model.WebResponse{
+			Code:   accountErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, model.WebResponse{
+		Code:   http.StatusAccepted,
+		Status: "a confirmation link has been sent to the new email",
+		Data:   nil,
+	})
+}
+
+// ConfirmEmailChange changes the email of the user with the token of a confirmation link
+func (controller *AccountController) ConfirmEmailChange(ctx *gin.Context) {
+	var request model.ConfirmEmailChangeRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   "Please Check Your Input",
+		})
+		return
+	}
+
+	err := controller.AccountService.ConfirmEmailChange(ctx.Request.Context(), request.Token)
+	if err != nil {
+		ctx.JSON(accountErrorCode(err), model.WebResponse{
+			Code:   accountErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "email successfully changed",
+		Data:   nil,
+	})
+}
+
+// accountErrorCode answers 403 for a wrong current password and 409 for an email another user has
+func accountErrorCode(err error) int {
+	switch err {
+	case service.ErrPasswordIncorrect:
+		return http.StatusForbidden
+	case service.ErrEmailTaken:
+		return http.StatusConflict
+	case service.ErrEmailUnchanged, service.ErrEmailChangeInvalid:
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
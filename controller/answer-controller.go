@@ -23,22 +23,32 @@ func NewAnswerController(answerService *service.AnswerService) *AnswerController
 func (controller *AnswerController) Route(router *gin.Engine) *gin.Engine {
 	authorized := router.Group("/api")
 	{
-		authorized.GET("/answers/all", middleware.UserHandler(controller.FindAll))
-		authorized.POST("/answers/create", middleware.UserHandler(controller.Create))
-		authorized.PUT("/answers/update/:answerId", middleware.UserHandler(controller.Update))
-		authorized.DELETE("/answers/:answerId", middleware.UserHandler(controller.Delete))
-		authorized.GET("/answers/by-user/:userId", middleware.UserHandler(controller.FindByUserId))
-		authorized.GET("/answers/:questionId", middleware.UserHandler(controller.FindById))
+		authorized.GET("/answers/all", middleware.Authorized(middleware.ActionList, middleware.ResourceAnswer, controller.FindAll))
+		authorized.POST("/answers/create", middleware.Authorized(middleware.ActionCreate, middleware.ResourceAnswer, controller.Create))
+		authorized.PUT("/answers/update/:answerId", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceAnswer, controller.Update))
+		authorized.DELETE("/answers/:answerId", middleware.Authorized(middleware.ActionDelete, middleware.ResourceAnswer, controller.Delete))
+		authorized.GET("/answers/by-user/:userId", middleware.Authorized(middleware.ActionRead, middleware.ResourceAnswer, controller.FindByUserId))
+		authorized.GET("/answers/:questionId", middleware.Authorized(middleware.ActionRead, middleware.ResourceAnswer, controller.FindById))
 	}
 
 	return router
 }
 
 func (controller *AnswerController) FindAll(ctx *gin.Context) {
-	answers, err := controller.AnswerService.FindAll(ctx.Request.Context())
+	query, err := bindListQuery(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
-			Code:   http.StatusInternalServerError,
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	answers, page, err := controller.AnswerService.FindAll(ctx.Request.Context(), query)
+	if err != nil {
+		ctx.JSON(listErrorCode(err), model.WebResponse{
+			Code:   listErrorCode(err),
 			Status: err.Error(),
 			Data:   nil,
 		})
@@ -46,9 +56,11 @@ func (controller *AnswerController) FindAll(ctx *gin.Context) {
 	}
 
 	ctx.JSON(http.StatusOK, model.WebResponse{
-		Code:   http.StatusOK,
-		Status: "OK",
-		Data:   answers,
+		Code:       http.StatusOK,
+		Status:     "OK",
+		NextCursor: page.NextCursor,
+		Total:      &page.Total,
+		Data:       answers,
 	})
 }
 
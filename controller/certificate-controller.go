@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rg-km/final-project-engineering-12/backend/middleware"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/pdf"
+	"github.com/rg-km/final-project-engineering-12/backend/service"
+)
+
+type CertificateController struct {
+	CertificateService service.CertificateService
+}
+
+func NewCertificateController(certificateService *service.CertificateService) *CertificateController {
+	return &CertificateController{
+		CertificateService: *certificateService,
+	}
+}
+
+func (controller *CertificateController) Route(router *gin.Engine) *gin.Engine {
+	authorized := router.Group("/api/courses/:code")
+	{
+		authorized.POST("/certificate", middleware.Authorized(middleware.ActionCreate, middleware.ResourceCertificate, controller.Issue))
+		authorized.GET("/certificate", middleware.Authorized(middleware.ActionRead, middleware.ResourceCertificate, controller.Certificate))
+		authorized.GET("/certificate/download", middleware.Authorized(middleware.ActionRead, middleware.ResourceCertificate, controller.Download))
+	}
+
+	public := router.Group("/api/certificates")
+	{
+		public.GET("/:code/verify", controller.Verify)
+	}
+
+	return router
+}
+
+// Issue issues the certificate of the course to the user logged in
+func (controller *CertificateController) Issue(ctx *gin.Context) {
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	certificate, err := controller.CertificateService.Issue(ctx.Request.Context(), ctx.Param("code"), principal.Id)
+	if err != nil {
+		ctx.JSON(certificateErrorCode(err), model.WebResponse{
+			Code:   certificateErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "certificate successfully issued",
+		Data:   certificate,
+	})
+}
+
+func (controller *CertificateController) Certificate(ctx *gin.Context) {
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	certificate, err := controller.CertificateService.Certificate(ctx.Request.Context(), ctx.Param("code"), principal.Id)
+	if err != nil {
+		ctx.JSON(certificateErrorCode(err), model.WebResponse{
+			Code:   certificateErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   certificate,
+	})
+}
+
+func (controller *CertificateController) Download(ctx *gin.Context) {
+	principal, _ := middleware.CurrentPrincipal(ctx)
+
+	var document bytes.Buffer
+	err := controller.CertificateService.Download(ctx.Request.Context(), &document, ctx.Param("code"), principal.Id)
+	if err != nil {
+		ctx.JSON(certificateErrorCode(err), model.WebResponse{
+			Code:   certificateErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.Header("Content-Type", pdf.ContentType)
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=certificate-%v.pdf", ctx.Param("code")))
+	ctx.Data(http.StatusOK, pdf.ContentType, document.Bytes())
+}
+
+// Verify is public so anyone given a certificate can check it was issued
+func (controller *CertificateController) Verify(ctx *gin.Context) {
+	certificate, err := controller.CertificateService.Verify(ctx.Request.Context(), ctx.Param("code"))
+	if err != nil {
+		ctx.JSON(certificateErrorCode(err), model.WebResponse{
+			Code:   certificateErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "certificate is valid",
+		Data:   certificate,
+	})
+}
+
+// certificateErrorCode answers 403 to users who can not get the certificate yet and 404 when there is none
+func certificateErrorCode(err error) int {
+	switch {
+	case errors.Is(err, service.ErrNotEnrolled), errors.Is(err, service.ErrCourseNotCompleted), errors.Is(err, service.ErrNotPassed):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrCertificateNotFound):
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
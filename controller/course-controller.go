@@ -6,7 +6,6 @@ import (
 	"github.com/rg-km/final-project-engineering-12/backend/model"
 	"github.com/rg-km/final-project-engineering-12/backend/service"
 	"net/http"
-	"strconv"
 )
 
 type CourseController struct {
@@ -24,51 +23,33 @@ func NewCourseController(courseService *service.CourseService, userCourseService
 func (controller *CourseController) Route(router *gin.Engine) *gin.Engine {
 	authorized := router.Group("/api/courses")
 	{
-		authorized.GET("", middleware.AdminHandler(controller.FindAll))
-		authorized.GET("/:code", middleware.UserHandler(controller.FindById))
-		authorized.GET("/:code/users", middleware.AdminHandler(controller.FindAllUserByCourseId))
-		authorized.POST("", middleware.AdminHandler(controller.Create))
-		authorized.PATCH("/:code", middleware.AdminHandler(controller.Update))
-		authorized.DELETE("/:code", middleware.AdminHandler(controller.Delete))
-		authorized.PATCH("/:code/status", middleware.AdminHandler(controller.ChangeStatus))
+		authorized.GET("", middleware.Authorized(middleware.ActionList, middleware.ResourceCourse, controller.FindAll))
+		authorized.GET("/:code", middleware.Authorized(middleware.ActionRead, middleware.ResourceCourse, controller.FindById))
+		authorized.GET("/:code/users", middleware.Authorized(middleware.ActionList, middleware.ResourceRoster, controller.FindAllUserByCourseId))
+		authorized.POST("", middleware.Authorized(middleware.ActionCreate, middleware.ResourceCourse, controller.Create))
+		authorized.PATCH("/:code", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceCourse, controller.Update))
+		authorized.DELETE("/:code", middleware.Authorized(middleware.ActionDelete, middleware.ResourceCourse, controller.Delete))
+		authorized.PATCH("/:code/status", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceCourse, controller.ChangeStatus))
 	}
 
 	return router
 }
 
 func (controller *CourseController) FindAll(ctx *gin.Context) {
-	status := true
-	if ctx.Query("status") != "" {
-		statuses, err := strconv.ParseBool(ctx.Query("status"))
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, model.WebResponse{
-				Code:   http.StatusInternalServerError,
-				Status: err.Error(),
-				Data:   nil,
-			})
-			return
-		}
-		status = statuses
-	}
-
-	limit := -1
-	if ctx.Query("limit") != "" {
-		limits, err := strconv.Atoi(ctx.Query("limit"))
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, model.WebResponse{
-				Code:   http.StatusInternalServerError,
-				Status: err.Error(),
-				Data:   nil,
-			})
-			return
-		}
-		limit = limits
+	query, err := bindListQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
 	}
 
-	courses, err := controller.CourseService.FindAll(ctx.Request.Context(), status, limit)
+	courses, page, err := controller.CourseService.FindAll(ctx.Request.Context(), query)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
-			Code:   http.StatusInternalServerError,
+		ctx.JSON(listErrorCode(err), model.WebResponse{
+			Code:   listErrorCode(err),
 			Status: err.Error(),
 			Data:   nil,
 		})
@@ -76,9 +57,11 @@ func (controller *CourseController) FindAll(ctx *gin.Context) {
 	}
 
 	ctx.JSON(http.StatusOK, model.WebResponse{
-		Code:   http.StatusOK,
-		Status: "OK",
-		Data:   courses,
+		Code:       http.StatusOK,
+		Status:     "OK",
+		NextCursor: page.NextCursor,
+		Total:      &page.Total,
+		Data:       courses,
 	})
 }
 
@@ -0,0 +1,249 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rg-km/final-project-engineering-12/backend/middleware"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/service"
+)
+
+type CurriculumController struct {
+	CurriculumService service.CurriculumService
+}
+
+func NewCurriculumController(curriculumService *service.CurriculumService) *CurriculumController {
+	return &CurriculumController{
+		CurriculumService: *curriculumService,
+	}
+}
+
+func (controller *CurriculumController) Route(router *gin.Engine) *gin.Engine {
+	authorized := router.Group("/api/courses/:code")
+	{
+		authorized.GET("/curriculum", middleware.Authorized(middleware.ActionRead, middleware.ResourceCurriculum, controller.Curriculum))
+		authorized.PUT("/curriculum", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceCurriculum, controller.Reorder))
+		authorized.GET("/curriculum/:type/:itemId/next", middleware.Authorized(middleware.ActionRead, middleware.ResourceCurriculum, controller.Next))
+		authorized.GET("/curriculum/:type/:itemId/previous", middleware.Authorized(middleware.ActionRead, middleware.ResourceCurriculum, controller.Previous))
+		authorized.POST("/sections", middleware.Authorized(middleware.ActionCreate, middleware.ResourceCurriculum, controller.CreateSection))
+		authorized.PATCH("/sections/:sectionId", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceCurriculum, controller.UpdateSection))
+		authorized.DELETE("/sections/:sectionId", middleware.Authorized(middleware.ActionDelete, middleware.ResourceCurriculum, controller.DeleteSection))
+	}
+
+	return router
+}
+
+func (controller *CurriculumController) Curriculum(ctx *gin.Context) {
+	curriculum, err := controller.CurriculumService.Curriculum(ctx.Request.Context(), ctx.Param("code"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   curriculum,
+	})
+}
+
+func (controller *CurriculumController) Reorder(ctx *gin.Context) {
+	var request model.ReorderCurriculumRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	curriculum, err := controller.CurriculumService.Reorder(ctx.Request.Context(), ctx.Param("code"), request)
+	if err != nil {
+		ctx.JSON(curriculumErrorCode(err), model.WebResponse{
+			Code:   curriculumErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "curriculum successfully reordered",
+		Data:   curriculum,
+	})
+}
+
+func (controller *CurriculumController) Next(ctx *gin.Context) {
+	itemId, err := strconv.Atoi(ctx.Param("itemId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	next, err := controller.CurriculumService.Next(ctx.Request.Context(), ctx.Param("code"), ctx.Param("type"), itemId)
+	if err != nil {
+		ctx.JSON(curriculumErrorCode(err), model.WebResponse{
+			Code:   curriculumErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   next,
+	})
+}
+
+func (controller *CurriculumController) Previous(ctx *gin.Context) {
+	itemId, err := strconv.Atoi(ctx.Param("itemId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	previous, err := controller.CurriculumService.Previous(ctx.Request.Context(), ctx.Param("code"), ctx.Param("type"), itemId)
+	if err != nil {
+		ctx.JSON(curriculumErrorCode(err), model.WebResponse{
+			Code:   curriculumErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   previous,
+	})
+}
+
+func (controller *CurriculumController) CreateSection(ctx *gin.Context) {
+	var request model.SaveCourseSectionRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	section, err := controller.CurriculumService.CreateSection(ctx.Request.Context(), ctx.Param("code"), request)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "section successfully created",
+		Data:   section,
+	})
+}
+
+func (controller *CurriculumController) UpdateSection(ctx *gin.Context) {
+	var request model.SaveCourseSectionRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	request.Id, err = strconv.Atoi(ctx.Param("sectionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	section, err := controller.CurriculumService.UpdateSection(ctx.Request.Context(), ctx.Param("code"), request)
+	if err != nil {
+		ctx.JSON(curriculumErrorCode(err), model.WebResponse{
+			Code:   curriculumErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "section successfully updated",
+		Data:   section,
+	})
+}
+
+func (controller *CurriculumController) DeleteSection(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("sectionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	err = controller.CurriculumService.DeleteSection(ctx.Request.Context(), ctx.Param("code"), id)
+	if err != nil {
+		ctx.JSON(curriculumErrorCode(err), model.WebResponse{
+			Code:   curriculumErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "section successfully deleted",
+		Data:   nil,
+	})
+}
+
+// curriculumErrorCode answers 404 for sections and items outside the course or past its ends and 400 for an
+// incomplete reorder
+func curriculumErrorCode(err error) int {
+	switch {
+	case errors.Is(err, service.ErrSectionNotFound), errors.Is(err, service.ErrCurriculumItemNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrCurriculumMismatch):
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,253 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rg-km/final-project-engineering-12/backend/middleware"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/service"
+)
+
+type EnrollmentController struct {
+	EnrollmentService service.EnrollmentService
+}
+
+func NewEnrollmentController(enrollmentService *service.EnrollmentService) *EnrollmentController {
+	return &EnrollmentController{
+		EnrollmentService: *enrollmentService,
+	}
+}
+
+func (controller *EnrollmentController) Route(router *gin.Engine) *gin.Engine {
+	authorized := router.Group("/api/courses/:code/enrollment")
+	{
+		authorized.GET("", middleware.Authorized(middleware.ActionRead, middleware.ResourceSelfEnrollment, controller.Enrollment))
+		authorized.POST("", middleware.Authorized(middleware.ActionCreate, middleware.ResourceSelfEnrollment, controller.Enroll))
+		authorized.DELETE("", middleware.Authorized(middleware.ActionDelete, middleware.ResourceSelfEnrollment, controller.Leave))
+		authorized.GET("/settings", middleware.Authorized(middleware.ActionRead, middleware.ResourceEnrollSettings, controller.Settings))
+		authorized.PUT("/settings", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceEnrollSettings, controller.SaveSettings))
+		authorized.GET("/requests", middleware.Authorized(middleware.ActionList, middleware.ResourceEnrollRequest, controller.FindAllRequests))
+		authorized.POST("/requests/:requestId/approve", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceEnrollRequest, controller.Approve))
+		authorized.DELETE("/requests/:requestId", middleware.Authorized(middleware.ActionDelete, middleware.ResourceEnrollRequest, controller.Reject))
+	}
+
+	return router
+}
+
+// Enrollment shows the user logged in how to enroll in the course and where the user stands
+func (controller *EnrollmentController) Enrollment(ctx *gin.Context) {
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	enrollment, err := controller.EnrollmentService.Enrollment(ctx.Request.Context(), ctx.Param("code"), principal.Id)
+	if err != nil {
+		ctx.JSON(enrollmentErrorCode(err), model.WebResponse{
+			Code:   enrollmentErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   enrollment,
+	})
+}
+
+// Enroll answers 201 when the user is enrolled and 202 when the user waits for approval or a seat
+func (controller *EnrollmentController) Enroll(ctx *gin.Context) {
+	var request model.EnrollRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	enrollment, err := controller.EnrollmentService.Enroll(ctx.Request.Context(), ctx.Param("code"), principal.Id, request)
+	if err != nil {
+		ctx.JSON(enrollmentErrorCode(err), model.WebResponse{
+			Code:   enrollmentErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	code, status := http.StatusAccepted, "enrollment request received"
+	if enrollment.Status == "enrolled" {
+		code, status = http.StatusCreated, "successfully enrolled"
+	}
+
+	ctx.JSON(code, model.WebResponse{
+		Code:   code,
+		Status: status,
+		Data:   enrollment,
+	})
+}
+
+// Leave takes the user logged in out of the course or its waitlist
+func (controller *EnrollmentController) Leave(ctx *gin.Context) {
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	err := controller.EnrollmentService.Leave(ctx.Request.Context(), ctx.Param("code"), principal.Id)
+	if err != nil {
+		ctx.JSON(enrollmentErrorCode(err), model.WebResponse{
+			Code:   enrollmentErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "successfully left the course",
+		Data:   nil,
+	})
+}
+
+func (controller *EnrollmentController) Settings(ctx *gin.Context) {
+	settings, err := controller.EnrollmentService.Settings(ctx.Request.Context(), ctx.Param("code"))
+	if err != nil {
+		ctx.JSON(enrollmentErrorCode(err), model.WebResponse{
+			Code:   enrollmentErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   settings,
+	})
+}
+
+func (controller *EnrollmentController) SaveSettings(ctx *gin.Context) {
+	var request model.SaveEnrollmentSettingsRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	settings, err := controller.EnrollmentService.SaveSettings(ctx.Request.Context(), ctx.Param("code"), request)
+	if err != nil {
+		ctx.JSON(enrollmentErrorCode(err), model.WebResponse{
+			Code:   enrollmentErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "enrollment settings successfully saved",
+		Data:   settings,
+	})
+}
+
+func (controller *EnrollmentController) FindAllRequests(ctx *gin.Context) {
+	requests, err := controller.EnrollmentService.FindAllRequests(ctx.Request.Context(), ctx.Param("code"))
+	if err != nil {
+		ctx.JSON(enrollmentErrorCode(err), model.WebResponse{
+			Code:   enrollmentErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   requests,
+	})
+}
+
+func (controller *EnrollmentController) Approve(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("requestId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	request, err := controller.EnrollmentService.Approve(ctx.Request.Context(), ctx.Param("code"), id)
+	if err != nil {
+		ctx.JSON(enrollmentErrorCode(err), model.WebResponse{
+			Code:   enrollmentErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "enrollment request successfully approved",
+		Data:   request,
+	})
+}
+
+func (controller *EnrollmentController) Reject(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("requestId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	err = controller.EnrollmentService.Reject(ctx.Request.Context(), ctx.Param("code"), id)
+	if err != nil {
+		ctx.JSON(enrollmentErrorCode(err), model.WebResponse{
+			Code:   enrollmentErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "enrollment request successfully rejected",
+		Data:   nil,
+	})
+}
+
+// enrollmentErrorCode answers 403 when the user can not enroll, 409 for users already enrolled or requests that are
+// not pending and 400 for invalid settings
+func enrollmentErrorCode(err error) int {
+	switch {
+	case errors.Is(err, service.ErrEnrollmentClosed), errors.Is(err, service.ErrInvalidEnrollmentKey):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrAlreadyEnrolled), errors.Is(err, service.ErrRequestNotPending):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrEnrollmentKeyRequired), errors.Is(err, service.ErrEnrollmentDates):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrNotEnrolled), errors.Is(err, service.ErrEnrollmentRequestNotFound):
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/storage"
+)
+
+// FileController serves the signed URLs of stores that do not serve files themselves, like the local store
+type FileController struct {
+	Store    storage.BlobStore
+	Verifier storage.SignatureVerifier
+}
+
+func NewFileController(store storage.BlobStore) *FileController {
+	verifier, _ := store.(storage.SignatureVerifier)
+
+	return &FileController{
+		Store:    store,
+		Verifier: verifier,
+	}
+}
+
+func (controller *FileController) Route(router *gin.Engine) *gin.Engine {
+	if controller.Verifier == nil {
+		return router
+	}
+
+	public := router.Group("/api/files")
+	{
+		public.GET("/:key", controller.Download)
+	}
+
+	return router
+}
+
+func (controller *FileController) Download(ctx *gin.Context) {
+	key := ctx.Param("key")
+	filename := ctx.Query("filename")
+
+	expires, err := strconv.ParseInt(ctx.Query("expires"), 10, 64)
+	if err == nil {
+		err = controller.Verifier.Verify(key, filename, expires, ctx.Query("signature"))
+	}
+	if err != nil {
+		ctx.JSON(http.StatusForbidden, model.WebResponse{
+			Code:   http.StatusForbidden,
+			Status: storage.ErrInvalidSignature.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	body, err := controller.Store.Get(ctx.Request.Context(), key)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, storage.ErrBlobNotFound) {
+			code = http.StatusNotFound
+		}
+		ctx.JSON(code, model.WebResponse{
+			Code:   code,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+	defer body.Close()
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if contentType := mime.TypeByExtension(filepath.Ext(key)); contentType != "" {
+		ctx.Header("Content-Type", contentType)
+	}
+
+	_, _ = io.Copy(ctx.Writer, body)
+}
@@ -0,0 +1,251 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rg-km/final-project-engineering-12/backend/middleware"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/service"
+	"github.com/rg-km/final-project-engineering-12/backend/spreadsheet"
+)
+
+type GradebookController struct {
+	GradebookService service.GradebookService
+}
+
+func NewGradebookController(gradebookService *service.GradebookService) *GradebookController {
+	return &GradebookController{
+		GradebookService: *gradebookService,
+	}
+}
+
+func (controller *GradebookController) Route(router *gin.Engine) *gin.Engine {
+	authorized := router.Group("/api/courses/:code")
+	{
+		authorized.GET("/gradebook", middleware.Authorized(middleware.ActionRead, middleware.ResourceGradebook, controller.Gradebook))
+		authorized.GET("/gradebook/export", middleware.Authorized(middleware.ActionRead, middleware.ResourceGradebook, controller.Export))
+		authorized.GET("/grade-categories", middleware.Authorized(middleware.ActionList, middleware.ResourceGradebook, controller.FindAllCategories))
+		authorized.POST("/grade-categories", middleware.Authorized(middleware.ActionCreate, middleware.ResourceGradebook, controller.CreateCategory))
+		authorized.PATCH("/grade-categories/:categoryId", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceGradebook, controller.UpdateCategory))
+		authorized.DELETE("/grade-categories/:categoryId", middleware.Authorized(middleware.ActionDelete, middleware.ResourceGradebook, controller.DeleteCategory))
+		authorized.GET("/grade-bands", middleware.Authorized(middleware.ActionRead, middleware.ResourceGradebook, controller.FindBands))
+		authorized.PUT("/grade-bands", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceGradebook, controller.SaveBands))
+	}
+
+	return router
+}
+
+func (controller *GradebookController) Gradebook(ctx *gin.Context) {
+	gradebook, err := controller.GradebookService.Gradebook(ctx.Request.Context(), ctx.Param("code"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   gradebook,
+	})
+}
+
+// Export downloads the gradebook, ?format=xlsx for a workbook and csv otherwise
+func (controller *GradebookController) Export(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", spreadsheet.FormatCSV)
+
+	var sheet bytes.Buffer
+	err := controller.GradebookService.Export(ctx.Request.Context(), &sheet, ctx.Param("code"), format)
+	if err != nil {
+		ctx.JSON(gradebookErrorCode(err), model.WebResponse{
+			Code:   gradebookErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=gradebook-%v.%v", ctx.Param("code"), format))
+	ctx.Data(http.StatusOK, spreadsheet.ContentType(format), sheet.Bytes())
+}
+
+func (controller *GradebookController) FindAllCategories(ctx *gin.Context) {
+	categories, err := controller.GradebookService.FindAllCategories(ctx.Request.Context(), ctx.Param("code"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   categories,
+	})
+}
+
+func (controller *GradebookController) CreateCategory(ctx *gin.Context) {
+	var request model.SaveGradeCategoryRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	category, err := controller.GradebookService.CreateCategory(ctx.Request.Context(), ctx.Param("code"), request)
+	if err != nil {
+		ctx.JSON(gradebookErrorCode(err), model.WebResponse{
+			Code:   gradebookErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "grade category successfully created",
+		Data:   category,
+	})
+}
+
+func (controller *GradebookController) UpdateCategory(ctx *gin.Context) {
+	var request model.SaveGradeCategoryRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	request.Id, err = strconv.Atoi(ctx.Param("categoryId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	category, err := controller.GradebookService.UpdateCategory(ctx.Request.Context(), ctx.Param("code"), request)
+	if err != nil {
+		ctx.JSON(gradebookErrorCode(err), model.WebResponse{
+			Code:   gradebookErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "grade category successfully updated",
+		Data:   category,
+	})
+}
+
+func (controller *GradebookController) DeleteCategory(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("categoryId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	err = controller.GradebookService.DeleteCategory(ctx.Request.Context(), ctx.Param("code"), id)
+	if err != nil {
+		ctx.JSON(gradebookErrorCode(err), model.WebResponse{
+			Code:   gradebookErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "grade category successfully deleted",
+		Data:   nil,
+	})
+}
+
+func (controller *GradebookController) FindBands(ctx *gin.Context) {
+	bands, err := controller.GradebookService.FindBands(ctx.Request.Context(), ctx.Param("code"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   bands,
+	})
+}
+
+func (controller *GradebookController) SaveBands(ctx *gin.Context) {
+	var request model.SaveGradeBandsRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	bands, err := controller.GradebookService.SaveBands(ctx.Request.Context(), ctx.Param("code"), request)
+	if err != nil {
+		ctx.JSON(gradebookErrorCode(err), model.WebResponse{
+			Code:   gradebookErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "grade bands successfully saved",
+		Data:   bands,
+	})
+}
+
+// gradebookErrorCode answers 404 for a category of another course and 400 for settings that do not add up
+func gradebookErrorCode(err error) int {
+	switch {
+	case errors.Is(err, service.ErrCategoryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrCategoryWeights), errors.Is(err, service.ErrDuplicateBand), errors.Is(err, spreadsheet.ErrUnknownFormat):
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
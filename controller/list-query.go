@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+// bindListQuery reads limit, cursor or offset and sort from the query string, every other parameter is passed on as a filter
+func bindListQuery(ctx *gin.Context) (model.ListQuery, error) {
+	query := model.ListQuery{
+		Limit:   model.DefaultListLimit,
+		Sort:    ctx.Query("sort"),
+		Filters: map[string]string{},
+	}
+
+	if ctx.Query("limit") != "" {
+		limit, err := strconv.Atoi(ctx.Query("limit"))
+		if err != nil || limit < 1 {
+			return model.ListQuery{}, fmt.Errorf("%w: limit must be a positive number", model.ErrInvalidListQuery)
+		}
+		if limit > model.MaxListLimit {
+			limit = model.MaxListLimit
+		}
+		query.Limit = limit
+	}
+
+	switch {
+	case ctx.Query("cursor") != "":
+		offset, err := utils.DecodeCursor(ctx.Query("cursor"))
+		if err != nil {
+			return model.ListQuery{}, fmt.Errorf("%w: cursor is not valid", err)
+		}
+		query.Offset = offset
+	case ctx.Query("offset") != "":
+		offset, err := strconv.Atoi(ctx.Query("offset"))
+		if err != nil || offset < 0 {
+			return model.ListQuery{}, fmt.Errorf("%w: offset must not be negative", model.ErrInvalidListQuery)
+		}
+		query.Offset = offset
+	}
+
+	for name, values := range ctx.Request.URL.Query() {
+		switch name {
+		case "limit", "cursor", "offset", "sort":
+			continue
+		}
+		if len(values) > 0 && values[0] != "" {
+			query.Filters[name] = values[0]
+		}
+	}
+
+	return query, nil
+}
+
+// listErrorCode answers 400 for list queries the resource does not allow and 500 for anything else
+func listErrorCode(err error) int {
+	if errors.Is(err, model.ErrInvalidListQuery) {
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
 	"github.com/rg-km/final-project-engineering-12/backend/middleware"
 	"github.com/rg-km/final-project-engineering-12/backend/model"
 	"github.com/rg-km/final-project-engineering-12/backend/service"
@@ -11,24 +12,26 @@ import (
 
 type ModuleArticlesController struct {
 	ModuleArticlesRepository service.ModuleArticlesService
+	ProgressService          service.ProgressService
 }
 
-func NewModuleArticlesController(moduleArticlesService *service.ModuleArticlesService) *ModuleArticlesController {
+func NewModuleArticlesController(moduleArticlesService *service.ModuleArticlesService, progressService *service.ProgressService) *ModuleArticlesController {
 	return &ModuleArticlesController{
 		ModuleArticlesRepository: *moduleArticlesService,
+		ProgressService:          *progressService,
 	}
 }
 
 func (controller *ModuleArticlesController) Route(router *gin.Engine) *gin.Engine {
 	authorized := router.Group("/api/courses/:code")
 	{
-		authorized.GET("/articles", middleware.UserHandler(controller.FindAll))
-		authorized.GET("/articles/:articleId", middleware.UserHandler(controller.FindByCode))
-		authorized.POST("/articles", middleware.AdminHandler(controller.Create))
-		authorized.PATCH("/articles/:articleId", middleware.AdminHandler(controller.Update))
-		authorized.DELETE("/articles/:articleId", middleware.AdminHandler(controller.Delete))
-		authorized.GET("/articles/:articleId/next", middleware.UserHandler(controller.Next))
-		authorized.GET("/articles/:articleId/previous", middleware.UserHandler(controller.Previous))
+		authorized.GET("/articles", middleware.Authorized(middleware.ActionRead, middleware.ResourceArticle, controller.FindAll))
+		authorized.GET("/articles/:articleId", middleware.Authorized(middleware.ActionRead, middleware.ResourceArticle, controller.FindByCode))
+		authorized.POST("/articles", middleware.Authorized(middleware.ActionCreate, middleware.ResourceArticle, controller.Create))
+		authorized.PATCH("/articles/:articleId", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceArticle, controller.Update))
+		authorized.DELETE("/articles/:articleId", middleware.Authorized(middleware.ActionDelete, middleware.ResourceArticle, controller.Delete))
+		authorized.GET("/articles/:articleId/next", middleware.Authorized(middleware.ActionRead, middleware.ResourceArticle, controller.Next))
+		authorized.GET("/articles/:articleId/previous", middleware.Authorized(middleware.ActionRead, middleware.ResourceArticle, controller.Previous))
 	}
 
 	return router
@@ -75,6 +78,17 @@ func (controller *ModuleArticlesController) FindByCode(ctx *gin.Context) {
 		return
 	}
 
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	err = controller.ProgressService.Open(ctx.Request.Context(), code, entity.CurriculumArticle, idArticle, principal.Id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, model.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -1,10 +1,12 @@
 package controller
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/rg-km/final-project-engineering-12/backend/middleware"
 	"github.com/rg-km/final-project-engineering-12/backend/model"
 	"github.com/rg-km/final-project-engineering-12/backend/service"
+	"github.com/rg-km/final-project-engineering-12/backend/storage"
 	"net/http"
 	"strconv"
 )
@@ -24,14 +26,17 @@ func NewModuleSubmissionsController(moduleSubmissionsService *service.ModuleSubm
 func (controller *ModuleSubmissionsController) Route(router *gin.Engine) *gin.Engine {
 	authorized := router.Group("/api/courses/:code")
 	{
-		authorized.GET("/submissions", middleware.UserHandler(controller.FindAll))
-		authorized.GET("/submissions/:submissionId", middleware.UserHandler(controller.FindByCode))
-		authorized.POST("/submissions", middleware.AdminHandler(controller.Create))
-		authorized.PATCH("/submissions/:submissionId", middleware.AdminHandler(controller.Update))
-		authorized.DELETE("/submissions/:submissionId", middleware.AdminHandler(controller.Delete))
-		authorized.GET("/submissions/:submissionId/next", middleware.UserHandler(controller.Next))
-		authorized.GET("/submissions/:submissionId/previous", middleware.UserHandler(controller.Previous))
-		authorized.GET("/submissions/:submissionId/get", middleware.AdminHandler(controller.TeacherSubmission))
+		authorized.GET("/submissions", middleware.Authorized(middleware.ActionRead, middleware.ResourceSubmission, controller.FindAll))
+		authorized.GET("/submissions/:submissionId", middleware.Authorized(middleware.ActionRead, middleware.ResourceSubmission, controller.FindByCode))
+		authorized.POST("/submissions", middleware.Authorized(middleware.ActionCreate, middleware.ResourceSubmission, controller.Create))
+		authorized.PATCH("/submissions/:submissionId", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceSubmission, controller.Update))
+		authorized.DELETE("/submissions/:submissionId", middleware.Authorized(middleware.ActionDelete, middleware.ResourceSubmission, controller.Delete))
+		authorized.GET("/submissions/:submissionId/next", middleware.Authorized(middleware.ActionRead, middleware.ResourceSubmission, controller.Next))
+		authorized.GET("/submissions/:submissionId/previous", middleware.Authorized(middleware.ActionRead, middleware.ResourceSubmission, controller.Previous))
+		authorized.GET("/submissions/:submissionId/get", middleware.Authorized(middleware.ActionList, middleware.ResourceUserSubmission, controller.TeacherSubmission))
+		authorized.GET("/submissions/:submissionId/extensions", middleware.Authorized(middleware.ActionList, middleware.ResourceUserSubmission, controller.FindAllExtensions))
+		authorized.PUT("/submissions/:submissionId/extensions/:userId", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceSubmission, controller.SaveExtension))
+		authorized.DELETE("/submissions/:submissionId/extensions/:userId", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceSubmission, controller.DeleteExtension))
 	}
 
 	return router
@@ -99,8 +104,8 @@ func (controller *ModuleSubmissionsController) Create(ctx *gin.Context) {
 	code := ctx.Param("code")
 	Modsubs, err := controller.ModuleSubmissionsService.Create(ctx, request, code)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
-			Code:   http.StatusInternalServerError,
+		ctx.JSON(moduleSubmissionErrorCode(err), model.WebResponse{
+			Code:   moduleSubmissionErrorCode(err),
 			Status: err.Error(),
 			Data:   nil,
 		})
@@ -139,8 +144,8 @@ func (controller *ModuleSubmissionsController) Update(ctx *gin.Context) {
 
 	Modsubs, err := controller.ModuleSubmissionsService.Update(ctx, request, code, idSubmission)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
-			Code:   http.StatusInternalServerError,
+		ctx.JSON(moduleSubmissionErrorCode(err), model.WebResponse{
+			Code:   moduleSubmissionErrorCode(err),
 			Status: err.Error(),
 			Data:   nil,
 		})
@@ -269,3 +274,131 @@ func (controller *ModuleSubmissionsController) TeacherSubmission(ctx *gin.Contex
 		Data:   teacherSubmissions,
 	})
 }
+
+func (controller *ModuleSubmissionsController) FindAllExtensions(ctx *gin.Context) {
+	code := ctx.Param("code")
+	idSubmission, err := strconv.Atoi(ctx.Param("submissionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	extensions, err := controller.ModuleSubmissionsService.FindAllExtensions(ctx.Request.Context(), code, idSubmission)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   extensions,
+	})
+}
+
+func (controller *ModuleSubmissionsController) SaveExtension(ctx *gin.Context) {
+	var request model.SaveExtensionRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	code := ctx.Param("code")
+	request.ModuleSubmissionId, err = strconv.Atoi(ctx.Param("submissionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+	request.UserId, err = strconv.Atoi(ctx.Param("userId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	err = controller.ModuleSubmissionsService.SaveExtension(ctx.Request.Context(), code, request)
+	if err != nil {
+		ctx.JSON(moduleSubmissionErrorCode(err), model.WebResponse{
+			Code:   moduleSubmissionErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "extension successfully saved",
+		Data:   nil,
+	})
+}
+
+func (controller *ModuleSubmissionsController) DeleteExtension(ctx *gin.Context) {
+	code := ctx.Param("code")
+	idSubmission, err := strconv.Atoi(ctx.Param("submissionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+	userId, err := strconv.Atoi(ctx.Param("userId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	err = controller.ModuleSubmissionsService.DeleteExtension(ctx.Request.Context(), code, idSubmission, userId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "extension successfully deleted",
+		Data:   nil,
+	})
+}
+
+// moduleSubmissionErrorCode answers 400 for invalid upload rules, late policies or deadlines
+func moduleSubmissionErrorCode(err error) int {
+	switch {
+	case errors.Is(err, storage.ErrUnknownFileType), errors.Is(err, service.ErrInvalidMaxSize),
+		errors.Is(err, service.ErrInvalidLatePolicy), errors.Is(err, service.ErrInvalidDeadline),
+		errors.Is(err, service.ErrCategoryNotFound):
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
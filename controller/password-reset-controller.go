@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rg-km/final-project-engineering-12/backend/middleware"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/service"
+)
+
+type PasswordResetController struct {
+	PasswordResetService service.PasswordResetService
+	ForgotLimiter        *middleware.RateLimiter
+}
+
+func NewPasswordResetController(passwordResetService *service.PasswordResetService, forgotLimiter *middleware.RateLimiter) *PasswordResetController {
+	return &PasswordResetController{
+		PasswordResetService: *passwordResetService,
+		ForgotLimiter:        forgotLimiter,
+	}
+}
+
+func (controller *PasswordResetController) Route(router *gin.Engine) *gin.Engine {
+	router.POST("/api/users/password/forgot", middleware.RateLimited(controller.ForgotLimiter, controller.Forgot))
+	router.POST("/api/users/password/reset", controller.Reset)
+
+	return router
+}
+
+// Forgot emails a password reset link. The answer is the same whether or not the email belongs to a user, so it can
+// not be used to find out who has an account.
+func (controller *PasswordResetController) Forgot(ctx *gin.Context) {
+	var request model.ForgotPasswordRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   "Please Check Your Input",
+		})
+		return
+	}
+
+	err := controller.PasswordResetService.Forgot(ctx.Request.Context(), request.Email, ctx.GetHeader("Accept-Language"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, model.WebResponse{
+		Code:   http.StatusAccepted,
+		Status: "if the email belongs to an account, a password reset link is on its way",
+		Data:   nil,
+	})
+}
+
+// Reset sets a new password with the token of a reset link and logs the user out of every session
+func (controller *PasswordResetController) Reset(ctx *gin.Context) {
+	var request model.ResetPasswordRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   "Please Check Your Input",
+		})
+		return
+	}
+
+	err := controller.PasswordResetService.Reset(ctx.Request.Context(), request.Token, request.Password)
+	if err == service.ErrPasswordResetInvalid {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "password successfully reset, please login again",
+		Data:   nil,
+	})
+}
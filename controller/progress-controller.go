@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/middleware"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/service"
+)
+
+type ProgressController struct {
+	ProgressService service.ProgressService
+}
+
+func NewProgressController(progressService *service.ProgressService) *ProgressController {
+	return &ProgressController{
+		ProgressService: *progressService,
+	}
+}
+
+func (controller *ProgressController) Route(router *gin.Engine) *gin.Engine {
+	authorized := router.Group("/api/courses/:code")
+	{
+		authorized.POST("/articles/:articleId/complete", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceProgress, controller.Complete))
+		authorized.GET("/progress", middleware.Authorized(middleware.ActionRead, middleware.ResourceProgress, controller.Progress))
+		authorized.GET("/progress/students", middleware.Authorized(middleware.ActionList, middleware.ResourceProgress, controller.FindAll))
+	}
+
+	return router
+}
+
+// Complete marks the article complete for the user logged in
+func (controller *ProgressController) Complete(ctx *gin.Context) {
+	idArticle, err := strconv.Atoi(ctx.Param("articleId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	progress, err := controller.ProgressService.Complete(ctx.Request.Context(), ctx.Param("code"), entity.CurriculumArticle, idArticle, principal.Id)
+	if err != nil {
+		ctx.JSON(progressErrorCode(err), model.WebResponse{
+			Code:   progressErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "article successfully completed",
+		Data:   progress,
+	})
+}
+
+func (controller *ProgressController) Progress(ctx *gin.Context) {
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	progress, err := controller.ProgressService.Progress(ctx.Request.Context(), ctx.Param("code"), principal.Id)
+	if err != nil {
+		ctx.JSON(progressErrorCode(err), model.WebResponse{
+			Code:   progressErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   progress,
+	})
+}
+
+func (controller *ProgressController) FindAll(ctx *gin.Context) {
+	progresses, err := controller.ProgressService.FindAll(ctx.Request.Context(), ctx.Param("code"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   progresses,
+	})
+}
+
+// progressErrorCode answers 403 to users not enrolled in the course and 404 for articles outside it
+func progressErrorCode(err error) int {
+	switch {
+	case errors.Is(err, service.ErrNotEnrolled):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrCurriculumItemNotFound):
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
@@ -22,22 +22,32 @@ func NewQuestionController(questionService *service.QuestionService) *QuestionCo
 func (controller *QuestionController) Route(router *gin.Engine) *gin.Engine {
 	authorized := router.Group("/api")
 	{
-		authorized.GET("/questions/all", middleware.UserHandler(controller.FindAll))
-		authorized.POST("/questions/create", middleware.UserHandler(controller.Create))
-		authorized.PUT("/questions/update/:questionId", middleware.UserHandler(controller.Update))
-		authorized.DELETE("/questions/:questionId", middleware.UserHandler(controller.Delete))
-		authorized.GET("/questions/by-user/:userId", middleware.UserHandler(controller.FindByUserId))
-		authorized.GET("/questions/:id", middleware.UserHandler(controller.FindById))
+		authorized.GET("/questions/all", middleware.Authorized(middleware.ActionList, middleware.ResourceQuestion, controller.FindAll))
+		authorized.POST("/questions/create", middleware.Authorized(middleware.ActionCreate, middleware.ResourceQuestion, controller.Create))
+		authorized.PUT("/questions/update/:questionId", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceQuestion, controller.Update))
+		authorized.DELETE("/questions/:questionId", middleware.Authorized(middleware.ActionDelete, middleware.ResourceQuestion, controller.Delete))
+		authorized.GET("/questions/by-user/:userId", middleware.Authorized(middleware.ActionRead, middleware.ResourceQuestion, controller.FindByUserId))
+		authorized.GET("/questions/:id", middleware.Authorized(middleware.ActionRead, middleware.ResourceQuestion, controller.FindById))
 	}
 
 	return router
 }
 
 func (controller *QuestionController) FindAll(ctx *gin.Context) {
-	questions, err := controller.QuestionService.FindAll(ctx.Request.Context())
+	query, err := bindListQuery(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
-			Code:   http.StatusInternalServerError,
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	questions, page, err := controller.QuestionService.FindAll(ctx.Request.Context(), query)
+	if err != nil {
+		ctx.JSON(listErrorCode(err), model.WebResponse{
+			Code:   listErrorCode(err),
 			Status: err.Error(),
 			Data:   nil,
 		})
@@ -45,9 +55,11 @@ func (controller *QuestionController) FindAll(ctx *gin.Context) {
 	}
 
 	ctx.JSON(http.StatusOK, model.WebResponse{
-		Code:   http.StatusOK,
-		Status: "OK",
-		Data:   questions,
+		Code:       http.StatusOK,
+		Status:     "OK",
+		NextCursor: page.NextCursor,
+		Total:      &page.Total,
+		Data:       questions,
 	})
 }
 
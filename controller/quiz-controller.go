@@ -0,0 +1,394 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rg-km/final-project-engineering-12/backend/middleware"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/service"
+)
+
+type QuizController struct {
+	QuizService service.QuizService
+}
+
+func NewQuizController(quizService *service.QuizService) *QuizController {
+	return &QuizController{
+		QuizService: *quizService,
+	}
+}
+
+func (controller *QuizController) Route(router *gin.Engine) *gin.Engine {
+	authorized := router.Group("/api/courses/:code")
+	{
+		authorized.GET("/quizzes", middleware.Authorized(middleware.ActionRead, middleware.ResourceQuiz, controller.FindAll))
+		authorized.GET("/quizzes/:quizId", middleware.Authorized(middleware.ActionRead, middleware.ResourceQuiz, controller.FindById))
+		authorized.POST("/quizzes", middleware.Authorized(middleware.ActionCreate, middleware.ResourceQuiz, controller.Create))
+		authorized.PATCH("/quizzes/:quizId", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceQuiz, controller.Update))
+		authorized.DELETE("/quizzes/:quizId", middleware.Authorized(middleware.ActionDelete, middleware.ResourceQuiz, controller.Delete))
+		authorized.GET("/quizzes/:quizId/next", middleware.Authorized(middleware.ActionRead, middleware.ResourceQuiz, controller.Next))
+		authorized.GET("/quizzes/:quizId/previous", middleware.Authorized(middleware.ActionRead, middleware.ResourceQuiz, controller.Previous))
+		authorized.GET("/quizzes/:quizId/attempts", middleware.Authorized(middleware.ActionRead, middleware.ResourceQuizAttempt, controller.FindAllAttempts))
+		authorized.POST("/quizzes/:quizId/attempts", middleware.Authorized(middleware.ActionCreate, middleware.ResourceQuizAttempt, controller.StartAttempt))
+		authorized.POST("/quizzes/:quizId/attempts/:attemptId/submit", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceQuizAttempt, controller.SubmitAttempt))
+		authorized.GET("/quizzes/:quizId/results", middleware.Authorized(middleware.ActionList, middleware.ResourceQuizAttempt, controller.FindAllResults))
+	}
+
+	return router
+}
+
+func (controller *QuizController) FindAll(ctx *gin.Context) {
+	quizzes, err := controller.QuizService.FindAll(ctx.Request.Context(), ctx.Param("code"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   quizzes,
+	})
+}
+
+// FindById shows the answers to the callers allowed to change the quiz
+func (controller *QuizController) FindById(ctx *gin.Context) {
+	idQuiz, err := strconv.Atoi(ctx.Param("quizId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	withAnswers := middleware.Allowed(principal.Role, middleware.ActionUpdate, middleware.ResourceQuiz)
+
+	quiz, err := controller.QuizService.FindById(ctx.Request.Context(), ctx.Param("code"), idQuiz, withAnswers)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   quiz,
+	})
+}
+
+func (controller *QuizController) Create(ctx *gin.Context) {
+	var request model.SaveQuizRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	quiz, err := controller.QuizService.Create(ctx.Request.Context(), ctx.Param("code"), request)
+	if err != nil {
+		ctx.JSON(quizErrorCode(err), model.WebResponse{
+			Code:   quizErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "quiz successfully created",
+		Data:   quiz,
+	})
+}
+
+func (controller *QuizController) Update(ctx *gin.Context) {
+	var request model.SaveQuizRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	request.Id, err = strconv.Atoi(ctx.Param("quizId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	quiz, err := controller.QuizService.Update(ctx.Request.Context(), ctx.Param("code"), request)
+	if err != nil {
+		ctx.JSON(quizErrorCode(err), model.WebResponse{
+			Code:   quizErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "quiz successfully updated",
+		Data:   quiz,
+	})
+}
+
+func (controller *QuizController) Delete(ctx *gin.Context) {
+	idQuiz, err := strconv.Atoi(ctx.Param("quizId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	err = controller.QuizService.Delete(ctx.Request.Context(), ctx.Param("code"), idQuiz)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "quiz successfully deleted",
+		Data:   nil,
+	})
+}
+
+func (controller *QuizController) Next(ctx *gin.Context) {
+	idQuiz, err := strconv.Atoi(ctx.Param("quizId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	nextQuiz, err := controller.QuizService.Next(ctx.Request.Context(), ctx.Param("code"), idQuiz)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   nextQuiz,
+	})
+}
+
+func (controller *QuizController) Previous(ctx *gin.Context) {
+	idQuiz, err := strconv.Atoi(ctx.Param("quizId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	previousQuiz, err := controller.QuizService.Previous(ctx.Request.Context(), ctx.Param("code"), idQuiz)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   previousQuiz,
+	})
+}
+
+func (controller *QuizController) FindAllAttempts(ctx *gin.Context) {
+	idQuiz, err := strconv.Atoi(ctx.Param("quizId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	attempts, err := controller.QuizService.FindAllAttempts(ctx.Request.Context(), ctx.Param("code"), idQuiz, principal.Id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   attempts,
+	})
+}
+
+func (controller *QuizController) StartAttempt(ctx *gin.Context) {
+	idQuiz, err := strconv.Atoi(ctx.Param("quizId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	attempt, err := controller.QuizService.StartAttempt(ctx.Request.Context(), ctx.Param("code"), idQuiz, principal.Id)
+	if err != nil {
+		ctx.JSON(quizErrorCode(err), model.WebResponse{
+			Code:   quizErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "quiz attempt successfully started",
+		Data:   attempt,
+	})
+}
+
+func (controller *QuizController) SubmitAttempt(ctx *gin.Context) {
+	var request model.SubmitQuizAttemptRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	request.QuizId, err = strconv.Atoi(ctx.Param("quizId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+	request.AttemptId, err = strconv.Atoi(ctx.Param("attemptId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	request.UserId = principal.Id
+
+	attempt, err := controller.QuizService.SubmitAttempt(ctx.Request.Context(), ctx.Param("code"), request)
+	if err != nil {
+		ctx.JSON(quizErrorCode(err), model.WebResponse{
+			Code:   quizErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "quiz attempt successfully graded",
+		Data:   attempt,
+	})
+}
+
+func (controller *QuizController) FindAllResults(ctx *gin.Context) {
+	idQuiz, err := strconv.Atoi(ctx.Param("quizId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	results, err := controller.QuizService.FindAllResults(ctx.Request.Context(), ctx.Param("code"), idQuiz)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   results,
+	})
+}
+
+// quizErrorCode answers 403 when the student may not take or hand in the attempt and 409 when it conflicts with it
+func quizErrorCode(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidQuizQuestion), errors.Is(err, service.ErrEmptyQuiz), errors.Is(err, service.ErrInvalidAnswer):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrNotEnrolled), errors.Is(err, service.ErrNoAttemptsLeft), errors.Is(err, service.ErrAttemptExpired):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrQuizInUse), errors.Is(err, service.ErrAttemptSubmitted):
+		return http.StatusConflict
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,236 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rg-km/final-project-engineering-12/backend/middleware"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/service"
+)
+
+type RubricController struct {
+	RubricService service.RubricService
+}
+
+func NewRubricController(rubricService *service.RubricService) *RubricController {
+	return &RubricController{
+		RubricService: *rubricService,
+	}
+}
+
+func (controller *RubricController) Route(router *gin.Engine) *gin.Engine {
+	authorized := router.Group("/api/courses/:code/submissions/:submissionId")
+	{
+		authorized.GET("/rubric", middleware.Authorized(middleware.ActionRead, middleware.ResourceSubmission, controller.FindRubric))
+		authorized.PUT("/rubric", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceSubmission, controller.SaveRubric))
+		authorized.DELETE("/rubric", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceSubmission, controller.DeleteRubric))
+		authorized.GET("/user-submit/:userSubmissionId/rubric", middleware.Authorized(middleware.ActionRead, middleware.ResourceUserSubmission, controller.FindScores))
+		authorized.PUT("/user-submit/:userSubmissionId/rubric", middleware.Authorized(middleware.ActionGrade, middleware.ResourceUserSubmission, controller.SaveScores))
+	}
+
+	return router
+}
+
+func (controller *RubricController) FindRubric(ctx *gin.Context) {
+	idSubmission, err := strconv.Atoi(ctx.Param("submissionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	rubric, err := controller.RubricService.FindRubric(ctx.Request.Context(), ctx.Param("code"), idSubmission)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   rubric,
+	})
+}
+
+func (controller *RubricController) SaveRubric(ctx *gin.Context) {
+	var request model.SaveRubricRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	request.ModuleSubmissionId, err = strconv.Atoi(ctx.Param("submissionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	rubric, err := controller.RubricService.SaveRubric(ctx.Request.Context(), ctx.Param("code"), request)
+	if err != nil {
+		ctx.JSON(rubricErrorCode(err), model.WebResponse{
+			Code:   rubricErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "rubric successfully saved",
+		Data:   rubric,
+	})
+}
+
+func (controller *RubricController) DeleteRubric(ctx *gin.Context) {
+	idSubmission, err := strconv.Atoi(ctx.Param("submissionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	err = controller.RubricService.DeleteRubric(ctx.Request.Context(), ctx.Param("code"), idSubmission)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "rubric successfully deleted",
+		Data:   nil,
+	})
+}
+
+func (controller *RubricController) FindScores(ctx *gin.Context) {
+	idSubmission, err := strconv.Atoi(ctx.Param("submissionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+	userSubmissionId, err := strconv.Atoi(ctx.Param("userSubmissionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	scores, err := controller.RubricService.FindScores(ctx.Request.Context(), ctx.Param("code"), idSubmission, userSubmissionId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	if !middleware.OwnerOrTeacher(ctx, scores.UserId) {
+		ctx.JSON(http.StatusForbidden, model.WebResponse{
+			Code:   http.StatusForbidden,
+			Status: "You are not allowed to view this submission",
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   scores,
+	})
+}
+
+func (controller *RubricController) SaveScores(ctx *gin.Context) {
+	var request model.SaveRubricScoresRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	idSubmission, err := strconv.Atoi(ctx.Param("submissionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+	request.UserSubmissionId, err = strconv.Atoi(ctx.Param("userSubmissionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	scores, err := controller.RubricService.SaveScores(ctx.Request.Context(), ctx.Param("code"), idSubmission, request)
+	if err != nil {
+		ctx.JSON(rubricErrorCode(err), model.WebResponse{
+			Code:   rubricErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "user submission successfully graded",
+		Data:   scores,
+	})
+}
+
+// rubricErrorCode answers 409 when a scored rubric would change and 400 for scores that do not fit the rubric
+func rubricErrorCode(err error) int {
+	switch {
+	case errors.Is(err, service.ErrRubricInUse):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrNoRubric), errors.Is(err, service.ErrInvalidRubricScores):
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
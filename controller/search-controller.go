@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rg-km/final-project-engineering-12/backend/middleware"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/service"
+)
+
+type SearchController struct {
+	SearchService service.SearchService
+}
+
+func NewSearchController(searchService *service.SearchService) *SearchController {
+	return &SearchController{
+		SearchService: *searchService,
+	}
+}
+
+func (controller *SearchController) Route(router *gin.Engine) *gin.Engine {
+	authorized := router.Group("/api")
+	{
+		authorized.GET("/search", middleware.Authenticated(controller.Search))
+	}
+
+	return router
+}
+
+func (controller *SearchController) Search(ctx *gin.Context) {
+	query, err := bindListQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	results, page, err := controller.SearchService.Search(ctx.Request.Context(), principal, ctx.Query("q"), query)
+	if err != nil {
+		code := listErrorCode(err)
+		switch {
+		case errors.Is(err, service.ErrEmptySearch):
+			code = http.StatusBadRequest
+		case errors.Is(err, service.ErrSearchUnavailable):
+			code = http.StatusNotImplemented
+		}
+
+		ctx.JSON(code, model.WebResponse{
+			Code:   code,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:       http.StatusOK,
+		Status:     "OK",
+		NextCursor: page.NextCursor,
+		Total:      &page.Total,
+		Data:       results,
+	})
+}
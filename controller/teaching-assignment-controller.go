@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rg-km/final-project-engineering-12/backend/middleware"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/service"
+)
+
+type TeachingAssignmentController struct {
+	TeachingAssignmentService service.TeachingAssignmentService
+}
+
+func NewTeachingAssignmentController(teachingAssignmentService *service.TeachingAssignmentService) *TeachingAssignmentController {
+	return &TeachingAssignmentController{
+		TeachingAssignmentService: *teachingAssignmentService,
+	}
+}
+
+func (controller *TeachingAssignmentController) Route(router *gin.Engine) *gin.Engine {
+	authorized := router.Group("/api")
+	{
+		authorized.GET("/courses/:code/teachers", middleware.Authorized(middleware.ActionList, middleware.ResourceTeacher, controller.FindAllTeacherByCourse))
+		authorized.POST("/courses/:code/teachers", middleware.Authorized(middleware.ActionCreate, middleware.ResourceTeacher, controller.Assign))
+		authorized.DELETE("/courses/:code/teachers/:userId", middleware.Authorized(middleware.ActionDelete, middleware.ResourceTeacher, controller.Unassign))
+		authorized.GET("/teachers/courses", middleware.Authenticated(controller.FindAllCourseByTeacher))
+	}
+
+	return router
+}
+
+func (controller *TeachingAssignmentController) FindAllTeacherByCourse(ctx *gin.Context) {
+	teachers, err := controller.TeachingAssignmentService.FindAllTeacherByCourse(ctx.Request.Context(), ctx.Param("code"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   teachers,
+	})
+}
+
+func (controller *TeachingAssignmentController) Assign(ctx *gin.Context) {
+	var request model.CreateTeachingAssignmentRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	teacher, err := controller.TeachingAssignmentService.Assign(ctx.Request.Context(), ctx.Param("code"), request)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if err == service.ErrNotTeacher {
+			code = http.StatusBadRequest
+		}
+		ctx.JSON(code, model.WebResponse{
+			Code:   code,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "teacher successfully assigned",
+		Data:   teacher,
+	})
+}
+
+func (controller *TeachingAssignmentController) Unassign(ctx *gin.Context) {
+	userId, err := strconv.Atoi(ctx.Param("userId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	err = controller.TeachingAssignmentService.Unassign(ctx.Request.Context(), ctx.Param("code"), userId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "teacher successfully unassigned",
+		Data:   nil,
+	})
+}
+
+func (controller *TeachingAssignmentController) FindAllCourseByTeacher(ctx *gin.Context) {
+	principal, exists := middleware.CurrentPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusNotFound, model.WebResponse{
+			Code:   http.StatusNotFound,
+			Status: "user not found",
+			Data:   nil,
+		})
+		return
+	}
+
+	courses, err := controller.TeachingAssignmentService.FindAllCourseByTeacher(ctx.Request.Context(), principal.Id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   courses,
+	})
+}
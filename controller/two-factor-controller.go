@@ -0,0 +1,297 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/middleware"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/service"
+)
+
+type TwoFactorController struct {
+	TwoFactorService    service.TwoFactorService
+	RefreshTokenService service.RefreshTokenService
+	ChallengeLimiter    *middleware.RateLimiter
+}
+
+func NewTwoFactorController(twoFactorService *service.TwoFactorService, refreshTokenService *service.RefreshTokenService, challengeLimiter *middleware.RateLimiter) *TwoFactorController {
+	return &TwoFactorController{
+		TwoFactorService:    *twoFactorService,
+		RefreshTokenService: *refreshTokenService,
+		ChallengeLimiter:    challengeLimiter,
+	}
+}
+
+func (controller *TwoFactorController) Route(router *gin.Engine) *gin.Engine {
+	router.GET("/api/users/2fa", middleware.Authenticated(controller.Status))
+	router.POST("/api/users/2fa/enroll", middleware.Authenticated(controller.Enroll))
+	router.POST("/api/users/2fa/confirm", middleware.Authenticated(controller.Confirm))
+	router.POST("/api/users/2fa/disable", middleware.Authenticated(controller.Disable))
+	router.GET("/api/users/2fa/policies", middleware.Authorized(middleware.ActionList, middleware.ResourceTwoFactor, controller.ListPolicies))
+	router.PUT("/api/users/2fa/policies/:role", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceTwoFactor, controller.SavePolicy))
+	router.POST("/api/users/login/2fa", middleware.RateLimited(controller.ChallengeLimiter, controller.VerifyChallenge))
+	router.POST("/api/users/login/2fa/enroll", middleware.RateLimited(controller.ChallengeLimiter, controller.EnrollChallenge))
+
+	return router
+}
+
+// Status tells the logged in user whether two-factor authentication is enabled and whether their role requires it
+func (controller *TwoFactorController) Status(ctx *gin.Context) {
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	response, err := controller.TwoFactorService.Status(ctx.Request.Context(), principal.Id, principal.Role)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+// Enroll returns a new secret for the logged in user to add to an authenticator app
+func (controller *TwoFactorController) Enroll(ctx *gin.Context) {
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	response, err := controller.TwoFactorService.Enroll(ctx.Request.Context(), principal.Id)
+	if err != nil {
+		ctx.JSON(twoFactorErrorCode(err), model.WebResponse{
+			Code:   twoFactorErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "enter a code of the authenticator app to enable two-factor authentication",
+		Data:   response,
+	})
+}
+
+// Confirm enables two-factor authentication with a first code of the authenticator app and answers the recovery codes.
+// The user has to log in again with the second step.
+func (controller *TwoFactorController) Confirm(ctx *gin.Context) {
+	var request model.TwoFactorCodeRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   "Please Check Your Input",
+		})
+		return
+	}
+
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	response, err := controller.TwoFactorService.Confirm(ctx.Request.Context(), principal.Id, request.Code)
+	if err != nil {
+		ctx.JSON(twoFactorErrorCode(err), model.WebResponse{
+			Code:   twoFactorErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "two-factor authentication enabled, please log in again",
+		Data:   response,
+	})
+}
+
+// Disable turns two-factor authentication off, it needs the password and a code or a recovery code
+func (controller *TwoFactorController) Disable(ctx *gin.Context) {
+	var request model.DisableTwoFactorRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   "Please Check Your Input",
+		})
+		return
+	}
+
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	err := controller.TwoFactorService.Disable(ctx.Request.Context(), principal.Id, principal.Role, request)
+	if err != nil {
+		ctx.JSON(twoFactorErrorCode(err), model.WebResponse{
+			Code:   twoFactorErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "two-factor authentication disabled",
+		Data:   nil,
+	})
+}
+
+// EnrollChallenge returns a new secret during a login that requires two-factor authentication the user has not set
+// up yet
+func (controller *TwoFactorController) EnrollChallenge(ctx *gin.Context) {
+	var request model.TwoFactorChallengeRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   "Please Check Your Input",
+		})
+		return
+	}
+
+	response, err := controller.TwoFactorService.EnrollChallenge(ctx.Request.Context(), request.ChallengeToken)
+	if err != nil {
+		ctx.JSON(twoFactorErrorCode(err), model.WebResponse{
+			Code:   twoFactorErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "enter a code of the authenticator app to log in",
+		Data:   response,
+	})
+}
+
+// VerifyChallenge completes a login with a code of the authenticator app or a recovery code and answers the tokens
+// the login answers for users without two-factor authentication
+func (controller *TwoFactorController) VerifyChallenge(ctx *gin.Context) {
+	var request model.VerifyTwoFactorChallengeRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   "Please Check Your Input",
+		})
+		return
+	}
+
+	response, err := controller.TwoFactorService.VerifyChallenge(ctx.Request.Context(), request)
+	if err != nil {
+		ctx.JSON(twoFactorErrorCode(err), model.WebResponse{
+			Code:   twoFactorErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	token, err := service.JWTAuthService().GenerateToken(ctx.Request.Context(), entity.Users{
+		Id:   response.Id,
+		Name: response.Name,
+		Role: response.Role,
+	}, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: "Internal Server Error",
+		})
+		return
+	}
+
+	refreshToken, err := controller.RefreshTokenService.Issue(ctx.Request.Context(), response.Id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: "Internal Server Error",
+		})
+		return
+	}
+
+	ctx.Header("Authorization", token)
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:         http.StatusOK,
+		Status:       "Login Successfull",
+		Token:        token,
+		RefreshToken: refreshToken,
+		Data:         response,
+	})
+}
+
+func (controller *TwoFactorController) ListPolicies(ctx *gin.Context) {
+	response, err := controller.TwoFactorService.ListPolicies(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+// SavePolicy sets whether users of the role can only log in with two-factor authentication. Users who have not set it
+// up yet are logged out and asked to at their next login.
+func (controller *TwoFactorController) SavePolicy(ctx *gin.Context) {
+	var request model.TwoFactorPolicyRequest
+
+	role, err := strconv.Atoi(ctx.Param("role"))
+	if err != nil || ctx.ShouldBindJSON(&request) != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   "Please Check Your Input",
+		})
+		return
+	}
+
+	response, err := controller.TwoFactorService.SavePolicy(ctx.Request.Context(), role, *request.Required)
+	if err != nil {
+		ctx.JSON(twoFactorErrorCode(err), model.WebResponse{
+			Code:   twoFactorErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+// twoFactorErrorCode answers 401 for a challenge that can not be completed any more and 403 for wrong codes and
+// passwords
+func twoFactorErrorCode(err error) int {
+	switch err {
+	case service.ErrTwoFactorChallengeInvalid:
+		return http.StatusUnauthorized
+	case service.ErrTwoFactorCode, service.ErrPasswordIncorrect, service.ErrTwoFactorRequired:
+		return http.StatusForbidden
+	case service.ErrTwoFactorEnabled:
+		return http.StatusConflict
+	case service.ErrTwoFactorNotEnrolled, service.ErrTwoFactorRole:
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
@@ -1,31 +1,35 @@
 package controller
 
 import (
-	"crypto/md5"
-	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	"github.com/rg-km/final-project-engineering-12/backend/entity"
 	"github.com/rg-km/final-project-engineering-12/backend/middleware"
 	"github.com/rg-km/final-project-engineering-12/backend/model"
 	"github.com/rg-km/final-project-engineering-12/backend/service"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
 )
 
 type UserController struct {
-	UserService       service.UserServiceImplement
-	UserCourseService service.UserCourseService
-	EmailService      service.EmailService
+	UserService         service.UserServiceImplement
+	UserCourseService   service.UserCourseService
+	EmailService        service.EmailService
+	RefreshTokenService service.RefreshTokenService
+	TwoFactorService    service.TwoFactorService
+	ResendLimiter       *middleware.RateLimiter
 }
 
-func NewUserController(userService *service.UserServiceImplement, userCourseService *service.UserCourseService, emailService *service.EmailService) UserController {
+func NewUserController(userService *service.UserServiceImplement, userCourseService *service.UserCourseService, emailService *service.EmailService, refreshTokenService *service.RefreshTokenService, twoFactorService *service.TwoFactorService, resendLimiter *middleware.RateLimiter) UserController {
 	return UserController{
-		UserService:       *userService,
-		UserCourseService: *userCourseService,
-		EmailService:      *emailService,
+		UserService:         *userService,
+		UserCourseService:   *userCourseService,
+		EmailService:        *emailService,
+		RefreshTokenService: *refreshTokenService,
+		TwoFactorService:    *twoFactorService,
+		ResendLimiter:       resendLimiter,
 	}
 }
 
@@ -41,17 +45,19 @@ func (controller *UserController) Route(router *gin.Engine) *gin.Engine {
 
 	api := router.Group("/api")
 	{
-		api.POST("/users", controller.UserRegister)                                                // done
-		api.POST("/users/login", controller.userLogin)                                             // done
-		api.GET("/userstatus", middleware.UserHandler(controller.userStatus))                      // done
-		api.POST("/users/logout", middleware.UserHandler(controller.userLogout))                   // done
-		api.PUT("/users/roleupdate/:id/:role", middleware.AdminHandler(controller.userRoleUpdate)) // done
-		api.GET("/users/:id", middleware.UserHandler(controller.getUserByID))                      // done
-		api.GET("/users", middleware.AdminHandler(controller.listUser))                            // done
-		api.PUT("/users/:id", middleware.UserHandler(controller.updateUser))                       // done
-		api.DELETE("/users/:id", middleware.AdminHandler(controller.deleteUser))
-		api.GET("/users/submissions", middleware.UserHandler(controller.StudentSubmission))
+		api.POST("/users", controller.UserRegister)                                                                                                    // done
+		api.POST("/users/login", controller.userLogin)                                                                                                 // done
+		api.GET("/userstatus", middleware.Authenticated(controller.userStatus))                                                                        // done
+		api.POST("/users/logout", middleware.Authenticated(controller.userLogout))                                                                     // done
+		api.PUT("/users/roleupdate/:id/:role", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceUserRole, controller.userRoleUpdate)) // done
+		api.GET("/users/:id", middleware.Authorized(middleware.ActionRead, middleware.ResourceUser, controller.getUserByID))                           // done
+		api.GET("/users", middleware.Authorized(middleware.ActionList, middleware.ResourceUser, controller.listUser))                                  // done
+		api.PUT("/users/:id", middleware.Authorized(middleware.ActionUpdate, middleware.ResourceUser, controller.updateUser))                          // done
+		api.DELETE("/users/:id", middleware.Authorized(middleware.ActionDelete, middleware.ResourceUser, controller.deleteUser))
+		api.GET("/users/submissions", middleware.Authenticated(controller.StudentSubmission))
 		api.GET("/users/verify", controller.VerifyEmail)
+		api.POST("/users/verify/resend", middleware.RateLimited(controller.ResendLimiter, controller.ResendVerification))
+		api.POST("/users/token/refresh", controller.refreshToken)
 	}
 	return router
 }
@@ -66,27 +72,20 @@ func (controller *UserController) UserRegister(ctx *gin.Context) {
 
 	// Data email verification
 	timestamp := time.Now().Add(1 * time.Hour).Unix()
-	timestampString := strconv.Itoa(int(timestamp))
-	signature := md5.Sum([]byte(user.Email + timestampString))
-	signatureString := fmt.Sprintf("%x", signature)
-
-	responses, err := controller.UserService.RegisterUser(ctx, user, signatureString, int(timestamp))
+	signature, err := utils.RandomToken(16)
 	if err != nil {
-		ctx.IndentedJSON(http.StatusUnauthorized, model.WebResponse{
-			Code:   401,
-			Status: err.Error(),
+		ctx.IndentedJSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   500,
+			Status: "Internal Server Error",
 		})
 		return
 	}
 
-	// Send email to user
-	message := fmt.Sprintf("visite this link to verification your email : http://localhost:8080/verification?email=%v&signature=%v", user.Email, signatureString)
-	err = controller.EmailService.SendEmailWithText(user.Email, message)
+	responses, err := controller.UserService.RegisterUser(ctx, user, signature, int(timestamp), ctx.GetHeader("Accept-Language"))
 	if err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, model.WebResponse{
-			Code:   400,
+		ctx.IndentedJSON(http.StatusUnauthorized, model.WebResponse{
+			Code:   401,
 			Status: err.Error(),
-			Data:   nil,
 		})
 		return
 	}
@@ -116,6 +115,15 @@ func (controller *UserController) userLogin(ctx *gin.Context) {
 
 	response, err := controller.UserService.UserLogin(ctx, user)
 
+	if err == service.ErrEmailNotVerified {
+		ctx.IndentedJSON(http.StatusForbidden, model.WebResponse{
+			Code:   403,
+			Status: "Forbidden",
+			Data:   "Please verify your email before logging in",
+		})
+		return
+	}
+
 	if err != nil {
 		ctx.IndentedJSON(http.StatusBadRequest, model.WebResponse{
 			Code:   400,
@@ -136,66 +144,110 @@ func (controller *UserController) userLogin(ctx *gin.Context) {
 	ctx.Header("Accept", "application/json")
 	ctx.Header("Content-Type", "application/json")
 
-	token := service.JWTAuthService().GenerateToken(entity.Users{
+	// Users with two-factor authentication only get their tokens once they completed the challenge
+	challenge, required, err := controller.TwoFactorService.Challenge(ctx.Request.Context(), response.Id, response.Role)
+	if err != nil {
+		ctx.IndentedJSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   500,
+			Status: "Internal Server Error",
+		})
+		return
+	}
+	if required {
+		ctx.IndentedJSON(http.StatusAccepted, model.WebResponse{
+			Code:   202,
+			Status: "Two-Factor Authentication Required",
+			Data:   challenge,
+		})
+		return
+	}
+
+	token, err := service.JWTAuthService().GenerateToken(ctx.Request.Context(), entity.Users{
 		Id:   response.Id,
 		Name: response.Name,
 		Role: response.Role,
-	})
+	}, ctx.ClientIP(), ctx.Request.UserAgent())
+	if err != nil {
+		ctx.IndentedJSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   500,
+			Status: "Internal Server Error",
+		})
+		return
+	}
+
+	refreshToken, err := controller.RefreshTokenService.Issue(ctx.Request.Context(), response.Id)
+	if err != nil {
+		ctx.IndentedJSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   500,
+			Status: "Internal Server Error",
+		})
+		return
+	}
 
 	ctx.Header("Authorization", token)
 
 	ctx.IndentedJSON(http.StatusOK, model.WebResponse{
-		Code:   200,
-		Status: "Login Successfull",
-		Token:  token,
-		Data:   response,
+		Code:         200,
+		Status:       "Login Successfull",
+		Token:        token,
+		RefreshToken: refreshToken,
+		Data:         response,
 	})
 }
 
-//Function to get user status
-func (controller *UserController) userStatus(ctx *gin.Context) {
-	token := ctx.GetHeader("Authorization")
-
-	if token == "" {
-		ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-			Code:   401,
-			Status: "Unauthorized",
-			Data:   "Please Login First",
-		})
-		return
-	}
+//Function to exchange a refresh token for a new token pair
+func (controller *UserController) refreshToken(ctx *gin.Context) {
+	var request model.RefreshTokenRequest
 
-	if ok := service.JWTAuthService().CheckToken(token); ok != nil {
-		ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-			Code:   401,
-			Status: "Unauthorized",
-			Data:   "Invalid Token",
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   400,
+			Status: "Bad Request",
+			Data:   "Please Check Your Input",
 		})
 		return
 	}
 
-	tokenClaims := jwt.MapClaims{}
-	_, err := jwt.ParseWithClaims(token, tokenClaims, func(token *jwt.Token) (interface{}, error) {
-		return []byte("your secret api key"), nil
-	},
-	)
+	tokens, err := controller.RefreshTokenService.Refresh(ctx.Request.Context(), request.RefreshToken, ctx.ClientIP(), ctx.Request.UserAgent())
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-			Code:   401,
-			Status: "Cannot parse token",
+		if err == service.ErrRefreshTokenInvalid || err == service.ErrRefreshTokenReused {
+			ctx.JSON(http.StatusUnauthorized, model.WebResponse{
+				Code:   401,
+				Status: "Unauthorized",
+				Data:   err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   500,
+			Status: "Internal Server Error",
 		})
 		return
 	}
 
-	id := tokenClaims["id"].(float64)
+	ctx.Header("Authorization", tokens.AccessToken)
+
+	ctx.IndentedJSON(http.StatusOK, model.WebResponse{
+		Code:         200,
+		Status:       "Token Refreshed",
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	})
+}
 
-	user, err := controller.UserService.GetUserbyID(ctx, int(id))
+//Function to get user status
+func (controller *UserController) userStatus(ctx *gin.Context) {
+	principal, _ := middleware.CurrentPrincipal(ctx)
+
+	user, err := controller.UserService.GetUserbyID(ctx, principal.Id)
 
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, model.WebResponse{
 			Code:   401,
 			Status: "Cannot get user",
 		})
+		return
 	}
 
 	ctx.IndentedJSON(http.StatusOK, model.WebResponse{
@@ -210,26 +262,20 @@ func (controller *UserController) userLogout(ctx *gin.Context) {
 	ctx.Header("Accept", "application/json")
 	ctx.Header("Content-Type", "application/json")
 
-	token := ctx.GetHeader("Authorization")
-
-	if token == "" {
-		ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-			Code:   401,
-			Status: "Unauthorized",
-		})
-		return
-	}
-
-	if ok := service.JWTAuthService().CheckToken(token); ok != nil {
-		ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-			Code:   401,
-			Status: "Unauthorized",
-			Data:   "Please Login First",
-		})
-		return
+	// The body is optional, without a refresh token only the session ends
+	var request model.LogoutRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, model.WebResponse{
+				Code:   400,
+				Status: "Bad Request",
+			})
+			return
+		}
 	}
 
-	err := service.JWTAuthService().DeleteToken(token)
+	principal, _ := middleware.CurrentPrincipal(ctx)
+	err := service.JWTAuthService().DeleteToken(ctx.Request.Context(), ctx.GetHeader("Authorization"))
 
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, model.WebResponse{
@@ -239,6 +285,17 @@ func (controller *UserController) userLogout(ctx *gin.Context) {
 		return
 	}
 
+	if request.RefreshToken != "" {
+		err = controller.RefreshTokenService.Revoke(ctx.Request.Context(), request.RefreshToken, principal.Id)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+				Code:   500,
+				Status: "Internal Server Error",
+			})
+			return
+		}
+	}
+
 	ctx.IndentedJSON(http.StatusOK, model.WebResponse{
 		Code:   200,
 		Status: "Logout Successfull",
@@ -259,49 +316,6 @@ func (controller *UserController) userRoleUpdate(ctx *gin.Context) {
 		return
 	}
 
-	token := ctx.GetHeader("Authorization")
-
-	if token == "" {
-		ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-			Code:   401,
-			Status: "Unauthorized",
-			Data:   "Please Login First",
-		})
-		return
-	}
-
-	if ok := service.JWTAuthService().CheckToken(token); ok != nil {
-		ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-			Code:   401,
-			Status: "Unauthorized",
-			Data:   "Invalid Token",
-		})
-		return
-	}
-
-	tokenClaims := jwt.MapClaims{}
-	_, err = jwt.ParseWithClaims(token, tokenClaims, func(token *jwt.Token) (interface{}, error) {
-		return []byte("your secret api key"), nil
-	},
-	)
-
-	iduser := tokenClaims["id"].(float64)
-	role := tokenClaims["role"].(string)
-	iduserint := int(iduser)
-
-	if iduserint != id && role != "1" {
-		ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-			Code:   401,
-			Status: "Unauthorized",
-			Data:   "You are not authorized to update role this user",
-		})
-		return
-	}
-
-	if err != nil {
-		return
-	}
-
 	response, err := controller.UserService.UpdateUserRole(ctx, id, roleUpdate)
 
 	if err != nil {
@@ -338,47 +352,7 @@ func (controller *UserController) getUserByID(ctx *gin.Context) {
 		return
 	}
 
-	response, err := controller.UserService.GetUserbyID(ctx, id)
-
-	if err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, model.WebResponse{
-			Code:   500,
-			Status: "Internal Server Error",
-		})
-		return
-	}
-
-	token := ctx.GetHeader("Authorization")
-
-	if token == "" {
-		ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-			Code:   401,
-			Status: "Unauthorized",
-			Data:   "Please Login First",
-		})
-		return
-	}
-
-	if ok := service.JWTAuthService().CheckToken(token); ok != nil {
-		ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-			Code:   401,
-			Status: "Unauthorized",
-			Data:   "Invalid Token",
-		})
-		return
-	}
-
-	tokenClaims := jwt.MapClaims{}
-	_, err = jwt.ParseWithClaims(token, tokenClaims, func(token *jwt.Token) (interface{}, error) {
-		return []byte("your secret api key"), nil
-	},
-	)
-
-	iduser := tokenClaims["id"].(float64)
-	role := tokenClaims["role"].(string)
-	iduserint := int(iduser)
-
-	if iduserint != id && role != "1" {
+	if !middleware.OwnerOrAdmin(ctx, id) {
 		ctx.JSON(http.StatusUnauthorized, model.WebResponse{
 			Code:   401,
 			Status: "Unauthorized",
@@ -387,7 +361,13 @@ func (controller *UserController) getUserByID(ctx *gin.Context) {
 		return
 	}
 
+	response, err := controller.UserService.GetUserbyID(ctx, id)
+
 	if err != nil {
+		ctx.IndentedJSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   500,
+			Status: "Internal Server Error",
+		})
 		return
 	}
 
@@ -411,15 +391,32 @@ func (controller *UserController) getUserByID(ctx *gin.Context) {
 
 //Function to show list user
 func (controller *UserController) listUser(ctx *gin.Context) {
-	responses, err := controller.UserService.ListUser(ctx)
+	query, err := bindListQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	responses, page, err := controller.UserService.ListUser(ctx, query)
 	if err != nil {
-		panic(err)
+		ctx.JSON(listErrorCode(err), model.WebResponse{
+			Code:   listErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
 	}
 
 	ctx.IndentedJSON(http.StatusOK, model.WebResponse{
-		Code:   200,
-		Status: "Get All User Successfull",
-		Data:   responses,
+		Code:       200,
+		Status:     "Get All User Successfull",
+		NextCursor: page.NextCursor,
+		Total:      &page.Total,
+		Data:       responses,
 	})
 }
 
@@ -437,56 +434,39 @@ func (controller *UserController) updateUser(ctx *gin.Context) {
 		return
 	}
 
-	token := ctx.GetHeader("Authorization")
-
-	if token == "" {
-		ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-			Code:   401,
-			Status: "Unauthorized",
-			Data:   "Please Login First",
-		})
-		return
-	}
-
-	if ok := service.JWTAuthService().CheckToken(token); ok != nil {
+	if !middleware.OwnerOrAdmin(ctx, id) {
 		ctx.JSON(http.StatusUnauthorized, model.WebResponse{
 			Code:   401,
 			Status: "Unauthorized",
-			Data:   "Invalid Token",
+			Data:   "You are not authorized to update this user",
 		})
 		return
 	}
 
-	tokenClaims := jwt.MapClaims{}
-	_, err = jwt.ParseWithClaims(token, tokenClaims, func(token *jwt.Token) (interface{}, error) {
-		return []byte("your secret api key"), nil
-	},
-	)
+	responses, err := controller.UserService.UpdateUser(ctx, id, user)
 
-	if err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, model.WebResponse{
-			Code:   500,
-			Status: "Internal Server Error",
+	if err == service.ErrUserNotFound {
+		ctx.JSON(http.StatusNotFound, model.WebResponse{
+			Code:   404,
+			Status: "User Not Found",
 		})
 		return
 	}
 
-	iduser := tokenClaims["id"].(float64)
-	role := tokenClaims["role"].(string)
-	iduserint := int(iduser)
-
-	if iduserint != id && role != "1" {
-		ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-			Code:   401,
-			Status: "Unauthorized",
-			Data:   "You are not authorized to update this user",
+	if err == service.ErrRoleChange {
+		ctx.JSON(http.StatusForbidden, model.WebResponse{
+			Code:   403,
+			Status: "Forbidden",
+			Data:   err.Error(),
 		})
 		return
 	}
 
-	responses, err := controller.UserService.UpdateUser(ctx, id, user)
-
 	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   500,
+			Status: "Internal Server Error",
+		})
 		return
 	}
 
@@ -540,7 +520,7 @@ func (controller *UserController) StudentSubmission(ctx *gin.Context) {
 		limit = limits
 	}
 
-	idUser, exists := ctx.Get("id_user")
+	principal, exists := middleware.CurrentPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusNotFound, model.WebResponse{
 			Code:   http.StatusNotFound,
@@ -550,7 +530,7 @@ func (controller *UserController) StudentSubmission(ctx *gin.Context) {
 		return
 	}
 
-	id := int(idUser.(float64))
+	id := principal.Id
 	studentSubmissions, err := controller.UserCourseService.FindAllStudentSubmissions(ctx, id, limit)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
@@ -591,3 +571,34 @@ func (controller *UserController) VerifyEmail(ctx *gin.Context) {
 		Data:   nil,
 	})
 }
+
+// ResendVerification sends a new verification link to the email. The answer is the same whether or not the email
+// belongs to an unverified user, so it can not be used to find out who has an account.
+func (controller *UserController) ResendVerification(ctx *gin.Context) {
+	var request model.ResendVerificationRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   "Please Check Your Input",
+		})
+		return
+	}
+
+	err := controller.EmailService.ResendVerification(ctx.Request.Context(), request.Email, ctx.GetHeader("Accept-Language"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, model.WebResponse{
+		Code:   http.StatusAccepted,
+		Status: "if the email belongs to an unverified account, a new verification link is on its way",
+		Data:   nil,
+	})
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rg-km/final-project-engineering-12/backend/middleware"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/service"
+)
+
+// maxImportSize is the largest CSV accepted by the import
+const maxImportSize = 5 << 20
+
+type UserImportController struct {
+	UserImportService service.UserImportService
+}
+
+func NewUserImportController(userImportService *service.UserImportService) *UserImportController {
+	return &UserImportController{
+		UserImportService: *userImportService,
+	}
+}
+
+func (controller *UserImportController) Route(router *gin.Engine) *gin.Engine {
+	router.POST("/api/users/import", middleware.Authorized(middleware.ActionCreate, middleware.ResourceUserImport, controller.Import))
+
+	return router
+}
+
+// Import registers the users of the CSV in the file field, with ?dry_run=true it only reports what would be imported.
+// Invalid rows are answered with 422 and the report of every row.
+func (controller *UserImportController) Import(ctx *gin.Context) {
+	dryRun, err := strconv.ParseBool(ctx.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "dry_run must be true or false",
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportSize+1<<20)
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		code := http.StatusBadRequest
+		if strings.HasSuffix(err.Error(), "http: request body too large") {
+			code = http.StatusRequestEntityTooLarge
+		}
+		ctx.JSON(code, model.WebResponse{
+			Code:   code,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+	if file.Size > maxImportSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, model.WebResponse{
+			Code:   http.StatusRequestEntityTooLarge,
+			Status: "the file is too large",
+			Data:   nil,
+		})
+		return
+	}
+
+	body, err := file.Open()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+	defer body.Close()
+
+	report, err := controller.UserImportService.Import(ctx.Request.Context(), body, dryRun)
+	if errors.Is(err, service.ErrImportInvalid) {
+		ctx.JSON(http.StatusUnprocessableEntity, model.WebResponse{
+			Code:   http.StatusUnprocessableEntity,
+			Status: err.Error(),
+			Data:   report,
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(userImportErrorCode(err), model.WebResponse{
+			Code:   userImportErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	code, status := http.StatusCreated, "users successfully imported"
+	if dryRun {
+		code, status = http.StatusOK, "every row can be imported"
+	}
+
+	ctx.JSON(code, model.WebResponse{
+		Code:   code,
+		Status: status,
+		Data:   report,
+	})
+}
+
+// userImportErrorCode answers 400 for files that can not be read as users
+func userImportErrorCode(err error) int {
+	switch {
+	case errors.Is(err, service.ErrImportFile), errors.Is(err, service.ErrImportColumns), errors.Is(err, service.ErrImportEmpty):
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
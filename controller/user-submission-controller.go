@@ -1,37 +1,45 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/rg-km/final-project-engineering-12/backend/middleware"
 	"github.com/rg-km/final-project-engineering-12/backend/model"
 	"github.com/rg-km/final-project-engineering-12/backend/service"
-	"github.com/rg-km/final-project-engineering-12/backend/utils"
+	"github.com/rg-km/final-project-engineering-12/backend/storage"
 	"io"
-	"log"
+	"mime"
 	"net/http"
-	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
 
 type UserSubmissionsController struct {
 	UserSubmissionsService service.UserSubmissionsService
+	// MaxUploadSize bounds the request body before the multipart form is parsed
+	MaxUploadSize int64
 }
 
-func NewUserSubmissionsController(userSubmissionService *service.UserSubmissionsService) *UserSubmissionsController {
+func NewUserSubmissionsController(userSubmissionService *service.UserSubmissionsService, maxUploadSize int64) *UserSubmissionsController {
 	return &UserSubmissionsController{
 		UserSubmissionsService: *userSubmissionService,
+		MaxUploadSize:          maxUploadSize,
 	}
 }
 
 func (controller *UserSubmissionsController) Route(router *gin.Engine) *gin.Engine {
 	authorized := router.Group("/api/courses/:code/submissions/:submissionId")
 	{
-		authorized.GET("/user-submit/:userSubmissionId", middleware.UserHandler(controller.FindUserSubmissionById))
-		authorized.POST("/user-submit", middleware.UserHandler(controller.Create))
-		authorized.PATCH("/user-submit/:userSubmissionId", middleware.AdminHandler(controller.UpdateGrade))
-		authorized.POST("/user-submit/:userSubmissionId/download", middleware.UserHandler(controller.Download))
+		authorized.GET("/user-submit/:userSubmissionId", middleware.Authorized(middleware.ActionRead, middleware.ResourceUserSubmission, controller.FindUserSubmissionById))
+		authorized.POST("/user-submit", middleware.Authorized(middleware.ActionCreate, middleware.ResourceUserSubmission, controller.Create))
+		authorized.PATCH("/user-submit/:userSubmissionId", middleware.Authorized(middleware.ActionGrade, middleware.ResourceUserSubmission, controller.UpdateGrade))
+		authorized.POST("/user-submit/:userSubmissionId/download", middleware.Authorized(middleware.ActionRead, middleware.ResourceUserSubmission, controller.Download))
+		authorized.GET("/user-submit/:userSubmissionId/download-url", middleware.Authorized(middleware.ActionRead, middleware.ResourceUserSubmission, controller.DownloadURL))
+		authorized.GET("/user-submit/:userSubmissionId/attempts", middleware.Authorized(middleware.ActionRead, middleware.ResourceUserSubmission, controller.FindAllAttempts))
+		authorized.POST("/user-submit/:userSubmissionId/attempts/:attemptId/download", middleware.Authorized(middleware.ActionRead, middleware.ResourceUserSubmission, controller.DownloadAttempt))
+		authorized.PATCH("/user-submit/:userSubmissionId/graded-attempt", middleware.Authorized(middleware.ActionGrade, middleware.ResourceUserSubmission, controller.SelectGradedAttempt))
 	}
 
 	return router
@@ -48,7 +56,7 @@ func (controller *UserSubmissionsController) FindUserSubmissionById(ctx *gin.Con
 		return
 	}
 
-	userSubmission, err := controller.UserSubmissionsService.FindUserSubmissionById(ctx, userSubmissionId)
+	submissionId, err := strconv.Atoi(ctx.Param("submissionId"))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
 			Code:   http.StatusInternalServerError,
@@ -58,6 +66,25 @@ func (controller *UserSubmissionsController) FindUserSubmissionById(ctx *gin.Con
 		return
 	}
 
+	userSubmission, err := controller.UserSubmissionsService.FindUserSubmissionById(ctx, ctx.Param("code"), submissionId, userSubmissionId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	if !middleware.OwnerOrTeacher(ctx, userSubmission.UserId) {
+		ctx.JSON(http.StatusForbidden, model.WebResponse{
+			Code:   http.StatusForbidden,
+			Status: "You are not allowed to view this submission",
+			Data:   nil,
+		})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, model.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -66,10 +93,16 @@ func (controller *UserSubmissionsController) FindUserSubmissionById(ctx *gin.Con
 }
 
 func (controller *UserSubmissionsController) Create(ctx *gin.Context) {
+	// The form around the file takes a few more bytes, the exact size is checked by the service
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, controller.MaxUploadSize+1<<20)
 	file, err := ctx.FormFile("file")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, model.WebResponse{
-			Code:   http.StatusBadRequest,
+		code := http.StatusBadRequest
+		if strings.HasSuffix(err.Error(), "http: request body too large") {
+			code = http.StatusRequestEntityTooLarge
+		}
+		ctx.JSON(code, model.WebResponse{
+			Code:   code,
 			Status: err.Error(),
 			Data:   nil,
 		})
@@ -86,19 +119,7 @@ func (controller *UserSubmissionsController) Create(ctx *gin.Context) {
 		return
 	}
 
-	ct := strings.Split(file.Filename, ".")
-	file.Filename = utils.RandomString(20) + "." + ct[len(ct)-1]
-	path, err := utils.GetPath("/assets/", file.Filename)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
-			Code:   http.StatusInternalServerError,
-			Status: err.Error(),
-			Data:   nil,
-		})
-		return
-	}
-
-	err = ctx.SaveUploadedFile(file, path)
+	body, err := file.Open()
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
 			Code:   http.StatusInternalServerError,
@@ -107,9 +128,10 @@ func (controller *UserSubmissionsController) Create(ctx *gin.Context) {
 		})
 		return
 	}
+	defer body.Close()
 
 	var request model.CreateUserSubmissionsRequest
-	idUser, exists := ctx.Get("id_user")
+	principal, exists := middleware.CurrentPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusNotFound, model.WebResponse{
 			Code:   http.StatusNotFound,
@@ -119,14 +141,18 @@ func (controller *UserSubmissionsController) Create(ctx *gin.Context) {
 		return
 	}
 
-	request.UserId = int(idUser.(float64))
+	request.CodeCourse = ctx.Param("code")
+	request.UserId = principal.Id
 	request.ModuleSubmissionId = submissionId
-	request.File = &file.Filename
 
-	userSubmission, err := controller.UserSubmissionsService.SubmitFile(ctx, request)
+	userSubmission, err := controller.UserSubmissionsService.SubmitFile(ctx, request, model.UploadFile{
+		Name: file.Filename,
+		Size: file.Size,
+		Body: body,
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
-			Code:   http.StatusInternalServerError,
+		ctx.JSON(uploadErrorCode(err), model.WebResponse{
+			Code:   uploadErrorCode(err),
 			Status: err.Error(),
 			Data:   nil,
 		})
@@ -162,9 +188,19 @@ func (controller *UserSubmissionsController) UpdateGrade(ctx *gin.Context) {
 		return
 	}
 
+	submissionId, err := strconv.Atoi(ctx.Param("submissionId"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
 	request.Id = userSubmissionId
 
-	err = controller.UserSubmissionsService.UpdateGrade(ctx, request)
+	err = controller.UserSubmissionsService.UpdateGrade(ctx, ctx.Param("code"), submissionId, request)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
 			Code:   http.StatusInternalServerError,
@@ -192,7 +228,7 @@ func (controller *UserSubmissionsController) Download(ctx *gin.Context) {
 		return
 	}
 
-	userSubmission, err := controller.UserSubmissionsService.FindUserSubmissionById(ctx, userSubmissionId)
+	submissionId, err := strconv.Atoi(ctx.Param("submissionId"))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
 			Code:   http.StatusInternalServerError,
@@ -201,21 +237,82 @@ func (controller *UserSubmissionsController) Download(ctx *gin.Context) {
 		})
 		return
 	}
-	if userSubmission.File == nil {
-		ctx.JSON(http.StatusNotFound, model.WebResponse{
-			Code:   http.StatusNotFound,
-			Status: "no file",
+
+	if !controller.mayView(ctx, submissionId, userSubmissionId) {
+		return
+	}
+
+	body, key, err := controller.UserSubmissionsService.OpenFile(ctx, ctx.Param("code"), submissionId, userSubmissionId)
+	if err != nil {
+		ctx.JSON(fileErrorCode(err), model.WebResponse{
+			Code:   fileErrorCode(err),
+			Status: err.Error(),
 			Data:   nil,
 		})
 		return
 	}
+	defer body.Close()
 
-	log.Println(*userSubmission.File)
-	path, err := utils.GetPath("/assets/", *userSubmission.File)
-	f, err := os.Open(path)
-	if f != nil {
-		defer f.Close()
+	contentDisposition := fmt.Sprintf("attachment; filename=%s", key)
+	ctx.Header("Content-Disposition", contentDisposition)
+	if contentType := mime.TypeByExtension(filepath.Ext(key)); contentType != "" {
+		ctx.Header("Content-Type", contentType)
+	}
+
+	if _, err := io.Copy(ctx.Writer, body); err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
 	}
+}
+
+func (controller *UserSubmissionsController) DownloadURL(ctx *gin.Context) {
+	userSubmissionId, err := strconv.Atoi(ctx.Param("userSubmissionId"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	submissionId, err := strconv.Atoi(ctx.Param("submissionId"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	if !controller.mayView(ctx, submissionId, userSubmissionId) {
+		return
+	}
+
+	fileURL, err := controller.UserSubmissionsService.FileURL(ctx, ctx.Param("code"), submissionId, userSubmissionId)
+	if err != nil {
+		ctx.JSON(fileErrorCode(err), model.WebResponse{
+			Code:   fileErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   fileURL,
+	})
+}
+
+func (controller *UserSubmissionsController) FindAllAttempts(ctx *gin.Context) {
+	userSubmissionId, err := strconv.Atoi(ctx.Param("userSubmissionId"))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
 			Code:   http.StatusInternalServerError,
@@ -225,10 +322,135 @@ func (controller *UserSubmissionsController) Download(ctx *gin.Context) {
 		return
 	}
 
-	contentDisposition := fmt.Sprintf("attachment; filename=%s", *userSubmission.File)
+	submissionId, err := strconv.Atoi(ctx.Param("submissionId"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	if !controller.mayView(ctx, submissionId, userSubmissionId) {
+		return
+	}
+
+	attempts, err := controller.UserSubmissionsService.FindAllAttempts(ctx, ctx.Param("code"), submissionId, userSubmissionId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   attempts,
+	})
+}
+
+func (controller *UserSubmissionsController) DownloadAttempt(ctx *gin.Context) {
+	userSubmissionId, err := strconv.Atoi(ctx.Param("userSubmissionId"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	submissionId, err := strconv.Atoi(ctx.Param("submissionId"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	attemptId, err := strconv.Atoi(ctx.Param("attemptId"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	if !controller.mayView(ctx, submissionId, userSubmissionId) {
+		return
+	}
+
+	body, key, err := controller.UserSubmissionsService.OpenAttempt(ctx, ctx.Param("code"), submissionId, userSubmissionId, attemptId)
+	if err != nil {
+		ctx.JSON(fileErrorCode(err), model.WebResponse{
+			Code:   fileErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+	defer body.Close()
+
+	contentDisposition := fmt.Sprintf("attachment; filename=%s", key)
 	ctx.Header("Content-Disposition", contentDisposition)
+	if contentType := mime.TypeByExtension(filepath.Ext(key)); contentType != "" {
+		ctx.Header("Content-Type", contentType)
+	}
+
+	if _, err := io.Copy(ctx.Writer, body); err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+}
+
+func (controller *UserSubmissionsController) SelectGradedAttempt(ctx *gin.Context) {
+	var request model.SelectGradedAttemptRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	userSubmissionId, err := strconv.Atoi(ctx.Param("userSubmissionId"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	submissionId, err := strconv.Atoi(ctx.Param("submissionId"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
+
+	request.Id = userSubmissionId
 
-	if _, err := io.Copy(ctx.Writer, f); err != nil {
+	userSubmission, err := controller.UserSubmissionsService.SelectGradedAttempt(ctx, ctx.Param("code"), submissionId, request)
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
 			Code:   http.StatusInternalServerError,
 			Status: err.Error(),
@@ -236,4 +458,60 @@ func (controller *UserSubmissionsController) Download(ctx *gin.Context) {
 		})
 		return
 	}
+
+	ctx.JSON(http.StatusOK, model.WebResponse{
+		Code:   http.StatusOK,
+		Status: "graded attempt successfully updated",
+		Data:   userSubmission,
+	})
+}
+
+// mayView answers 403 unless the caller submitted the user submission, teaches the course or is an admin. Students may
+// read user submissions, so the policy alone lets them read the ones of other students.
+func (controller *UserSubmissionsController) mayView(ctx *gin.Context, submissionId int, userSubmissionId int) bool {
+	userSubmission, err := controller.UserSubmissionsService.FindUserSubmissionById(ctx, ctx.Param("code"), submissionId, userSubmissionId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
+			Code:   http.StatusInternalServerError,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return false
+	}
+
+	if !middleware.OwnerOrTeacher(ctx, userSubmission.UserId) {
+		ctx.JSON(http.StatusForbidden, model.WebResponse{
+			Code:   http.StatusForbidden,
+			Status: "You are not allowed to view this submission",
+			Data:   nil,
+		})
+		return false
+	}
+
+	return true
+}
+
+// fileErrorCode answers 404 when the submission has no file or its blob is gone
+func fileErrorCode(err error) int {
+	if errors.Is(err, service.ErrNoFile) || errors.Is(err, storage.ErrBlobNotFound) {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
+
+// uploadErrorCode tells the client why an upload was refused
+func uploadErrorCode(err error) int {
+	switch {
+	case errors.Is(err, storage.ErrFileTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, storage.ErrFileTypeNotAllowed), errors.Is(err, storage.ErrFileContentMismatch):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, storage.ErrArchiveTooLarge):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, service.ErrSubmissionClosed):
+		return http.StatusForbidden
+	}
+
+	return http.StatusInternalServerError
 }
@@ -4,7 +4,6 @@ import (
 	"net/http"
 	"strconv"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	"github.com/rg-km/final-project-engineering-12/backend/middleware"
 	"github.com/rg-km/final-project-engineering-12/backend/model"
@@ -34,11 +33,11 @@ func (controller *UserCourseController) Route(router *gin.Engine) *gin.Engine {
 
 	api := router.Group("/api")
 	{
-		api.POST("/usercourse", middleware.AdminHandler(controller.usercourseCreate))
-		api.GET("/usercourse/:id/:course", middleware.UserHandler(controller.getUserCourseByUserCourse))
-		api.GET("/usercourse", middleware.AdminHandler(controller.listUserCourse))
-		api.GET("/usercourse/courses", middleware.UserHandler(controller.FindAllCourseByUserId))
-		api.DELETE("/usercourse/:userid/:courseid", middleware.AdminHandler(controller.deleteUserCourse))
+		api.POST("/usercourse", middleware.Authorized(middleware.ActionCreate, middleware.ResourceEnrollment, controller.usercourseCreate))
+		api.GET("/usercourse/:id/:course", middleware.Authorized(middleware.ActionRead, middleware.ResourceEnrollment, controller.getUserCourseByUserCourse))
+		api.GET("/usercourse", middleware.Authorized(middleware.ActionList, middleware.ResourceEnrollment, controller.listUserCourse))
+		api.GET("/usercourse/courses", middleware.Authenticated(controller.FindAllCourseByUserId))
+		api.DELETE("/usercourse/:userid/:courseid", middleware.Authorized(middleware.ActionDelete, middleware.ResourceEnrollment, controller.deleteUserCourse))
 	}
 	return router
 }
@@ -77,43 +76,7 @@ func (controller *UserCourseController) getUserCourseByUserCourse(ctx *gin.Conte
 		return
 	}
 
-	response, err := controller.UserCourseService.FindByUserCourse(ctx, utils.ToString(id), utils.ToString(course))
-
-	if err != nil {
-		return
-	}
-
-	token := ctx.GetHeader("Authorization")
-
-	if token == "" {
-		ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-			Code:   401,
-			Status: "Unauthorized",
-			Data:   "Please Login First",
-		})
-		return
-	}
-
-	if ok := service.JWTAuthService().CheckToken(token); ok != nil {
-		ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-			Code:   401,
-			Status: "Unauthorized",
-			Data:   "Invalid Token",
-		})
-		return
-	}
-
-	tokenClaims := jwt.MapClaims{}
-	_, err = jwt.ParseWithClaims(token, tokenClaims, func(token *jwt.Token) (interface{}, error) {
-		return []byte("your secret api key"), nil
-	},
-	)
-
-	iduser := tokenClaims["id"].(float64)
-	role := tokenClaims["role"].(string)
-	iduserint := int(iduser)
-
-	if iduserint != id && role != "1" {
+	if !middleware.OwnerOrAdmin(ctx, id) {
 		ctx.JSON(http.StatusUnauthorized, model.WebResponse{
 			Code:   401,
 			Status: "Unauthorized",
@@ -122,6 +85,8 @@ func (controller *UserCourseController) getUserCourseByUserCourse(ctx *gin.Conte
 		return
 	}
 
+	response, err := controller.UserCourseService.FindByUserCourse(ctx, utils.ToString(id), utils.ToString(course))
+
 	if err != nil {
 		return
 	}
@@ -145,16 +110,32 @@ func (controller *UserCourseController) getUserCourseByUserCourse(ctx *gin.Conte
 }
 
 func (controller *UserCourseController) listUserCourse(ctx *gin.Context) {
-	responses, err := controller.UserCourseService.FindAll(ctx)
+	query, err := bindListQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: err.Error(),
+			Data:   nil,
+		})
+		return
+	}
 
+	responses, page, err := controller.UserCourseService.FindAll(ctx, query)
 	if err != nil {
+		ctx.JSON(listErrorCode(err), model.WebResponse{
+			Code:   listErrorCode(err),
+			Status: err.Error(),
+			Data:   nil,
+		})
 		return
 	}
 
 	ctx.IndentedJSON(http.StatusOK, model.WebResponse{
-		Code:   200,
-		Status: "Get All User Course Successfull",
-		Data:   responses,
+		Code:       200,
+		Status:     "Get All User Course Successfull",
+		NextCursor: page.NextCursor,
+		Total:      &page.Total,
+		Data:       responses,
 	})
 }
 
@@ -184,7 +165,7 @@ func (controller *UserCourseController) deleteUserCourse(ctx *gin.Context) {
 }
 
 func (controller *UserCourseController) FindAllCourseByUserId(ctx *gin.Context) {
-	idUser, exists := ctx.Get("id_user")
+	principal, exists := middleware.CurrentPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusNotFound, model.WebResponse{
 			Code:   http.StatusNotFound,
@@ -194,7 +175,7 @@ func (controller *UserCourseController) FindAllCourseByUserId(ctx *gin.Context)
 		return
 	}
 
-	id := int(idUser.(float64))
+	id := principal.Id
 	responses, err := controller.UserCourseService.FindAllCourseByUserId(ctx, id)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, model.WebResponse{
@@ -0,0 +1 @@
+CREATE DATABASE teenager_test OWNER teenager;
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// Certificates is issued once a student completed a course, StudentName and CourseName are the names printed on it
+// and CodeCourse is only filled when it is looked up by its code
+type Certificates struct {
+	Id          int
+	UserId      int
+	CourseId    int
+	CodeCourse  string
+	Code        string
+	StudentName string
+	CourseName  string
+	Score       *float64
+	IssuedAt    time.Time
+}
@@ -0,0 +1,28 @@
+package entity
+
+const (
+	CurriculumArticle    = "article"
+	CurriculumSubmission = "submission"
+	CurriculumQuiz       = "quiz"
+)
+
+type CourseSections struct {
+	Id       int
+	CourseId int
+	Name     string
+	Position int
+}
+
+// CurriculumItems places a module of the Type table in the curriculum, SectionId is nil for the items before the
+// first section and Position orders the items of a section. Name and Estimate, the minutes an article takes to read,
+// are read from the module.
+type CurriculumItems struct {
+	Id        int
+	CourseId  int
+	SectionId *int
+	Type      string
+	ItemId    int
+	Position  int
+	Name      string
+	Estimate  int
+}
@@ -0,0 +1,12 @@
+package entity
+
+// EmailChanges is an email a user asked to change to, it replaces the email of the user once it is confirmed
+type EmailChanges struct {
+	Id        int
+	UserId    int
+	Email     string
+	TokenHash string
+	UsedAt    *int64
+	ExpiredAt int64
+	CreatedAt int64
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// Statuses of the emails in the outbox
+const (
+	EmailPending = "pending"
+	EmailSent    = "sent"
+	EmailFailed  = "failed"
+)
+
+type EmailOutbox struct {
+	Id            int
+	Recipient     string
+	Subject       string
+	Body          string
+	Status        string
+	Attempts      int
+	NextAttemptAt int64
+	LastError     *string
+	CreatedAt     time.Time
+	SentAt        *time.Time
+}
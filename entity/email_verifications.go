@@ -5,4 +5,5 @@ type EmailVerification struct {
 	Email     string
 	Signature string
 	Expired   int
+	SentAt    int
 }
@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+// Ways students can enroll themselves in a course
+const (
+	EnrollmentClosed   = "closed"
+	EnrollmentOpen     = "open"
+	EnrollmentKey      = "key"
+	EnrollmentApproval = "approval"
+)
+
+// Statuses of an enrollment request
+const (
+	EnrollmentPending    = "pending"
+	EnrollmentWaitlisted = "waitlisted"
+)
+
+// EnrollmentSettings is how students enroll in a course, Capacity is nil for no limit and the dates are nil when
+// enrollment is not limited in time
+type EnrollmentSettings struct {
+	CourseId int
+	Mode     string
+	Key      *string
+	Capacity *int
+	StartsAt *time.Time
+	EndsAt   *time.Time
+}
+
+// EnrollmentRequests is a student waiting to be enrolled, Name and Username are those of the student
+type EnrollmentRequests struct {
+	Id           int
+	UserId       int
+	CourseId     int
+	Status       string
+	CreatedAt    time.Time
+	WaitlistedAt *time.Time
+	Name         string
+	Username     string
+}
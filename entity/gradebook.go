@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// GradeCategories groups module submissions of a course, Weight is the percent of the final score it counts for
+type GradeCategories struct {
+	Id         int
+	CourseId   int
+	Name       string
+	Weight     int
+	DropLowest int
+}
+
+type GradeBands struct {
+	CourseId int
+	Letter   string
+	MinScore int
+}
+
+// GradebookGrades is the grade of a student for a module submission with what the late penalty needs
+type GradebookGrades struct {
+	UserId             int
+	ModuleSubmissionId int
+	Grade              *int
+	SubmittedAt        *time.Time
+	ExtendedDeadline   *time.Time
+}
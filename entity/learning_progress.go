@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// LearningProgress is what a user did with a curriculum item, Opens counts the times it was opened and VisitedAt is
+// the last time it was opened or completed
+type LearningProgress struct {
+	UserId      int
+	CourseId    int
+	Type        string
+	ItemId      int
+	Opens       int
+	VisitedAt   time.Time
+	CompletedAt *time.Time
+}
@@ -2,15 +2,45 @@ package entity
 
 import "time"
 
+const (
+	LatePolicyAccept    = "accept"
+	LatePolicyHardClose = "hard_close"
+	LatePolicyGrace     = "grace"
+	LatePolicyPenalty   = "penalty"
+)
+
 type ModuleSubmissions struct {
 	Id          int
 	CourseId    int
 	Name        string
 	Description string
 	Deadline    time.Time
+	// AllowedTypes is a comma separated list of storage.FileTypes names
+	AllowedTypes string
+	MaxSize      int64
+	LatePolicy   string
+	// GracePeriod is in minutes and PenaltyPerDay in percent of the grade
+	GracePeriod   int
+	PenaltyPerDay int
+	// CategoryId is the grade category the module submission counts in, nil when it has none
+	CategoryId *int
 }
 
 type NextPreviousModuleSubmissions struct {
 	Id         int
 	CodeCourse string
 }
+
+type SubmissionExtensions struct {
+	ModuleSubmissionId int
+	UserId             int
+	Deadline           time.Time
+	CreatedAt          time.Time
+}
+
+type StudentExtensions struct {
+	UserId    int
+	UserName  string
+	Deadline  time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,10 @@
+package entity
+
+type PasswordResets struct {
+	Id        int
+	UserId    int
+	TokenHash string
+	UsedAt    *int64
+	ExpiredAt int64
+	CreatedAt int64
+}
@@ -0,0 +1,88 @@
+package entity
+
+import "time"
+
+const (
+	QuestionMultipleChoice = "multiple_choice"
+	QuestionMultipleSelect = "multiple_select"
+	QuestionTrueFalse      = "true_false"
+	QuestionShortAnswer    = "short_answer"
+	QuestionNumeric        = "numeric"
+)
+
+// Quizzes is the auto-graded module of a course, TimeLimit is in minutes and a zero TimeLimit or MaxAttempts has no limit
+type Quizzes struct {
+	Id               int
+	CourseId         int
+	Name             string
+	Description      string
+	TimeLimit        int
+	MaxAttempts      int
+	ShuffleQuestions bool
+}
+
+// QuizQuestions are answered with choices, except numeric questions which are right within Tolerance of NumericAnswer
+type QuizQuestions struct {
+	Id            int
+	QuizId        int
+	Type          string
+	Prompt        string
+	Points        int
+	Position      int
+	NumericAnswer *float64
+	Tolerance     float64
+}
+
+// QuizChoices are the options of a question, for a short answer question they are the accepted answers
+type QuizChoices struct {
+	Id         int
+	QuestionId int
+	Content    string
+	Correct    bool
+	Position   int
+}
+
+// QuizAttempts is a try of a student, QuestionOrder lists the question ids in the order they were shown
+type QuizAttempts struct {
+	Id            int
+	QuizId        int
+	UserId        int
+	QuestionOrder string
+	StartedAt     time.Time
+	Deadline      *time.Time
+	SubmittedAt   *time.Time
+	Score         *int
+	MaxScore      int
+}
+
+// QuizResponses is the graded answer to a question, choices are stored as their ids separated by commas
+type QuizResponses struct {
+	AttemptId  int
+	QuestionId int
+	Answer     string
+	Correct    bool
+	Points     int
+}
+
+// UserQuizzes is the grade record of a student for a quiz, Grade is the percent of the best attempt
+type UserQuizzes struct {
+	Id              int
+	UserId          int
+	QuizId          int
+	Grade           *int
+	GradedAttemptId *int
+}
+
+type UserQuizResults struct {
+	UserId          int
+	UserName        string
+	UserUsername    string
+	Grade           *int
+	GradedAttemptId *int
+	Attempts        int
+}
+
+type NextPreviousQuizzes struct {
+	Id         int
+	CodeCourse string
+}
@@ -0,0 +1,17 @@
+package entity
+
+type RefreshTokenFamilies struct {
+	Id        string
+	UserId    int
+	RevokedAt *int64
+	CreatedAt int64
+}
+
+type RefreshTokens struct {
+	Id        int
+	FamilyId  string
+	TokenHash string
+	UsedAt    *int64
+	ExpiredAt int64
+	CreatedAt int64
+}
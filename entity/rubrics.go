@@ -0,0 +1,36 @@
+package entity
+
+type RubricCriteria struct {
+	Id                 int
+	ModuleSubmissionId int
+	Name               string
+	Description        string
+	Position           int
+}
+
+type RubricLevels struct {
+	Id          int
+	CriterionId int
+	Name        string
+	Description string
+	Points      int
+}
+
+type RubricScores struct {
+	UserSubmissionId int
+	CriterionId      int
+	LevelId          int
+	Points           int
+	Comment          string
+}
+
+// CriterionScores is the score of a criterion together with the names a student reads
+type CriterionScores struct {
+	CriterionId   int
+	CriterionName string
+	LevelId       int
+	LevelName     string
+	Points        int
+	MaxPoints     int
+	Comment       string
+}
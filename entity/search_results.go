@@ -0,0 +1,10 @@
+package entity
+
+type SearchResults struct {
+	Type       string
+	Id         int
+	CodeCourse string
+	Title      string
+	Snippet    string
+	Rank       float64
+}
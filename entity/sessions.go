@@ -0,0 +1,10 @@
+package entity
+
+type Sessions struct {
+	Id        string
+	UserId    int
+	IssuedAt  int64
+	ExpiredAt int64
+	ClientIp  string
+	UserAgent string
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+type SubmissionAttempts struct {
+	Id               int
+	UserSubmissionId int
+	File             string
+	// Hash and Size are unknown for the files submitted before attempts were recorded
+	Hash      *string
+	Size      *int64
+	Late      bool
+	CreatedAt time.Time
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+type TeachingAssignments struct {
+	UserId    int
+	CourseId  int
+	CreatedAt time.Time
+}
+
+type CourseTeachers struct {
+	IdUser       int
+	UserName     string
+	UserUsername string
+	UserEmail    string
+}
@@ -0,0 +1,34 @@
+package entity
+
+// TwoFactor is the TOTP secret of a user, two-factor authentication is only enabled once ConfirmedAt is set
+type TwoFactor struct {
+	UserId      int
+	Secret      string
+	LastStep    int64
+	ConfirmedAt *int64
+	CreatedAt   int64
+}
+
+type RecoveryCodes struct {
+	Id        int
+	UserId    int
+	CodeHash  string
+	UsedAt    *int64
+	CreatedAt int64
+}
+
+type TwoFactorChallenges struct {
+	Id        int
+	UserId    int
+	TokenHash string
+	Attempts  int
+	UsedAt    *int64
+	ExpiredAt int64
+	CreatedAt int64
+}
+
+type TwoFactorPolicies struct {
+	Role      int
+	Required  bool
+	UpdatedAt int64
+}
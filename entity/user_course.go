@@ -1,5 +1,7 @@
 package entity
 
+import "time"
+
 type UserCourse struct {
 	UserId   int
 	CourseId int
@@ -21,11 +23,19 @@ type UserTeacherCourse struct {
 
 type StudentSubmissions struct {
 	IdModuleSubmission   int
+	IdUserSubmission     int
 	CourseName           string
 	CodeCourse           string
 	ModuleSubmissionName string
 	Grade                *int
 	File                 *string
+	SubmittedAt          *time.Time
+	Deadline             time.Time
+	ExtendedDeadline     *time.Time
+	LatePolicy           string
+	GracePeriod          int
+	PenaltyPerDay        int
+	Feedback             *string
 }
 
 type TeacherSubmissions struct {
@@ -34,4 +44,10 @@ type TeacherSubmissions struct {
 	ModuleSubmissionName string
 	Grade                *int
 	File                 *string
+	SubmittedAt          *time.Time
+	Deadline             time.Time
+	ExtendedDeadline     *time.Time
+	LatePolicy           string
+	GracePeriod          int
+	PenaltyPerDay        int
 }
@@ -6,4 +6,6 @@ type UserSubmissions struct {
 	ModuleSubmissionId int
 	File               *string
 	Grade              *int
+	GradedAttemptId    *int
+	Feedback           *string
 }
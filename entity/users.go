@@ -2,6 +2,12 @@ package entity
 
 import "time"
 
+const (
+	RoleAdmin   = 1
+	RoleStudent = 2
+	RoleTeacher = 3
+)
+
 type Users struct {
 	Id                int
 	Name              string
@@ -16,7 +22,7 @@ type Users struct {
 	Birthdate         string
 	Image             *string
 	Description       *string
-	EmailVerification time.Time
+	EmailVerification *time.Time
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 }
@@ -0,0 +1,46 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+type fileTransport struct {
+	Directory string
+}
+
+// NewFileTransport writes every email to its own .eml file in directory instead of sending it
+func NewFileTransport(directory string) (Transport, error) {
+	err := os.MkdirAll(directory, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileTransport{
+		Directory: directory,
+	}, nil
+}
+
+func (transport *fileTransport) Send(ctx context.Context, message Message) error {
+	suffix, err := utils.RandomToken(4)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	name := filepath.Join(transport.Directory, fmt.Sprintf("%v-%v.eml", now.UnixNano(), suffix))
+	content := fmt.Sprintf(
+		"Date: %v\r\nTo: %v\r\nSubject: %v\r\nContent-Type: text/html; charset=UTF-8\r\n\r\n%v",
+		now.Format(time.RFC1123Z),
+		message.To,
+		message.Subject,
+		message.Body,
+	)
+
+	return os.WriteFile(name, []byte(content), 0644)
+}
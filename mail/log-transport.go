@@ -0,0 +1,20 @@
+package mail
+
+import (
+	"context"
+	"log"
+)
+
+type logTransport struct {
+}
+
+// NewLogTransport writes the emails to the standard logger instead of sending them
+func NewLogTransport() Transport {
+	return &logTransport{}
+}
+
+func (transport *logTransport) Send(ctx context.Context, message Message) error {
+	log.Printf("mail to %v\nSubject: %v\n\n%v\n", message.To, message.Subject, message.Body)
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package mail
+
+import (
+	"context"
+
+	"gopkg.in/gomail.v2"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+type smtpTransport struct {
+	Config SMTPConfig
+}
+
+func NewSMTPTransport(config SMTPConfig) Transport {
+	return &smtpTransport{
+		Config: config,
+	}
+}
+
+func (transport *smtpTransport) Send(ctx context.Context, message Message) error {
+	mailer := gomail.NewMessage()
+	mailer.SetHeader("From", transport.Config.From)
+	mailer.SetHeader("To", message.To)
+	mailer.SetHeader("Subject", message.Subject)
+	mailer.SetBody("text/html", message.Body)
+
+	dialer := gomail.NewDialer(
+		transport.Config.Host,
+		transport.Config.Port,
+		transport.Config.Username,
+		transport.Config.Password,
+	)
+
+	return dialer.DialAndSend(mailer)
+}
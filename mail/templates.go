@@ -0,0 +1,116 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+//go:embed templates/*.html
+var files embed.FS
+
+var ErrTemplateNotFound = errors.New("email template not found")
+
+// Templates renders emails from the templates/<name>.<language>.html files. Every file defines a subject and a body
+// template.
+type Templates struct {
+	DefaultLanguage string
+	templates       map[string]map[string]*template.Template
+}
+
+// NewTemplates parses the embedded templates, emails fall back to defaultLanguage when there is no template in the
+// language asked for so every template must have a variant in it
+func NewTemplates(defaultLanguage string) (*Templates, error) {
+	names, err := fs.Glob(files, "templates/*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	templates := map[string]map[string]*template.Template{}
+	for _, name := range names {
+		parts := strings.Split(strings.TrimSuffix(path.Base(name), ".html"), ".")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("email template %v must be named <name>.<language>.html", name)
+		}
+
+		parsed, err := template.ParseFS(files, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, block := range []string{"subject", "body"} {
+			if parsed.Lookup(block) == nil {
+				return nil, fmt.Errorf("email template %v does not define %v", name, block)
+			}
+		}
+
+		if templates[parts[0]] == nil {
+			templates[parts[0]] = map[string]*template.Template{}
+		}
+		templates[parts[0]][parts[1]] = parsed
+	}
+
+	for name, variants := range templates {
+		if _, ok := variants[defaultLanguage]; !ok {
+			return nil, fmt.Errorf("email template %v has no %v variant", name, defaultLanguage)
+		}
+	}
+
+	return &Templates{
+		DefaultLanguage: defaultLanguage,
+		templates:       templates,
+	}, nil
+}
+
+// Render renders the subject and the body of the template in the first of languages it has, languages is a language
+// or the value of an Accept-Language header
+func (templates *Templates) Render(name string, languages string, data interface{}) (string, string, error) {
+	variants, ok := templates.templates[name]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %v", ErrTemplateNotFound, name)
+	}
+
+	var parsed *template.Template
+	for _, language := range append(parseLanguages(languages), templates.DefaultLanguage) {
+		if variant, ok := variants[language]; ok {
+			parsed = variant
+			break
+		}
+	}
+	if parsed == nil {
+		return "", "", fmt.Errorf("%w: %v.%v", ErrTemplateNotFound, name, templates.DefaultLanguage)
+	}
+
+	var subject, body bytes.Buffer
+	err := parsed.ExecuteTemplate(&subject, "subject", data)
+	if err != nil {
+		return "", "", err
+	}
+	err = parsed.ExecuteTemplate(&body, "body", data)
+	if err != nil {
+		return "", "", err
+	}
+
+	// The subject is no HTML, only the body needs its data escaped
+	return strings.TrimSpace(html.UnescapeString(subject.String())), strings.TrimSpace(body.String()), nil
+}
+
+// parseLanguages returns the primary language of every tag of an Accept-Language value in the order they are listed,
+// e.g. id and en for "id-ID,id;q=0.9,en;q=0.8"
+func parseLanguages(value string) []string {
+	var languages []string
+	for _, tag := range strings.Split(value, ",") {
+		tag = strings.TrimSpace(strings.SplitN(tag, ";", 2)[0])
+		tag = strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+		if tag != "" && tag != "*" {
+			languages = append(languages, tag)
+		}
+	}
+
+	return languages
+}
@@ -0,0 +1,71 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+)
+
+const (
+	TransportSMTP = "smtp"
+	TransportLog  = "log"
+	TransportFile = "file"
+)
+
+// Message is an email ready to be sent, Body is HTML
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Transport delivers emails, an error means the email was not sent and may be sent again later
+type Transport interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// New opens the transport selected by MAIL_TRANSPORT. Without it emails are sent with SMTP when MAIL_HOST is set and
+// written to the log otherwise, except in production where the emails carry tokens that must not end up in the log.
+func New(configuration config.Config) (Transport, error) {
+	transport := configuration.Get("MAIL_TRANSPORT")
+	if transport == "" {
+		switch {
+		case configuration.Get("MAIL_HOST") != "":
+			transport = TransportSMTP
+		case configuration.Get("APP_ENV") == "production":
+			return nil, errors.New("MAIL_TRANSPORT or MAIL_HOST must be set in production")
+		default:
+			transport = TransportLog
+		}
+	}
+
+	switch transport {
+	case TransportSMTP:
+		port, err := strconv.Atoi(configuration.Get("MAIL_PORT"))
+		if err != nil {
+			return nil, fmt.Errorf("MAIL_PORT must be a number: %w", err)
+		}
+
+		return NewSMTPTransport(SMTPConfig{
+			Host:     configuration.Get("MAIL_HOST"),
+			Port:     port,
+			Username: configuration.Get("MAIL_USERNAME"),
+			Password: configuration.Get("MAIL_PASSWORD"),
+			From:     configuration.Get("MAIL_FROM_ADDRESS"),
+		}), nil
+	case TransportLog:
+		return NewLogTransport(), nil
+	case TransportFile:
+		directory := configuration.Get("MAIL_FILE_PATH")
+		if directory == "" {
+			directory = "mails"
+		}
+
+		return NewFileTransport(directory)
+	default:
+		return nil, fmt.Errorf("unknown mail transport %v", transport)
+	}
+}
@@ -1,14 +1,53 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/migration"
+	"github.com/rg-km/final-project-engineering-12/backend/repository"
 	"github.com/rg-km/final-project-engineering-12/backend/route"
+	"github.com/rg-km/final-project-engineering-12/backend/storage"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
 )
 
 func main() {
 	configuration := config.New()
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := migrate(configuration, os.Args[2:])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "assets" {
+		err := assets(configuration, os.Args[2:])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "users" {
+		err := users(configuration, os.Args[2:])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	initialized := route.NewInitializedServer(configuration)
 	// Run
 	PORT := fmt.Sprintf(":%v", configuration.Get("APP_PORT"))
@@ -20,6 +59,169 @@ func main() {
 	}
 }
 
+// migrate runs `migrate up`, `migrate down [steps]` or `migrate status` against the configured database
+func migrate(configuration config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up|down [steps]|status")
+	}
+
+	db := config.NewDatabase(configuration)
+	defer db.Close()
+
+	migrator, err := migration.NewMigrator(db, configuration.Get("DB_CONNECTION"))
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		migrations, err := migrator.Up(ctx)
+		for _, migration := range migrations {
+			fmt.Printf("migrated   %04d_%v\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(migrations) == 0 {
+			fmt.Println("nothing to migrate")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("steps must be a positive number")
+			}
+		}
+
+		migrations, err := migrator.Down(ctx, steps)
+		for _, migration := range migrations {
+			fmt.Printf("rolled back %04d_%v\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(migrations) == 0 {
+			fmt.Println("nothing to roll back")
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-40v %v\n", status.Version, status.Name, appliedAt)
+		}
+	default:
+		return fmt.Errorf("usage: migrate up|down [steps]|status")
+	}
+
+	return nil
+}
+
+// assets runs `assets migrate [directory]`, moving the files uploaded before the blob store existed
+// (./assets by default) into the configured store and pointing their submissions to the new keys
+func assets(configuration config.Config, args []string) error {
+	if len(args) == 0 || args[0] != "migrate" {
+		return fmt.Errorf("usage: assets migrate [directory]")
+	}
+	directory := "assets"
+	if len(args) > 1 {
+		directory = args[1]
+	}
+
+	db := config.NewDatabase(configuration)
+	defer db.Close()
+	repository.UseDriver(configuration.Get("DB_CONNECTION"))
+
+	store, err := storage.New(configuration)
+	if err != nil {
+		return err
+	}
+
+	userSubmissionRepository := repository.NewUserSubmissionsRepository()
+	submissionAttemptRepository := repository.NewSubmissionAttemptRepository()
+	ctx := context.Background()
+	imported, err := storage.Import(ctx, store, directory, func(imported storage.Imported) (err error) {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil {
+				_ = tx.Rollback()
+				return
+			}
+			err = tx.Commit()
+		}()
+
+		_, err = userSubmissionRepository.ReplaceFile(ctx, tx, imported.Name, imported.Key)
+		if err != nil {
+			return err
+		}
+
+		hash := strings.TrimSuffix(imported.Key, filepath.Ext(imported.Key))
+		_, err = submissionAttemptRepository.ReplaceFile(ctx, tx, imported.Name, imported.Key, hash, imported.Size)
+		return err
+	})
+	for _, file := range imported {
+		fmt.Printf("moved %v -> %v (%v bytes)\n", file.Name, file.Key, file.Size)
+	}
+	if err != nil {
+		return err
+	}
+	if len(imported) == 0 {
+		fmt.Println("nothing to move")
+	}
+
+	return nil
+}
+
+// users runs `users role <email> <role>`. Everyone registers as a student, so this is how the first admin gets its
+// role, the admins give the others theirs through the API.
+func users(configuration config.Config, args []string) error {
+	if len(args) != 3 || args[0] != "role" {
+		return fmt.Errorf("usage: users role <email> <role>")
+	}
+	role, err := strconv.Atoi(args[2])
+	if err != nil || role < entity.RoleAdmin || role > entity.RoleTeacher {
+		return fmt.Errorf("role must be one of 1 2 3")
+	}
+
+	db := config.NewDatabase(configuration)
+	defer db.Close()
+	repository.UseDriver(configuration.Get("DB_CONNECTION"))
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	ctx := context.Background()
+	userRepository := repository.NewUserRepository()
+	user, err := userRepository.FindByEmail(ctx, tx, args[1])
+	if err != nil {
+		return err
+	}
+	if user.Id == 0 {
+		return fmt.Errorf("no user has the email %v", args[1])
+	}
+
+	_, err = userRepository.UpdateRole(ctx, tx, user.Id, role)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%v is now role %v\n", user.Email, role)
+
+	return nil
+}
+
 func teenager(port string) {
 	fmt.Print(`
 ┏━━━━┓
@@ -1,114 +1,138 @@
 package middleware
 
 import (
+	"context"
+	"log"
 	"net/http"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
 	"github.com/rg-km/final-project-engineering-12/backend/model"
 	"github.com/rg-km/final-project-engineering-12/backend/service"
 )
 
-func UserHandler(handler func(ctx *gin.Context)) gin.HandlerFunc {
-	return func(ctx *gin.Context) {
-		token := ctx.GetHeader("Authorization")
-		if token == "" {
-			ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-				Code:   401,
-				Status: "Unauthorized",
-			})
-			return
-		}
+const principalKey = "principal"
 
-		err := service.JWTAuthService().CheckToken(token)
+// CourseScope tells whether a user teaches a course, it backs rules with ScopeOwnCourse
+type CourseScope interface {
+	Teaches(ctx context.Context, userId int, codeCourse string) (bool, error)
+}
 
-		if err != nil {
-			ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-				Code:   401,
-				Status: "Unauthorized",
-				Data:   "Please Login First",
-			})
-			return
-		}
+var courseScope CourseScope
 
-		tokenClaims := jwt.MapClaims{}
-		tkn, err := jwt.ParseWithClaims(token, tokenClaims, func(token *jwt.Token) (interface{}, error) {
-			return []byte("your secret api key"), nil
-		},
-		)
-		if err != nil {
-			ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-				Code:   401,
-				Status: "Cannot parse token",
-			})
-			return
-		}
+// UseCourseScope sets the lookup Authorized uses for rules limited to the caller's own courses
+func UseCourseScope(scope CourseScope) {
+	courseScope = scope
+}
 
-		if !tkn.Valid {
-			ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-				Code:   401,
-				Status: "Invalid token",
-			})
+// Authenticated only lets the request through when it carries a token of a live session.
+// The caller is stored in the context and can be read with CurrentPrincipal.
+func Authenticated(handler func(ctx *gin.Context)) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !authenticate(ctx) {
 			return
 		}
 
-		ctx.Set("id_user", tokenClaims["id"])
 		handler(ctx)
 	}
 }
 
-func AdminHandler(handler func(ctx *gin.Context)) gin.HandlerFunc {
+// Authorized authenticates the request and then checks the Policy for the caller's role
+func Authorized(action Action, resource Resource, handler func(ctx *gin.Context)) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		token := ctx.GetHeader("Authorization")
-		if token == "" {
-			ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-				Code:   401,
-				Status: "Token is required",
-			})
+		if !authenticate(ctx) {
 			return
 		}
 
-		err := service.JWTAuthService().CheckToken(token)
-
-		if err != nil {
-			ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-				Code:   401,
-				Status: "Unauthorized",
-				Data:   "Please Login First",
+		principal, _ := CurrentPrincipal(ctx)
+		effect, scope := Evaluate(principal.Role, action, resource)
+		if effect != Allow || (scope == ScopeOwnCourse && !teaches(ctx, principal)) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, model.WebResponse{
+				Code:   http.StatusForbidden,
+				Status: "Forbidden",
+				Data:   "You are not allowed to " + string(action) + " " + string(resource),
 			})
 			return
 		}
 
-		tokenClaims := jwt.MapClaims{}
-		tkn, err := jwt.ParseWithClaims(token, tokenClaims, func(token *jwt.Token) (interface{}, error) {
-			return []byte("your secret api key"), nil
-		},
-		)
-		if err != nil {
-			ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-				Code:   401,
-				Status: "Cannot parse token",
-			})
-			return
-		}
+		handler(ctx)
+	}
+}
 
-		if !tkn.Valid {
-			ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-				Code:   401,
-				Status: "Session token is invalid",
-			})
-			return
-		}
+// CurrentPrincipal returns the caller set by Authenticated or Authorized
+func CurrentPrincipal(ctx *gin.Context) (model.Principal, bool) {
+	value, exists := ctx.Get(principalKey)
+	if !exists {
+		return model.Principal{}, false
+	}
 
-		if tokenClaims["role"] != "1" {
-			ctx.JSON(http.StatusUnauthorized, model.WebResponse{
-				Code:   401,
-				Status: "You are not admin",
-			})
-			return
-		}
+	principal, ok := value.(model.Principal)
+	return principal, ok
+}
 
-		ctx.Set("id_user", tokenClaims["id"])
-		handler(ctx)
+// OwnerOrAdmin reports whether the caller is the user identified by ownerId or an admin
+func OwnerOrAdmin(ctx *gin.Context, ownerId int) bool {
+	principal, ok := CurrentPrincipal(ctx)
+	if !ok {
+		return false
 	}
+
+	return principal.Id == ownerId || IsAdmin(ctx)
+}
+
+// OwnerOrTeacher reports whether the caller is the user identified by ownerId, an admin or a teacher of the course in
+// the :code path parameter
+func OwnerOrTeacher(ctx *gin.Context, ownerId int) bool {
+	principal, ok := CurrentPrincipal(ctx)
+	if !ok {
+		return false
+	}
+
+	return OwnerOrAdmin(ctx, ownerId) || principal.Role == entity.RoleTeacher && teaches(ctx, principal)
+}
+
+// IsAdmin reports whether the caller is a platform admin
+func IsAdmin(ctx *gin.Context) bool {
+	principal, ok := CurrentPrincipal(ctx)
+	return ok && principal.Role == entity.RoleAdmin
+}
+
+func teaches(ctx *gin.Context, principal model.Principal) bool {
+	codeCourse := ctx.Param("code")
+	if courseScope == nil || codeCourse == "" {
+		return false
+	}
+
+	ok, err := courseScope.Teaches(ctx.Request.Context(), principal.Id, codeCourse)
+	if err != nil {
+		log.Println(err)
+		return false
+	}
+
+	return ok
+}
+
+func authenticate(ctx *gin.Context) bool {
+	token := ctx.GetHeader("Authorization")
+	if token == "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, model.WebResponse{
+			Code:   http.StatusUnauthorized,
+			Status: "Unauthorized",
+			Data:   "Please Login First",
+		})
+		return false
+	}
+
+	principal, err := service.JWTAuthService().Authenticate(ctx.Request.Context(), token)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, model.WebResponse{
+			Code:   http.StatusUnauthorized,
+			Status: "Unauthorized",
+			Data:   "Please Login First",
+		})
+		return false
+	}
+
+	ctx.Set(principalKey, principal)
+	return true
 }
@@ -0,0 +1,156 @@
+package middleware
+
+import "github.com/rg-km/final-project-engineering-12/backend/entity"
+
+type Action string
+
+const (
+	ActionAny    Action = "*"
+	ActionRead   Action = "read"
+	ActionList   Action = "list"
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+	ActionGrade  Action = "grade"
+)
+
+type Resource string
+
+const (
+	ResourceAny            Resource = "*"
+	ResourceUser           Resource = "user"
+	ResourceUserRole       Resource = "user_role"
+	ResourceUserImport     Resource = "user_import"
+	ResourceCourse         Resource = "course"
+	ResourceRoster         Resource = "roster"
+	ResourceArticle        Resource = "article"
+	ResourceSubmission     Resource = "submission"
+	ResourceUserSubmission Resource = "user_submission"
+	ResourceEnrollment     Resource = "enrollment"
+	ResourceQuestion       Resource = "question"
+	ResourceAnswer         Resource = "answer"
+	ResourceTeacher        Resource = "teacher"
+	ResourceGradebook      Resource = "gradebook"
+	ResourceQuiz           Resource = "quiz"
+	ResourceQuizAttempt    Resource = "quiz_attempt"
+	ResourceCurriculum     Resource = "curriculum"
+	ResourceProgress       Resource = "progress"
+	ResourceCertificate    Resource = "certificate"
+	ResourceSelfEnrollment Resource = "self_enrollment"
+	ResourceEnrollSettings Resource = "enrollment_settings"
+	ResourceEnrollRequest  Resource = "enrollment_request"
+	ResourceTwoFactor      Resource = "two_factor"
+)
+
+type Effect int
+
+const (
+	Deny Effect = iota
+	Allow
+)
+
+// Scope narrows an Allow rule to a subset of the resources it names
+type Scope int
+
+const (
+	// ScopeAll applies the rule to every course
+	ScopeAll Scope = iota
+	// ScopeOwnCourse only applies the rule to the course in the :code path parameter, and only when the caller teaches it
+	ScopeOwnCourse
+)
+
+type Rule struct {
+	Role     int
+	Action   Action
+	Resource Resource
+	Effect   Effect
+	Scope    Scope
+}
+
+// Policy decides which role may perform which action on which resource.
+// Anything that is not listed is denied, and a matching Deny rule always wins over an Allow rule.
+// Ownership (e.g. a student reading their own profile) is checked by the handler with OwnerOrAdmin, or with
+// OwnerOrTeacher where the teachers of the course may read it too (e.g. user submissions),
+// teachers are limited to the courses they teach through ScopeOwnCourse.
+var Policy = []Rule{
+	{Role: entity.RoleAdmin, Action: ActionAny, Resource: ResourceAny, Effect: Allow},
+
+	{Role: entity.RoleStudent, Action: ActionRead, Resource: ResourceUser, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionUpdate, Resource: ResourceUser, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionAny, Resource: ResourceUserRole, Effect: Deny},
+	{Role: entity.RoleStudent, Action: ActionRead, Resource: ResourceCourse, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionRead, Resource: ResourceArticle, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionRead, Resource: ResourceSubmission, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionRead, Resource: ResourceUserSubmission, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionCreate, Resource: ResourceUserSubmission, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionRead, Resource: ResourceEnrollment, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionRead, Resource: ResourceQuiz, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionRead, Resource: ResourceQuizAttempt, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionCreate, Resource: ResourceQuizAttempt, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionUpdate, Resource: ResourceQuizAttempt, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionRead, Resource: ResourceCurriculum, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionRead, Resource: ResourceProgress, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionUpdate, Resource: ResourceProgress, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionRead, Resource: ResourceCertificate, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionCreate, Resource: ResourceCertificate, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionRead, Resource: ResourceSelfEnrollment, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionCreate, Resource: ResourceSelfEnrollment, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionDelete, Resource: ResourceSelfEnrollment, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionAny, Resource: ResourceQuestion, Effect: Allow},
+	{Role: entity.RoleStudent, Action: ActionAny, Resource: ResourceAnswer, Effect: Allow},
+
+	{Role: entity.RoleTeacher, Action: ActionRead, Resource: ResourceUser, Effect: Allow},
+	{Role: entity.RoleTeacher, Action: ActionUpdate, Resource: ResourceUser, Effect: Allow},
+	{Role: entity.RoleTeacher, Action: ActionAny, Resource: ResourceUserRole, Effect: Deny},
+	{Role: entity.RoleTeacher, Action: ActionRead, Resource: ResourceCourse, Effect: Allow},
+	{Role: entity.RoleTeacher, Action: ActionAny, Resource: ResourceArticle, Effect: Allow, Scope: ScopeOwnCourse},
+	{Role: entity.RoleTeacher, Action: ActionAny, Resource: ResourceSubmission, Effect: Allow, Scope: ScopeOwnCourse},
+	{Role: entity.RoleTeacher, Action: ActionRead, Resource: ResourceUserSubmission, Effect: Allow, Scope: ScopeOwnCourse},
+	{Role: entity.RoleTeacher, Action: ActionList, Resource: ResourceUserSubmission, Effect: Allow, Scope: ScopeOwnCourse},
+	{Role: entity.RoleTeacher, Action: ActionGrade, Resource: ResourceUserSubmission, Effect: Allow, Scope: ScopeOwnCourse},
+	{Role: entity.RoleTeacher, Action: ActionList, Resource: ResourceRoster, Effect: Allow, Scope: ScopeOwnCourse},
+	{Role: entity.RoleTeacher, Action: ActionList, Resource: ResourceTeacher, Effect: Allow, Scope: ScopeOwnCourse},
+	{Role: entity.RoleTeacher, Action: ActionAny, Resource: ResourceGradebook, Effect: Allow, Scope: ScopeOwnCourse},
+	{Role: entity.RoleTeacher, Action: ActionAny, Resource: ResourceQuiz, Effect: Allow, Scope: ScopeOwnCourse},
+	{Role: entity.RoleTeacher, Action: ActionList, Resource: ResourceQuizAttempt, Effect: Allow, Scope: ScopeOwnCourse},
+	{Role: entity.RoleTeacher, Action: ActionAny, Resource: ResourceCurriculum, Effect: Allow, Scope: ScopeOwnCourse},
+	{Role: entity.RoleTeacher, Action: ActionAny, Resource: ResourceProgress, Effect: Allow, Scope: ScopeOwnCourse},
+	{Role: entity.RoleTeacher, Action: ActionRead, Resource: ResourceSelfEnrollment, Effect: Allow},
+	{Role: entity.RoleTeacher, Action: ActionAny, Resource: ResourceEnrollSettings, Effect: Allow, Scope: ScopeOwnCourse},
+	{Role: entity.RoleTeacher, Action: ActionAny, Resource: ResourceEnrollRequest, Effect: Allow, Scope: ScopeOwnCourse},
+	{Role: entity.RoleTeacher, Action: ActionAny, Resource: ResourceQuestion, Effect: Allow},
+	{Role: entity.RoleTeacher, Action: ActionAny, Resource: ResourceAnswer, Effect: Allow},
+}
+
+// Allowed evaluates the policy for role, action and resource, ignoring rule scopes
+func Allowed(role int, action Action, resource Resource) bool {
+	effect, _ := Evaluate(role, action, resource)
+	return effect == Allow
+}
+
+// Evaluate returns the effect of the policy for role, action and resource and the scope the caller is limited to.
+// An unscoped Allow rule wins over a scoped one.
+func Evaluate(role int, action Action, resource Resource) (Effect, Scope) {
+	effect, scope := Deny, ScopeOwnCourse
+	for _, rule := range Policy {
+		if rule.Role != role {
+			continue
+		}
+		if rule.Action != ActionAny && rule.Action != action {
+			continue
+		}
+		if rule.Resource != ResourceAny && rule.Resource != resource {
+			continue
+		}
+
+		if rule.Effect == Deny {
+			return Deny, ScopeAll
+		}
+		effect = Allow
+		if rule.Scope == ScopeAll {
+			scope = ScopeAll
+		}
+	}
+
+	return effect, scope
+}
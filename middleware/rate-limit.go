@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+)
+
+// RateLimiter counts the requests of every client IP in fixed windows. The counts are kept in memory, so every
+// instance of the application counts on its own.
+type RateLimiter struct {
+	Limit  int
+	Window time.Duration
+
+	mutex   sync.Mutex
+	clients map[string]*rateWindow
+	purged  time.Time
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// NewRateLimiter lets a client make limit requests per window
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		Limit:   limit,
+		Window:  window,
+		clients: map[string]*rateWindow{},
+	}
+}
+
+// Allow counts a request of the client and returns false with the time left until the next window once the client
+// used up its requests
+func (limiter *RateLimiter) Allow(client string, now time.Time) (bool, time.Duration) {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	// Windows that are over are dropped once per window so the map does not grow with every client ever seen
+	if now.Sub(limiter.purged) >= limiter.Window {
+		for key, window := range limiter.clients {
+			if now.Sub(window.start) >= limiter.Window {
+				delete(limiter.clients, key)
+			}
+		}
+		limiter.purged = now
+	}
+
+	window, ok := limiter.clients[client]
+	if !ok || now.Sub(window.start) >= limiter.Window {
+		window = &rateWindow{start: now}
+		limiter.clients[client] = window
+	}
+	if window.count >= limiter.Limit {
+		return false, window.start.Add(limiter.Window).Sub(now)
+	}
+	window.count++
+
+	return true, 0
+}
+
+// RateLimited answers 429 with a Retry-After header once the client IP made more requests than the limiter allows
+func RateLimited(limiter *RateLimiter, handler func(ctx *gin.Context)) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		allowed, retryAfter := limiter.Allow(ctx.ClientIP(), time.Now())
+		if !allowed {
+			ctx.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, model.WebResponse{
+				Code:   http.StatusTooManyRequests,
+				Status: "Too Many Requests",
+				Data:   "Please try again later",
+			})
+			return
+		}
+
+		handler(ctx)
+	}
+}
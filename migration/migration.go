@@ -0,0 +1,275 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+// Migrations are kept per driver, named <version>_<name>.up.sql and <version>_<name>.down.sql.
+// sqlite3_fts5 holds the SQLite migrations that need the FTS5 extension, they are only loaded when it is built in.
+//
+//go:embed sqlite3/*.sql sqlite3_fts5/*.sql postgres/*.sql
+var files embed.FS
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+type Migrator interface {
+	Up(ctx context.Context) ([]Migration, error)
+	Down(ctx context.Context, steps int) ([]Migration, error)
+	Status(ctx context.Context) ([]Status, error)
+}
+
+type migrator struct {
+	DB         *sql.DB
+	Driver     string
+	Migrations []Migration
+}
+
+func NewMigrator(db *sql.DB, driver string) (Migrator, error) {
+	migrations, err := load(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return &migrator{
+		DB:         db,
+		Driver:     driver,
+		Migrations: migrations,
+	}, nil
+}
+
+// Up applies every migration that has not been applied yet, oldest first
+func (migrator *migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied, err := migrator.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrated []Migration
+	for _, migration := range migrator.Migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err := migrator.run(ctx, migration.Up, `INSERT INTO schema_migrations(version, name, applied_at) VALUES(?,?,?)`, migration.Version, migration.Name, time.Now().Unix())
+		if err != nil {
+			return migrated, fmt.Errorf("migration %04d_%v: %w", migration.Version, migration.Name, err)
+		}
+		migrated = append(migrated, migration)
+	}
+
+	return migrated, nil
+}
+
+// Down reverts the last steps applied migrations, newest first
+func (migrator *migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	applied, err := migrator.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrated []Migration
+	for i := len(migrator.Migrations) - 1; i >= 0 && len(migrated) < steps; i-- {
+		migration := migrator.Migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		err := migrator.run(ctx, migration.Down, `DELETE FROM schema_migrations WHERE version = ?`, migration.Version)
+		if err != nil {
+			return migrated, fmt.Errorf("migration %04d_%v: %w", migration.Version, migration.Name, err)
+		}
+		migrated = append(migrated, migration)
+	}
+
+	return migrated, nil
+}
+
+func (migrator *migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := migrator.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+	for _, migration := range migrator.Migrations {
+		status := Status{
+			Version: migration.Version,
+			Name:    migration.Name,
+		}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			appliedAt := time.Unix(appliedAt, 0)
+			status.AppliedAt = &appliedAt
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// run executes the migration script and records it in schema_migrations within one transaction
+func (migrator *migrator) run(ctx context.Context, script string, record string, args ...interface{}) (err error) {
+	tx, err := migrator.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	_, err = tx.ExecContext(ctx, script)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, utils.Rebind(migrator.Driver, record), args...)
+	return err
+}
+
+// applied creates schema_migrations when needed and returns the applied versions with the time they were applied
+func (migrator *migrator) applied(ctx context.Context) (map[int]int64, error) {
+	_, err := migrator.DB.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations(
+version INTEGER PRIMARY KEY,
+name VARCHAR(255) NOT NULL,
+applied_at BIGINT NOT NULL
+)`)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := migrator.DB.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			return
+		}
+	}(rows)
+
+	applied := map[int]int64{}
+	for rows.Next() {
+		var version int
+		var appliedAt int64
+		err := rows.Scan(&version, &appliedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+// directories lists where the migrations of driver are kept
+func directories(driver string) []string {
+	if driver == utils.DriverSQLite && utils.FTS5 {
+		return []string{driver, "sqlite3_fts5"}
+	}
+
+	return []string{driver}
+}
+
+func load(driver string) ([]Migration, error) {
+	type file struct {
+		directory string
+		name      string
+	}
+
+	var migrationFiles []file
+	for _, directory := range directories(driver) {
+		entries, err := fs.ReadDir(files, directory)
+		if err != nil {
+			return nil, fmt.Errorf("no migrations for driver %v", driver)
+		}
+		for _, entry := range entries {
+			migrationFiles = append(migrationFiles, file{directory: directory, name: entry.Name()})
+		}
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, migrationFile := range migrationFiles {
+		fileName := migrationFile.name
+
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(fileName, "."+direction+".sql")
+		parts := strings.SplitN(base, "_", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid migration file name %v", fileName)
+		}
+		version, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration file name %v", fileName)
+		}
+
+		content, err := files.ReadFile(path.Join(migrationFile.directory, fileName))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: parts[1]}
+			byVersion[version] = migration
+		}
+		if migration.Name != parts[1] {
+			return nil, fmt.Errorf("migration %v has more than one name", version)
+		}
+
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	var migrations []Migration
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %v needs both an up and a down file", migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
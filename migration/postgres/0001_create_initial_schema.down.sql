@@ -0,0 +1,10 @@
+DROP TABLE answers;
+DROP TABLE questions;
+DROP TABLE user_submissions;
+DROP TABLE module_submissions;
+DROP TABLE module_articles;
+DROP TABLE email_verifications;
+DROP TABLE user_course;
+DROP TABLE user_details;
+DROP TABLE users;
+DROP TABLE courses;
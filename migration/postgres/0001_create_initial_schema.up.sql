@@ -0,0 +1,93 @@
+CREATE TABLE courses(
+id SERIAL PRIMARY KEY,
+name VARCHAR(50) NOT NULL,
+code_course VARCHAR(10) NOT NULL UNIQUE,
+class VARCHAR(20) NOT NULL,
+tools TEXT,
+about TEXT,
+description TEXT,
+created_at TIMESTAMP,
+updated_at TIMESTAMP,
+is_active BOOLEAN DEFAULT TRUE
+);
+
+CREATE TABLE users(
+id SERIAL PRIMARY KEY,
+name VARCHAR(50) NOT NULL,
+username VARCHAR(10) NOT NULL,
+email VARCHAR(50) NOT NULL,
+password VARCHAR(512) NOT NULL,
+role SMALLINT NOT NULL CONSTRAINT users_role_check CHECK(role<3),
+email_verification TIMESTAMP NOT NULL,
+created_at TIMESTAMP NOT NULL,
+updated_at TIMESTAMP NOT NULL,
+UNIQUE(username, email)
+);
+
+CREATE TABLE user_details(
+id SERIAL PRIMARY KEY,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+phone VARCHAR(14),
+gender INTEGER CHECK(gender<3),
+type_of_disability INTEGER CHECK(type_of_disability<4),
+address VARCHAR(50),
+birthdate DATE,
+image VARCHAR(50),
+description VARCHAR(150)
+);
+
+CREATE TABLE user_course(
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE
+);
+
+CREATE TABLE email_verifications(
+id SERIAL PRIMARY KEY,
+email VARCHAR(50) NOT NULL UNIQUE,
+signature VARCHAR(50) NOT NULL,
+expired BIGINT NOT NULL
+);
+
+CREATE TABLE module_articles(
+id SERIAL PRIMARY KEY,
+course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+name VARCHAR(50) NOT NULL,
+content TEXT NOT NULL,
+estimate INTEGER NOT NULL
+);
+
+CREATE TABLE module_submissions(
+id SERIAL PRIMARY KEY,
+course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+name VARCHAR(50) NOT NULL,
+description VARCHAR(50),
+deadline TIMESTAMP
+);
+
+CREATE TABLE user_submissions(
+id SERIAL PRIMARY KEY,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+module_submission_id INTEGER NOT NULL REFERENCES module_submissions(id) ON DELETE CASCADE,
+file VARCHAR(50),
+grade INTEGER
+);
+
+CREATE TABLE questions(
+id SERIAL PRIMARY KEY,
+course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+title VARCHAR(50) NOT NULL,
+tags VARCHAR(50),
+description VARCHAR(100),
+created_at TIMESTAMP,
+updated_at TIMESTAMP
+);
+
+CREATE TABLE answers(
+id SERIAL PRIMARY KEY,
+question_id INTEGER NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+description VARCHAR(100) NOT NULL,
+created_at TIMESTAMP,
+updated_at TIMESTAMP
+);
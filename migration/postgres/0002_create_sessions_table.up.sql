@@ -0,0 +1,10 @@
+CREATE TABLE sessions(
+id VARCHAR(64) PRIMARY KEY,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+issued_at BIGINT NOT NULL,
+expired_at BIGINT NOT NULL,
+client_ip VARCHAR(45),
+user_agent VARCHAR(255)
+);
+CREATE INDEX sessions_user_id ON sessions(user_id);
+CREATE INDEX sessions_expired_at ON sessions(expired_at);
@@ -0,0 +1,15 @@
+CREATE TABLE refresh_token_families(
+id VARCHAR(64) PRIMARY KEY,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+revoked_at BIGINT,
+created_at BIGINT NOT NULL
+);
+CREATE TABLE refresh_tokens(
+id SERIAL PRIMARY KEY,
+family_id VARCHAR(64) NOT NULL REFERENCES refresh_token_families(id) ON DELETE CASCADE,
+token_hash VARCHAR(64) NOT NULL UNIQUE,
+used_at BIGINT,
+expired_at BIGINT NOT NULL,
+created_at BIGINT NOT NULL
+);
+CREATE INDEX refresh_tokens_family_id ON refresh_tokens(family_id);
@@ -0,0 +1,6 @@
+DROP TABLE teaching_assignments;
+
+-- Teachers can not be represented once the constraint is back to admin and student only
+DELETE FROM users WHERE role >= 3;
+ALTER TABLE users DROP CONSTRAINT users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK(role<3);
@@ -0,0 +1,10 @@
+ALTER TABLE users DROP CONSTRAINT users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK(role IN (1,2,3));
+
+CREATE TABLE teaching_assignments(
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+created_at TIMESTAMP NOT NULL,
+PRIMARY KEY(user_id, course_id)
+);
+CREATE INDEX teaching_assignments_course_id ON teaching_assignments(course_id);
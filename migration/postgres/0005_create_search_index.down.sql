@@ -0,0 +1,9 @@
+DROP INDEX answers_search;
+DROP INDEX questions_search;
+DROP INDEX module_articles_search;
+DROP INDEX courses_search;
+
+ALTER TABLE answers DROP COLUMN search;
+ALTER TABLE questions DROP COLUMN search;
+ALTER TABLE module_articles DROP COLUMN search;
+ALTER TABLE courses DROP COLUMN search;
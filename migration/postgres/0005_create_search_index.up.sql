@@ -0,0 +1,9 @@
+ALTER TABLE courses ADD COLUMN search tsvector GENERATED ALWAYS AS (to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(class, '') || ' ' || coalesce(about, '') || ' ' || coalesce(description, ''))) STORED;
+ALTER TABLE module_articles ADD COLUMN search tsvector GENERATED ALWAYS AS (to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(content, ''))) STORED;
+ALTER TABLE questions ADD COLUMN search tsvector GENERATED ALWAYS AS (to_tsvector('simple', coalesce(title, '') || ' ' || coalesce(tags, '') || ' ' || coalesce(description, ''))) STORED;
+ALTER TABLE answers ADD COLUMN search tsvector GENERATED ALWAYS AS (to_tsvector('simple', coalesce(description, ''))) STORED;
+
+CREATE INDEX courses_search ON courses USING GIN (search);
+CREATE INDEX module_articles_search ON module_articles USING GIN (search);
+CREATE INDEX questions_search ON questions USING GIN (search);
+CREATE INDEX answers_search ON answers USING GIN (search);
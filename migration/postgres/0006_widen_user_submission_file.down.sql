@@ -0,0 +1,2 @@
+UPDATE user_submissions SET file = NULL WHERE LENGTH(file) > 50;
+ALTER TABLE user_submissions ALTER COLUMN file TYPE VARCHAR(50);
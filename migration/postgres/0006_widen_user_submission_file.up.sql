@@ -0,0 +1,2 @@
+-- Files are stored under their content address, the hex SHA-256 followed by the extension
+ALTER TABLE user_submissions ALTER COLUMN file TYPE VARCHAR(255);
@@ -0,0 +1,2 @@
+ALTER TABLE user_submissions DROP COLUMN graded_attempt_id;
+DROP TABLE submission_attempts;
@@ -0,0 +1,17 @@
+CREATE TABLE submission_attempts(
+id SERIAL PRIMARY KEY,
+user_submission_id INTEGER NOT NULL REFERENCES user_submissions(id) ON DELETE CASCADE,
+file VARCHAR(255) NOT NULL,
+hash VARCHAR(64),
+size BIGINT,
+late BOOLEAN NOT NULL DEFAULT FALSE,
+created_at TIMESTAMP NOT NULL
+);
+CREATE INDEX submission_attempts_user_submission_id ON submission_attempts(user_submission_id);
+
+-- The graded attempt is picked by a teacher, NULL grades the latest attempt
+ALTER TABLE user_submissions ADD COLUMN graded_attempt_id INTEGER REFERENCES submission_attempts(id) ON DELETE SET NULL;
+
+-- Files submitted before the history existed become the first attempt, their hash and size are unknown
+INSERT INTO submission_attempts(user_submission_id, file, late, created_at)
+SELECT id, file, FALSE, CURRENT_TIMESTAMP FROM user_submissions WHERE file IS NOT NULL;
@@ -0,0 +1,5 @@
+DROP TABLE submission_extensions;
+
+ALTER TABLE module_submissions DROP COLUMN penalty_per_day;
+ALTER TABLE module_submissions DROP COLUMN grace_period;
+ALTER TABLE module_submissions DROP COLUMN late_policy;
@@ -0,0 +1,12 @@
+-- late_policy is accept, hard_close, grace or penalty. grace_period is in minutes, penalty_per_day in percent of the grade.
+ALTER TABLE module_submissions ADD COLUMN late_policy VARCHAR(20) NOT NULL DEFAULT 'accept';
+ALTER TABLE module_submissions ADD COLUMN grace_period INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE module_submissions ADD COLUMN penalty_per_day INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE submission_extensions(
+module_submission_id INTEGER NOT NULL REFERENCES module_submissions(id) ON DELETE CASCADE,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+deadline TIMESTAMP NOT NULL,
+created_at TIMESTAMP NOT NULL,
+PRIMARY KEY(module_submission_id, user_id)
+);
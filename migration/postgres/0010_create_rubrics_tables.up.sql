@@ -0,0 +1,29 @@
+CREATE TABLE rubric_criteria(
+id SERIAL PRIMARY KEY,
+module_submission_id INTEGER NOT NULL REFERENCES module_submissions(id) ON DELETE CASCADE,
+name VARCHAR(255) NOT NULL,
+description TEXT NOT NULL DEFAULT '',
+position INTEGER NOT NULL
+);
+CREATE INDEX rubric_criteria_module_submission_id ON rubric_criteria(module_submission_id);
+
+CREATE TABLE rubric_levels(
+id SERIAL PRIMARY KEY,
+criterion_id INTEGER NOT NULL REFERENCES rubric_criteria(id) ON DELETE CASCADE,
+name VARCHAR(255) NOT NULL,
+description TEXT NOT NULL DEFAULT '',
+points INTEGER NOT NULL
+);
+CREATE INDEX rubric_levels_criterion_id ON rubric_levels(criterion_id);
+
+-- One level is picked for every criterion, points are copied so the total stays when the rubric changes
+CREATE TABLE rubric_scores(
+user_submission_id INTEGER NOT NULL REFERENCES user_submissions(id) ON DELETE CASCADE,
+criterion_id INTEGER NOT NULL REFERENCES rubric_criteria(id) ON DELETE CASCADE,
+level_id INTEGER NOT NULL REFERENCES rubric_levels(id) ON DELETE CASCADE,
+points INTEGER NOT NULL,
+comment TEXT NOT NULL DEFAULT '',
+PRIMARY KEY(user_submission_id, criterion_id)
+);
+
+ALTER TABLE user_submissions ADD COLUMN feedback TEXT;
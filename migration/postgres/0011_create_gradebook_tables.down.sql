@@ -0,0 +1,5 @@
+DROP TABLE grade_bands;
+
+ALTER TABLE module_submissions DROP COLUMN category_id;
+
+DROP TABLE grade_categories;
@@ -0,0 +1,6 @@
+DROP TABLE user_quizzes;
+DROP TABLE quiz_responses;
+DROP TABLE quiz_attempts;
+DROP TABLE quiz_choices;
+DROP TABLE quiz_questions;
+DROP TABLE quizzes;
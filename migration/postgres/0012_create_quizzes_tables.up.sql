@@ -0,0 +1,67 @@
+-- time_limit is in minutes and max_attempts counts the attempts a student may start, 0 leaves them unlimited
+CREATE TABLE quizzes(
+id SERIAL PRIMARY KEY,
+course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+name VARCHAR(255) NOT NULL,
+description TEXT NOT NULL DEFAULT '',
+time_limit INTEGER NOT NULL DEFAULT 0,
+max_attempts INTEGER NOT NULL DEFAULT 0,
+shuffle_questions BOOLEAN NOT NULL DEFAULT FALSE
+);
+CREATE INDEX quizzes_course_id ON quizzes(course_id);
+
+-- numeric questions are right within tolerance of numeric_answer, the other types keep their answers in quiz_choices
+CREATE TABLE quiz_questions(
+id SERIAL PRIMARY KEY,
+quiz_id INTEGER NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+type VARCHAR(20) NOT NULL,
+prompt TEXT NOT NULL,
+points INTEGER NOT NULL,
+position INTEGER NOT NULL,
+numeric_answer DOUBLE PRECISION,
+tolerance DOUBLE PRECISION NOT NULL DEFAULT 0
+);
+CREATE INDEX quiz_questions_quiz_id ON quiz_questions(quiz_id);
+
+-- The accepted answers of a short answer question are correct choices that are never shown to students
+CREATE TABLE quiz_choices(
+id SERIAL PRIMARY KEY,
+question_id INTEGER NOT NULL REFERENCES quiz_questions(id) ON DELETE CASCADE,
+content TEXT NOT NULL,
+correct BOOLEAN NOT NULL DEFAULT FALSE,
+position INTEGER NOT NULL
+);
+CREATE INDEX quiz_choices_question_id ON quiz_choices(question_id);
+
+-- question_order keeps the order the attempt showed the questions in, deadline is NULL without a time limit
+CREATE TABLE quiz_attempts(
+id SERIAL PRIMARY KEY,
+quiz_id INTEGER NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+question_order TEXT NOT NULL,
+started_at TIMESTAMP NOT NULL,
+deadline TIMESTAMP,
+submitted_at TIMESTAMP,
+score INTEGER,
+max_score INTEGER NOT NULL
+);
+CREATE INDEX quiz_attempts_quiz_id_user_id ON quiz_attempts(quiz_id, user_id);
+
+CREATE TABLE quiz_responses(
+attempt_id INTEGER NOT NULL REFERENCES quiz_attempts(id) ON DELETE CASCADE,
+question_id INTEGER NOT NULL REFERENCES quiz_questions(id) ON DELETE CASCADE,
+answer TEXT NOT NULL,
+correct BOOLEAN NOT NULL,
+points INTEGER NOT NULL,
+PRIMARY KEY(attempt_id, question_id)
+);
+
+-- The grade record of a student for a quiz, like user_submissions: grade is the percent of the best attempt
+CREATE TABLE user_quizzes(
+id SERIAL PRIMARY KEY,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+quiz_id INTEGER NOT NULL REFERENCES quizzes(id) ON DELETE CASCADE,
+grade INTEGER,
+graded_attempt_id INTEGER REFERENCES quiz_attempts(id) ON DELETE SET NULL,
+UNIQUE(user_id, quiz_id)
+);
@@ -0,0 +1,2 @@
+DROP TABLE curriculum_items;
+DROP TABLE course_sections;
@@ -0,0 +1 @@
+DROP TABLE learning_progress;
@@ -0,0 +1,13 @@
+-- One row per user and curriculum item the user opened or marked complete. opens counts the times the item was
+-- opened and visited_at is the last time it was opened or completed.
+CREATE TABLE learning_progress(
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+item_type VARCHAR(20) NOT NULL,
+item_id INTEGER NOT NULL,
+opens INTEGER NOT NULL DEFAULT 0,
+visited_at TIMESTAMP NOT NULL,
+completed_at TIMESTAMP,
+PRIMARY KEY(user_id, item_type, item_id)
+);
+CREATE INDEX learning_progress_course_id ON learning_progress(course_id);
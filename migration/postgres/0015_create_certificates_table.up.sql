@@ -0,0 +1,13 @@
+-- One certificate per student and course. The student and course names are kept as they were when it was issued so
+-- the verification keeps showing what the PDF says.
+CREATE TABLE certificates(
+id SERIAL PRIMARY KEY,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+code VARCHAR(20) NOT NULL UNIQUE,
+student_name VARCHAR(255) NOT NULL,
+course_name VARCHAR(255) NOT NULL,
+score DOUBLE PRECISION,
+issued_at TIMESTAMP NOT NULL,
+UNIQUE(user_id, course_id)
+);
@@ -0,0 +1,2 @@
+DROP TABLE enrollment_requests;
+DROP TABLE enrollment_settings;
@@ -0,0 +1,22 @@
+-- How students can enroll themselves in a course. mode is closed, open, key or approval and capacity is the number
+-- of seats, NULL for no limit. Courses without a row are closed.
+CREATE TABLE enrollment_settings(
+course_id INTEGER PRIMARY KEY REFERENCES courses(id) ON DELETE CASCADE,
+mode VARCHAR(20) NOT NULL DEFAULT 'closed',
+enrollment_key VARCHAR(100),
+capacity INTEGER,
+starts_at TIMESTAMP,
+ends_at TIMESTAMP
+);
+-- Students waiting to be enrolled, either pending the approval of a teacher or waitlisted for a seat. The waitlist
+-- is served in waitlisted_at order.
+CREATE TABLE enrollment_requests(
+id SERIAL PRIMARY KEY,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+status VARCHAR(20) NOT NULL,
+created_at TIMESTAMP NOT NULL,
+waitlisted_at TIMESTAMP,
+UNIQUE(user_id, course_id)
+);
+CREATE INDEX enrollment_requests_course_id ON enrollment_requests(course_id);
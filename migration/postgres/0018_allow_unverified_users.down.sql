@@ -0,0 +1,5 @@
+ALTER TABLE email_verifications DROP COLUMN sent_at;
+
+-- Unverified users count as verified when they registered
+UPDATE users SET email_verification = created_at WHERE email_verification IS NULL;
+ALTER TABLE users ALTER COLUMN email_verification SET NOT NULL;
@@ -0,0 +1,5 @@
+-- Users who did not verify their email yet have no email_verification
+ALTER TABLE users ALTER COLUMN email_verification DROP NOT NULL;
+
+-- When the last verification email was sent, a unix timestamp, so it is not sent again too soon
+ALTER TABLE email_verifications ADD COLUMN sent_at BIGINT;
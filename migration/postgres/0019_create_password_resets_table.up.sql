@@ -0,0 +1,9 @@
+CREATE TABLE password_resets(
+id SERIAL PRIMARY KEY,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+token_hash VARCHAR(64) NOT NULL UNIQUE,
+used_at BIGINT,
+expired_at BIGINT NOT NULL,
+created_at BIGINT NOT NULL
+);
+CREATE INDEX password_resets_user_id ON password_resets(user_id);
@@ -0,0 +1,10 @@
+CREATE TABLE email_changes(
+id SERIAL PRIMARY KEY,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+email VARCHAR(50) NOT NULL,
+token_hash VARCHAR(64) NOT NULL UNIQUE,
+used_at BIGINT,
+expired_at BIGINT NOT NULL,
+created_at BIGINT NOT NULL
+);
+CREATE INDEX email_changes_user_id ON email_changes(user_id);
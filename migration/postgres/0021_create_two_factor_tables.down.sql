@@ -0,0 +1,4 @@
+DROP TABLE two_factor_policies;
+DROP TABLE two_factor_challenges;
+DROP TABLE recovery_codes;
+DROP TABLE two_factor;
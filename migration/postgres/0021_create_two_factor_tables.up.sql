@@ -0,0 +1,33 @@
+-- The TOTP secret of a user. confirmed_at stays NULL until the user entered a first code, last_step is the time step
+-- of the last accepted code so a code can not be used twice.
+CREATE TABLE two_factor(
+user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+secret VARCHAR(64) NOT NULL,
+last_step BIGINT NOT NULL DEFAULT 0,
+confirmed_at BIGINT,
+created_at BIGINT NOT NULL
+);
+CREATE TABLE recovery_codes(
+id SERIAL PRIMARY KEY,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+code_hash VARCHAR(64) NOT NULL,
+used_at BIGINT,
+created_at BIGINT NOT NULL
+);
+CREATE INDEX recovery_codes_user_id ON recovery_codes(user_id);
+-- A login waiting for its second factor, attempts counts the wrong codes entered for it
+CREATE TABLE two_factor_challenges(
+id SERIAL PRIMARY KEY,
+user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+token_hash VARCHAR(64) NOT NULL UNIQUE,
+attempts INTEGER NOT NULL DEFAULT 0,
+used_at BIGINT,
+expired_at BIGINT NOT NULL,
+created_at BIGINT NOT NULL
+);
+-- Roles whose users can only log in with two-factor authentication, roles without a row do not require it
+CREATE TABLE two_factor_policies(
+role INTEGER PRIMARY KEY,
+required BOOLEAN NOT NULL DEFAULT FALSE,
+updated_at BIGINT NOT NULL
+);
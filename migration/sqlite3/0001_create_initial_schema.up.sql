@@ -0,0 +1,104 @@
+CREATE TABLE courses(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+name VARCHAR(50) NOT NULL,
+code_course VARCHAR(10) NOT NULL UNIQUE,
+class VARCHAR(20) NOT NULL,
+tools TEXT,
+about TEXT,
+description TEXT,
+created_at TIMESTAMP,
+updated_at TIMESTAMP,
+is_active INTEGER(1) DEFAULT 1
+);
+
+CREATE TABLE users(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+name VARCHAR(50) NOT NULL,
+username VARCHAR(10) NOT NULL,
+email VARCHAR(50) NOT NULL,
+password VARCHAR(512) NOT NULL,
+role INTEGER(1) CHECK(role<3) NOT NULL,
+email_verification TIMESTAMP NOT NULL,
+created_at TIMESTAMP NOT NULL,
+updated_at TIMESTAMP NOT NULL,
+UNIQUE(username, email)
+);
+
+CREATE TABLE user_details(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+user_id INTEGER NOT NULL,
+phone VARCHAR(14),
+gender INTEGER CHECK(gender<3),
+type_of_disability INTEGER CHECK(type_of_disability<4),
+address VARCHAR(50),
+birthdate DATE,
+image VARCHAR(50),
+description VARCHAR(150),
+FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE user_course(
+user_id INTEGER NOT NULL,
+course_id INTEGER NOT NULL,
+FOREIGN KEY (user_id) REFERENCES users(id),
+FOREIGN KEY (course_id) REFERENCES courses(id)
+);
+
+CREATE TABLE email_verifications(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+email VARCHAR(50) NOT NULL UNIQUE,
+signature VARCHAR(50) NOT NULL,
+expired INTEGER(11) NOT NULL
+);
+
+CREATE TABLE module_articles(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+course_id INTEGER NOT NULL,
+name VARCHAR(50) NOT NULL,
+content TEXT NOT NULL,
+estimate INTEGER(11) NOT NULL,
+FOREIGN KEY (course_id) REFERENCES courses(id)
+);
+
+CREATE TABLE module_submissions(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+course_id INTEGER NOT NULL,
+name VARCHAR(50) NOT NULL,
+description VARCHAR(50),
+deadline TIMESTAMP,
+FOREIGN KEY (course_id) REFERENCES courses(id)
+);
+
+CREATE TABLE user_submissions(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+user_id INTEGER NOT NULL,
+module_submission_id INTEGER NOT NULL,
+file VARCHAR(50),
+grade INTEGER(11),
+FOREIGN KEY (user_id) REFERENCES users(id),
+FOREIGN KEY (module_submission_id) REFERENCES module_submissions(id)
+);
+
+CREATE TABLE questions(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+course_id INTEGER NOT NULL,
+user_id INTEGER NOT NULL,
+title VARCHAR(50) NOT NULL,
+tags VARCHAR(50),
+description VARCHAR(100),
+created_at TIMESTAMP,
+updated_at TIMESTAMP,
+FOREIGN KEY (course_id) REFERENCES courses(id),
+FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE answers(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+question_id INTEGER NOT NULL,
+user_id INTEGER NOT NULL,
+description VARCHAR(100) NOT NULL,
+created_at TIMESTAMP,
+updated_at TIMESTAMP,
+FOREIGN KEY (question_id) REFERENCES questions(id),
+FOREIGN KEY (user_id) REFERENCES users(id)
+);
@@ -0,0 +1,11 @@
+CREATE TABLE sessions(
+id VARCHAR(64) PRIMARY KEY,
+user_id INTEGER NOT NULL,
+issued_at INTEGER(11) NOT NULL,
+expired_at INTEGER(11) NOT NULL,
+client_ip VARCHAR(45),
+user_agent VARCHAR(255),
+FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE INDEX sessions_user_id ON sessions(user_id);
+CREATE INDEX sessions_expired_at ON sessions(expired_at);
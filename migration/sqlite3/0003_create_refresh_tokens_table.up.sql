@@ -0,0 +1,17 @@
+CREATE TABLE refresh_token_families(
+id VARCHAR(64) PRIMARY KEY,
+user_id INTEGER NOT NULL,
+revoked_at INTEGER(11),
+created_at INTEGER(11) NOT NULL,
+FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE TABLE refresh_tokens(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+family_id VARCHAR(64) NOT NULL,
+token_hash VARCHAR(64) NOT NULL UNIQUE,
+used_at INTEGER(11),
+expired_at INTEGER(11) NOT NULL,
+created_at INTEGER(11) NOT NULL,
+FOREIGN KEY (family_id) REFERENCES refresh_token_families(id)
+);
+CREATE INDEX refresh_tokens_family_id ON refresh_tokens(family_id);
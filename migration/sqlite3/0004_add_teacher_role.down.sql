@@ -0,0 +1,18 @@
+DROP TABLE teaching_assignments;
+
+-- Teachers can not be represented once the constraint is back to admin and student only
+CREATE TABLE users_old(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+name VARCHAR(50) NOT NULL,
+username VARCHAR(10) NOT NULL,
+email VARCHAR(50) NOT NULL,
+password VARCHAR(512) NOT NULL,
+role INTEGER(1) CHECK(role<3) NOT NULL,
+email_verification TIMESTAMP NOT NULL,
+created_at TIMESTAMP NOT NULL,
+updated_at TIMESTAMP NOT NULL,
+UNIQUE(username, email)
+);
+INSERT INTO users_old SELECT * FROM users WHERE role < 3;
+DROP TABLE users;
+ALTER TABLE users_old RENAME TO users;
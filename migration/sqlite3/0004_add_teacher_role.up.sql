@@ -0,0 +1,25 @@
+CREATE TABLE users_new(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+name VARCHAR(50) NOT NULL,
+username VARCHAR(10) NOT NULL,
+email VARCHAR(50) NOT NULL,
+password VARCHAR(512) NOT NULL,
+role INTEGER(1) CHECK(role IN (1,2,3)) NOT NULL,
+email_verification TIMESTAMP NOT NULL,
+created_at TIMESTAMP NOT NULL,
+updated_at TIMESTAMP NOT NULL,
+UNIQUE(username, email)
+);
+INSERT INTO users_new SELECT * FROM users;
+DROP TABLE users;
+ALTER TABLE users_new RENAME TO users;
+
+CREATE TABLE teaching_assignments(
+user_id INTEGER NOT NULL,
+course_id INTEGER NOT NULL,
+created_at TIMESTAMP NOT NULL,
+PRIMARY KEY(user_id, course_id),
+FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE
+);
+CREATE INDEX teaching_assignments_course_id ON teaching_assignments(course_id);
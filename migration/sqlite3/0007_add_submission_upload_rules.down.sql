@@ -0,0 +1,2 @@
+ALTER TABLE module_submissions DROP COLUMN max_size;
+ALTER TABLE module_submissions DROP COLUMN allowed_types;
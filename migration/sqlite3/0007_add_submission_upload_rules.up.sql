@@ -0,0 +1,3 @@
+-- allowed_types is a comma separated list of file types, empty allows any type. max_size is in bytes, 0 uses the server limit.
+ALTER TABLE module_submissions ADD COLUMN allowed_types VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE module_submissions ADD COLUMN max_size INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+ALTER TABLE user_submissions DROP COLUMN feedback;
+
+DROP TABLE rubric_scores;
+DROP TABLE rubric_levels;
+DROP TABLE rubric_criteria;
@@ -0,0 +1,34 @@
+CREATE TABLE rubric_criteria(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+module_submission_id INTEGER NOT NULL,
+name VARCHAR(255) NOT NULL,
+description TEXT NOT NULL DEFAULT '',
+position INTEGER NOT NULL,
+FOREIGN KEY (module_submission_id) REFERENCES module_submissions(id) ON DELETE CASCADE
+);
+CREATE INDEX rubric_criteria_module_submission_id ON rubric_criteria(module_submission_id);
+
+CREATE TABLE rubric_levels(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+criterion_id INTEGER NOT NULL,
+name VARCHAR(255) NOT NULL,
+description TEXT NOT NULL DEFAULT '',
+points INTEGER NOT NULL,
+FOREIGN KEY (criterion_id) REFERENCES rubric_criteria(id) ON DELETE CASCADE
+);
+CREATE INDEX rubric_levels_criterion_id ON rubric_levels(criterion_id);
+
+-- One level is picked for every criterion, points are copied so the total stays when the rubric changes
+CREATE TABLE rubric_scores(
+user_submission_id INTEGER NOT NULL,
+criterion_id INTEGER NOT NULL,
+level_id INTEGER NOT NULL,
+points INTEGER NOT NULL,
+comment TEXT NOT NULL DEFAULT '',
+PRIMARY KEY(user_submission_id, criterion_id),
+FOREIGN KEY (user_submission_id) REFERENCES user_submissions(id) ON DELETE CASCADE,
+FOREIGN KEY (criterion_id) REFERENCES rubric_criteria(id) ON DELETE CASCADE,
+FOREIGN KEY (level_id) REFERENCES rubric_levels(id) ON DELETE CASCADE
+);
+
+ALTER TABLE user_submissions ADD COLUMN feedback TEXT;
@@ -0,0 +1,21 @@
+-- weight is the percent of the final score a category counts for, the drop_lowest lowest scores of a student are ignored
+CREATE TABLE grade_categories(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+course_id INTEGER NOT NULL,
+name VARCHAR(100) NOT NULL,
+weight INTEGER NOT NULL DEFAULT 0,
+drop_lowest INTEGER NOT NULL DEFAULT 0,
+FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE
+);
+CREATE INDEX grade_categories_course_id ON grade_categories(course_id);
+
+ALTER TABLE module_submissions ADD COLUMN category_id INTEGER REFERENCES grade_categories(id) ON DELETE SET NULL;
+
+-- A final score of at least min_score earns the letter
+CREATE TABLE grade_bands(
+course_id INTEGER NOT NULL,
+letter VARCHAR(5) NOT NULL,
+min_score INTEGER NOT NULL,
+PRIMARY KEY(course_id, letter),
+FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE
+);
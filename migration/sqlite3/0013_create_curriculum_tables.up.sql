@@ -0,0 +1,33 @@
+CREATE TABLE course_sections(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+course_id INTEGER NOT NULL,
+name VARCHAR(100) NOT NULL,
+position INTEGER NOT NULL,
+FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE
+);
+CREATE INDEX course_sections_course_id ON course_sections(course_id);
+
+-- item_type names the table of item_id: article for module_articles, submission for module_submissions and quiz for
+-- quizzes. Items without a section come first, then every section in position order.
+CREATE TABLE curriculum_items(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+course_id INTEGER NOT NULL,
+section_id INTEGER,
+item_type VARCHAR(20) NOT NULL,
+item_id INTEGER NOT NULL,
+position INTEGER NOT NULL,
+UNIQUE(item_type, item_id),
+FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
+FOREIGN KEY (section_id) REFERENCES course_sections(id) ON DELETE SET NULL
+);
+CREATE INDEX curriculum_items_course_id ON curriculum_items(course_id);
+
+INSERT INTO curriculum_items(course_id, item_type, item_id, position)
+SELECT course_id, item_type, item_id, ROW_NUMBER() OVER (PARTITION BY course_id ORDER BY type_order, item_id)
+FROM (
+    SELECT course_id, 'article' AS item_type, id AS item_id, 1 AS type_order FROM module_articles
+    UNION ALL
+    SELECT course_id, 'submission', id, 2 FROM module_submissions
+    UNION ALL
+    SELECT course_id, 'quiz', id, 3 FROM quizzes
+) AS items;
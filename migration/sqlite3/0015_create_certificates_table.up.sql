@@ -0,0 +1,15 @@
+-- One certificate per student and course. The student and course names are kept as they were when it was issued so
+-- the verification keeps showing what the PDF says.
+CREATE TABLE certificates(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+user_id INTEGER NOT NULL,
+course_id INTEGER NOT NULL,
+code VARCHAR(20) NOT NULL UNIQUE,
+student_name VARCHAR(255) NOT NULL,
+course_name VARCHAR(255) NOT NULL,
+score REAL,
+issued_at TIMESTAMP NOT NULL,
+UNIQUE(user_id, course_id),
+FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE
+);
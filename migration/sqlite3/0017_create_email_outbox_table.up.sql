@@ -0,0 +1,15 @@
+-- Emails waiting to be sent. They are added in the transaction of the change they are about and sent by a worker,
+-- which tries again at next_attempt_at (a unix timestamp) until it gives up and marks the email failed.
+CREATE TABLE email_outbox(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+recipient VARCHAR(255) NOT NULL,
+subject VARCHAR(255) NOT NULL,
+body TEXT NOT NULL,
+status VARCHAR(20) NOT NULL DEFAULT 'pending',
+attempts INTEGER NOT NULL DEFAULT 0,
+next_attempt_at INTEGER(11) NOT NULL,
+last_error TEXT,
+created_at TIMESTAMP NOT NULL,
+sent_at TIMESTAMP
+);
+CREATE INDEX email_outbox_status_next_attempt_at ON email_outbox(status, next_attempt_at);
@@ -0,0 +1,18 @@
+ALTER TABLE email_verifications DROP COLUMN sent_at;
+
+-- Unverified users count as verified when they registered
+CREATE TABLE users_old(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+name VARCHAR(50) NOT NULL,
+username VARCHAR(10) NOT NULL,
+email VARCHAR(50) NOT NULL,
+password VARCHAR(512) NOT NULL,
+role INTEGER(1) CHECK(role IN (1,2,3)) NOT NULL,
+email_verification TIMESTAMP NOT NULL,
+created_at TIMESTAMP NOT NULL,
+updated_at TIMESTAMP NOT NULL,
+UNIQUE(username, email)
+);
+INSERT INTO users_old SELECT id, name, username, email, password, role, COALESCE(email_verification, created_at), created_at, updated_at FROM users;
+DROP TABLE users;
+ALTER TABLE users_old RENAME TO users;
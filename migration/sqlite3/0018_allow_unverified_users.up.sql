@@ -0,0 +1,19 @@
+-- Users who did not verify their email yet have no email_verification
+CREATE TABLE users_new(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+name VARCHAR(50) NOT NULL,
+username VARCHAR(10) NOT NULL,
+email VARCHAR(50) NOT NULL,
+password VARCHAR(512) NOT NULL,
+role INTEGER(1) CHECK(role IN (1,2,3)) NOT NULL,
+email_verification TIMESTAMP,
+created_at TIMESTAMP NOT NULL,
+updated_at TIMESTAMP NOT NULL,
+UNIQUE(username, email)
+);
+INSERT INTO users_new SELECT * FROM users;
+DROP TABLE users;
+ALTER TABLE users_new RENAME TO users;
+
+-- When the last verification email was sent, a unix timestamp, so it is not sent again too soon
+ALTER TABLE email_verifications ADD COLUMN sent_at INTEGER(11);
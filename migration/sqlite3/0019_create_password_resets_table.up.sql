@@ -0,0 +1,10 @@
+CREATE TABLE password_resets(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+user_id INTEGER NOT NULL,
+token_hash VARCHAR(64) NOT NULL UNIQUE,
+used_at INTEGER(11),
+expired_at INTEGER(11) NOT NULL,
+created_at INTEGER(11) NOT NULL,
+FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE INDEX password_resets_user_id ON password_resets(user_id);
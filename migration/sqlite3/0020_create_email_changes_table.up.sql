@@ -0,0 +1,11 @@
+CREATE TABLE email_changes(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+user_id INTEGER NOT NULL,
+email VARCHAR(50) NOT NULL,
+token_hash VARCHAR(64) NOT NULL UNIQUE,
+used_at INTEGER(11),
+expired_at INTEGER(11) NOT NULL,
+created_at INTEGER(11) NOT NULL,
+FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE INDEX email_changes_user_id ON email_changes(user_id);
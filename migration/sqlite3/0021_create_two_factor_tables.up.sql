@@ -0,0 +1,36 @@
+-- The TOTP secret of a user. confirmed_at stays NULL until the user entered a first code, last_step is the time step
+-- of the last accepted code so a code can not be used twice.
+CREATE TABLE two_factor(
+user_id INTEGER PRIMARY KEY,
+secret VARCHAR(64) NOT NULL,
+last_step INTEGER NOT NULL DEFAULT 0,
+confirmed_at INTEGER(11),
+created_at INTEGER(11) NOT NULL,
+FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE TABLE recovery_codes(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+user_id INTEGER NOT NULL,
+code_hash VARCHAR(64) NOT NULL,
+used_at INTEGER(11),
+created_at INTEGER(11) NOT NULL,
+FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX recovery_codes_user_id ON recovery_codes(user_id);
+-- A login waiting for its second factor, attempts counts the wrong codes entered for it
+CREATE TABLE two_factor_challenges(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+user_id INTEGER NOT NULL,
+token_hash VARCHAR(64) NOT NULL UNIQUE,
+attempts INTEGER NOT NULL DEFAULT 0,
+used_at INTEGER(11),
+expired_at INTEGER(11) NOT NULL,
+created_at INTEGER(11) NOT NULL,
+FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+-- Roles whose users can only log in with two-factor authentication, roles without a row do not require it
+CREATE TABLE two_factor_policies(
+role INTEGER PRIMARY KEY,
+required BOOLEAN NOT NULL DEFAULT FALSE,
+updated_at INTEGER(11) NOT NULL
+);
@@ -0,0 +1,17 @@
+DROP TRIGGER answers_fts_update;
+DROP TRIGGER answers_fts_delete;
+DROP TRIGGER answers_fts_insert;
+DROP TRIGGER questions_fts_update;
+DROP TRIGGER questions_fts_delete;
+DROP TRIGGER questions_fts_insert;
+DROP TRIGGER module_articles_fts_update;
+DROP TRIGGER module_articles_fts_delete;
+DROP TRIGGER module_articles_fts_insert;
+DROP TRIGGER courses_fts_update;
+DROP TRIGGER courses_fts_delete;
+DROP TRIGGER courses_fts_insert;
+
+DROP TABLE answers_fts;
+DROP TABLE questions_fts;
+DROP TABLE module_articles_fts;
+DROP TABLE courses_fts;
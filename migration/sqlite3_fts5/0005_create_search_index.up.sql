@@ -0,0 +1,53 @@
+CREATE VIRTUAL TABLE courses_fts USING fts5(name, class, about, description, content='courses', content_rowid='id');
+CREATE VIRTUAL TABLE module_articles_fts USING fts5(name, content, content='module_articles', content_rowid='id');
+CREATE VIRTUAL TABLE questions_fts USING fts5(title, tags, description, content='questions', content_rowid='id');
+CREATE VIRTUAL TABLE answers_fts USING fts5(description, content='answers', content_rowid='id');
+
+INSERT INTO courses_fts(courses_fts) VALUES('rebuild');
+INSERT INTO module_articles_fts(module_articles_fts) VALUES('rebuild');
+INSERT INTO questions_fts(questions_fts) VALUES('rebuild');
+INSERT INTO answers_fts(answers_fts) VALUES('rebuild');
+
+CREATE TRIGGER courses_fts_insert AFTER INSERT ON courses BEGIN
+INSERT INTO courses_fts(rowid, name, class, about, description) VALUES (new.id, new.name, new.class, new.about, new.description);
+END;
+CREATE TRIGGER courses_fts_delete AFTER DELETE ON courses BEGIN
+INSERT INTO courses_fts(courses_fts, rowid, name, class, about, description) VALUES ('delete', old.id, old.name, old.class, old.about, old.description);
+END;
+CREATE TRIGGER courses_fts_update AFTER UPDATE ON courses BEGIN
+INSERT INTO courses_fts(courses_fts, rowid, name, class, about, description) VALUES ('delete', old.id, old.name, old.class, old.about, old.description);
+INSERT INTO courses_fts(rowid, name, class, about, description) VALUES (new.id, new.name, new.class, new.about, new.description);
+END;
+
+CREATE TRIGGER module_articles_fts_insert AFTER INSERT ON module_articles BEGIN
+INSERT INTO module_articles_fts(rowid, name, content) VALUES (new.id, new.name, new.content);
+END;
+CREATE TRIGGER module_articles_fts_delete AFTER DELETE ON module_articles BEGIN
+INSERT INTO module_articles_fts(module_articles_fts, rowid, name, content) VALUES ('delete', old.id, old.name, old.content);
+END;
+CREATE TRIGGER module_articles_fts_update AFTER UPDATE ON module_articles BEGIN
+INSERT INTO module_articles_fts(module_articles_fts, rowid, name, content) VALUES ('delete', old.id, old.name, old.content);
+INSERT INTO module_articles_fts(rowid, name, content) VALUES (new.id, new.name, new.content);
+END;
+
+CREATE TRIGGER questions_fts_insert AFTER INSERT ON questions BEGIN
+INSERT INTO questions_fts(rowid, title, tags, description) VALUES (new.id, new.title, new.tags, new.description);
+END;
+CREATE TRIGGER questions_fts_delete AFTER DELETE ON questions BEGIN
+INSERT INTO questions_fts(questions_fts, rowid, title, tags, description) VALUES ('delete', old.id, old.title, old.tags, old.description);
+END;
+CREATE TRIGGER questions_fts_update AFTER UPDATE ON questions BEGIN
+INSERT INTO questions_fts(questions_fts, rowid, title, tags, description) VALUES ('delete', old.id, old.title, old.tags, old.description);
+INSERT INTO questions_fts(rowid, title, tags, description) VALUES (new.id, new.title, new.tags, new.description);
+END;
+
+CREATE TRIGGER answers_fts_insert AFTER INSERT ON answers BEGIN
+INSERT INTO answers_fts(rowid, description) VALUES (new.id, new.description);
+END;
+CREATE TRIGGER answers_fts_delete AFTER DELETE ON answers BEGIN
+INSERT INTO answers_fts(answers_fts, rowid, description) VALUES ('delete', old.id, old.description);
+END;
+CREATE TRIGGER answers_fts_update AFTER UPDATE ON answers BEGIN
+INSERT INTO answers_fts(answers_fts, rowid, description) VALUES ('delete', old.id, old.description);
+INSERT INTO answers_fts(rowid, description) VALUES (new.id, new.description);
+END;
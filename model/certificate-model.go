@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// GetCertificateResponse is what the PDF of a certificate says, Score is the final score of the gradebook and nil for
+// courses without graded module submissions
+type GetCertificateResponse struct {
+	Code        string    `json:"code"`
+	CodeCourse  string    `json:"code_course"`
+	CourseName  string    `json:"course_name"`
+	StudentName string    `json:"student_name"`
+	Score       *float64  `json:"score"`
+	IssuedAt    time.Time `json:"issued_at"`
+	VerifyUrl   string    `json:"verify_url"`
+}
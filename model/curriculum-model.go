@@ -0,0 +1,49 @@
+package model
+
+type SaveCourseSectionRequest struct {
+	Id   int
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// ReorderCurriculumRequest lists every section and item of the course in their new order, Items are the ones before
+// the first section
+type ReorderCurriculumRequest struct {
+	Items    []CurriculumItemRequest `json:"items" binding:"dive"`
+	Sections []ReorderSectionRequest `json:"sections" binding:"dive"`
+}
+
+type ReorderSectionRequest struct {
+	Id    int                     `json:"id" binding:"required"`
+	Items []CurriculumItemRequest `json:"items" binding:"dive"`
+}
+
+type CurriculumItemRequest struct {
+	Type string `json:"type" binding:"required,oneof=article submission quiz"`
+	Id   int    `json:"id" binding:"required"`
+}
+
+type GetCurriculumResponse struct {
+	CodeCourse string                     `json:"code_course"`
+	Sections   []GetCourseSectionResponse `json:"sections"`
+}
+
+// GetCourseSectionResponse has a nil Id for the items before the first section
+type GetCourseSectionResponse struct {
+	Id       *int                        `json:"id"`
+	Name     string                      `json:"name"`
+	Position int                         `json:"position"`
+	Items    []GetCurriculumItemResponse `json:"items"`
+}
+
+type GetCurriculumItemResponse struct {
+	Type     string `json:"type"`
+	Id       int    `json:"id"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+}
+
+type GetNextPreviousCurriculumResponse struct {
+	Type       string `json:"type"`
+	Id         int    `json:"id"`
+	CodeCourse string `json:"code_course"`
+}
@@ -4,3 +4,7 @@ type GetEmailVerificationRequest struct {
 	Email     string
 	Signature string
 }
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+// SaveEnrollmentSettingsRequest replaces the enrollment settings of a course. Key is required by the key mode, an
+// empty Capacity means no limit and the dates are RFC 3339 timestamps or dates, empty when not limited.
+type SaveEnrollmentSettingsRequest struct {
+	Mode     string `json:"mode" binding:"required,oneof=closed open key approval"`
+	Key      string `json:"key" binding:"max=100"`
+	Capacity *int   `json:"capacity" binding:"omitempty,min=1"`
+	StartsAt string `json:"starts_at"`
+	EndsAt   string `json:"ends_at"`
+}
+
+// GetEnrollmentSettingsResponse is what teachers see of the enrollment of their course
+type GetEnrollmentSettingsResponse struct {
+	CodeCourse string     `json:"code_course"`
+	Mode       string     `json:"mode"`
+	Key        *string    `json:"key"`
+	Capacity   *int       `json:"capacity"`
+	StartsAt   *time.Time `json:"starts_at"`
+	EndsAt     *time.Time `json:"ends_at"`
+	Enrolled   int        `json:"enrolled"`
+	Pending    int        `json:"pending"`
+	Waitlisted int        `json:"waitlisted"`
+}
+
+type EnrollRequest struct {
+	Key string `json:"key"`
+}
+
+// GetEnrollmentResponse is what a user sees of the enrollment of a course. Status is enrolled, pending, waitlisted or
+// none, Position is the place in the waitlist and SeatsLeft is nil for courses without a capacity.
+type GetEnrollmentResponse struct {
+	CodeCourse string     `json:"code_course"`
+	Mode       string     `json:"mode"`
+	Open       bool       `json:"open"`
+	Capacity   *int       `json:"capacity"`
+	SeatsLeft  *int       `json:"seats_left"`
+	StartsAt   *time.Time `json:"starts_at"`
+	EndsAt     *time.Time `json:"ends_at"`
+	Status     string     `json:"status"`
+	Position   *int       `json:"position"`
+}
+
+type GetEnrollmentRequestResponse struct {
+	Id        int       `json:"id"`
+	UserId    int       `json:"user_id"`
+	Name      string    `json:"name"`
+	Username  string    `json:"username"`
+	Status    string    `json:"status"`
+	Position  *int      `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,79 @@
+package model
+
+type SaveGradeCategoryRequest struct {
+	Id         int
+	Name       string `json:"name" binding:"required,max=100"`
+	Weight     int    `json:"weight" binding:"min=0,max=100"`
+	DropLowest int    `json:"drop_lowest" binding:"min=0"`
+}
+
+type GetGradeCategoryResponse struct {
+	Id         int    `json:"id"`
+	Name       string `json:"name"`
+	Weight     int    `json:"weight"`
+	DropLowest int    `json:"drop_lowest"`
+}
+
+type SaveGradeBandsRequest struct {
+	Bands []GradeBandRequest `json:"bands" binding:"required,min=1,dive"`
+}
+
+type GradeBandRequest struct {
+	Letter   string `json:"letter" binding:"required,max=5"`
+	MinScore int    `json:"min_score" binding:"min=0,max=100"`
+}
+
+type GetGradeBandResponse struct {
+	Letter   string `json:"letter"`
+	MinScore int    `json:"min_score"`
+}
+
+// GetGradebookResponse is the student × assignment matrix of a course. Scores are percents, Score is the weighted
+// final score of a student and Letter its band.
+type GetGradebookResponse struct {
+	CodeCourse  string                           `json:"code_course"`
+	Assignments []GetGradebookAssignmentResponse `json:"assignments"`
+	Categories  []GetGradebookCategoryResponse   `json:"categories"`
+	Bands       []GetGradeBandResponse           `json:"bands"`
+	Students    []GetGradebookStudentResponse    `json:"students"`
+}
+
+// GetGradebookAssignmentResponse is a column of the matrix, MaxPoints is what its rubric can give or 100 without one
+type GetGradebookAssignmentResponse struct {
+	Id         int    `json:"id"`
+	Name       string `json:"name"`
+	CategoryId *int   `json:"category_id"`
+	MaxPoints  int    `json:"max_points"`
+}
+
+// GetGradebookCategoryResponse describes a category, Id is nil for the assignments without one
+type GetGradebookCategoryResponse struct {
+	Id         *int   `json:"id"`
+	Name       string `json:"name"`
+	Weight     int    `json:"weight"`
+	DropLowest int    `json:"drop_lowest"`
+}
+
+type GetGradebookStudentResponse struct {
+	UserId     int                                 `json:"user_id"`
+	Name       string                              `json:"name"`
+	Username   string                              `json:"username"`
+	Email      string                              `json:"email"`
+	Grades     []GetGradebookGradeResponse         `json:"grades"`
+	Categories []GetGradebookCategoryScoreResponse `json:"categories"`
+	Score      *float64                            `json:"score"`
+	Letter     string                              `json:"letter"`
+}
+
+// GetGradebookGradeResponse is a cell of the matrix, Grade is after the late penalty
+type GetGradebookGradeResponse struct {
+	ModuleSubmissionId int      `json:"module_submission_id"`
+	Grade              *int     `json:"grade"`
+	Score              *float64 `json:"score"`
+	Dropped            bool     `json:"dropped"`
+}
+
+type GetGradebookCategoryScoreResponse struct {
+	CategoryId *int     `json:"category_id"`
+	Score      *float64 `json:"score"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import "errors"
+
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+// ErrInvalidListQuery is returned when a list query sorts or filters on a field the resource does not allow
+var ErrInvalidListQuery = errors.New("invalid list query")
+
+// ListQuery is the pagination, sorting and filtering shared by the list endpoints.
+// Sort names a whitelisted field, prefixed with "-" for descending order. Filters holds the
+// remaining query parameters, each resource only applies the ones it knows.
+type ListQuery struct {
+	Limit   int
+	Offset  int
+	Sort    string
+	Filters map[string]string
+}
+
+// ListPage describes where a page sits within the whole list
+type ListPage struct {
+	NextCursor string
+	Total      int
+}
@@ -1,11 +1,24 @@
 package model
 
+import "time"
+
 type GetModuleSubmissionsResponse struct {
 	Id          int    `json:"id,omitempty"`
 	CourseId    int    `json:"course_id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Deadline    string `json:"deadline"`
+	// AllowedTypes restricts uploads to these file types (pdf, docx, zip, image), empty allows any type
+	AllowedTypes []string `json:"allowed_types"`
+	// MaxSize is the largest upload in bytes, 0 uses the server limit
+	MaxSize int64 `json:"max_size"`
+	// LatePolicy is accept, hard_close, grace or penalty
+	LatePolicy string `json:"late_policy"`
+	// GracePeriod is in minutes, PenaltyPerDay in percent of the grade
+	GracePeriod   int `json:"grace_period"`
+	PenaltyPerDay int `json:"penalty_per_day"`
+	// CategoryId is the grade category of the gradebook the module submission counts in
+	CategoryId *int `json:"category_id"`
 }
 
 type GetNextPreviousSubmissionsResponse struct {
@@ -14,15 +27,40 @@ type GetNextPreviousSubmissionsResponse struct {
 }
 
 type CreateModuleSubmissionsRequest struct {
-	CourseId    int    `json:"course_id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Deadline    string `json:"deadline"`
+	CourseId      int      `json:"course_id"`
+	Name          string   `json:"name"`
+	Description   string   `json:"description"`
+	Deadline      string   `json:"deadline"`
+	AllowedTypes  []string `json:"allowed_types"`
+	MaxSize       int64    `json:"max_size"`
+	LatePolicy    string   `json:"late_policy"`
+	GracePeriod   int      `json:"grace_period"`
+	PenaltyPerDay int      `json:"penalty_per_day"`
+	CategoryId    *int     `json:"category_id"`
 }
 
 type UpdateModuleSubmissionsRequest struct {
-	CourseId    int    `json:"course_id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Deadline    string `json:"deadline"`
+	CourseId      int      `json:"course_id"`
+	Name          string   `json:"name"`
+	Description   string   `json:"description"`
+	Deadline      string   `json:"deadline"`
+	AllowedTypes  []string `json:"allowed_types"`
+	MaxSize       int64    `json:"max_size"`
+	LatePolicy    string   `json:"late_policy"`
+	GracePeriod   int      `json:"grace_period"`
+	PenaltyPerDay int      `json:"penalty_per_day"`
+	CategoryId    *int     `json:"category_id"`
+}
+
+type SaveExtensionRequest struct {
+	ModuleSubmissionId int
+	UserId             int
+	Deadline           string `json:"deadline" binding:"required"`
+}
+
+type GetExtensionResponse struct {
+	UserId    int       `json:"user_id"`
+	UserName  string    `json:"user_name"`
+	Deadline  time.Time `json:"deadline"`
+	CreatedAt time.Time `json:"created_at"`
 }
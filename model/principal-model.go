@@ -0,0 +1,9 @@
+package model
+
+// Principal is the authenticated caller of a request, built once from the access token
+type Principal struct {
+	Id      int
+	Name    string
+	Role    int
+	TokenId string
+}
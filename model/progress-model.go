@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// GetCourseProgressResponse counts the completed curriculum items of a user, TimeRemaining is the estimate in minutes
+// of the articles left to complete
+type GetCourseProgressResponse struct {
+	UserId        int                       `json:"user_id"`
+	CodeCourse    string                    `json:"code_course"`
+	Completed     int                       `json:"completed"`
+	Total         int                       `json:"total"`
+	Progress      int                       `json:"progress"`
+	TimeRemaining int                       `json:"time_remaining"`
+	LastVisited   *GetLastVisitedResponse   `json:"last_visited"`
+	Items         []GetItemProgressResponse `json:"items,omitempty"`
+}
+
+type GetLastVisitedResponse struct {
+	Type      string    `json:"type"`
+	Id        int       `json:"id"`
+	Name      string    `json:"name"`
+	VisitedAt time.Time `json:"visited_at"`
+}
+
+type GetItemProgressResponse struct {
+	Type        string     `json:"type"`
+	Id          int        `json:"id"`
+	Name        string     `json:"name"`
+	Estimate    int        `json:"estimate"`
+	Opens       int        `json:"opens"`
+	VisitedAt   *time.Time `json:"visited_at"`
+	Completed   bool       `json:"completed"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+type GetStudentProgressResponse struct {
+	UserId        int                     `json:"user_id"`
+	Name          string                  `json:"name"`
+	Username      string                  `json:"username"`
+	Completed     int                     `json:"completed"`
+	Total         int                     `json:"total"`
+	Progress      int                     `json:"progress"`
+	TimeRemaining int                     `json:"time_remaining"`
+	LastVisited   *GetLastVisitedResponse `json:"last_visited"`
+}
@@ -0,0 +1,108 @@
+package model
+
+import "time"
+
+type SaveQuizRequest struct {
+	Id               int
+	Name             string                `json:"name" binding:"required,max=255"`
+	Description      string                `json:"description"`
+	TimeLimit        int                   `json:"time_limit" binding:"min=0"`
+	MaxAttempts      int                   `json:"max_attempts" binding:"min=0"`
+	ShuffleQuestions bool                  `json:"shuffle_questions"`
+	Questions        []QuizQuestionRequest `json:"questions" binding:"omitempty,min=1,dive"`
+}
+
+// QuizQuestionRequest describes a question by its type: choices for multiple_choice and multiple_select, answers
+// with "true" or "false" for true_false, the accepted answers for short_answer and numeric_answer for numeric
+type QuizQuestionRequest struct {
+	Type          string              `json:"type" binding:"required,oneof=multiple_choice multiple_select true_false short_answer numeric"`
+	Prompt        string              `json:"prompt" binding:"required"`
+	Points        int                 `json:"points" binding:"min=0"`
+	Choices       []QuizChoiceRequest `json:"choices" binding:"dive"`
+	Answers       []string            `json:"answers"`
+	NumericAnswer *float64            `json:"numeric_answer"`
+	Tolerance     float64             `json:"tolerance" binding:"min=0"`
+}
+
+type QuizChoiceRequest struct {
+	Content string `json:"content" binding:"required"`
+	Correct bool   `json:"correct"`
+}
+
+type GetQuizResponse struct {
+	Id               int                       `json:"id"`
+	CourseId         int                       `json:"course_id"`
+	Name             string                    `json:"name"`
+	Description      string                    `json:"description"`
+	TimeLimit        int                       `json:"time_limit"`
+	MaxAttempts      int                       `json:"max_attempts"`
+	ShuffleQuestions bool                      `json:"shuffle_questions"`
+	MaxScore         int                       `json:"max_score"`
+	Questions        []GetQuizQuestionResponse `json:"questions,omitempty"`
+}
+
+// GetQuizQuestionResponse leaves out Answers, NumericAnswer, Tolerance and Correct for students
+type GetQuizQuestionResponse struct {
+	Id            int                     `json:"id"`
+	Type          string                  `json:"type"`
+	Prompt        string                  `json:"prompt"`
+	Points        int                     `json:"points"`
+	Choices       []GetQuizChoiceResponse `json:"choices"`
+	Answers       []string                `json:"answers,omitempty"`
+	NumericAnswer *float64                `json:"numeric_answer,omitempty"`
+	Tolerance     *float64                `json:"tolerance,omitempty"`
+}
+
+type GetQuizChoiceResponse struct {
+	Id      int    `json:"id"`
+	Content string `json:"content"`
+	Correct *bool  `json:"correct,omitempty"`
+}
+
+type GetNextPreviousQuizzesResponse struct {
+	Id         int    `json:"id"`
+	CodeCourse string `json:"code_course"`
+}
+
+type SubmitQuizAttemptRequest struct {
+	QuizId    int
+	AttemptId int
+	UserId    int
+	Answers   []QuizAnswerRequest `json:"answers" binding:"dive"`
+}
+
+// QuizAnswerRequest answers a question with choice_ids for the choice types and text for short_answer and numeric
+type QuizAnswerRequest struct {
+	QuestionId int    `json:"question_id" binding:"required"`
+	ChoiceIds  []int  `json:"choice_ids"`
+	Text       string `json:"text"`
+}
+
+// GetQuizAttemptResponse shows the questions while the attempt is open and the graded answers once it is submitted
+type GetQuizAttemptResponse struct {
+	Id          int                       `json:"id"`
+	QuizId      int                       `json:"quiz_id"`
+	StartedAt   time.Time                 `json:"started_at"`
+	Deadline    *time.Time                `json:"deadline"`
+	SubmittedAt *time.Time                `json:"submitted_at"`
+	Score       *int                      `json:"score"`
+	MaxScore    int                       `json:"max_score"`
+	Questions   []GetQuizQuestionResponse `json:"questions,omitempty"`
+	Results     []GetQuizResultResponse   `json:"results,omitempty"`
+}
+
+type GetQuizResultResponse struct {
+	QuestionId int    `json:"question_id"`
+	Answer     string `json:"answer"`
+	Correct    bool   `json:"correct"`
+	Points     int    `json:"points"`
+}
+
+type GetUserQuizResponse struct {
+	UserId          int    `json:"user_id"`
+	Name            string `json:"name"`
+	Username        string `json:"username"`
+	Grade           *int   `json:"grade"`
+	GradedAttemptId *int   `json:"graded_attempt_id"`
+	Attempts        int    `json:"attempts"`
+}
@@ -0,0 +1,71 @@
+package model
+
+type SaveRubricRequest struct {
+	ModuleSubmissionId int
+	Criteria           []SaveRubricCriterionRequest `json:"criteria" binding:"dive"`
+}
+
+type SaveRubricCriterionRequest struct {
+	Name        string                   `json:"name" binding:"required,max=255"`
+	Description string                   `json:"description"`
+	Levels      []SaveRubricLevelRequest `json:"levels" binding:"required,min=1,dive"`
+}
+
+type SaveRubricLevelRequest struct {
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description"`
+	Points      int    `json:"points" binding:"min=0"`
+}
+
+type GetRubricResponse struct {
+	ModuleSubmissionId int                          `json:"module_submission_id"`
+	MaxPoints          int                          `json:"max_points"`
+	Criteria           []GetRubricCriterionResponse `json:"criteria"`
+}
+
+type GetRubricCriterionResponse struct {
+	Id          int                      `json:"id"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	MaxPoints   int                      `json:"max_points"`
+	Levels      []GetRubricLevelResponse `json:"levels"`
+}
+
+type GetRubricLevelResponse struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Points      int    `json:"points"`
+}
+
+type SaveRubricScoresRequest struct {
+	UserSubmissionId int
+	Scores           []SaveCriterionScoreRequest `json:"scores" binding:"required,min=1,dive"`
+	Feedback         *string                     `json:"feedback"`
+}
+
+type SaveCriterionScoreRequest struct {
+	CriterionId int    `json:"criterion_id" binding:"required"`
+	LevelId     int    `json:"level_id" binding:"required"`
+	Comment     string `json:"comment"`
+}
+
+// GetRubricScoresResponse is the breakdown of a grade given with the rubric, Total is the grade
+type GetRubricScoresResponse struct {
+	UserSubmissionId int                         `json:"user_submission_id"`
+	UserId           int                         `json:"user_id"`
+	Total            int                         `json:"total"`
+	MaxPoints        int                         `json:"max_points"`
+	Feedback         *string                     `json:"feedback,omitempty"`
+	Scores           []GetCriterionScoreResponse `json:"scores"`
+}
+
+type GetCriterionScoreResponse struct {
+	CriterionId   int    `json:"criterion_id"`
+	CriterionName string `json:"criterion_name"`
+	LevelId       int    `json:"level_id"`
+	LevelName     string `json:"level_name"`
+	Points        int    `json:"points"`
+	MaxPoints     int    `json:"max_points"`
+	Comment       string `json:"comment"`
+}
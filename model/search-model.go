@@ -0,0 +1,10 @@
+package model
+
+type SearchResultResponse struct {
+	Type       string  `json:"type"`
+	Id         int     `json:"id"`
+	CodeCourse string  `json:"code_course"`
+	Title      string  `json:"title"`
+	Snippet    string  `json:"snippet"`
+	Score      float64 `json:"score"`
+}
@@ -0,0 +1,12 @@
+package model
+
+type CreateTeachingAssignmentRequest struct {
+	UserId int `json:"user_id" binding:"required"`
+}
+
+type GetCourseTeacherResponse struct {
+	IdUser       int    `json:"id_user"`
+	UserName     string `json:"user_name"`
+	UserUsername string `json:"user_username"`
+	UserEmail    string `json:"user_email"`
+}
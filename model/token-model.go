@@ -0,0 +1,15 @@
+package model
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest optionally names the refresh token of the session, its family is revoked along with the session
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type TokenResponse struct {
+	AccessToken  string
+	RefreshToken string
+}
@@ -0,0 +1,56 @@
+package model
+
+type TwoFactorStatusResponse struct {
+	Enabled           bool `json:"enabled"`
+	Required          bool `json:"required"`
+	RecoveryCodesLeft int  `json:"recovery_codes_left"`
+}
+
+type TwoFactorEnrollResponse struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"otpauth_uri"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorChallengeResponse struct {
+	ChallengeToken string `json:"challenge_token"`
+	ExpiresIn      int    `json:"expires_in"`
+	SetupRequired  bool   `json:"setup_required"`
+}
+
+type TwoFactorChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
+
+type VerifyTwoFactorChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// TwoFactorLoginResponse carries the recovery codes when the login also finished the setup of two-factor
+// authentication, they are only shown this one time
+type TwoFactorLoginResponse struct {
+	UserLoginResponse
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+type TwoFactorPolicyRequest struct {
+	Required *bool `json:"required" binding:"required"`
+}
+
+type TwoFactorPolicyResponse struct {
+	Role     int  `json:"role"`
+	Required bool `json:"required"`
+}
@@ -0,0 +1,43 @@
+package model
+
+// ImportUserRow is a row of an users CSV, the csv tags are the names of its columns. Courses lists the codes of the
+// courses the user is enrolled in.
+type ImportUserRow struct {
+	Name           string   `csv:"name" binding:"required"`
+	Username       string   `csv:"username" binding:"required"`
+	Email          string   `csv:"email" binding:"required,email"`
+	Password       string   `csv:"password" binding:"required"`
+	Role           int      `csv:"role" binding:"oneof=1 2 3"`
+	Phone          string   `csv:"phone"`
+	Gender         int      `csv:"gender"`
+	DisabilityType int      `csv:"type_of_disability"`
+	Birthdate      string   `csv:"birthdate" binding:"omitempty,datetime=2006-01-02"`
+	Courses        []string `csv:"courses"`
+}
+
+// ImportUsersResponse reports an import, Imported stays 0 for dry runs and imports with invalid rows
+type ImportUsersResponse struct {
+	DryRun   bool                   `json:"dry_run"`
+	Rows     int                    `json:"rows"`
+	Imported int                    `json:"imported"`
+	Errors   []ImportUserRowError   `json:"errors"`
+	Users    []ImportedUserResponse `json:"users"`
+}
+
+// ImportUserRowError lists what is wrong with a row, Row is its line in the file
+type ImportUserRowError struct {
+	Row    int      `json:"row"`
+	Errors []string `json:"errors"`
+}
+
+type ImportedUserResponse struct {
+	Row      int      `json:"row"`
+	Id       int      `json:"id,omitempty"`
+	Name     string   `json:"name"`
+	Username string   `json:"username"`
+	Email    string   `json:"email"`
+	Role     int      `json:"role"`
+	Courses  []string `json:"courses"`
+	// Waitlisted lists the courses the user joins the waitlist of because they are full
+	Waitlisted []string `json:"waitlisted"`
+}
@@ -1,20 +1,57 @@
 package model
 
+import (
+	"io"
+	"time"
+)
+
 type GetUserSubmissionsResponse struct {
 	Id                 int     `json:"id,omitempty"`
 	UserId             int     `json:"user_id"`
 	ModuleSubmissionId int     `json:"module_submission_id"`
 	File               *string `json:"file"`
 	Grade              *int    `json:"grade,omitempty"`
+	GradedAttemptId    *int    `json:"graded_attempt_id,omitempty"`
+	Feedback           *string `json:"feedback,omitempty"`
 }
 
 type CreateUserSubmissionsRequest struct {
+	CodeCourse         string
 	UserId             int
 	ModuleSubmissionId int
 	File               *string
 }
 
+type GetSubmissionAttemptResponse struct {
+	Id               int       `json:"id"`
+	UserSubmissionId int       `json:"user_submission_id"`
+	File             string    `json:"file"`
+	Hash             *string   `json:"hash"`
+	Size             *int64    `json:"size"`
+	Late             bool      `json:"late"`
+	Graded           bool      `json:"graded"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type SelectGradedAttemptRequest struct {
+	Id        int
+	AttemptId int `json:"attempt_id" binding:"required"`
+}
+
 type UpdateUserGradeRequest struct {
-	Id    int
-	Grade int `json:"grade"`
+	Id       int
+	Grade    int     `json:"grade"`
+	Feedback *string `json:"feedback"`
+}
+
+// UploadFile is a file received in a multipart form
+type UploadFile struct {
+	Name string
+	Size int64
+	Body io.ReadSeeker
+}
+
+type GetFileURLResponse struct {
+	Url       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type GetUserCourseResponse struct {
 	UserId   int `json:"user_id"`
 	CourseId int `json:"course_id"`
@@ -10,11 +12,15 @@ type CreateUserCourseRequest struct {
 	CourseId int `json:"course_id"`
 }
 
+// GetStudentCourseResponse has the progress of the student in percent and the minutes of reading left
 type GetStudentCourseResponse struct {
-	IdCourse    int    `json:"id_course"`
-	CourseName  string `json:"course_name"`
-	CourseCode  string `json:"course_code"`
-	CourseClass string `json:"course_class"`
+	IdCourse      int                     `json:"id_course"`
+	CourseName    string                  `json:"course_name"`
+	CourseCode    string                  `json:"course_code"`
+	CourseClass   string                  `json:"course_class"`
+	Progress      int                     `json:"progress"`
+	TimeRemaining int                     `json:"time_remaining"`
+	LastVisited   *GetLastVisitedResponse `json:"last_visited"`
 }
 
 type GetUserTeacherCourseResponse struct {
@@ -24,19 +30,31 @@ type GetUserTeacherCourseResponse struct {
 	UserEmail    string `json:"user_email"`
 }
 
+// GetStudentSubmissionsResponse shows the grade after the late penalty, RawGrade is the one the teacher gave.
+// Rubric is the breakdown of the grade when the teacher graded with the rubric.
 type GetStudentSubmissionsResponse struct {
-	IdModuleSubmission   int     `json:"id_module_submission"`
-	NameCourse           string  `json:"name_course"`
-	CodeCourse           string  `json:"code_course"`
-	NameModuleSubmission string  `json:"name_module_submission"`
-	Grade                *int    `json:"grade,omitempty"`
-	File                 *string `json:"file,omitempty"`
+	IdModuleSubmission   int                         `json:"id_module_submission"`
+	NameCourse           string                      `json:"name_course"`
+	CodeCourse           string                      `json:"code_course"`
+	NameModuleSubmission string                      `json:"name_module_submission"`
+	Grade                *int                        `json:"grade,omitempty"`
+	RawGrade             *int                        `json:"raw_grade,omitempty"`
+	Penalty              *int                        `json:"penalty,omitempty"`
+	Late                 bool                        `json:"late"`
+	SubmittedAt          *time.Time                  `json:"submitted_at,omitempty"`
+	File                 *string                     `json:"file,omitempty"`
+	Feedback             *string                     `json:"feedback,omitempty"`
+	Rubric               []GetCriterionScoreResponse `json:"rubric,omitempty"`
 }
 
 type GetTeacherSubmissionsResponse struct {
-	IdUserSubmission     *int    `json:"id_user_submission,omitempty"`
-	UserName             string  `json:"user_name"`
-	ModuleSubmissionName string  `json:"module_submission_name"`
-	Grade                *int    `json:"grade,omitempty"`
-	File                 *string `json:"file,omitempty"`
+	IdUserSubmission     *int       `json:"id_user_submission,omitempty"`
+	UserName             string     `json:"user_name"`
+	ModuleSubmissionName string     `json:"module_submission_name"`
+	Grade                *int       `json:"grade,omitempty"`
+	RawGrade             *int       `json:"raw_grade,omitempty"`
+	Penalty              *int       `json:"penalty,omitempty"`
+	Late                 bool       `json:"late"`
+	SubmittedAt          *time.Time `json:"submitted_at,omitempty"`
+	File                 *string    `json:"file,omitempty"`
 }
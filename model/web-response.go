@@ -1,8 +1,11 @@
 package model
 
 type WebResponse struct {
-	Code   int         `json:"code"`
-	Status string      `json:"status"`
-	Token  string      `json:"token,omitempty"`
-	Data   interface{} `json:"data,omitempty"`
+	Code         int         `json:"code"`
+	Status       string      `json:"status"`
+	Token        string      `json:"token,omitempty"`
+	RefreshToken string      `json:"refresh_token,omitempty"`
+	NextCursor   string      `json:"next_cursor,omitempty"`
+	Total        *int        `json:"total,omitempty"`
+	Data         interface{} `json:"data,omitempty"`
 }
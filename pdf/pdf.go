@@ -0,0 +1,147 @@
+// Package pdf writes single page PDF documents with text and lines. Text is set in the standard Helvetica fonts,
+// which every reader has, so no font is embedded and only the characters of WinAnsi (Latin-1) can be printed.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+const ContentType = "application/pdf"
+
+// Sizes of the pages in points, a point is 1/72 inch
+const (
+	A4Width  = 595
+	A4Height = 842
+)
+
+// Document is a page being drawn, the origin is the bottom left corner
+type Document struct {
+	width   float64
+	height  float64
+	content bytes.Buffer
+}
+
+func New(width float64, height float64) *Document {
+	return &Document{
+		width:  width,
+		height: height,
+	}
+}
+
+func (document *Document) Width() float64 {
+	return document.width
+}
+
+func (document *Document) Height() float64 {
+	return document.height
+}
+
+// Text draws text with its baseline starting at x, y
+func (document *Document) Text(x float64, y float64, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+
+	fmt.Fprintf(&document.content, "BT /%v %v Tf %v %v Td (%v) Tj ET\n", font, number(size), number(x), number(y), escape(encode(text)))
+}
+
+// CenteredText draws text centered on the width of the page
+func (document *Document) CenteredText(y float64, size float64, bold bool, text string) {
+	document.Text((document.width-TextWidth(text, size, bold))/2, y, size, bold, text)
+}
+
+// Rect strokes a rectangle with its bottom left corner at x, y
+func (document *Document) Rect(x float64, y float64, width float64, height float64, lineWidth float64) {
+	fmt.Fprintf(&document.content, "%v w %v %v %v %v re S\n", number(lineWidth), number(x), number(y), number(width), number(height))
+}
+
+// Line strokes a line from x1, y1 to x2, y2
+func (document *Document) Line(x1 float64, y1 float64, x2 float64, y2 float64, lineWidth float64) {
+	fmt.Fprintf(&document.content, "%v w %v %v m %v %v l S\n", number(lineWidth), number(x1), number(y1), number(x2), number(y2))
+}
+
+// Write writes the document with its cross-reference table
+func (document *Document) Write(writer io.Writer) error {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %v %v] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>", number(document.width), number(document.height)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %v >>\nstream\n%vendstream", document.content.Len(), document.content.String()),
+	}
+
+	var file bytes.Buffer
+	file.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = file.Len()
+		fmt.Fprintf(&file, "%v 0 obj\n%v\nendobj\n", i+1, object)
+	}
+
+	xref := file.Len()
+	fmt.Fprintf(&file, "xref\n0 %v\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&file, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&file, "trailer\n<< /Size %v /Root 1 0 R >>\nstartxref\n%v\n%%%%EOF\n", len(objects)+1, xref)
+
+	_, err := file.WriteTo(writer)
+	return err
+}
+
+// TextWidth returns the width of text in points
+func TextWidth(text string, size float64, bold bool) float64 {
+	widths := helveticaWidths
+	if bold {
+		widths = helveticaBoldWidths
+	}
+
+	total := 0
+	for _, char := range encode(text) {
+		if char >= 32 && char <= 126 {
+			total += widths[char-32]
+		} else {
+			total += defaultWidth
+		}
+	}
+
+	return float64(total) * size / 1000
+}
+
+// encode maps text to WinAnsi, the characters it does not have become a question mark
+func encode(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, char := range text {
+		if char < 32 || char > 255 || (char >= 127 && char < 160) {
+			char = '?'
+		}
+		encoded = append(encoded, byte(char))
+	}
+
+	return encoded
+}
+
+// escape escapes the characters that end or break a literal string
+func escape(text []byte) string {
+	var escaped bytes.Buffer
+	for _, char := range text {
+		if char == '(' || char == ')' || char == '\\' {
+			escaped.WriteByte('\\')
+		}
+		escaped.WriteByte(char)
+	}
+
+	return escaped.String()
+}
+
+// number writes a coordinate or size rounded to a hundredth of a point
+func number(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
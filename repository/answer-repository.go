@@ -5,10 +5,11 @@ import (
 	"database/sql"
 	"errors"
 	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
 )
 
 type AnswerRepository interface {
-	FindAll(ctx context.Context, tx *sql.Tx) ([]entity.Answers, error)
+	FindAll(ctx context.Context, tx *sql.Tx, query model.ListQuery) ([]entity.Answers, int, error)
 	Create(ctx context.Context, tx *sql.Tx, answer entity.Answers) (entity.Answers, error)
 	Delete(ctx context.Context, tx *sql.Tx, answerId int) error
 	FindById(ctx context.Context, tx *sql.Tx, answerId int) (entity.Answers, error)
@@ -25,35 +26,58 @@ func NewAnswerRepository() AnswerRepository {
 }
 
 func (repository *answerRepository) Create(ctx context.Context, tx *sql.Tx, answer entity.Answers) (entity.Answers, error) {
-	query := `INSERT INTO answers(question_id, user_id, description, created_at, updated_at) VALUES(?,?,?,?,?)`
+	query := `INSERT INTO answers(question_id, user_id, description, created_at, updated_at) VALUES(?,?,?,?,?) RETURNING id`
 
-	queryContext, err := tx.ExecContext(
+	var id int
+	err := tx.QueryRowContext(
 		ctx,
-		query,
+		bind(query),
 		answer.QuestionId,
 		answer.UserId,
 		answer.Description,
 		answer.CreatedAt,
 		answer.UpdatedAt,
-	)
+	).Scan(&id)
 	if err != nil {
 		return entity.Answers{}, err
 	}
+	answer.Id = id
+
+	return answer, nil
+}
 
-	id, err := queryContext.LastInsertId()
+var answerList = listSpec{
+	Sorts: map[string]string{
+		"created_at": "created_at",
+	},
+	DefaultSort: "-created_at",
+	Tiebreak:    "id",
+	Filters: map[string]listFilter{
+		"question_id": {Column: "question_id", Parse: parseInt},
+		"user_id":     {Column: "user_id", Parse: parseInt},
+	},
+}
+
+func (repository *answerRepository) FindAll(ctx context.Context, tx *sql.Tx, query model.ListQuery) ([]entity.Answers, int, error) {
+	where, args, err := answerList.where(query)
 	if err != nil {
-		return entity.Answers{}, err
+		return nil, 0, err
+	}
+	orderBy, err := answerList.orderBy(query)
+	if err != nil {
+		return nil, 0, err
 	}
-	answer.Id = int(id)
 
-	return answer, nil
-}
+	var total int
+	err = tx.QueryRowContext(ctx, bind(`SELECT COUNT(*) FROM answers`+where), args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
 
-func (repository *answerRepository) FindAll(ctx context.Context, tx *sql.Tx) ([]entity.Answers, error) {
-	query := `SELECT * FROM answers ORDER BY created_at DESC`
-	queryContext, err := tx.QueryContext(ctx, query)
+	statement := `SELECT id, question_id, user_id, description, created_at, updated_at FROM answers` + where + orderBy
+	queryContext, err := tx.QueryContext(ctx, bind(statement), append(args, query.Limit, query.Offset)...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer func(queryContext *sql.Rows) {
 		err := queryContext.Close()
@@ -74,18 +98,18 @@ func (repository *answerRepository) FindAll(ctx context.Context, tx *sql.Tx) ([]
 			&answer.UpdatedAt,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		answers = append(answers, answer)
 	}
 
-	return answers, nil
+	return answers, total, nil
 }
 
 func (repository *answerRepository) Delete(ctx context.Context, tx *sql.Tx, answerId int) error {
 	query := "DELETE FROM answers WHERE id = ?"
-	_, err := tx.ExecContext(ctx, query, answerId)
+	_, err := tx.ExecContext(ctx, bind(query), answerId)
 	if err != nil {
 		return err
 	}
@@ -94,8 +118,8 @@ func (repository *answerRepository) Delete(ctx context.Context, tx *sql.Tx, answ
 }
 
 func (repository *answerRepository) FindById(ctx context.Context, tx *sql.Tx, answerId int) (entity.Answers, error) {
-	query := `SELECT * FROM answers WHERE id = ?`
-	queryContext, err := tx.QueryContext(ctx, query, answerId)
+	query := `SELECT id, question_id, user_id, description, created_at, updated_at FROM answers WHERE id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), answerId)
 	if err != nil {
 		return entity.Answers{}, err
 	}
@@ -131,7 +155,7 @@ func (repository *answerRepository) Update(ctx context.Context, tx *sql.Tx, answ
 	query := `UPDATE answers SET question_id = ?, description = ?, updated_at = ? WHERE id = ?`
 	_, err := tx.ExecContext(
 		ctx,
-		query,
+		bind(query),
 		answer.QuestionId,
 		answer.Description,
 		answer.UpdatedAt,
@@ -145,8 +169,8 @@ func (repository *answerRepository) Update(ctx context.Context, tx *sql.Tx, answ
 }
 
 func (repository *answerRepository) FindByUserId(ctx context.Context, tx *sql.Tx, userId int) ([]entity.Answers, error) {
-	query := `SELECT * FROM answers WHERE user_id = ? ORDER BY created_at DESC`
-	queryContext, err := tx.QueryContext(ctx, query, userId)
+	query := `SELECT id, question_id, user_id, description, created_at, updated_at FROM answers WHERE user_id = ? ORDER BY created_at DESC`
+	queryContext, err := tx.QueryContext(ctx, bind(query), userId)
 	if err != nil {
 		return []entity.Answers{}, err
 	}
@@ -179,8 +203,8 @@ func (repository *answerRepository) FindByUserId(ctx context.Context, tx *sql.Tx
 }
 
 func (repository *answerRepository) FindByIdQuestion(ctx context.Context, tx *sql.Tx, questionId int) ([]entity.Answers, error) {
-	query := `SELECT * FROM answers WHERE question_id = ? ORDER BY created_at DESC`
-	queryContext, err := tx.QueryContext(ctx, query, questionId)
+	query := `SELECT id, question_id, user_id, description, created_at, updated_at FROM answers WHERE question_id = ? ORDER BY created_at DESC`
+	queryContext, err := tx.QueryContext(ctx, bind(query), questionId)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+)
+
+type CertificateRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, certificate entity.Certificates) (entity.Certificates, error)
+	FindByUserCourse(ctx context.Context, tx *sql.Tx, userId int, courseId int) (entity.Certificates, error)
+	FindByCode(ctx context.Context, tx *sql.Tx, code string) (entity.Certificates, error)
+}
+
+type certificateRepository struct {
+}
+
+func NewCertificateRepository() CertificateRepository {
+	return &certificateRepository{}
+}
+
+func (repository *certificateRepository) Create(ctx context.Context, tx *sql.Tx, certificate entity.Certificates) (entity.Certificates, error) {
+	query := `INSERT INTO certificates(user_id, course_id, code, student_name, course_name, score, issued_at) VALUES(?,?,?,?,?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
+		ctx,
+		bind(query),
+		certificate.UserId,
+		certificate.CourseId,
+		certificate.Code,
+		certificate.StudentName,
+		certificate.CourseName,
+		certificate.Score,
+		certificate.IssuedAt,
+	).Scan(&id)
+	if err != nil {
+		return entity.Certificates{}, err
+	}
+	certificate.Id = id
+
+	return certificate, nil
+}
+
+func (repository *certificateRepository) FindByUserCourse(ctx context.Context, tx *sql.Tx, userId int, courseId int) (entity.Certificates, error) {
+	query := `SELECT ce.id, ce.user_id, ce.course_id, c.code_course, ce.code, ce.student_name, ce.course_name, ce.score, ce.issued_at
+			  FROM certificates ce
+			  JOIN courses c ON c.id = ce.course_id
+			  WHERE ce.user_id = ? AND ce.course_id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), userId, courseId)
+	if err != nil {
+		return entity.Certificates{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var certificate entity.Certificates
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&certificate.Id,
+			&certificate.UserId,
+			&certificate.CourseId,
+			&certificate.CodeCourse,
+			&certificate.Code,
+			&certificate.StudentName,
+			&certificate.CourseName,
+			&certificate.Score,
+			&certificate.IssuedAt,
+		)
+		if err != nil {
+			return entity.Certificates{}, err
+		}
+
+		return certificate, nil
+	}
+
+	return certificate, errors.New("certificate not found")
+}
+
+func (repository *certificateRepository) FindByCode(ctx context.Context, tx *sql.Tx, code string) (entity.Certificates, error) {
+	query := `SELECT ce.id, ce.user_id, ce.course_id, c.code_course, ce.code, ce.student_name, ce.course_name, ce.score, ce.issued_at
+			  FROM certificates ce
+			  JOIN courses c ON c.id = ce.course_id
+			  WHERE ce.code = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), code)
+	if err != nil {
+		return entity.Certificates{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var certificate entity.Certificates
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&certificate.Id,
+			&certificate.UserId,
+			&certificate.CourseId,
+			&certificate.CodeCourse,
+			&certificate.Code,
+			&certificate.StudentName,
+			&certificate.CourseName,
+			&certificate.Score,
+			&certificate.IssuedAt,
+		)
+		if err != nil {
+			return entity.Certificates{}, err
+		}
+
+		return certificate, nil
+	}
+
+	return certificate, errors.New("certificate not found")
+}
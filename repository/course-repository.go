@@ -5,10 +5,11 @@ import (
 	"database/sql"
 	"errors"
 	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
 )
 
 type CourseRepository interface {
-	FindAll(ctx context.Context, tx *sql.Tx, status bool, limit int) ([]entity.Courses, error)
+	FindAll(ctx context.Context, tx *sql.Tx, query model.ListQuery) ([]entity.Courses, int, error)
 	FindByCode(ctx context.Context, tx *sql.Tx, code string) (entity.Courses, error)
 	Create(ctx context.Context, tx *sql.Tx, courses entity.Courses) (entity.Courses, error)
 	Update(ctx context.Context, tx *sql.Tx, courses entity.Courses, code string) (entity.Courses, error)
@@ -23,12 +24,41 @@ func NewCourseRepository() CourseRepository {
 	return &courseRepository{}
 }
 
-func (repository *courseRepository) FindAll(ctx context.Context, tx *sql.Tx, status bool, limit int) ([]entity.Courses, error) {
-	// query := `SELECT * FROM courses WHERE is_active = ? ORDER BY created_at DESC LIMIT ?`
-	query := `SELECT * FROM courses ORDER BY created_at DESC LIMIT ?` // disable WHERE clause `is_active` 
-	queryContext, err := tx.QueryContext(ctx, query, limit)
+var courseList = listSpec{
+	Sorts: map[string]string{
+		"name":       "name",
+		"code":       "code_course",
+		"class":      "class",
+		"created_at": "created_at",
+	},
+	DefaultSort: "-created_at",
+	Tiebreak:    "id",
+	Filters: map[string]listFilter{
+		"class":     {Column: "class", Parse: parseString},
+		"is_active": {Column: "is_active", Parse: parseBool},
+	},
+}
+
+func (repository *courseRepository) FindAll(ctx context.Context, tx *sql.Tx, query model.ListQuery) ([]entity.Courses, int, error) {
+	where, args, err := courseList.where(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	orderBy, err := courseList.orderBy(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	err = tx.QueryRowContext(ctx, bind(`SELECT COUNT(*) FROM courses`+where), args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	statement := `SELECT id, name, code_course, class, tools, about, description, created_at, updated_at, is_active FROM courses` + where + orderBy
+	queryContext, err := tx.QueryContext(ctx, bind(statement), append(args, query.Limit, query.Offset)...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer func(queryContext *sql.Rows) {
 		err := queryContext.Close()
@@ -53,18 +83,18 @@ func (repository *courseRepository) FindAll(ctx context.Context, tx *sql.Tx, sta
 			&course.IsActive,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		courses = append(courses, course)
 	}
 
-	return courses, nil
+	return courses, total, nil
 }
 
 func (repository *courseRepository) FindByCode(ctx context.Context, tx *sql.Tx, code string) (entity.Courses, error) {
-	query := `SELECT * FROM courses WHERE code_course = ?`
-	queryContext, err := tx.QueryContext(ctx, query, code)
+	query := `SELECT id, name, code_course, class, tools, about, description, created_at, updated_at, is_active FROM courses WHERE code_course = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), code)
 	if err != nil {
 		return entity.Courses{}, err
 	}
@@ -100,10 +130,11 @@ func (repository *courseRepository) FindByCode(ctx context.Context, tx *sql.Tx,
 }
 
 func (repository *courseRepository) Create(ctx context.Context, tx *sql.Tx, courses entity.Courses) (entity.Courses, error) {
-	query := `INSERT INTO courses(name,code_course,class,tools,about,description,created_at,updated_at) VALUES(?,?,?,?,?,?,?,?)`
-	queryContext, err := tx.ExecContext(
+	query := `INSERT INTO courses(name,code_course,class,tools,about,description,created_at,updated_at,is_active) VALUES(?,?,?,?,?,?,?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
 		ctx,
-		query,
+		bind(query),
 		courses.Name,
 		courses.CodeCourse,
 		courses.Class,
@@ -113,16 +144,11 @@ func (repository *courseRepository) Create(ctx context.Context, tx *sql.Tx, cour
 		courses.CreatedAt,
 		courses.UpdatedAt,
 		courses.IsActive,
-	)
-	if err != nil {
-		return entity.Courses{}, err
-	}
-
-	id, err := queryContext.LastInsertId()
+	).Scan(&id)
 	if err != nil {
 		return entity.Courses{}, err
 	}
-	courses.Id = int(id)
+	courses.Id = id
 
 	return courses, nil
 }
@@ -131,7 +157,7 @@ func (repository *courseRepository) Update(ctx context.Context, tx *sql.Tx, cour
 	query := `UPDATE courses SET name = ?, class = ?, tools = ?, about = ?, description = ?, updated_at = ? WHERE code_course = ?`
 	_, err := tx.ExecContext(
 		ctx,
-		query,
+		bind(query),
 		courses.Name,
 		courses.Class,
 		courses.Tools,
@@ -149,7 +175,7 @@ func (repository *courseRepository) Update(ctx context.Context, tx *sql.Tx, cour
 
 func (repository *courseRepository) Delete(ctx context.Context, tx *sql.Tx, code string) error {
 	query := "DELETE FROM courses WHERE code_course = ?"
-	_, err := tx.ExecContext(ctx, query, code)
+	_, err := tx.ExecContext(ctx, bind(query), code)
 	if err != nil {
 		return err
 	}
@@ -161,7 +187,7 @@ func (repository *courseRepository) ChangeActiveCourse(ctx context.Context, tx *
 	query := `UPDATE courses SET is_active = ? WHERE code_course = ?`
 	_, err := tx.ExecContext(
 		ctx,
-		query,
+		bind(query),
 		status,
 		code,
 	)
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+)
+
+type CurriculumRepository interface {
+	FindAllSections(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.CourseSections, error)
+	FindSectionById(ctx context.Context, tx *sql.Tx, courseId int, id int) (entity.CourseSections, error)
+	CreateSection(ctx context.Context, tx *sql.Tx, section entity.CourseSections) (entity.CourseSections, error)
+	UpdateSection(ctx context.Context, tx *sql.Tx, section entity.CourseSections) (entity.CourseSections, error)
+	DeleteSection(ctx context.Context, tx *sql.Tx, id int) error
+	FindAllItems(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.CurriculumItems, error)
+	CreateItem(ctx context.Context, tx *sql.Tx, item entity.CurriculumItems) (entity.CurriculumItems, error)
+	UpdateItem(ctx context.Context, tx *sql.Tx, item entity.CurriculumItems) error
+	DeleteItem(ctx context.Context, tx *sql.Tx, itemType string, itemId int) error
+}
+
+type curriculumRepository struct {
+}
+
+func NewCurriculumRepository() CurriculumRepository {
+	return &curriculumRepository{}
+}
+
+func (repository *curriculumRepository) FindAllSections(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.CourseSections, error) {
+	query := `SELECT id, course_id, name, position FROM course_sections WHERE course_id = ? ORDER BY position, id`
+	queryContext, err := tx.QueryContext(ctx, bind(query), courseId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var sections []entity.CourseSections
+	for queryContext.Next() {
+		var section entity.CourseSections
+		err := queryContext.Scan(
+			&section.Id,
+			&section.CourseId,
+			&section.Name,
+			&section.Position,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		sections = append(sections, section)
+	}
+
+	return sections, nil
+}
+
+func (repository *curriculumRepository) FindSectionById(ctx context.Context, tx *sql.Tx, courseId int, id int) (entity.CourseSections, error) {
+	query := `SELECT id, course_id, name, position FROM course_sections WHERE course_id = ? AND id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), courseId, id)
+	if err != nil {
+		return entity.CourseSections{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var section entity.CourseSections
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&section.Id,
+			&section.CourseId,
+			&section.Name,
+			&section.Position,
+		)
+		if err != nil {
+			return entity.CourseSections{}, err
+		}
+
+		return section, nil
+	}
+
+	return section, errors.New("section not found")
+}
+
+func (repository *curriculumRepository) CreateSection(ctx context.Context, tx *sql.Tx, section entity.CourseSections) (entity.CourseSections, error) {
+	query := `INSERT INTO course_sections(course_id, name, position) VALUES(?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
+		ctx,
+		bind(query),
+		section.CourseId,
+		section.Name,
+		section.Position,
+	).Scan(&id)
+	if err != nil {
+		return entity.CourseSections{}, err
+	}
+	section.Id = id
+
+	return section, nil
+}
+
+func (repository *curriculumRepository) UpdateSection(ctx context.Context, tx *sql.Tx, section entity.CourseSections) (entity.CourseSections, error) {
+	query := `UPDATE course_sections SET name = ?, position = ? WHERE id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), section.Name, section.Position, section.Id)
+	if err != nil {
+		return entity.CourseSections{}, err
+	}
+
+	return section, nil
+}
+
+func (repository *curriculumRepository) DeleteSection(ctx context.Context, tx *sql.Tx, id int) error {
+	query := `DELETE FROM course_sections WHERE id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindAllItems returns the items of the course in curriculum order with the name of their module
+func (repository *curriculumRepository) FindAllItems(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.CurriculumItems, error) {
+	query := `SELECT
+				ci.id,
+				ci.course_id,
+				ci.section_id,
+				ci.item_type,
+				ci.item_id,
+				ci.position,
+				COALESCE(ma.name, ms.name, q.name, ''),
+				COALESCE(ma.estimate, 0)
+			  FROM curriculum_items ci
+			  LEFT JOIN course_sections cs ON cs.id = ci.section_id
+			  LEFT JOIN module_articles ma ON ci.item_type = 'article' AND ma.id = ci.item_id
+			  LEFT JOIN module_submissions ms ON ci.item_type = 'submission' AND ms.id = ci.item_id
+			  LEFT JOIN quizzes q ON ci.item_type = 'quiz' AND q.id = ci.item_id
+			  WHERE ci.course_id = ?
+			  ORDER BY COALESCE(cs.position, 0), cs.id, ci.position, ci.id`
+	queryContext, err := tx.QueryContext(ctx, bind(query), courseId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var items []entity.CurriculumItems
+	for queryContext.Next() {
+		var item entity.CurriculumItems
+		err := queryContext.Scan(
+			&item.Id,
+			&item.CourseId,
+			&item.SectionId,
+			&item.Type,
+			&item.ItemId,
+			&item.Position,
+			&item.Name,
+			&item.Estimate,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (repository *curriculumRepository) CreateItem(ctx context.Context, tx *sql.Tx, item entity.CurriculumItems) (entity.CurriculumItems, error) {
+	query := `INSERT INTO curriculum_items(course_id, section_id, item_type, item_id, position) VALUES(?,?,?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
+		ctx,
+		bind(query),
+		item.CourseId,
+		item.SectionId,
+		item.Type,
+		item.ItemId,
+		item.Position,
+	).Scan(&id)
+	if err != nil {
+		return entity.CurriculumItems{}, err
+	}
+	item.Id = id
+
+	return item, nil
+}
+
+func (repository *curriculumRepository) UpdateItem(ctx context.Context, tx *sql.Tx, item entity.CurriculumItems) error {
+	query := `UPDATE curriculum_items SET section_id = ?, position = ? WHERE id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), item.SectionId, item.Position, item.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repository *curriculumRepository) DeleteItem(ctx context.Context, tx *sql.Tx, itemType string, itemId int) error {
+	query := `DELETE FROM curriculum_items WHERE item_type = ? AND item_id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), itemType, itemId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
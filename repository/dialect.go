@@ -0,0 +1,23 @@
+package repository
+
+import "github.com/rg-km/final-project-engineering-12/backend/utils"
+
+var driver = utils.DriverSQLite
+
+// UseDriver sets the database driver the repositories render their queries for
+func UseDriver(name string) {
+	driver = name
+}
+
+func bind(query string) string {
+	return utils.Rebind(driver, query)
+}
+
+// limitArg renders a negative limit, meaning no limit, in a way the driver accepts
+func limitArg(limit int) interface{} {
+	if limit < 0 && driver == utils.DriverPostgres {
+		return nil
+	}
+
+	return limit
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+)
+
+type EmailChangeRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, emailChange entity.EmailChanges) (entity.EmailChanges, error)
+	FindByHash(ctx context.Context, tx *sql.Tx, tokenHash string) (entity.EmailChanges, error)
+	MarkUsed(ctx context.Context, tx *sql.Tx, id int, usedAt int64) (bool, error)
+	MarkUsedByUser(ctx context.Context, tx *sql.Tx, userId int, usedAt int64) error
+}
+
+type emailChangeRepository struct {
+}
+
+func NewEmailChangeRepository() EmailChangeRepository {
+	return &emailChangeRepository{}
+}
+
+func (repository *emailChangeRepository) Create(ctx context.Context, tx *sql.Tx, emailChange entity.EmailChanges) (entity.EmailChanges, error) {
+	query := `INSERT INTO email_changes(user_id, email, token_hash, expired_at, created_at) VALUES(?,?,?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
+		ctx,
+		bind(query),
+		emailChange.UserId,
+		emailChange.Email,
+		emailChange.TokenHash,
+		emailChange.ExpiredAt,
+		emailChange.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return entity.EmailChanges{}, err
+	}
+	emailChange.Id = id
+
+	return emailChange, nil
+}
+
+func (repository *emailChangeRepository) FindByHash(ctx context.Context, tx *sql.Tx, tokenHash string) (entity.EmailChanges, error) {
+	query := `SELECT id, user_id, email, token_hash, used_at, expired_at, created_at FROM email_changes WHERE token_hash = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), tokenHash)
+	if err != nil {
+		return entity.EmailChanges{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var emailChange entity.EmailChanges
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&emailChange.Id,
+			&emailChange.UserId,
+			&emailChange.Email,
+			&emailChange.TokenHash,
+			&emailChange.UsedAt,
+			&emailChange.ExpiredAt,
+			&emailChange.CreatedAt,
+		)
+		if err != nil {
+			return entity.EmailChanges{}, err
+		}
+
+		return emailChange, nil
+	}
+
+	return emailChange, errors.New("email change not found")
+}
+
+// MarkUsed flags the token as used and reports false when it had already been used before
+func (repository *emailChangeRepository) MarkUsed(ctx context.Context, tx *sql.Tx, id int, usedAt int64) (bool, error) {
+	query := `UPDATE email_changes SET used_at = ? WHERE id = ? AND used_at IS NULL`
+	result, err := tx.ExecContext(ctx, bind(query), usedAt, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// MarkUsedByUser flags every token of the user that has not been used yet as used
+func (repository *emailChangeRepository) MarkUsedByUser(ctx context.Context, tx *sql.Tx, userId int, usedAt int64) error {
+	query := `UPDATE email_changes SET used_at = ? WHERE user_id = ? AND used_at IS NULL`
+	_, err := tx.ExecContext(ctx, bind(query), usedAt, userId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
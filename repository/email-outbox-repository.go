@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+)
+
+type EmailOutboxRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, email entity.EmailOutbox) (entity.EmailOutbox, error)
+	FindDue(ctx context.Context, tx *sql.Tx, now int64, limit int) ([]entity.EmailOutbox, error)
+	Claim(ctx context.Context, tx *sql.Tx, id int, now int64, until int64) (bool, error)
+	MarkSent(ctx context.Context, tx *sql.Tx, id int, sentAt time.Time) error
+	MarkFailed(ctx context.Context, tx *sql.Tx, id int, status string, nextAttemptAt int64, lastError string) error
+}
+
+type emailOutboxRepository struct {
+}
+
+func NewEmailOutboxRepository() EmailOutboxRepository {
+	return &emailOutboxRepository{}
+}
+
+func (repository *emailOutboxRepository) Create(ctx context.Context, tx *sql.Tx, email entity.EmailOutbox) (entity.EmailOutbox, error) {
+	query := `INSERT INTO email_outbox(recipient, subject, body, status, attempts, next_attempt_at, created_at) VALUES(?,?,?,?,?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
+		ctx,
+		bind(query),
+		email.Recipient,
+		email.Subject,
+		email.Body,
+		email.Status,
+		email.Attempts,
+		email.NextAttemptAt,
+		email.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return entity.EmailOutbox{}, err
+	}
+	email.Id = id
+
+	return email, nil
+}
+
+// FindDue returns the oldest pending emails that are due to be sent at now
+func (repository *emailOutboxRepository) FindDue(ctx context.Context, tx *sql.Tx, now int64, limit int) ([]entity.EmailOutbox, error) {
+	query := `SELECT id, recipient, subject, body, status, attempts, next_attempt_at, last_error, created_at, sent_at
+			  FROM email_outbox
+			  WHERE status = ? AND next_attempt_at <= ?
+			  ORDER BY next_attempt_at, id
+			  LIMIT ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), entity.EmailPending, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var emails []entity.EmailOutbox
+	for queryContext.Next() {
+		var email entity.EmailOutbox
+		err := queryContext.Scan(
+			&email.Id,
+			&email.Recipient,
+			&email.Subject,
+			&email.Body,
+			&email.Status,
+			&email.Attempts,
+			&email.NextAttemptAt,
+			&email.LastError,
+			&email.CreatedAt,
+			&email.SentAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		emails = append(emails, email)
+	}
+
+	return emails, nil
+}
+
+// Claim counts an attempt to send the email and keeps other workers from sending it until the attempt is over, it
+// returns false when another worker claimed the email first
+func (repository *emailOutboxRepository) Claim(ctx context.Context, tx *sql.Tx, id int, now int64, until int64) (bool, error) {
+	query := `UPDATE email_outbox SET attempts = attempts + 1, next_attempt_at = ? WHERE id = ? AND status = ? AND next_attempt_at <= ?`
+	result, err := tx.ExecContext(ctx, bind(query), until, id, entity.EmailPending, now)
+	if err != nil {
+		return false, err
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return claimed == 1, nil
+}
+
+func (repository *emailOutboxRepository) MarkSent(ctx context.Context, tx *sql.Tx, id int, sentAt time.Time) error {
+	query := `UPDATE email_outbox SET status = ?, sent_at = ?, last_error = NULL WHERE id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), entity.EmailSent, sentAt, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarkFailed records why an attempt failed, the email is tried again at nextAttemptAt while its status is pending
+func (repository *emailOutboxRepository) MarkFailed(ctx context.Context, tx *sql.Tx, id int, status string, nextAttemptAt int64, lastError string) error {
+	query := `UPDATE email_outbox SET status = ?, next_attempt_at = ?, last_error = ? WHERE id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), status, nextAttemptAt, lastError, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
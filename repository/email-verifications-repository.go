@@ -23,8 +23,8 @@ func NewEmailVerificationRepository() EmailVerificationRepository {
 }
 
 func (repository *emailVerificationRepository) FindByEmailAndSignature(ctx context.Context, tx *sql.Tx, verification entity.EmailVerification) (entity.EmailVerification, error) {
-	query := "SELECT * FROM email_verifications WHERE email = ? AND signature = ?"
-	rows, err := tx.QueryContext(ctx, query, verification.Email, verification.Signature)
+	query := "SELECT id, email, signature, expired, COALESCE(sent_at, 0) FROM email_verifications WHERE email = ? AND signature = ?"
+	rows, err := tx.QueryContext(ctx, bind(query), verification.Email, verification.Signature)
 	if err != nil {
 		return entity.EmailVerification{}, err
 	}
@@ -38,7 +38,7 @@ func (repository *emailVerificationRepository) FindByEmailAndSignature(ctx conte
 
 	var userRequest entity.EmailVerification
 	if rows.Next() {
-		err := rows.Scan(&userRequest.Id, &userRequest.Email, &userRequest.Signature, &userRequest.Expired)
+		err := rows.Scan(&userRequest.Id, &userRequest.Email, &userRequest.Signature, &userRequest.Expired, &userRequest.SentAt)
 		if err != nil {
 			return entity.EmailVerification{}, err
 		}
@@ -50,8 +50,8 @@ func (repository *emailVerificationRepository) FindByEmailAndSignature(ctx conte
 }
 
 func (repository *emailVerificationRepository) FindByEmail(ctx context.Context, tx *sql.Tx, email string) (entity.EmailVerification, error) {
-	query := "SELECT * FROM email_verifications WHERE email = ?"
-	rows, err := tx.QueryContext(ctx, query, email)
+	query := "SELECT id, email, signature, expired, COALESCE(sent_at, 0) FROM email_verifications WHERE email = ?"
+	rows, err := tx.QueryContext(ctx, bind(query), email)
 	if err != nil {
 		return entity.EmailVerification{}, err
 	}
@@ -64,7 +64,7 @@ func (repository *emailVerificationRepository) FindByEmail(ctx context.Context,
 
 	var userRequest entity.EmailVerification
 	if rows.Next() {
-		err := rows.Scan(&userRequest.Id, &userRequest.Email, &userRequest.Signature, &userRequest.Expired)
+		err := rows.Scan(&userRequest.Id, &userRequest.Email, &userRequest.Signature, &userRequest.Expired, &userRequest.SentAt)
 		if err != nil {
 			return entity.EmailVerification{}, err
 		}
@@ -76,8 +76,8 @@ func (repository *emailVerificationRepository) FindByEmail(ctx context.Context,
 }
 
 func (repository *emailVerificationRepository) Create(ctx context.Context, tx *sql.Tx, verif entity.EmailVerification) (entity.EmailVerification, error) {
-	query := "INSERT INTO email_verifications (email,signature,expired) VALUES(?,?,?)"
-	_, err := tx.ExecContext(ctx, query, verif.Email, verif.Signature, verif.Expired)
+	query := "INSERT INTO email_verifications (email,signature,expired,sent_at) VALUES(?,?,?,?)"
+	_, err := tx.ExecContext(ctx, bind(query), verif.Email, verif.Signature, verif.Expired, verif.SentAt)
 	if err != nil {
 		return entity.EmailVerification{}, err
 	}
@@ -86,8 +86,8 @@ func (repository *emailVerificationRepository) Create(ctx context.Context, tx *s
 }
 
 func (repository *emailVerificationRepository) Update(ctx context.Context, tx *sql.Tx, verif entity.EmailVerification) (entity.EmailVerification, error) {
-	query := "UPDATE email_verifications SET signature = ?, expired = ? WHERE email = ?"
-	_, err := tx.ExecContext(ctx, query, verif.Signature, verif.Expired, verif.Email)
+	query := "UPDATE email_verifications SET signature = ?, expired = ?, sent_at = ? WHERE email = ?"
+	_, err := tx.ExecContext(ctx, bind(query), verif.Signature, verif.Expired, verif.SentAt, verif.Email)
 	if err != nil {
 		return entity.EmailVerification{}, err
 	}
@@ -97,7 +97,7 @@ func (repository *emailVerificationRepository) Update(ctx context.Context, tx *s
 
 func (repository *emailVerificationRepository) Delete(ctx context.Context, tx *sql.Tx, email string) error {
 	query := "DELETE FROM email_verifications WHERE email = ?"
-	_, err := tx.ExecContext(ctx, query, email)
+	_, err := tx.ExecContext(ctx, bind(query), email)
 	if err != nil {
 		return err
 	}
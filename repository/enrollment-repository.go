@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+)
+
+type EnrollmentRepository interface {
+	FindSettings(ctx context.Context, tx *sql.Tx, courseId int) (entity.EnrollmentSettings, error)
+	SaveSettings(ctx context.Context, tx *sql.Tx, settings entity.EnrollmentSettings) error
+	FindAllRequests(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.EnrollmentRequests, error)
+	FindRequestById(ctx context.Context, tx *sql.Tx, courseId int, id int) (entity.EnrollmentRequests, error)
+	FindRequestByUser(ctx context.Context, tx *sql.Tx, courseId int, userId int) (entity.EnrollmentRequests, error)
+	CreateRequest(ctx context.Context, tx *sql.Tx, request entity.EnrollmentRequests) (entity.EnrollmentRequests, error)
+	UpdateRequest(ctx context.Context, tx *sql.Tx, request entity.EnrollmentRequests) error
+	DeleteRequest(ctx context.Context, tx *sql.Tx, id int) error
+}
+
+type enrollmentRepository struct {
+}
+
+func NewEnrollmentRepository() EnrollmentRepository {
+	return &enrollmentRepository{}
+}
+
+func (repository *enrollmentRepository) FindSettings(ctx context.Context, tx *sql.Tx, courseId int) (entity.EnrollmentSettings, error) {
+	query := `SELECT course_id, mode, enrollment_key, capacity, starts_at, ends_at FROM enrollment_settings WHERE course_id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), courseId)
+	if err != nil {
+		return entity.EnrollmentSettings{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var settings entity.EnrollmentSettings
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&settings.CourseId,
+			&settings.Mode,
+			&settings.Key,
+			&settings.Capacity,
+			&settings.StartsAt,
+			&settings.EndsAt,
+		)
+		if err != nil {
+			return entity.EnrollmentSettings{}, err
+		}
+
+		return settings, nil
+	}
+
+	return settings, errors.New("enrollment settings not found")
+}
+
+func (repository *enrollmentRepository) SaveSettings(ctx context.Context, tx *sql.Tx, settings entity.EnrollmentSettings) error {
+	query := `INSERT INTO enrollment_settings(course_id, mode, enrollment_key, capacity, starts_at, ends_at) VALUES(?,?,?,?,?,?)
+			  ON CONFLICT(course_id) DO UPDATE SET mode = excluded.mode, enrollment_key = excluded.enrollment_key, capacity = excluded.capacity,
+			  starts_at = excluded.starts_at, ends_at = excluded.ends_at`
+	_, err := tx.ExecContext(
+		ctx,
+		bind(query),
+		settings.CourseId,
+		settings.Mode,
+		settings.Key,
+		settings.Capacity,
+		settings.StartsAt,
+		settings.EndsAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindAllRequests returns the waitlist in the order it is served followed by the requests pending approval
+func (repository *enrollmentRepository) FindAllRequests(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.EnrollmentRequests, error) {
+	query := `SELECT er.id, er.user_id, er.course_id, er.status, er.created_at, er.waitlisted_at, u.name, u.username
+			  FROM enrollment_requests er
+			  JOIN users u ON u.id = er.user_id
+			  WHERE er.course_id = ?
+			  ORDER BY CASE WHEN er.status = 'waitlisted' THEN 0 ELSE 1 END, COALESCE(er.waitlisted_at, er.created_at), er.id`
+	queryContext, err := tx.QueryContext(ctx, bind(query), courseId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var requests []entity.EnrollmentRequests
+	for queryContext.Next() {
+		var request entity.EnrollmentRequests
+		err := queryContext.Scan(
+			&request.Id,
+			&request.UserId,
+			&request.CourseId,
+			&request.Status,
+			&request.CreatedAt,
+			&request.WaitlistedAt,
+			&request.Name,
+			&request.Username,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+func (repository *enrollmentRepository) FindRequestById(ctx context.Context, tx *sql.Tx, courseId int, id int) (entity.EnrollmentRequests, error) {
+	query := `SELECT er.id, er.user_id, er.course_id, er.status, er.created_at, er.waitlisted_at, u.name, u.username
+			  FROM enrollment_requests er
+			  JOIN users u ON u.id = er.user_id
+			  WHERE er.course_id = ? AND er.id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), courseId, id)
+	if err != nil {
+		return entity.EnrollmentRequests{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var request entity.EnrollmentRequests
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&request.Id,
+			&request.UserId,
+			&request.CourseId,
+			&request.Status,
+			&request.CreatedAt,
+			&request.WaitlistedAt,
+			&request.Name,
+			&request.Username,
+		)
+		if err != nil {
+			return entity.EnrollmentRequests{}, err
+		}
+
+		return request, nil
+	}
+
+	return request, errors.New("enrollment request not found")
+}
+
+func (repository *enrollmentRepository) FindRequestByUser(ctx context.Context, tx *sql.Tx, courseId int, userId int) (entity.EnrollmentRequests, error) {
+	query := `SELECT er.id, er.user_id, er.course_id, er.status, er.created_at, er.waitlisted_at, u.name, u.username
+			  FROM enrollment_requests er
+			  JOIN users u ON u.id = er.user_id
+			  WHERE er.course_id = ? AND er.user_id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), courseId, userId)
+	if err != nil {
+		return entity.EnrollmentRequests{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var request entity.EnrollmentRequests
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&request.Id,
+			&request.UserId,
+			&request.CourseId,
+			&request.Status,
+			&request.CreatedAt,
+			&request.WaitlistedAt,
+			&request.Name,
+			&request.Username,
+		)
+		if err != nil {
+			return entity.EnrollmentRequests{}, err
+		}
+
+		return request, nil
+	}
+
+	return request, errors.New("enrollment request not found")
+}
+
+func (repository *enrollmentRepository) CreateRequest(ctx context.Context, tx *sql.Tx, request entity.EnrollmentRequests) (entity.EnrollmentRequests, error) {
+	query := `INSERT INTO enrollment_requests(user_id, course_id, status, created_at, waitlisted_at) VALUES(?,?,?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
+		ctx,
+		bind(query),
+		request.UserId,
+		request.CourseId,
+		request.Status,
+		request.CreatedAt,
+		request.WaitlistedAt,
+	).Scan(&id)
+	if err != nil {
+		return entity.EnrollmentRequests{}, err
+	}
+	request.Id = id
+
+	return request, nil
+}
+
+func (repository *enrollmentRepository) UpdateRequest(ctx context.Context, tx *sql.Tx, request entity.EnrollmentRequests) error {
+	query := `UPDATE enrollment_requests SET status = ?, waitlisted_at = ? WHERE id = ?`
+	_, err := tx.ExecContext(
+		ctx,
+		bind(query),
+		request.Status,
+		request.WaitlistedAt,
+		request.Id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repository *enrollmentRepository) DeleteRequest(ctx context.Context, tx *sql.Tx, id int) error {
+	query := `DELETE FROM enrollment_requests WHERE id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
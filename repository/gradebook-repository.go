@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+)
+
+type GradebookRepository interface {
+	FindAllCategories(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.GradeCategories, error)
+	FindCategoryById(ctx context.Context, tx *sql.Tx, courseId int, id int) (entity.GradeCategories, error)
+	CreateCategory(ctx context.Context, tx *sql.Tx, category entity.GradeCategories) (entity.GradeCategories, error)
+	UpdateCategory(ctx context.Context, tx *sql.Tx, category entity.GradeCategories) (entity.GradeCategories, error)
+	DeleteCategory(ctx context.Context, tx *sql.Tx, id int) error
+	FindAllBands(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.GradeBands, error)
+	SaveBands(ctx context.Context, tx *sql.Tx, courseId int, bands []entity.GradeBands) error
+	FindAllGrades(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.GradebookGrades, error)
+}
+
+type gradebookRepository struct {
+}
+
+func NewGradebookRepository() GradebookRepository {
+	return &gradebookRepository{}
+}
+
+func (repository *gradebookRepository) FindAllCategories(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.GradeCategories, error) {
+	query := `SELECT id, course_id, name, weight, drop_lowest FROM grade_categories WHERE course_id = ? ORDER BY id`
+	queryContext, err := tx.QueryContext(ctx, bind(query), courseId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var categories []entity.GradeCategories
+	for queryContext.Next() {
+		var category entity.GradeCategories
+		err := queryContext.Scan(
+			&category.Id,
+			&category.CourseId,
+			&category.Name,
+			&category.Weight,
+			&category.DropLowest,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		categories = append(categories, category)
+	}
+
+	return categories, nil
+}
+
+func (repository *gradebookRepository) FindCategoryById(ctx context.Context, tx *sql.Tx, courseId int, id int) (entity.GradeCategories, error) {
+	query := `SELECT id, course_id, name, weight, drop_lowest FROM grade_categories WHERE course_id = ? AND id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), courseId, id)
+	if err != nil {
+		return entity.GradeCategories{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var category entity.GradeCategories
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&category.Id,
+			&category.CourseId,
+			&category.Name,
+			&category.Weight,
+			&category.DropLowest,
+		)
+		if err != nil {
+			return entity.GradeCategories{}, err
+		}
+
+		return category, nil
+	}
+
+	return category, errors.New("grade category not found")
+}
+
+func (repository *gradebookRepository) CreateCategory(ctx context.Context, tx *sql.Tx, category entity.GradeCategories) (entity.GradeCategories, error) {
+	query := `INSERT INTO grade_categories(course_id, name, weight, drop_lowest) VALUES(?,?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
+		ctx,
+		bind(query),
+		category.CourseId,
+		category.Name,
+		category.Weight,
+		category.DropLowest,
+	).Scan(&id)
+	if err != nil {
+		return entity.GradeCategories{}, err
+	}
+	category.Id = id
+
+	return category, nil
+}
+
+func (repository *gradebookRepository) UpdateCategory(ctx context.Context, tx *sql.Tx, category entity.GradeCategories) (entity.GradeCategories, error) {
+	query := `UPDATE grade_categories SET name = ?, weight = ?, drop_lowest = ? WHERE id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), category.Name, category.Weight, category.DropLowest, category.Id)
+	if err != nil {
+		return entity.GradeCategories{}, err
+	}
+
+	return category, nil
+}
+
+// DeleteCategory removes the category, its module submissions are left without one
+func (repository *gradebookRepository) DeleteCategory(ctx context.Context, tx *sql.Tx, id int) error {
+	query := `UPDATE module_submissions SET category_id = NULL WHERE category_id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), id)
+	if err != nil {
+		return err
+	}
+
+	query = `DELETE FROM grade_categories WHERE id = ?`
+	_, err = tx.ExecContext(ctx, bind(query), id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindAllBands returns the letter bands of a course, the highest first
+func (repository *gradebookRepository) FindAllBands(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.GradeBands, error) {
+	query := `SELECT course_id, letter, min_score FROM grade_bands WHERE course_id = ? ORDER BY min_score DESC`
+	queryContext, err := tx.QueryContext(ctx, bind(query), courseId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var bands []entity.GradeBands
+	for queryContext.Next() {
+		var band entity.GradeBands
+		err := queryContext.Scan(
+			&band.CourseId,
+			&band.Letter,
+			&band.MinScore,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		bands = append(bands, band)
+	}
+
+	return bands, nil
+}
+
+// SaveBands replaces the letter bands of a course
+func (repository *gradebookRepository) SaveBands(ctx context.Context, tx *sql.Tx, courseId int, bands []entity.GradeBands) error {
+	query := `DELETE FROM grade_bands WHERE course_id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), courseId)
+	if err != nil {
+		return err
+	}
+
+	query = `INSERT INTO grade_bands(course_id, letter, min_score) VALUES(?,?,?)`
+	for _, band := range bands {
+		_, err = tx.ExecContext(ctx, bind(query), courseId, band.Letter, band.MinScore)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FindAllGrades returns the grades of every student of a course, with the time of the graded attempt and the
+// deadline of their extension so the late penalty can be applied
+func (repository *gradebookRepository) FindAllGrades(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.GradebookGrades, error) {
+	query := `SELECT us.user_id, us.module_submission_id, us.grade, sa.created_at, se.deadline FROM user_submissions us
+			  JOIN module_submissions ms ON ms.id = us.module_submission_id
+			  LEFT JOIN submission_attempts sa ON sa.id = COALESCE(us.graded_attempt_id, (SELECT MAX(id) FROM submission_attempts WHERE user_submission_id = us.id))
+			  LEFT JOIN submission_extensions se ON se.module_submission_id = ms.id AND se.user_id = us.user_id
+			  WHERE ms.course_id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), courseId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var grades []entity.GradebookGrades
+	for queryContext.Next() {
+		var grade entity.GradebookGrades
+		err := queryContext.Scan(
+			&grade.UserId,
+			&grade.ModuleSubmissionId,
+			&grade.Grade,
+			&grade.SubmittedAt,
+			&grade.ExtendedDeadline,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		grades = append(grades, grade)
+	}
+
+	return grades, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+)
+
+// listFilter maps a filter parameter to a column, Contains matches a case-insensitive substring instead of the exact value
+type listFilter struct {
+	Column   string
+	Contains bool
+	Parse    func(value string) (interface{}, error)
+}
+
+// listSpec whitelists the fields a list query may sort and filter on and maps them to columns
+type listSpec struct {
+	Sorts       map[string]string
+	DefaultSort string
+	// Tiebreak keeps the order stable between pages when the sorted column has duplicates, it is always ascending
+	Tiebreak string
+	Filters  map[string]listFilter
+}
+
+func parseString(value string) (interface{}, error) {
+	return value, nil
+}
+
+func parseInt(value string) (interface{}, error) {
+	return strconv.Atoi(value)
+}
+
+func parseBool(value string) (interface{}, error) {
+	return strconv.ParseBool(value)
+}
+
+// likeEscaper escapes the wildcards of LIKE, so a Contains filter matches them as they are
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// where renders the WHERE clause and its arguments for the filters of query, filters the spec does not know are refused
+// like unknown sort fields
+func (spec listSpec) where(query model.ListQuery) (string, []interface{}, error) {
+	var names []string
+	for name := range query.Filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var conditions []string
+	var args []interface{}
+	for _, name := range names {
+		filter, ok := spec.Filters[name]
+		if !ok {
+			return "", nil, fmt.Errorf("%w: cannot filter by %v", model.ErrInvalidListQuery, name)
+		}
+		value, err := filter.Parse(query.Filters[name])
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %v must be a valid value", model.ErrInvalidListQuery, name)
+		}
+
+		if filter.Contains {
+			conditions = append(conditions, "LOWER("+filter.Column+`) LIKE ? ESCAPE '\'`)
+			args = append(args, "%"+likeEscaper.Replace(strings.ToLower(fmt.Sprint(value)))+"%")
+			continue
+		}
+		conditions = append(conditions, filter.Column+" = ?")
+		args = append(args, value)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil, nil
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+// orderBy renders the ORDER BY, LIMIT and OFFSET clauses, their arguments are the query limit and offset
+func (spec listSpec) orderBy(query model.ListQuery) (string, error) {
+	field := query.Sort
+	if field == "" {
+		field = spec.DefaultSort
+	}
+
+	direction := "ASC"
+	if strings.HasPrefix(field, "-") {
+		direction = "DESC"
+		field = strings.TrimPrefix(field, "-")
+	}
+
+	column, ok := spec.Sorts[field]
+	if !ok {
+		return "", fmt.Errorf("%w: cannot sort by %v", model.ErrInvalidListQuery, field)
+	}
+
+	return " ORDER BY " + column + " " + direction + ", " + spec.Tiebreak + " ASC LIMIT ? OFFSET ?", nil
+}
@@ -25,8 +25,8 @@ func NewModuleArticlesRepository() ModuleArticlesRepository {
 }
 
 func (repository *moduleArticlesRepository) FindAll(ctx context.Context, tx *sql.Tx, idCourse int) ([]entity.ModuleArticles, error) {
-	query := `SELECT * FROM module_articles WHERE course_id = ?`
-	queryContext, err := tx.QueryContext(ctx, query, idCourse)
+	query := `SELECT id, course_id, name, content, estimate FROM module_articles WHERE course_id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), idCourse)
 	if err != nil {
 		return nil, err
 	}
@@ -58,8 +58,8 @@ func (repository *moduleArticlesRepository) FindAll(ctx context.Context, tx *sql
 }
 
 func (repository *moduleArticlesRepository) FindByModId(ctx context.Context, tx *sql.Tx, idCourse int, idArticle int) (entity.ModuleArticles, error) {
-	query := `SELECT * FROM module_articles WHERE course_id = ? AND id = ?`
-	queryContext, err := tx.QueryContext(ctx, query, idCourse, idArticle)
+	query := `SELECT id, course_id, name, content, estimate FROM module_articles WHERE course_id = ? AND id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), idCourse, idArticle)
 	if err != nil {
 		return entity.ModuleArticles{}, err
 	}
@@ -90,24 +90,20 @@ func (repository *moduleArticlesRepository) FindByModId(ctx context.Context, tx
 }
 
 func (repository *moduleArticlesRepository) Create(ctx context.Context, tx *sql.Tx, ModArs entity.ModuleArticles) (entity.ModuleArticles, error) {
-	query := `INSERT INTO module_articles(course_id,name,content,estimate) VALUES(?,?,?,?)`
-	queryContext, err := tx.ExecContext(
+	query := `INSERT INTO module_articles(course_id,name,content,estimate) VALUES(?,?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
 		ctx,
-		query,
+		bind(query),
 		ModArs.CourseId,
 		ModArs.Name,
 		ModArs.Content,
 		ModArs.Estimate,
-	)
-	if err != nil {
-		return entity.ModuleArticles{}, err
-	}
-
-	id, err := queryContext.LastInsertId()
+	).Scan(&id)
 	if err != nil {
 		return entity.ModuleArticles{}, err
 	}
-	ModArs.Id = int(id)
+	ModArs.Id = id
 
 	return ModArs, nil
 }
@@ -116,7 +112,7 @@ func (repository *moduleArticlesRepository) Update(ctx context.Context, tx *sql.
 	query := `UPDATE module_articles SET name = ?, content = ?, estimate = ? WHERE id = ?`
 	_, err := tx.ExecContext(
 		ctx,
-		query,
+		bind(query),
 		ModArs.Name,
 		ModArs.Content,
 		ModArs.Estimate,
@@ -131,7 +127,7 @@ func (repository *moduleArticlesRepository) Update(ctx context.Context, tx *sql.
 
 func (repository *moduleArticlesRepository) Delete(ctx context.Context, tx *sql.Tx, idArticle int) error {
 	query := "DELETE FROM module_articles WHERE id = ?"
-	_, err := tx.ExecContext(ctx, query, idArticle)
+	_, err := tx.ExecContext(ctx, bind(query), idArticle)
 	if err != nil {
 		return err
 	}
@@ -147,7 +143,7 @@ func (repository *moduleArticlesRepository) Next(ctx context.Context, tx *sql.Tx
 			  LEFT JOIN courses c ON c.id = ma.course_id 
 			  WHERE ma.id > ? AND ma.course_id = ?
 			  LIMIT 1`
-	queryContext, err := tx.QueryContext(ctx, query, idArticle, idCourse)
+	queryContext, err := tx.QueryContext(ctx, bind(query), idArticle, idCourse)
 	if err != nil {
 		return entity.NextPreviousModuleArticles{}, err
 	}
@@ -183,7 +179,7 @@ func (repository *moduleArticlesRepository) Previous(ctx context.Context, tx *sq
 			  WHERE ma.id < ? AND ma.course_id = ?
 			  ORDER BY ma.id DESC
 			  LIMIT 1`
-	queryContext, err := tx.QueryContext(ctx, query, idArticle, idCourse)
+	queryContext, err := tx.QueryContext(ctx, bind(query), idArticle, idCourse)
 	if err != nil {
 		return entity.NextPreviousModuleArticles{}, err
 	}
@@ -25,8 +25,8 @@ func NewModuleSubmissionsRepository() ModuleSubmissionsRepository {
 }
 
 func (repository *moduleSubmissionsRepository) FindAll(ctx context.Context, tx *sql.Tx, idCourse int) ([]entity.ModuleSubmissions, error) {
-	query := `SELECT * FROM module_submissions WHERE course_id = ?`
-	queryContext, err := tx.QueryContext(ctx, query, idCourse)
+	query := `SELECT id, course_id, name, description, deadline, allowed_types, max_size, late_policy, grace_period, penalty_per_day, category_id FROM module_submissions WHERE course_id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), idCourse)
 	if err != nil {
 		return nil, err
 	}
@@ -46,6 +46,12 @@ func (repository *moduleSubmissionsRepository) FindAll(ctx context.Context, tx *
 			&modsub.Name,
 			&modsub.Description,
 			&modsub.Deadline,
+			&modsub.AllowedTypes,
+			&modsub.MaxSize,
+			&modsub.LatePolicy,
+			&modsub.GracePeriod,
+			&modsub.PenaltyPerDay,
+			&modsub.CategoryId,
 		)
 		if err != nil {
 			return nil, err
@@ -58,8 +64,8 @@ func (repository *moduleSubmissionsRepository) FindAll(ctx context.Context, tx *
 }
 
 func (repository *moduleSubmissionsRepository) FindByModId(ctx context.Context, tx *sql.Tx, idCourse int, idSubmission int) (entity.ModuleSubmissions, error) {
-	query := `SELECT * FROM module_submissions WHERE course_id = ? AND id = ?`
-	queryContext, err := tx.QueryContext(ctx, query, idCourse, idSubmission)
+	query := `SELECT id, course_id, name, description, deadline, allowed_types, max_size, late_policy, grace_period, penalty_per_day, category_id FROM module_submissions WHERE course_id = ? AND id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), idCourse, idSubmission)
 	if err != nil {
 		return entity.ModuleSubmissions{}, err
 	}
@@ -78,6 +84,12 @@ func (repository *moduleSubmissionsRepository) FindByModId(ctx context.Context,
 			&modsub.Name,
 			&modsub.Description,
 			&modsub.Deadline,
+			&modsub.AllowedTypes,
+			&modsub.MaxSize,
+			&modsub.LatePolicy,
+			&modsub.GracePeriod,
+			&modsub.PenaltyPerDay,
+			&modsub.CategoryId,
 		)
 		if err != nil {
 			return entity.ModuleSubmissions{}, err
@@ -90,36 +102,44 @@ func (repository *moduleSubmissionsRepository) FindByModId(ctx context.Context,
 }
 
 func (repository *moduleSubmissionsRepository) Create(ctx context.Context, tx *sql.Tx, modsub entity.ModuleSubmissions) (entity.ModuleSubmissions, error) {
-	query := `INSERT INTO module_submissions(course_id, name, description, deadline) VALUES(?,?,?,?)`
-	queryContext, err := tx.ExecContext(
+	query := `INSERT INTO module_submissions(course_id, name, description, deadline, allowed_types, max_size, late_policy, grace_period, penalty_per_day, category_id) VALUES(?,?,?,?,?,?,?,?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
 		ctx,
-		query,
+		bind(query),
 		modsub.CourseId,
 		modsub.Name,
 		modsub.Description,
 		modsub.Deadline,
-	)
-	if err != nil {
-		return entity.ModuleSubmissions{}, err
-	}
-
-	id, err := queryContext.LastInsertId()
+		modsub.AllowedTypes,
+		modsub.MaxSize,
+		modsub.LatePolicy,
+		modsub.GracePeriod,
+		modsub.PenaltyPerDay,
+		modsub.CategoryId,
+	).Scan(&id)
 	if err != nil {
 		return entity.ModuleSubmissions{}, err
 	}
-	modsub.Id = int(id)
+	modsub.Id = id
 
 	return modsub, nil
 }
 
 func (repository *moduleSubmissionsRepository) Update(ctx context.Context, tx *sql.Tx, modsub entity.ModuleSubmissions, idSubmission int) (entity.ModuleSubmissions, error) {
-	query := `UPDATE module_submissions SET name = ?, description = ?, deadline = ? WHERE id = ?`
+	query := `UPDATE module_submissions SET name = ?, description = ?, deadline = ?, allowed_types = ?, max_size = ?, late_policy = ?, grace_period = ?, penalty_per_day = ?, category_id = ? WHERE id = ?`
 	_, err := tx.ExecContext(
 		ctx,
-		query,
+		bind(query),
 		modsub.Name,
 		modsub.Description,
 		modsub.Deadline,
+		modsub.AllowedTypes,
+		modsub.MaxSize,
+		modsub.LatePolicy,
+		modsub.GracePeriod,
+		modsub.PenaltyPerDay,
+		modsub.CategoryId,
 		idSubmission,
 	)
 	if err != nil {
@@ -131,7 +151,7 @@ func (repository *moduleSubmissionsRepository) Update(ctx context.Context, tx *s
 
 func (repository *moduleSubmissionsRepository) Delete(ctx context.Context, tx *sql.Tx, idSubmission int) error {
 	query := "DELETE FROM module_submissions WHERE id = ?"
-	_, err := tx.ExecContext(ctx, query, idSubmission)
+	_, err := tx.ExecContext(ctx, bind(query), idSubmission)
 	if err != nil {
 		return err
 	}
@@ -147,7 +167,7 @@ func (repository *moduleSubmissionsRepository) Next(ctx context.Context, tx *sql
 			  LEFT JOIN courses c ON c.id = ma.course_id 
 			  WHERE ma.id > ? AND ma.course_id = ?
 			  LIMIT 1`
-	queryContext, err := tx.QueryContext(ctx, query, idSubmission, idCourse)
+	queryContext, err := tx.QueryContext(ctx, bind(query), idSubmission, idCourse)
 	if err != nil {
 		return entity.NextPreviousModuleSubmissions{}, err
 	}
@@ -183,7 +203,7 @@ func (repository *moduleSubmissionsRepository) Previous(ctx context.Context, tx
 			  WHERE ma.id < ? AND ma.course_id = ?
 			  ORDER BY ma.id DESC
 			  LIMIT 1`
-	queryContext, err := tx.QueryContext(ctx, query, idSubmission, idCourse)
+	queryContext, err := tx.QueryContext(ctx, bind(query), idSubmission, idCourse)
 	if err != nil {
 		return entity.NextPreviousModuleSubmissions{}, err
 	}
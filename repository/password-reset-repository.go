@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, passwordReset entity.PasswordResets) (entity.PasswordResets, error)
+	FindByHash(ctx context.Context, tx *sql.Tx, tokenHash string) (entity.PasswordResets, error)
+	MarkUsed(ctx context.Context, tx *sql.Tx, id int, usedAt int64) (bool, error)
+	MarkUsedByUser(ctx context.Context, tx *sql.Tx, userId int, usedAt int64) error
+}
+
+type passwordResetRepository struct {
+}
+
+func NewPasswordResetRepository() PasswordResetRepository {
+	return &passwordResetRepository{}
+}
+
+func (repository *passwordResetRepository) Create(ctx context.Context, tx *sql.Tx, passwordReset entity.PasswordResets) (entity.PasswordResets, error) {
+	query := `INSERT INTO password_resets(user_id, token_hash, expired_at, created_at) VALUES(?,?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
+		ctx,
+		bind(query),
+		passwordReset.UserId,
+		passwordReset.TokenHash,
+		passwordReset.ExpiredAt,
+		passwordReset.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return entity.PasswordResets{}, err
+	}
+	passwordReset.Id = id
+
+	return passwordReset, nil
+}
+
+func (repository *passwordResetRepository) FindByHash(ctx context.Context, tx *sql.Tx, tokenHash string) (entity.PasswordResets, error) {
+	query := `SELECT id, user_id, token_hash, used_at, expired_at, created_at FROM password_resets WHERE token_hash = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), tokenHash)
+	if err != nil {
+		return entity.PasswordResets{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var passwordReset entity.PasswordResets
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&passwordReset.Id,
+			&passwordReset.UserId,
+			&passwordReset.TokenHash,
+			&passwordReset.UsedAt,
+			&passwordReset.ExpiredAt,
+			&passwordReset.CreatedAt,
+		)
+		if err != nil {
+			return entity.PasswordResets{}, err
+		}
+
+		return passwordReset, nil
+	}
+
+	return passwordReset, errors.New("password reset not found")
+}
+
+// MarkUsed flags the token as used and reports false when it had already been used before
+func (repository *passwordResetRepository) MarkUsed(ctx context.Context, tx *sql.Tx, id int, usedAt int64) (bool, error) {
+	query := `UPDATE password_resets SET used_at = ? WHERE id = ? AND used_at IS NULL`
+	result, err := tx.ExecContext(ctx, bind(query), usedAt, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// MarkUsedByUser flags every token of the user that has not been used yet as used
+func (repository *passwordResetRepository) MarkUsedByUser(ctx context.Context, tx *sql.Tx, userId int, usedAt int64) error {
+	query := `UPDATE password_resets SET used_at = ? WHERE user_id = ? AND used_at IS NULL`
+	_, err := tx.ExecContext(ctx, bind(query), usedAt, userId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+)
+
+type ProgressRepository interface {
+	Open(ctx context.Context, tx *sql.Tx, progress entity.LearningProgress) error
+	Complete(ctx context.Context, tx *sql.Tx, progress entity.LearningProgress) error
+	FindAll(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.LearningProgress, error)
+	FindAllSubmitted(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.LearningProgress, error)
+}
+
+type progressRepository struct {
+}
+
+func NewProgressRepository() ProgressRepository {
+	return &progressRepository{}
+}
+
+func (repository *progressRepository) Open(ctx context.Context, tx *sql.Tx, progress entity.LearningProgress) error {
+	query := `INSERT INTO learning_progress(user_id, course_id, item_type, item_id, opens, visited_at) VALUES(?,?,?,?,1,?)
+			  ON CONFLICT(user_id, item_type, item_id) DO UPDATE SET opens = learning_progress.opens + 1, visited_at = excluded.visited_at`
+	_, err := tx.ExecContext(
+		ctx,
+		bind(query),
+		progress.UserId,
+		progress.CourseId,
+		progress.Type,
+		progress.ItemId,
+		progress.VisitedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Complete keeps the first time the item was completed
+func (repository *progressRepository) Complete(ctx context.Context, tx *sql.Tx, progress entity.LearningProgress) error {
+	query := `INSERT INTO learning_progress(user_id, course_id, item_type, item_id, opens, visited_at, completed_at) VALUES(?,?,?,?,0,?,?)
+			  ON CONFLICT(user_id, item_type, item_id) DO UPDATE SET visited_at = excluded.visited_at, completed_at = COALESCE(learning_progress.completed_at, excluded.completed_at)`
+	_, err := tx.ExecContext(
+		ctx,
+		bind(query),
+		progress.UserId,
+		progress.CourseId,
+		progress.Type,
+		progress.ItemId,
+		progress.VisitedAt,
+		progress.CompletedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repository *progressRepository) FindAll(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.LearningProgress, error) {
+	query := `SELECT user_id, course_id, item_type, item_id, opens, visited_at, completed_at FROM learning_progress WHERE course_id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), courseId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var progresses []entity.LearningProgress
+	for queryContext.Next() {
+		var progress entity.LearningProgress
+		err := queryContext.Scan(
+			&progress.UserId,
+			&progress.CourseId,
+			&progress.Type,
+			&progress.ItemId,
+			&progress.Opens,
+			&progress.VisitedAt,
+			&progress.CompletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		progresses = append(progresses, progress)
+	}
+
+	return progresses, nil
+}
+
+// FindAllSubmitted returns the module submissions a user submitted a file to and the quizzes a user submitted an
+// attempt of as completed, once for every submission
+func (repository *progressRepository) FindAllSubmitted(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.LearningProgress, error) {
+	query := `SELECT us.user_id, ms.course_id, 'submission', ms.id, sa.created_at
+			  FROM submission_attempts sa
+			  JOIN user_submissions us ON us.id = sa.user_submission_id
+			  JOIN module_submissions ms ON ms.id = us.module_submission_id
+			  WHERE ms.course_id = ?
+			  UNION ALL
+			  SELECT qa.user_id, q.course_id, 'quiz', q.id, qa.submitted_at
+			  FROM quiz_attempts qa
+			  JOIN quizzes q ON q.id = qa.quiz_id
+			  WHERE q.course_id = ? AND qa.submitted_at IS NOT NULL`
+	queryContext, err := tx.QueryContext(ctx, bind(query), courseId, courseId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var progresses []entity.LearningProgress
+	for queryContext.Next() {
+		var progress entity.LearningProgress
+		var completedAt time.Time
+		err := queryContext.Scan(
+			&progress.UserId,
+			&progress.CourseId,
+			&progress.Type,
+			&progress.ItemId,
+			&completedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		progress.CompletedAt = &completedAt
+
+		progresses = append(progresses, progress)
+	}
+
+	return progresses, nil
+}
@@ -5,10 +5,11 @@ import (
 	"database/sql"
 	"errors"
 	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
 )
 
 type QuestionRepository interface {
-	FindAll(ctx context.Context, tx *sql.Tx) ([]entity.QuestionCourse, error)
+	FindAll(ctx context.Context, tx *sql.Tx, query model.ListQuery) ([]entity.QuestionCourse, int, error)
 	Create(ctx context.Context, tx *sql.Tx, question entity.Questions) (entity.Questions, error)
 	Delete(ctx context.Context, tx *sql.Tx, questionId int) error
 	FindById(ctx context.Context, tx *sql.Tx, questionId int) (entity.QuestionCourse, error)
@@ -24,11 +25,12 @@ func NewQuestionRepository() QuestionRepository {
 }
 
 func (repository *questionRepository) Create(ctx context.Context, tx *sql.Tx, question entity.Questions) (entity.Questions, error) {
-	query := `INSERT INTO questions(user_id, course_id, title, tags, description, created_at, updated_at) VALUES(?,?,?,?,?,?,?)`
+	query := `INSERT INTO questions(user_id, course_id, title, tags, description, created_at, updated_at) VALUES(?,?,?,?,?,?,?) RETURNING id`
 
-	queryContext, err := tx.ExecContext(
+	var id int
+	err := tx.QueryRowContext(
 		ctx,
-		query,
+		bind(query),
 		question.UserId,
 		question.CourseId,
 		question.Title,
@@ -36,28 +38,51 @@ func (repository *questionRepository) Create(ctx context.Context, tx *sql.Tx, qu
 		question.Description,
 		question.CreatedAt,
 		question.UpdatedAt,
-	)
+	).Scan(&id)
 	if err != nil {
 		return entity.Questions{}, err
 	}
+	question.Id = id
 
-	id, err := queryContext.LastInsertId()
+	return question, nil
+}
+
+var questionList = listSpec{
+	Sorts: map[string]string{
+		"title":      "q.title",
+		"created_at": "q.created_at",
+	},
+	DefaultSort: "-created_at",
+	Tiebreak:    "q.id",
+	Filters: map[string]listFilter{
+		"tag":       {Column: "q.tags", Contains: true, Parse: parseString},
+		"course_id": {Column: "q.course_id", Parse: parseInt},
+		"user_id":   {Column: "q.user_id", Parse: parseInt},
+	},
+}
+
+func (repository *questionRepository) FindAll(ctx context.Context, tx *sql.Tx, query model.ListQuery) ([]entity.QuestionCourse, int, error) {
+	where, args, err := questionList.where(query)
 	if err != nil {
-		return entity.Questions{}, err
+		return nil, 0, err
+	}
+	orderBy, err := questionList.orderBy(query)
+	if err != nil {
+		return nil, 0, err
 	}
-	question.Id = int(id)
 
-	return question, nil
-}
+	var total int
+	err = tx.QueryRowContext(ctx, bind(`SELECT COUNT(*) FROM questions q`+where), args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
 
-func (repository *questionRepository) FindAll(ctx context.Context, tx *sql.Tx) ([]entity.QuestionCourse, error) {
-	query := `SELECT q.id,q.course_id,c.name,c.class,q.user_id,u.name,q.title,q.tags,q.description,q.created_at,q.updated_at FROM questions q
+	statement := `SELECT q.id,q.course_id,c.name,c.class,q.user_id,u.name,q.title,q.tags,q.description,q.created_at,q.updated_at FROM questions q
 			  LEFT JOIN courses c on c.id = q.course_id
-			  LEFT JOIN users u on u.id = q.user_id
-		      ORDER BY q.created_at DESC`
-	queryContext, err := tx.QueryContext(ctx, query)
+			  LEFT JOIN users u on u.id = q.user_id` + where + orderBy
+	queryContext, err := tx.QueryContext(ctx, bind(statement), append(args, query.Limit, query.Offset)...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer func(queryContext *sql.Rows) {
 		err := queryContext.Close()
@@ -83,18 +108,18 @@ func (repository *questionRepository) FindAll(ctx context.Context, tx *sql.Tx) (
 			&question.UpdatedAt,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		questions = append(questions, question)
 	}
 
-	return questions, nil
+	return questions, total, nil
 }
 
 func (repository *questionRepository) Delete(ctx context.Context, tx *sql.Tx, questionId int) error {
 	query := "DELETE FROM questions WHERE id = ?"
-	_, err := tx.ExecContext(ctx, query, questionId)
+	_, err := tx.ExecContext(ctx, bind(query), questionId)
 	if err != nil {
 		return err
 	}
@@ -108,7 +133,7 @@ func (repository *questionRepository) FindById(ctx context.Context, tx *sql.Tx,
 			  LEFT JOIN users u on u.id = q.user_id
 			  WHERE q.id = ?
 		      ORDER BY q.created_at DESC`
-	queryContext, err := tx.QueryContext(ctx, query, questionId)
+	queryContext, err := tx.QueryContext(ctx, bind(query), questionId)
 	if err != nil {
 		return entity.QuestionCourse{}, err
 	}
@@ -149,7 +174,7 @@ func (repository *questionRepository) Update(ctx context.Context, tx *sql.Tx, qu
 	query := `UPDATE questions SET course_id = ?, title = ?, tags = ?, description = ?, updated_at = ? WHERE id = ?`
 	_, err := tx.ExecContext(
 		ctx,
-		query,
+		bind(query),
 		question.CourseId,
 		question.Title,
 		question.Tags,
@@ -170,7 +195,7 @@ func (repository *questionRepository) FindByUserId(ctx context.Context, tx *sql.
 			  LEFT JOIN users u on u.id = q.user_id
 			  WHERE q.user_id = ?
 		      ORDER BY q.created_at DESC`
-	queryContext, err := tx.QueryContext(ctx, query, userId)
+	queryContext, err := tx.QueryContext(ctx, bind(query), userId)
 	if err != nil {
 		return []entity.QuestionCourse{}, err
 	}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+)
+
+type QuizAttemptRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, attempt entity.QuizAttempts) (entity.QuizAttempts, error)
+	FindAll(ctx context.Context, tx *sql.Tx, quizId int, userId int) ([]entity.QuizAttempts, error)
+	FindById(ctx context.Context, tx *sql.Tx, quizId int, id int) (entity.QuizAttempts, error)
+	Count(ctx context.Context, tx *sql.Tx, quizId int) (int, error)
+	Submit(ctx context.Context, tx *sql.Tx, attempt entity.QuizAttempts) error
+	SaveResponse(ctx context.Context, tx *sql.Tx, response entity.QuizResponses) error
+	FindResponses(ctx context.Context, tx *sql.Tx, attemptId int) ([]entity.QuizResponses, error)
+}
+
+type quizAttemptRepository struct {
+}
+
+func NewQuizAttemptRepository() QuizAttemptRepository {
+	return &quizAttemptRepository{}
+}
+
+func (repository *quizAttemptRepository) Create(ctx context.Context, tx *sql.Tx, attempt entity.QuizAttempts) (entity.QuizAttempts, error) {
+	query := `INSERT INTO quiz_attempts(quiz_id, user_id, question_order, started_at, deadline, max_score) VALUES(?,?,?,?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
+		ctx,
+		bind(query),
+		attempt.QuizId,
+		attempt.UserId,
+		attempt.QuestionOrder,
+		attempt.StartedAt,
+		attempt.Deadline,
+		attempt.MaxScore,
+	).Scan(&id)
+	if err != nil {
+		return entity.QuizAttempts{}, err
+	}
+	attempt.Id = id
+
+	return attempt, nil
+}
+
+// FindAll returns the attempts of a student at a quiz, the first attempt comes first
+func (repository *quizAttemptRepository) FindAll(ctx context.Context, tx *sql.Tx, quizId int, userId int) ([]entity.QuizAttempts, error) {
+	query := `SELECT id, quiz_id, user_id, question_order, started_at, deadline, submitted_at, score, max_score FROM quiz_attempts WHERE quiz_id = ? AND user_id = ? ORDER BY id`
+	queryContext, err := tx.QueryContext(ctx, bind(query), quizId, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var attempts []entity.QuizAttempts
+	for queryContext.Next() {
+		var attempt entity.QuizAttempts
+		err := queryContext.Scan(
+			&attempt.Id,
+			&attempt.QuizId,
+			&attempt.UserId,
+			&attempt.QuestionOrder,
+			&attempt.StartedAt,
+			&attempt.Deadline,
+			&attempt.SubmittedAt,
+			&attempt.Score,
+			&attempt.MaxScore,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, nil
+}
+
+func (repository *quizAttemptRepository) FindById(ctx context.Context, tx *sql.Tx, quizId int, id int) (entity.QuizAttempts, error) {
+	query := `SELECT id, quiz_id, user_id, question_order, started_at, deadline, submitted_at, score, max_score FROM quiz_attempts WHERE quiz_id = ? AND id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), quizId, id)
+	if err != nil {
+		return entity.QuizAttempts{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var attempt entity.QuizAttempts
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&attempt.Id,
+			&attempt.QuizId,
+			&attempt.UserId,
+			&attempt.QuestionOrder,
+			&attempt.StartedAt,
+			&attempt.Deadline,
+			&attempt.SubmittedAt,
+			&attempt.Score,
+			&attempt.MaxScore,
+		)
+		if err != nil {
+			return entity.QuizAttempts{}, err
+		}
+
+		return attempt, nil
+	}
+
+	return attempt, errors.New("quiz attempt not found")
+}
+
+// Count returns how many attempts were started at a quiz by every student
+func (repository *quizAttemptRepository) Count(ctx context.Context, tx *sql.Tx, quizId int) (int, error) {
+	query := `SELECT COUNT(*) FROM quiz_attempts WHERE quiz_id = ?`
+	var count int
+	err := tx.QueryRowContext(ctx, bind(query), quizId).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (repository *quizAttemptRepository) Submit(ctx context.Context, tx *sql.Tx, attempt entity.QuizAttempts) error {
+	query := `UPDATE quiz_attempts SET submitted_at = ?, score = ? WHERE id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), attempt.SubmittedAt, attempt.Score, attempt.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repository *quizAttemptRepository) SaveResponse(ctx context.Context, tx *sql.Tx, response entity.QuizResponses) error {
+	query := `INSERT INTO quiz_responses(attempt_id, question_id, answer, correct, points) VALUES(?,?,?,?,?)`
+	_, err := tx.ExecContext(
+		ctx,
+		bind(query),
+		response.AttemptId,
+		response.QuestionId,
+		response.Answer,
+		response.Correct,
+		response.Points,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repository *quizAttemptRepository) FindResponses(ctx context.Context, tx *sql.Tx, attemptId int) ([]entity.QuizResponses, error) {
+	query := `SELECT attempt_id, question_id, answer, correct, points FROM quiz_responses WHERE attempt_id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), attemptId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var responses []entity.QuizResponses
+	for queryContext.Next() {
+		var response entity.QuizResponses
+		err := queryContext.Scan(
+			&response.AttemptId,
+			&response.QuestionId,
+			&response.Answer,
+			&response.Correct,
+			&response.Points,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
@@ -0,0 +1,364 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+)
+
+type QuizRepository interface {
+	FindAll(ctx context.Context, tx *sql.Tx, idCourse int) ([]entity.Quizzes, error)
+	FindById(ctx context.Context, tx *sql.Tx, idCourse int, id int) (entity.Quizzes, error)
+	Create(ctx context.Context, tx *sql.Tx, quiz entity.Quizzes) (entity.Quizzes, error)
+	Update(ctx context.Context, tx *sql.Tx, quiz entity.Quizzes) (entity.Quizzes, error)
+	Delete(ctx context.Context, tx *sql.Tx, id int) error
+	Next(ctx context.Context, tx *sql.Tx, idCourse int, id int) (entity.NextPreviousQuizzes, error)
+	Previous(ctx context.Context, tx *sql.Tx, idCourse int, id int) (entity.NextPreviousQuizzes, error)
+	CreateQuestion(ctx context.Context, tx *sql.Tx, question entity.QuizQuestions) (entity.QuizQuestions, error)
+	CreateChoice(ctx context.Context, tx *sql.Tx, choice entity.QuizChoices) (entity.QuizChoices, error)
+	DeleteQuestions(ctx context.Context, tx *sql.Tx, quizId int) error
+	FindQuestions(ctx context.Context, tx *sql.Tx, quizId int) ([]entity.QuizQuestions, error)
+	FindChoices(ctx context.Context, tx *sql.Tx, quizId int) ([]entity.QuizChoices, error)
+}
+
+type quizRepository struct {
+}
+
+func NewQuizRepository() QuizRepository {
+	return &quizRepository{}
+}
+
+func (repository *quizRepository) FindAll(ctx context.Context, tx *sql.Tx, idCourse int) ([]entity.Quizzes, error) {
+	query := `SELECT id, course_id, name, description, time_limit, max_attempts, shuffle_questions FROM quizzes WHERE course_id = ? ORDER BY id`
+	queryContext, err := tx.QueryContext(ctx, bind(query), idCourse)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var quizzes []entity.Quizzes
+	for queryContext.Next() {
+		var quiz entity.Quizzes
+		err := queryContext.Scan(
+			&quiz.Id,
+			&quiz.CourseId,
+			&quiz.Name,
+			&quiz.Description,
+			&quiz.TimeLimit,
+			&quiz.MaxAttempts,
+			&quiz.ShuffleQuestions,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		quizzes = append(quizzes, quiz)
+	}
+
+	return quizzes, nil
+}
+
+func (repository *quizRepository) FindById(ctx context.Context, tx *sql.Tx, idCourse int, id int) (entity.Quizzes, error) {
+	query := `SELECT id, course_id, name, description, time_limit, max_attempts, shuffle_questions FROM quizzes WHERE course_id = ? AND id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), idCourse, id)
+	if err != nil {
+		return entity.Quizzes{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var quiz entity.Quizzes
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&quiz.Id,
+			&quiz.CourseId,
+			&quiz.Name,
+			&quiz.Description,
+			&quiz.TimeLimit,
+			&quiz.MaxAttempts,
+			&quiz.ShuffleQuestions,
+		)
+		if err != nil {
+			return entity.Quizzes{}, err
+		}
+
+		return quiz, nil
+	}
+
+	return quiz, errors.New("quiz not found")
+}
+
+func (repository *quizRepository) Create(ctx context.Context, tx *sql.Tx, quiz entity.Quizzes) (entity.Quizzes, error) {
+	query := `INSERT INTO quizzes(course_id, name, description, time_limit, max_attempts, shuffle_questions) VALUES(?,?,?,?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
+		ctx,
+		bind(query),
+		quiz.CourseId,
+		quiz.Name,
+		quiz.Description,
+		quiz.TimeLimit,
+		quiz.MaxAttempts,
+		quiz.ShuffleQuestions,
+	).Scan(&id)
+	if err != nil {
+		return entity.Quizzes{}, err
+	}
+	quiz.Id = id
+
+	return quiz, nil
+}
+
+func (repository *quizRepository) Update(ctx context.Context, tx *sql.Tx, quiz entity.Quizzes) (entity.Quizzes, error) {
+	query := `UPDATE quizzes SET name = ?, description = ?, time_limit = ?, max_attempts = ?, shuffle_questions = ? WHERE id = ?`
+	_, err := tx.ExecContext(
+		ctx,
+		bind(query),
+		quiz.Name,
+		quiz.Description,
+		quiz.TimeLimit,
+		quiz.MaxAttempts,
+		quiz.ShuffleQuestions,
+		quiz.Id,
+	)
+	if err != nil {
+		return entity.Quizzes{}, err
+	}
+
+	return quiz, nil
+}
+
+// Delete removes the quiz with its questions, attempts and grade records
+func (repository *quizRepository) Delete(ctx context.Context, tx *sql.Tx, id int) error {
+	queries := []string{
+		`DELETE FROM quiz_responses WHERE attempt_id IN (SELECT id FROM quiz_attempts WHERE quiz_id = ?)`,
+		`DELETE FROM user_quizzes WHERE quiz_id = ?`,
+		`DELETE FROM quiz_attempts WHERE quiz_id = ?`,
+		`DELETE FROM quiz_choices WHERE question_id IN (SELECT id FROM quiz_questions WHERE quiz_id = ?)`,
+		`DELETE FROM quiz_questions WHERE quiz_id = ?`,
+		`DELETE FROM quizzes WHERE id = ?`,
+	}
+	for _, query := range queries {
+		_, err := tx.ExecContext(ctx, bind(query), id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repository *quizRepository) Next(ctx context.Context, tx *sql.Tx, idCourse int, id int) (entity.NextPreviousQuizzes, error) {
+	query := `SELECT 
+				q.id,
+				c.code_course
+			  FROM quizzes q
+			  LEFT JOIN courses c ON c.id = q.course_id 
+			  WHERE q.id > ? AND q.course_id = ?
+			  ORDER BY q.id
+			  LIMIT 1`
+	queryContext, err := tx.QueryContext(ctx, bind(query), id, idCourse)
+	if err != nil {
+		return entity.NextPreviousQuizzes{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var quiz entity.NextPreviousQuizzes
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&quiz.Id,
+			&quiz.CodeCourse,
+		)
+		if err != nil {
+			return entity.NextPreviousQuizzes{}, err
+		}
+
+		return quiz, nil
+	}
+
+	return quiz, errors.New("quiz not found")
+}
+
+func (repository *quizRepository) Previous(ctx context.Context, tx *sql.Tx, idCourse int, id int) (entity.NextPreviousQuizzes, error) {
+	query := `SELECT 
+				q.id,
+				c.code_course
+			  FROM quizzes q
+			  LEFT JOIN courses c ON c.id = q.course_id 
+			  WHERE q.id < ? AND q.course_id = ?
+			  ORDER BY q.id DESC
+			  LIMIT 1`
+	queryContext, err := tx.QueryContext(ctx, bind(query), id, idCourse)
+	if err != nil {
+		return entity.NextPreviousQuizzes{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var quiz entity.NextPreviousQuizzes
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&quiz.Id,
+			&quiz.CodeCourse,
+		)
+		if err != nil {
+			return entity.NextPreviousQuizzes{}, err
+		}
+
+		return quiz, nil
+	}
+
+	return quiz, errors.New("quiz not found")
+}
+
+func (repository *quizRepository) CreateQuestion(ctx context.Context, tx *sql.Tx, question entity.QuizQuestions) (entity.QuizQuestions, error) {
+	query := `INSERT INTO quiz_questions(quiz_id, type, prompt, points, position, numeric_answer, tolerance) VALUES(?,?,?,?,?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
+		ctx,
+		bind(query),
+		question.QuizId,
+		question.Type,
+		question.Prompt,
+		question.Points,
+		question.Position,
+		question.NumericAnswer,
+		question.Tolerance,
+	).Scan(&id)
+	if err != nil {
+		return entity.QuizQuestions{}, err
+	}
+	question.Id = id
+
+	return question, nil
+}
+
+func (repository *quizRepository) CreateChoice(ctx context.Context, tx *sql.Tx, choice entity.QuizChoices) (entity.QuizChoices, error) {
+	query := `INSERT INTO quiz_choices(question_id, content, correct, position) VALUES(?,?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
+		ctx,
+		bind(query),
+		choice.QuestionId,
+		choice.Content,
+		choice.Correct,
+		choice.Position,
+	).Scan(&id)
+	if err != nil {
+		return entity.QuizChoices{}, err
+	}
+	choice.Id = id
+
+	return choice, nil
+}
+
+// DeleteQuestions removes the questions of a quiz and their choices
+func (repository *quizRepository) DeleteQuestions(ctx context.Context, tx *sql.Tx, quizId int) error {
+	query := `DELETE FROM quiz_choices WHERE question_id IN (SELECT id FROM quiz_questions WHERE quiz_id = ?)`
+	_, err := tx.ExecContext(ctx, bind(query), quizId)
+	if err != nil {
+		return err
+	}
+
+	query = `DELETE FROM quiz_questions WHERE quiz_id = ?`
+	_, err = tx.ExecContext(ctx, bind(query), quizId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindQuestions returns the questions of a quiz in the order the teacher wrote them
+func (repository *quizRepository) FindQuestions(ctx context.Context, tx *sql.Tx, quizId int) ([]entity.QuizQuestions, error) {
+	query := `SELECT id, quiz_id, type, prompt, points, position, numeric_answer, tolerance FROM quiz_questions WHERE quiz_id = ? ORDER BY position, id`
+	queryContext, err := tx.QueryContext(ctx, bind(query), quizId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var questions []entity.QuizQuestions
+	for queryContext.Next() {
+		var question entity.QuizQuestions
+		err := queryContext.Scan(
+			&question.Id,
+			&question.QuizId,
+			&question.Type,
+			&question.Prompt,
+			&question.Points,
+			&question.Position,
+			&question.NumericAnswer,
+			&question.Tolerance,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		questions = append(questions, question)
+	}
+
+	return questions, nil
+}
+
+// FindChoices returns the choices of every question of a quiz
+func (repository *quizRepository) FindChoices(ctx context.Context, tx *sql.Tx, quizId int) ([]entity.QuizChoices, error) {
+	query := `SELECT qc.id, qc.question_id, qc.content, qc.correct, qc.position FROM quiz_choices qc
+			  JOIN quiz_questions qq ON qq.id = qc.question_id
+			  WHERE qq.quiz_id = ?
+			  ORDER BY qc.question_id, qc.position, qc.id`
+	queryContext, err := tx.QueryContext(ctx, bind(query), quizId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var choices []entity.QuizChoices
+	for queryContext.Next() {
+		var choice entity.QuizChoices
+		err := queryContext.Scan(
+			&choice.Id,
+			&choice.QuestionId,
+			&choice.Content,
+			&choice.Correct,
+			&choice.Position,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		choices = append(choices, choice)
+	}
+
+	return choices, nil
+}
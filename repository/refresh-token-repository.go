@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+)
+
+type RefreshTokenRepository interface {
+	CreateFamily(ctx context.Context, tx *sql.Tx, family entity.RefreshTokenFamilies) (entity.RefreshTokenFamilies, error)
+	FindFamilyById(ctx context.Context, tx *sql.Tx, id string) (entity.RefreshTokenFamilies, error)
+	RevokeFamily(ctx context.Context, tx *sql.Tx, id string, revokedAt int64) error
+	RevokeFamiliesByUser(ctx context.Context, tx *sql.Tx, userId int, revokedAt int64) error
+	Create(ctx context.Context, tx *sql.Tx, refreshToken entity.RefreshTokens) (entity.RefreshTokens, error)
+	FindByHash(ctx context.Context, tx *sql.Tx, tokenHash string) (entity.RefreshTokens, error)
+	MarkUsed(ctx context.Context, tx *sql.Tx, id int, usedAt int64) (bool, error)
+}
+
+type refreshTokenRepository struct {
+}
+
+func NewRefreshTokenRepository() RefreshTokenRepository {
+	return &refreshTokenRepository{}
+}
+
+func (repository *refreshTokenRepository) CreateFamily(ctx context.Context, tx *sql.Tx, family entity.RefreshTokenFamilies) (entity.RefreshTokenFamilies, error) {
+	query := `INSERT INTO refresh_token_families(id, user_id, created_at) VALUES(?,?,?)`
+	_, err := tx.ExecContext(ctx, bind(query), family.Id, family.UserId, family.CreatedAt)
+	if err != nil {
+		return entity.RefreshTokenFamilies{}, err
+	}
+
+	return family, nil
+}
+
+func (repository *refreshTokenRepository) FindFamilyById(ctx context.Context, tx *sql.Tx, id string) (entity.RefreshTokenFamilies, error) {
+	query := `SELECT id, user_id, revoked_at, created_at FROM refresh_token_families WHERE id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), id)
+	if err != nil {
+		return entity.RefreshTokenFamilies{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var family entity.RefreshTokenFamilies
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&family.Id,
+			&family.UserId,
+			&family.RevokedAt,
+			&family.CreatedAt,
+		)
+		if err != nil {
+			return entity.RefreshTokenFamilies{}, err
+		}
+
+		return family, nil
+	}
+
+	return family, errors.New("refresh token family not found")
+}
+
+func (repository *refreshTokenRepository) RevokeFamily(ctx context.Context, tx *sql.Tx, id string, revokedAt int64) error {
+	query := `UPDATE refresh_token_families SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`
+	_, err := tx.ExecContext(ctx, bind(query), revokedAt, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RevokeFamiliesByUser revokes every token family of the user, so none of their refresh tokens can be used anymore
+func (repository *refreshTokenRepository) RevokeFamiliesByUser(ctx context.Context, tx *sql.Tx, userId int, revokedAt int64) error {
+	query := `UPDATE refresh_token_families SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`
+	_, err := tx.ExecContext(ctx, bind(query), revokedAt, userId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repository *refreshTokenRepository) Create(ctx context.Context, tx *sql.Tx, refreshToken entity.RefreshTokens) (entity.RefreshTokens, error) {
+	query := `INSERT INTO refresh_tokens(family_id, token_hash, expired_at, created_at) VALUES(?,?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
+		ctx,
+		bind(query),
+		refreshToken.FamilyId,
+		refreshToken.TokenHash,
+		refreshToken.ExpiredAt,
+		refreshToken.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return entity.RefreshTokens{}, err
+	}
+	refreshToken.Id = id
+
+	return refreshToken, nil
+}
+
+func (repository *refreshTokenRepository) FindByHash(ctx context.Context, tx *sql.Tx, tokenHash string) (entity.RefreshTokens, error) {
+	query := `SELECT id, family_id, token_hash, used_at, expired_at, created_at FROM refresh_tokens WHERE token_hash = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), tokenHash)
+	if err != nil {
+		return entity.RefreshTokens{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var refreshToken entity.RefreshTokens
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&refreshToken.Id,
+			&refreshToken.FamilyId,
+			&refreshToken.TokenHash,
+			&refreshToken.UsedAt,
+			&refreshToken.ExpiredAt,
+			&refreshToken.CreatedAt,
+		)
+		if err != nil {
+			return entity.RefreshTokens{}, err
+		}
+
+		return refreshToken, nil
+	}
+
+	return refreshToken, errors.New("refresh token not found")
+}
+
+// MarkUsed flags the token as used and reports false when it had already been used before
+func (repository *refreshTokenRepository) MarkUsed(ctx context.Context, tx *sql.Tx, id int, usedAt int64) (bool, error) {
+	query := `UPDATE refresh_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL`
+	result, err := tx.ExecContext(ctx, bind(query), usedAt, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+)
+
+type RubricRepository interface {
+	CreateCriterion(ctx context.Context, tx *sql.Tx, criterion entity.RubricCriteria) (entity.RubricCriteria, error)
+	CreateLevel(ctx context.Context, tx *sql.Tx, level entity.RubricLevels) (entity.RubricLevels, error)
+	Delete(ctx context.Context, tx *sql.Tx, moduleSubmissionId int) error
+	FindCriteria(ctx context.Context, tx *sql.Tx, moduleSubmissionId int) ([]entity.RubricCriteria, error)
+	FindLevels(ctx context.Context, tx *sql.Tx, moduleSubmissionId int) ([]entity.RubricLevels, error)
+	CountScores(ctx context.Context, tx *sql.Tx, moduleSubmissionId int) (int, error)
+	SaveScore(ctx context.Context, tx *sql.Tx, score entity.RubricScores) error
+	DeleteScores(ctx context.Context, tx *sql.Tx, userSubmissionId int) error
+	FindScores(ctx context.Context, tx *sql.Tx, userSubmissionId int) ([]entity.CriterionScores, error)
+}
+
+type rubricRepository struct {
+}
+
+func NewRubricRepository() RubricRepository {
+	return &rubricRepository{}
+}
+
+func (repository *rubricRepository) CreateCriterion(ctx context.Context, tx *sql.Tx, criterion entity.RubricCriteria) (entity.RubricCriteria, error) {
+	query := `INSERT INTO rubric_criteria(module_submission_id, name, description, position) VALUES(?,?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
+		ctx,
+		bind(query),
+		criterion.ModuleSubmissionId,
+		criterion.Name,
+		criterion.Description,
+		criterion.Position,
+	).Scan(&id)
+	if err != nil {
+		return entity.RubricCriteria{}, err
+	}
+	criterion.Id = id
+
+	return criterion, nil
+}
+
+func (repository *rubricRepository) CreateLevel(ctx context.Context, tx *sql.Tx, level entity.RubricLevels) (entity.RubricLevels, error) {
+	query := `INSERT INTO rubric_levels(criterion_id, name, description, points) VALUES(?,?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
+		ctx,
+		bind(query),
+		level.CriterionId,
+		level.Name,
+		level.Description,
+		level.Points,
+	).Scan(&id)
+	if err != nil {
+		return entity.RubricLevels{}, err
+	}
+	level.Id = id
+
+	return level, nil
+}
+
+// Delete removes the criteria of a module submission with their levels and scores
+func (repository *rubricRepository) Delete(ctx context.Context, tx *sql.Tx, moduleSubmissionId int) error {
+	queries := []string{
+		`DELETE FROM rubric_scores WHERE criterion_id IN (SELECT id FROM rubric_criteria WHERE module_submission_id = ?)`,
+		`DELETE FROM rubric_levels WHERE criterion_id IN (SELECT id FROM rubric_criteria WHERE module_submission_id = ?)`,
+		`DELETE FROM rubric_criteria WHERE module_submission_id = ?`,
+	}
+	for _, query := range queries {
+		_, err := tx.ExecContext(ctx, bind(query), moduleSubmissionId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repository *rubricRepository) FindCriteria(ctx context.Context, tx *sql.Tx, moduleSubmissionId int) ([]entity.RubricCriteria, error) {
+	query := `SELECT id, module_submission_id, name, description, position FROM rubric_criteria WHERE module_submission_id = ? ORDER BY position`
+	queryContext, err := tx.QueryContext(ctx, bind(query), moduleSubmissionId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var criteria []entity.RubricCriteria
+	for queryContext.Next() {
+		var criterion entity.RubricCriteria
+		err := queryContext.Scan(
+			&criterion.Id,
+			&criterion.ModuleSubmissionId,
+			&criterion.Name,
+			&criterion.Description,
+			&criterion.Position,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		criteria = append(criteria, criterion)
+	}
+
+	return criteria, nil
+}
+
+// FindLevels returns the levels of every criterion of a module submission, the lowest points first
+func (repository *rubricRepository) FindLevels(ctx context.Context, tx *sql.Tx, moduleSubmissionId int) ([]entity.RubricLevels, error) {
+	query := `SELECT rl.id, rl.criterion_id, rl.name, rl.description, rl.points FROM rubric_levels rl
+			  JOIN rubric_criteria rc ON rc.id = rl.criterion_id
+			  WHERE rc.module_submission_id = ?
+			  ORDER BY rl.points, rl.id`
+	queryContext, err := tx.QueryContext(ctx, bind(query), moduleSubmissionId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var levels []entity.RubricLevels
+	for queryContext.Next() {
+		var level entity.RubricLevels
+		err := queryContext.Scan(
+			&level.Id,
+			&level.CriterionId,
+			&level.Name,
+			&level.Description,
+			&level.Points,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		levels = append(levels, level)
+	}
+
+	return levels, nil
+}
+
+func (repository *rubricRepository) CountScores(ctx context.Context, tx *sql.Tx, moduleSubmissionId int) (int, error) {
+	query := `SELECT COUNT(*) FROM rubric_scores rs
+			  JOIN rubric_criteria rc ON rc.id = rs.criterion_id
+			  WHERE rc.module_submission_id = ?`
+	var count int
+	err := tx.QueryRowContext(ctx, bind(query), moduleSubmissionId).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (repository *rubricRepository) SaveScore(ctx context.Context, tx *sql.Tx, score entity.RubricScores) error {
+	query := `INSERT INTO rubric_scores(user_submission_id, criterion_id, level_id, points, comment) VALUES(?,?,?,?,?)`
+	_, err := tx.ExecContext(ctx, bind(query), score.UserSubmissionId, score.CriterionId, score.LevelId, score.Points, score.Comment)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repository *rubricRepository) DeleteScores(ctx context.Context, tx *sql.Tx, userSubmissionId int) error {
+	query := `DELETE FROM rubric_scores WHERE user_submission_id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), userSubmissionId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindScores returns the scores of a user submission in the order of the criteria of the rubric
+func (repository *rubricRepository) FindScores(ctx context.Context, tx *sql.Tx, userSubmissionId int) ([]entity.CriterionScores, error) {
+	query := `SELECT rc.id, rc.name, rl.id, rl.name, rs.points,
+			  (SELECT MAX(points) FROM rubric_levels WHERE criterion_id = rc.id), rs.comment FROM rubric_scores rs
+			  JOIN rubric_criteria rc ON rc.id = rs.criterion_id
+			  JOIN rubric_levels rl ON rl.id = rs.level_id
+			  WHERE rs.user_submission_id = ?
+			  ORDER BY rc.position`
+	queryContext, err := tx.QueryContext(ctx, bind(query), userSubmissionId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var scores []entity.CriterionScores
+	for queryContext.Next() {
+		var score entity.CriterionScores
+		err := queryContext.Scan(
+			&score.CriterionId,
+			&score.CriterionName,
+			&score.LevelId,
+			&score.LevelName,
+			&score.Points,
+			&score.MaxPoints,
+			&score.Comment,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		scores = append(scores, score)
+	}
+
+	return scores, nil
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+const (
+	// snippetStart and snippetStop surround the matches in the snippets the database makes. They are private use
+	// characters that do not show up in text, so the snippet can be HTML escaped before the matches are marked.
+	snippetStart = "\uE000"
+	snippetStop  = "\uE001"
+)
+
+const (
+	SearchCourse   = "course"
+	SearchArticle  = "article"
+	SearchQuestion = "question"
+	SearchAnswer   = "answer"
+)
+
+type SearchRepository interface {
+	Available() bool
+	Search(ctx context.Context, tx *sql.Tx, text string, visibleTo int, query model.ListQuery) ([]entity.SearchResults, int, error)
+}
+
+type searchRepository struct {
+}
+
+func NewSearchRepository() SearchRepository {
+	return &searchRepository{}
+}
+
+// searchSource is the part of the search over one kind of result. Match uses the text argument Uses times,
+// Course is the column holding the course id that enrollment is checked against, empty when everybody may see the result.
+type searchSource struct {
+	Type   string
+	Match  string
+	Uses   int
+	Course string
+}
+
+var sqliteSearchSources = []searchSource{
+	{
+		Type: SearchCourse,
+		Match: `SELECT 'course' AS type, c.id AS id, c.code_course AS code_course, c.name AS title, snippet(courses_fts, -1, '` + snippetStart + `', '` + snippetStop + `', '...', 16) AS snippet, bm25(courses_fts) AS rank
+FROM courses_fts JOIN courses c ON c.id = courses_fts.rowid WHERE courses_fts MATCH ?`,
+		Uses: 1,
+	},
+	{
+		Type: SearchArticle,
+		Match: `SELECT 'article' AS type, a.id AS id, c.code_course AS code_course, a.name AS title, snippet(module_articles_fts, -1, '` + snippetStart + `', '` + snippetStop + `', '...', 16) AS snippet, bm25(module_articles_fts) AS rank
+FROM module_articles_fts JOIN module_articles a ON a.id = module_articles_fts.rowid JOIN courses c ON c.id = a.course_id WHERE module_articles_fts MATCH ?`,
+		Uses:   1,
+		Course: "a.course_id",
+	},
+	{
+		Type: SearchQuestion,
+		Match: `SELECT 'question' AS type, q.id AS id, c.code_course AS code_course, q.title AS title, snippet(questions_fts, -1, '` + snippetStart + `', '` + snippetStop + `', '...', 16) AS snippet, bm25(questions_fts) AS rank
+FROM questions_fts JOIN questions q ON q.id = questions_fts.rowid JOIN courses c ON c.id = q.course_id WHERE questions_fts MATCH ?`,
+		Uses:   1,
+		Course: "q.course_id",
+	},
+	{
+		Type: SearchAnswer,
+		Match: `SELECT 'answer' AS type, an.id AS id, c.code_course AS code_course, q.title AS title, snippet(answers_fts, -1, '` + snippetStart + `', '` + snippetStop + `', '...', 16) AS snippet, bm25(answers_fts) AS rank
+FROM answers_fts JOIN answers an ON an.id = answers_fts.rowid JOIN questions q ON q.id = an.question_id JOIN courses c ON c.id = q.course_id WHERE answers_fts MATCH ?`,
+		Uses:   1,
+		Course: "q.course_id",
+	},
+}
+
+var postgresSearchSources = []searchSource{
+	{
+		Type: SearchCourse,
+		Match: `SELECT 'course' AS type, c.id AS id, c.code_course AS code_course, c.name AS title, ts_headline('simple', coalesce(c.name, '') || ' ' || coalesce(c.about, '') || ' ' || coalesce(c.description, ''), plainto_tsquery('simple', ?), 'StartSel=` + snippetStart + `, StopSel=` + snippetStop + `, MaxWords=16, MinWords=4') AS snippet, -ts_rank(c.search, plainto_tsquery('simple', ?)) AS rank
+FROM courses c WHERE c.search @@ plainto_tsquery('simple', ?)`,
+		Uses: 3,
+	},
+	{
+		Type: SearchArticle,
+		Match: `SELECT 'article' AS type, a.id AS id, c.code_course AS code_course, a.name AS title, ts_headline('simple', a.content, plainto_tsquery('simple', ?), 'StartSel=` + snippetStart + `, StopSel=` + snippetStop + `, MaxWords=16, MinWords=4') AS snippet, -ts_rank(a.search, plainto_tsquery('simple', ?)) AS rank
+FROM module_articles a JOIN courses c ON c.id = a.course_id WHERE a.search @@ plainto_tsquery('simple', ?)`,
+		Uses:   3,
+		Course: "a.course_id",
+	},
+	{
+		Type: SearchQuestion,
+		Match: `SELECT 'question' AS type, q.id AS id, c.code_course AS code_course, q.title AS title, ts_headline('simple', coalesce(q.title, '') || ' ' || coalesce(q.description, ''), plainto_tsquery('simple', ?), 'StartSel=` + snippetStart + `, StopSel=` + snippetStop + `, MaxWords=16, MinWords=4') AS snippet, -ts_rank(q.search, plainto_tsquery('simple', ?)) AS rank
+FROM questions q JOIN courses c ON c.id = q.course_id WHERE q.search @@ plainto_tsquery('simple', ?)`,
+		Uses:   3,
+		Course: "q.course_id",
+	},
+	{
+		Type: SearchAnswer,
+		Match: `SELECT 'answer' AS type, an.id AS id, c.code_course AS code_course, q.title AS title, ts_headline('simple', an.description, plainto_tsquery('simple', ?), 'StartSel=` + snippetStart + `, StopSel=` + snippetStop + `, MaxWords=16, MinWords=4') AS snippet, -ts_rank(an.search, plainto_tsquery('simple', ?)) AS rank
+FROM answers an JOIN questions q ON q.id = an.question_id JOIN courses c ON c.id = q.course_id WHERE an.search @@ plainto_tsquery('simple', ?)`,
+		Uses:   3,
+		Course: "q.course_id",
+	},
+}
+
+// Available reports whether the database can run full-text searches, SQLite needs the FTS5 extension
+func (repository *searchRepository) Available() bool {
+	return driver == utils.DriverPostgres || utils.FTS5
+}
+
+// Search ranks the courses, articles, questions and answers matching text, best match first.
+// When visibleTo is not 0 articles, questions and answers are limited to the courses that user is enrolled in or teaches.
+func (repository *searchRepository) Search(ctx context.Context, tx *sql.Tx, text string, visibleTo int, query model.ListQuery) ([]entity.SearchResults, int, error) {
+	sources := sqliteSearchSources
+	match := matchExpression(text)
+	if driver == utils.DriverPostgres {
+		sources = postgresSearchSources
+		match = text
+	}
+
+	searchType := query.Filters["type"]
+	var selects []string
+	var args []interface{}
+	for _, source := range sources {
+		if searchType != "" && searchType != source.Type {
+			continue
+		}
+
+		statement := source.Match
+		for i := 0; i < source.Uses; i++ {
+			args = append(args, match)
+		}
+		if visibleTo != 0 && source.Course != "" {
+			statement += ` AND (` + source.Course + ` IN (SELECT course_id FROM user_course WHERE user_id = ?) OR ` + source.Course + ` IN (SELECT course_id FROM teaching_assignments WHERE user_id = ?))`
+			args = append(args, visibleTo, visibleTo)
+		}
+		selects = append(selects, statement)
+	}
+	if len(selects) == 0 {
+		return nil, 0, fmt.Errorf("%w: cannot search type %v", model.ErrInvalidListQuery, searchType)
+	}
+	union := strings.Join(selects, "\nUNION ALL\n")
+
+	var total int
+	err := tx.QueryRowContext(ctx, bind(`SELECT COUNT(*) FROM (`+union+`) results`), args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	statement := `SELECT type, id, code_course, title, snippet, rank FROM (` + union + `) results ORDER BY rank ASC, type ASC, id ASC LIMIT ? OFFSET ?`
+	queryContext, err := tx.QueryContext(ctx, bind(statement), append(args, query.Limit, query.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var results []entity.SearchResults
+	for queryContext.Next() {
+		var result entity.SearchResults
+		err := queryContext.Scan(
+			&result.Type,
+			&result.Id,
+			&result.CodeCourse,
+			&result.Title,
+			&result.Snippet,
+			&result.Rank,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		result.Snippet = highlight(result.Snippet)
+
+		results = append(results, result)
+	}
+
+	return results, total, nil
+}
+
+// highlight escapes the text of a snippet, which users wrote, and wraps the matches in <mark>
+func highlight(snippet string) string {
+	return strings.NewReplacer(snippetStart, "<mark>", snippetStop, "</mark>").Replace(html.EscapeString(snippet))
+}
+
+// matchExpression quotes every word of text so FTS5 treats it as a plain term instead of query syntax, all words must match
+func matchExpression(text string) string {
+	var terms []string
+	for _, word := range strings.Fields(text) {
+		terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"`)
+	}
+
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, session entity.Sessions) (entity.Sessions, error)
+	FindById(ctx context.Context, tx *sql.Tx, id string) (entity.Sessions, error)
+	Delete(ctx context.Context, tx *sql.Tx, id string) error
+	DeleteByUser(ctx context.Context, tx *sql.Tx, userId int) error
+	DeleteExpired(ctx context.Context, tx *sql.Tx, now int64) (int64, error)
+}
+
+type sessionRepository struct {
+}
+
+func NewSessionRepository() SessionRepository {
+	return &sessionRepository{}
+}
+
+func (repository *sessionRepository) Create(ctx context.Context, tx *sql.Tx, session entity.Sessions) (entity.Sessions, error) {
+	query := `INSERT INTO sessions(id, user_id, issued_at, expired_at, client_ip, user_agent) VALUES(?,?,?,?,?,?)`
+	_, err := tx.ExecContext(
+		ctx,
+		bind(query),
+		session.Id,
+		session.UserId,
+		session.IssuedAt,
+		session.ExpiredAt,
+		session.ClientIp,
+		session.UserAgent,
+	)
+	if err != nil {
+		return entity.Sessions{}, err
+	}
+
+	return session, nil
+}
+
+func (repository *sessionRepository) FindById(ctx context.Context, tx *sql.Tx, id string) (entity.Sessions, error) {
+	query := `SELECT id, user_id, issued_at, expired_at, client_ip, user_agent FROM sessions WHERE id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), id)
+	if err != nil {
+		return entity.Sessions{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var session entity.Sessions
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&session.Id,
+			&session.UserId,
+			&session.IssuedAt,
+			&session.ExpiredAt,
+			&session.ClientIp,
+			&session.UserAgent,
+		)
+		if err != nil {
+			return entity.Sessions{}, err
+		}
+
+		return session, nil
+	}
+
+	return session, errors.New("session not found")
+}
+
+func (repository *sessionRepository) Delete(ctx context.Context, tx *sql.Tx, id string) error {
+	query := "DELETE FROM sessions WHERE id = ?"
+	_, err := tx.ExecContext(ctx, bind(query), id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repository *sessionRepository) DeleteByUser(ctx context.Context, tx *sql.Tx, userId int) error {
+	query := "DELETE FROM sessions WHERE user_id = ?"
+	_, err := tx.ExecContext(ctx, bind(query), userId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repository *sessionRepository) DeleteExpired(ctx context.Context, tx *sql.Tx, now int64) (int64, error) {
+	query := "DELETE FROM sessions WHERE expired_at <= ?"
+	result, err := tx.ExecContext(ctx, bind(query), now)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
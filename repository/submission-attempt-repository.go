@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+)
+
+type SubmissionAttemptRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, attempt entity.SubmissionAttempts) (entity.SubmissionAttempts, error)
+	FindAll(ctx context.Context, tx *sql.Tx, userSubmissionId int) ([]entity.SubmissionAttempts, error)
+	FindById(ctx context.Context, tx *sql.Tx, userSubmissionId int, id int) (entity.SubmissionAttempts, error)
+	ReplaceFile(ctx context.Context, tx *sql.Tx, oldFile string, newFile string, hash string, size int64) (int64, error)
+}
+
+type submissionAttemptRepository struct {
+}
+
+func NewSubmissionAttemptRepository() SubmissionAttemptRepository {
+	return &submissionAttemptRepository{}
+}
+
+func (repository *submissionAttemptRepository) Create(ctx context.Context, tx *sql.Tx, attempt entity.SubmissionAttempts) (entity.SubmissionAttempts, error) {
+	query := `INSERT INTO submission_attempts(user_submission_id, file, hash, size, late, created_at) VALUES(?,?,?,?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
+		ctx,
+		bind(query),
+		attempt.UserSubmissionId,
+		attempt.File,
+		attempt.Hash,
+		attempt.Size,
+		attempt.Late,
+		attempt.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return entity.SubmissionAttempts{}, err
+	}
+	attempt.Id = id
+
+	return attempt, nil
+}
+
+// FindAll returns the attempts of a user submission, the first attempt comes first
+func (repository *submissionAttemptRepository) FindAll(ctx context.Context, tx *sql.Tx, userSubmissionId int) ([]entity.SubmissionAttempts, error) {
+	query := `SELECT id, user_submission_id, file, hash, size, late, created_at FROM submission_attempts WHERE user_submission_id = ? ORDER BY id`
+	queryContext, err := tx.QueryContext(ctx, bind(query), userSubmissionId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var attempts []entity.SubmissionAttempts
+	for queryContext.Next() {
+		var attempt entity.SubmissionAttempts
+		err := queryContext.Scan(
+			&attempt.Id,
+			&attempt.UserSubmissionId,
+			&attempt.File,
+			&attempt.Hash,
+			&attempt.Size,
+			&attempt.Late,
+			&attempt.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, nil
+}
+
+func (repository *submissionAttemptRepository) FindById(ctx context.Context, tx *sql.Tx, userSubmissionId int, id int) (entity.SubmissionAttempts, error) {
+	query := `SELECT id, user_submission_id, file, hash, size, late, created_at FROM submission_attempts WHERE user_submission_id = ? AND id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), userSubmissionId, id)
+	if err != nil {
+		return entity.SubmissionAttempts{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var attempt entity.SubmissionAttempts
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&attempt.Id,
+			&attempt.UserSubmissionId,
+			&attempt.File,
+			&attempt.Hash,
+			&attempt.Size,
+			&attempt.Late,
+			&attempt.CreatedAt,
+		)
+		if err != nil {
+			return entity.SubmissionAttempts{}, err
+		}
+
+		return attempt, nil
+	}
+
+	return attempt, errors.New("submission attempt not found")
+}
+
+// ReplaceFile points the attempts of a file that moved into the blob store to its content address
+func (repository *submissionAttemptRepository) ReplaceFile(ctx context.Context, tx *sql.Tx, oldFile string, newFile string, hash string, size int64) (int64, error) {
+	query := `UPDATE submission_attempts SET file = ?, hash = ?, size = ? WHERE file = ?`
+	result, err := tx.ExecContext(ctx, bind(query), newFile, hash, size, oldFile)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+)
+
+type SubmissionExtensionRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, extension entity.SubmissionExtensions) (entity.SubmissionExtensions, error)
+	Delete(ctx context.Context, tx *sql.Tx, moduleSubmissionId int, userId int) error
+	FindByUser(ctx context.Context, tx *sql.Tx, moduleSubmissionId int, userId int) (*entity.SubmissionExtensions, error)
+	FindAllByModuleSubmission(ctx context.Context, tx *sql.Tx, moduleSubmissionId int) ([]entity.StudentExtensions, error)
+}
+
+type submissionExtensionRepository struct {
+}
+
+func NewSubmissionExtensionRepository() SubmissionExtensionRepository {
+	return &submissionExtensionRepository{}
+}
+
+// Save grants the extension, or moves the deadline of the one the student already has
+func (repository *submissionExtensionRepository) Save(ctx context.Context, tx *sql.Tx, extension entity.SubmissionExtensions) (entity.SubmissionExtensions, error) {
+	query := `INSERT INTO submission_extensions(module_submission_id, user_id, deadline, created_at) VALUES(?,?,?,?)
+			  ON CONFLICT(module_submission_id, user_id) DO UPDATE SET deadline = excluded.deadline`
+	_, err := tx.ExecContext(ctx, bind(query), extension.ModuleSubmissionId, extension.UserId, extension.Deadline, extension.CreatedAt)
+	if err != nil {
+		return entity.SubmissionExtensions{}, err
+	}
+
+	return extension, nil
+}
+
+func (repository *submissionExtensionRepository) Delete(ctx context.Context, tx *sql.Tx, moduleSubmissionId int, userId int) error {
+	query := `DELETE FROM submission_extensions WHERE module_submission_id = ? AND user_id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), moduleSubmissionId, userId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindByUser returns nil when the student has no extension
+func (repository *submissionExtensionRepository) FindByUser(ctx context.Context, tx *sql.Tx, moduleSubmissionId int, userId int) (*entity.SubmissionExtensions, error) {
+	query := `SELECT module_submission_id, user_id, deadline, created_at FROM submission_extensions WHERE module_submission_id = ? AND user_id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), moduleSubmissionId, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	if queryContext.Next() {
+		var extension entity.SubmissionExtensions
+		err := queryContext.Scan(
+			&extension.ModuleSubmissionId,
+			&extension.UserId,
+			&extension.Deadline,
+			&extension.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &extension, nil
+	}
+
+	return nil, nil
+}
+
+func (repository *submissionExtensionRepository) FindAllByModuleSubmission(ctx context.Context, tx *sql.Tx, moduleSubmissionId int) ([]entity.StudentExtensions, error) {
+	query := `SELECT u.id, u.name, se.deadline, se.created_at FROM submission_extensions se
+			  LEFT JOIN users u ON u.id = se.user_id
+			  WHERE se.module_submission_id = ?
+			  ORDER BY u.name`
+	queryContext, err := tx.QueryContext(ctx, bind(query), moduleSubmissionId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var extensions []entity.StudentExtensions
+	for queryContext.Next() {
+		var extension entity.StudentExtensions
+		err := queryContext.Scan(
+			&extension.UserId,
+			&extension.UserName,
+			&extension.Deadline,
+			&extension.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		extensions = append(extensions, extension)
+	}
+
+	return extensions, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+)
+
+type TeachingAssignmentRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, assignment entity.TeachingAssignments) (entity.TeachingAssignments, error)
+	Delete(ctx context.Context, tx *sql.Tx, userId int, courseId int) error
+	Exists(ctx context.Context, tx *sql.Tx, userId int, courseId int) (bool, error)
+	FindAllTeacherByCourseId(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.CourseTeachers, error)
+	FindAllCourseByUserId(ctx context.Context, tx *sql.Tx, userId int) ([]entity.StudentCourse, error)
+}
+
+type teachingAssignmentRepository struct {
+}
+
+func NewTeachingAssignmentRepository() TeachingAssignmentRepository {
+	return &teachingAssignmentRepository{}
+}
+
+func (repository *teachingAssignmentRepository) Create(ctx context.Context, tx *sql.Tx, assignment entity.TeachingAssignments) (entity.TeachingAssignments, error) {
+	query := `INSERT INTO teaching_assignments(user_id, course_id, created_at) VALUES(?,?,?)`
+	_, err := tx.ExecContext(ctx, bind(query), assignment.UserId, assignment.CourseId, assignment.CreatedAt)
+	if err != nil {
+		return entity.TeachingAssignments{}, err
+	}
+
+	return assignment, nil
+}
+
+func (repository *teachingAssignmentRepository) Delete(ctx context.Context, tx *sql.Tx, userId int, courseId int) error {
+	query := `DELETE FROM teaching_assignments WHERE user_id = ? AND course_id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), userId, courseId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repository *teachingAssignmentRepository) Exists(ctx context.Context, tx *sql.Tx, userId int, courseId int) (bool, error) {
+	query := `SELECT COUNT(*) FROM teaching_assignments WHERE user_id = ? AND course_id = ?`
+	var total int
+	err := tx.QueryRowContext(ctx, bind(query), userId, courseId).Scan(&total)
+	if err != nil {
+		return false, err
+	}
+
+	return total > 0, nil
+}
+
+func (repository *teachingAssignmentRepository) FindAllTeacherByCourseId(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.CourseTeachers, error) {
+	query := `SELECT u.id,u.name,u.username,u.email FROM teaching_assignments ta
+			  LEFT JOIN users u on u.id = ta.user_id
+			  WHERE ta.course_id = ?
+			  ORDER BY ta.created_at`
+	queryContext, err := tx.QueryContext(ctx, bind(query), courseId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var teachers []entity.CourseTeachers
+	for queryContext.Next() {
+		var teacher entity.CourseTeachers
+		err := queryContext.Scan(
+			&teacher.IdUser,
+			&teacher.UserName,
+			&teacher.UserUsername,
+			&teacher.UserEmail,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		teachers = append(teachers, teacher)
+	}
+
+	return teachers, nil
+}
+
+func (repository *teachingAssignmentRepository) FindAllCourseByUserId(ctx context.Context, tx *sql.Tx, userId int) ([]entity.StudentCourse, error) {
+	query := `SELECT c.id,c.name,c.code_course,c.class FROM teaching_assignments ta
+			  LEFT JOIN courses c on c.id = ta.course_id
+			  WHERE ta.user_id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), userId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var courses []entity.StudentCourse
+	for queryContext.Next() {
+		var course entity.StudentCourse
+		err := queryContext.Scan(
+			&course.IdCourse,
+			&course.CourseName,
+			&course.CourseCode,
+			&course.CourseClass,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		courses = append(courses, course)
+	}
+
+	return courses, nil
+}
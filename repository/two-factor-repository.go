@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+)
+
+type TwoFactorRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, twoFactor entity.TwoFactor) error
+	FindByUser(ctx context.Context, tx *sql.Tx, userId int) (entity.TwoFactor, error)
+	Confirm(ctx context.Context, tx *sql.Tx, userId int, confirmedAt int64) error
+	UseStep(ctx context.Context, tx *sql.Tx, userId int, step int64) (bool, error)
+	Delete(ctx context.Context, tx *sql.Tx, userId int) error
+	ReplaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userId int, codeHashes []string, createdAt int64) error
+	UseRecoveryCode(ctx context.Context, tx *sql.Tx, userId int, codeHash string, usedAt int64) (bool, error)
+	CountRecoveryCodes(ctx context.Context, tx *sql.Tx, userId int) (int, error)
+	CreateChallenge(ctx context.Context, tx *sql.Tx, challenge entity.TwoFactorChallenges) (entity.TwoFactorChallenges, error)
+	FindChallengeByHash(ctx context.Context, tx *sql.Tx, tokenHash string) (entity.TwoFactorChallenges, error)
+	AddChallengeAttempt(ctx context.Context, tx *sql.Tx, id int) error
+	UseChallenge(ctx context.Context, tx *sql.Tx, id int, usedAt int64) (bool, error)
+	FindPolicies(ctx context.Context, tx *sql.Tx) ([]entity.TwoFactorPolicies, error)
+	SavePolicy(ctx context.Context, tx *sql.Tx, policy entity.TwoFactorPolicies) error
+	FindUsersWithout(ctx context.Context, tx *sql.Tx, role int) ([]int, error)
+}
+
+type twoFactorRepository struct {
+}
+
+func NewTwoFactorRepository() TwoFactorRepository {
+	return &twoFactorRepository{}
+}
+
+// Save stores a new secret for the user, replacing a secret that has not been confirmed yet
+func (repository *twoFactorRepository) Save(ctx context.Context, tx *sql.Tx, twoFactor entity.TwoFactor) error {
+	query := `INSERT INTO two_factor(user_id, secret, last_step, confirmed_at, created_at) VALUES(?,?,?,?,?)
+			  ON CONFLICT(user_id) DO UPDATE SET secret = excluded.secret, last_step = excluded.last_step, confirmed_at = excluded.confirmed_at,
+			  created_at = excluded.created_at`
+	_, err := tx.ExecContext(
+		ctx,
+		bind(query),
+		twoFactor.UserId,
+		twoFactor.Secret,
+		twoFactor.LastStep,
+		twoFactor.ConfirmedAt,
+		twoFactor.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repository *twoFactorRepository) FindByUser(ctx context.Context, tx *sql.Tx, userId int) (entity.TwoFactor, error) {
+	query := `SELECT user_id, secret, last_step, confirmed_at, created_at FROM two_factor WHERE user_id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), userId)
+	if err != nil {
+		return entity.TwoFactor{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var twoFactor entity.TwoFactor
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&twoFactor.UserId,
+			&twoFactor.Secret,
+			&twoFactor.LastStep,
+			&twoFactor.ConfirmedAt,
+			&twoFactor.CreatedAt,
+		)
+		if err != nil {
+			return entity.TwoFactor{}, err
+		}
+
+		return twoFactor, nil
+	}
+
+	return twoFactor, errors.New("two-factor authentication not found")
+}
+
+func (repository *twoFactorRepository) Confirm(ctx context.Context, tx *sql.Tx, userId int, confirmedAt int64) error {
+	query := `UPDATE two_factor SET confirmed_at = ? WHERE user_id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), confirmedAt, userId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UseStep records the time step of an accepted code and reports false when a code of that step or a later one has
+// already been accepted, so a code can not be used twice
+func (repository *twoFactorRepository) UseStep(ctx context.Context, tx *sql.Tx, userId int, step int64) (bool, error) {
+	query := `UPDATE two_factor SET last_step = ? WHERE user_id = ? AND last_step < ?`
+	result, err := tx.ExecContext(ctx, bind(query), step, userId, step)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// Delete removes the secret and the recovery codes of the user
+func (repository *twoFactorRepository) Delete(ctx context.Context, tx *sql.Tx, userId int) error {
+	query := `DELETE FROM recovery_codes WHERE user_id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), userId)
+	if err != nil {
+		return err
+	}
+
+	query = `DELETE FROM two_factor WHERE user_id = ?`
+	_, err = tx.ExecContext(ctx, bind(query), userId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes removes the recovery codes of the user, used or not, and stores the new ones
+func (repository *twoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userId int, codeHashes []string, createdAt int64) error {
+	query := `DELETE FROM recovery_codes WHERE user_id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), userId)
+	if err != nil {
+		return err
+	}
+
+	query = `INSERT INTO recovery_codes(user_id, code_hash, created_at) VALUES(?,?,?)`
+	for _, codeHash := range codeHashes {
+		_, err = tx.ExecContext(ctx, bind(query), userId, codeHash, createdAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UseRecoveryCode flags the recovery code as used and reports false when the user has no such code left
+func (repository *twoFactorRepository) UseRecoveryCode(ctx context.Context, tx *sql.Tx, userId int, codeHash string, usedAt int64) (bool, error) {
+	query := `UPDATE recovery_codes SET used_at = ? WHERE user_id = ? AND code_hash = ? AND used_at IS NULL`
+	result, err := tx.ExecContext(ctx, bind(query), usedAt, userId, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// CountRecoveryCodes returns how many recovery codes of the user have not been used yet
+func (repository *twoFactorRepository) CountRecoveryCodes(ctx context.Context, tx *sql.Tx, userId int) (int, error) {
+	query := `SELECT COUNT(*) FROM recovery_codes WHERE user_id = ? AND used_at IS NULL`
+	var count int
+	err := tx.QueryRowContext(ctx, bind(query), userId).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (repository *twoFactorRepository) CreateChallenge(ctx context.Context, tx *sql.Tx, challenge entity.TwoFactorChallenges) (entity.TwoFactorChallenges, error) {
+	query := `INSERT INTO two_factor_challenges(user_id, token_hash, expired_at, created_at) VALUES(?,?,?,?) RETURNING id`
+	var id int
+	err := tx.QueryRowContext(
+		ctx,
+		bind(query),
+		challenge.UserId,
+		challenge.TokenHash,
+		challenge.ExpiredAt,
+		challenge.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return entity.TwoFactorChallenges{}, err
+	}
+	challenge.Id = id
+
+	return challenge, nil
+}
+
+func (repository *twoFactorRepository) FindChallengeByHash(ctx context.Context, tx *sql.Tx, tokenHash string) (entity.TwoFactorChallenges, error) {
+	query := `SELECT id, user_id, token_hash, attempts, used_at, expired_at, created_at FROM two_factor_challenges WHERE token_hash = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), tokenHash)
+	if err != nil {
+		return entity.TwoFactorChallenges{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var challenge entity.TwoFactorChallenges
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&challenge.Id,
+			&challenge.UserId,
+			&challenge.TokenHash,
+			&challenge.Attempts,
+			&challenge.UsedAt,
+			&challenge.ExpiredAt,
+			&challenge.CreatedAt,
+		)
+		if err != nil {
+			return entity.TwoFactorChallenges{}, err
+		}
+
+		return challenge, nil
+	}
+
+	return challenge, errors.New("two-factor challenge not found")
+}
+
+func (repository *twoFactorRepository) AddChallengeAttempt(ctx context.Context, tx *sql.Tx, id int) error {
+	query := `UPDATE two_factor_challenges SET attempts = attempts + 1 WHERE id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UseChallenge flags the challenge as used and reports false when it had already been used before
+func (repository *twoFactorRepository) UseChallenge(ctx context.Context, tx *sql.Tx, id int, usedAt int64) (bool, error) {
+	query := `UPDATE two_factor_challenges SET used_at = ? WHERE id = ? AND used_at IS NULL`
+	result, err := tx.ExecContext(ctx, bind(query), usedAt, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// FindPolicies returns the roles that have a policy, roles without one do not require two-factor authentication
+func (repository *twoFactorRepository) FindPolicies(ctx context.Context, tx *sql.Tx) ([]entity.TwoFactorPolicies, error) {
+	query := `SELECT role, required, updated_at FROM two_factor_policies ORDER BY role`
+	queryContext, err := tx.QueryContext(ctx, bind(query))
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var policies []entity.TwoFactorPolicies
+	for queryContext.Next() {
+		var policy entity.TwoFactorPolicies
+		err := queryContext.Scan(
+			&policy.Role,
+			&policy.Required,
+			&policy.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+func (repository *twoFactorRepository) SavePolicy(ctx context.Context, tx *sql.Tx, policy entity.TwoFactorPolicies) error {
+	query := `INSERT INTO two_factor_policies(role, required, updated_at) VALUES(?,?,?)
+			  ON CONFLICT(role) DO UPDATE SET required = excluded.required, updated_at = excluded.updated_at`
+	_, err := tx.ExecContext(
+		ctx,
+		bind(query),
+		policy.Role,
+		policy.Required,
+		policy.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindUsersWithout returns the ids of the users of the role who have not confirmed two-factor authentication
+func (repository *twoFactorRepository) FindUsersWithout(ctx context.Context, tx *sql.Tx, role int) ([]int, error) {
+	query := `SELECT users.id FROM users LEFT JOIN two_factor ON two_factor.user_id = users.id AND two_factor.confirmed_at IS NOT NULL
+			  WHERE users.role = ? AND two_factor.user_id IS NULL ORDER BY users.id`
+	queryContext, err := tx.QueryContext(ctx, bind(query), role)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var userIds []int
+	for queryContext.Next() {
+		var userId int
+		err := queryContext.Scan(&userId)
+		if err != nil {
+			return nil, err
+		}
+		userIds = append(userIds, userId)
+	}
+
+	return userIds, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+)
+
+type UserQuizRepository interface {
+	OnlyCreate(ctx context.Context, tx *sql.Tx, userId int, quizId int) error
+	FindByUser(ctx context.Context, tx *sql.Tx, userId int, quizId int) (entity.UserQuizzes, error)
+	UpdateGrade(ctx context.Context, tx *sql.Tx, userQuiz entity.UserQuizzes) error
+	FindAll(ctx context.Context, tx *sql.Tx, quizId int) ([]entity.UserQuizResults, error)
+}
+
+type userQuizRepository struct {
+}
+
+func NewUserQuizRepository() UserQuizRepository {
+	return &userQuizRepository{}
+}
+
+// OnlyCreate adds the empty result of a user, users who already have one keep it so re-enrolling keeps their grade
+func (repository *userQuizRepository) OnlyCreate(ctx context.Context, tx *sql.Tx, userId int, quizId int) error {
+	query := `INSERT INTO user_quizzes(user_id, quiz_id) VALUES(?,?) ON CONFLICT(user_id, quiz_id) DO NOTHING`
+	_, err := tx.ExecContext(ctx, bind(query), userId, quizId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repository *userQuizRepository) FindByUser(ctx context.Context, tx *sql.Tx, userId int, quizId int) (entity.UserQuizzes, error) {
+	query := `SELECT id, user_id, quiz_id, grade, graded_attempt_id FROM user_quizzes WHERE user_id = ? AND quiz_id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), userId, quizId)
+	if err != nil {
+		return entity.UserQuizzes{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var userQuiz entity.UserQuizzes
+	if queryContext.Next() {
+		err := queryContext.Scan(
+			&userQuiz.Id,
+			&userQuiz.UserId,
+			&userQuiz.QuizId,
+			&userQuiz.Grade,
+			&userQuiz.GradedAttemptId,
+		)
+		if err != nil {
+			return entity.UserQuizzes{}, err
+		}
+
+		return userQuiz, nil
+	}
+
+	return userQuiz, errors.New("user quiz not found")
+}
+
+func (repository *userQuizRepository) UpdateGrade(ctx context.Context, tx *sql.Tx, userQuiz entity.UserQuizzes) error {
+	query := `UPDATE user_quizzes SET grade = ?, graded_attempt_id = ? WHERE id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), userQuiz.Grade, userQuiz.GradedAttemptId, userQuiz.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindAll returns the grade record of every student of a quiz with the number of attempts they started
+func (repository *userQuizRepository) FindAll(ctx context.Context, tx *sql.Tx, quizId int) ([]entity.UserQuizResults, error) {
+	query := `SELECT uq.user_id, u.name, u.username, uq.grade, uq.graded_attempt_id,
+			  (SELECT COUNT(*) FROM quiz_attempts qa WHERE qa.quiz_id = uq.quiz_id AND qa.user_id = uq.user_id)
+			  FROM user_quizzes uq
+			  JOIN users u ON u.id = uq.user_id
+			  WHERE uq.quiz_id = ?
+			  ORDER BY u.name`
+	queryContext, err := tx.QueryContext(ctx, bind(query), quizId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var results []entity.UserQuizResults
+	for queryContext.Next() {
+		var result entity.UserQuizResults
+		err := queryContext.Scan(
+			&result.UserId,
+			&result.UserName,
+			&result.UserUsername,
+			&result.Grade,
+			&result.GradedAttemptId,
+			&result.Attempts,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
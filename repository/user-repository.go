@@ -13,16 +13,20 @@ import (
 )
 
 type UserRepository interface {
-	Register(ctx context.Context, tx *sql.Tx, user entity.Users) error
+	Register(ctx context.Context, tx *sql.Tx, user entity.Users) (entity.Users, error)
 	Login(ctx context.Context, tx *sql.Tx, data model.GetUserLogin) (entity.Users, error)
 	UpdateRole(ctx context.Context, tx *sql.Tx, id int, role int) (entity.Users, error)
 	GetUserByID(ctx context.Context, tx *sql.Tx, id int) (entity.Users, error)
-	ListUser(ctx context.Context, tx *sql.Tx) ([]entity.Users, error)
-	GetLastInsertUser(ctx context.Context, tx *sql.Tx) (entity.Users, error)
+	ListUser(ctx context.Context, tx *sql.Tx, query model.ListQuery) ([]entity.Users, int, error)
 	Delete(ctx context.Context, tx *sql.Tx, id int) error
 	Update(ctx context.Context, tx *sql.Tx, user entity.Users) error
 	CheckUserByEmail(ctx context.Context, tx *sql.Tx, email string) error
 	UpdateVerifiedAt(ctx context.Context, tx *sql.Tx, timeVerifiedAt time.Time, email string) error
+	CheckRegistered(ctx context.Context, tx *sql.Tx, username string, email string) error
+	FindByEmail(ctx context.Context, tx *sql.Tx, email string) (entity.Users, error)
+	UpdatePassword(ctx context.Context, tx *sql.Tx, id int, password string, updatedAt time.Time) error
+	CheckPassword(ctx context.Context, tx *sql.Tx, id int, password string) error
+	UpdateEmail(ctx context.Context, tx *sql.Tx, id int, email string, verifiedAt time.Time) error
 }
 
 type userRepository struct {
@@ -32,56 +36,29 @@ func NewUserRepository() UserRepository {
 	return &userRepository{}
 }
 
-// Register is a function to register a new user to the database
-func (repository *userRepository) Register(ctx context.Context, tx *sql.Tx, user entity.Users) error {
-	var id int
-	var email, username string
-	var emailArr, usernameArr []string
-
-	rowsCheck, err := tx.QueryContext(ctx, "SELECT email, username FROM users")
-
+// Register is a function to register a new user to the database, it returns the user with its new id
+func (repository *userRepository) Register(ctx context.Context, tx *sql.Tx, user entity.Users) (entity.Users, error) {
+	err := repository.CheckRegistered(ctx, tx, user.Username, user.Email)
 	if err != nil {
-		return err
-	}
-
-	for rowsCheck.Next() {
-		rowsCheck.Scan(&email, &username)
-		emailArr = append(emailArr, email)
-		usernameArr = append(usernameArr, username)
-	}
-
-	for _, value := range usernameArr {
-		if value == user.Username {
-			return fmt.Errorf("username has been registered")
-		}
-	}
-
-	for _, value := range emailArr {
-		if value == user.Email {
-			return fmt.Errorf("email has been registered")
-		}
+		return entity.Users{}, err
 	}
 
 	temp, _ := bcrypt.GenerateFromPassword([]byte(user.Password), 12)
 	user.Password = string(temp)
 
-	_, err = tx.ExecContext(ctx, "INSERT INTO users (name, username, email, password, role, email_verification, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", user.Name, user.Username, user.Email, user.Password, user.Role, user.EmailVerification, user.CreatedAt, user.UpdatedAt)
+	err = tx.QueryRowContext(ctx, bind("INSERT INTO users (name, username, email, password, role, email_verification, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING id"), user.Name, user.Username, user.Email, user.Password, user.Role, user.EmailVerification, user.CreatedAt, user.UpdatedAt).Scan(&user.Id)
 
 	if err != nil {
-		return err
+		return entity.Users{}, err
 	}
 
-	rows := tx.QueryRowContext(ctx, "SELECT id FROM users WHERE username = ?", user.Username)
-
-	rows.Scan(&id)
-
-	_, err = tx.ExecContext(ctx, "INSERT INTO user_details (user_id, phone, gender, type_of_disability, birthdate) VALUES (?, ?, ?, ?, ?)", id, user.Phone, user.Gender, user.DisabilityType, user.Birthdate)
+	_, err = tx.ExecContext(ctx, bind("INSERT INTO user_details (user_id, phone, gender, type_of_disability, birthdate) VALUES (?, ?, ?, ?, ?)"), user.Id, user.Phone, user.Gender, user.DisabilityType, user.Birthdate)
 
 	if err != nil {
-		return err
+		return entity.Users{}, err
 	}
 
-	return nil
+	return user, nil
 }
 
 // Login is a function to login a user by email and password
@@ -89,11 +66,11 @@ func (repository *userRepository) Login(ctx context.Context, tx *sql.Tx, data mo
 
 	var user entity.Users
 
-	rows := tx.QueryRowContext(ctx, "SELECT id, name, username, email, password, role FROM users WHERE email = ?", data.Email)
+	rows := tx.QueryRowContext(ctx, bind("SELECT id, name, username, email, password, role, email_verification FROM users WHERE email = ?"), data.Email)
 
-	rows.Scan(&user.Id, &user.Name, &user.Username, &user.Email, &user.Password, &user.Role)
+	rows.Scan(&user.Id, &user.Name, &user.Username, &user.Email, &user.Password, &user.Role, &user.EmailVerification)
 
-	rows = tx.QueryRowContext(ctx, "SELECT gender, type_of_disability FROM user_details WHERE user_id = ?", user.Id)
+	rows = tx.QueryRowContext(ctx, bind("SELECT gender, type_of_disability FROM user_details WHERE user_id = ?"), user.Id)
 
 	rows.Scan(&user.Gender, &user.DisabilityType)
 
@@ -110,13 +87,13 @@ func (repository *userRepository) Login(ctx context.Context, tx *sql.Tx, data mo
 func (repository *userRepository) UpdateRole(ctx context.Context, tx *sql.Tx, id int, role int) (entity.Users, error) {
 	var user entity.Users
 
-	_, err := tx.ExecContext(ctx, "UPDATE users SET role = ? WHERE id = ?", role, id)
+	_, err := tx.ExecContext(ctx, bind("UPDATE users SET role = ? WHERE id = ?"), role, id)
 
 	if err != nil {
 		return entity.Users{}, err
 	}
 
-	rows := tx.QueryRowContext(ctx, "SELECT users.id, users.name, users.username, users.role, user_details.phone, user_details.gender, user_details.type_of_disability, user_details.address, user_details.birthdate, user_details.image, user_details.description FROM users INNER JOIN user_details ON user_details.user_id = users.id WHERE users.id = ?", id)
+	rows := tx.QueryRowContext(ctx, bind("SELECT users.id, users.name, users.username, users.role, user_details.phone, user_details.gender, user_details.type_of_disability, user_details.address, user_details.birthdate, user_details.image, user_details.description FROM users INNER JOIN user_details ON user_details.user_id = users.id WHERE users.id = ?"), id)
 
 	rows.Scan(&user.Id, &user.Name, &user.Username, &user.Role, &user.Phone, &user.Gender, &user.DisabilityType, &user.Address, &user.Birthdate, &user.Image, &user.Description)
 
@@ -128,18 +105,47 @@ func (repository *userRepository) GetUserByID(ctx context.Context, tx *sql.Tx, i
 
 	var user entity.Users
 
-	rows := tx.QueryRowContext(ctx, "SELECT users.id, users.name, users.username, users.role, user_details.phone, user_details.gender, user_details.type_of_disability, user_details.address, user_details.birthdate, user_details.image, user_details.description FROM users INNER JOIN user_details ON user_details.user_id = users.id WHERE users.id = ?", id)
+	rows := tx.QueryRowContext(ctx, bind("SELECT users.id, users.name, users.username, users.email, users.role, user_details.phone, user_details.gender, user_details.type_of_disability, user_details.address, user_details.birthdate, user_details.image, user_details.description FROM users INNER JOIN user_details ON user_details.user_id = users.id WHERE users.id = ?"), id)
 
-	rows.Scan(&user.Id, &user.Name, &user.Username, &user.Role, &user.Phone, &user.Gender, &user.DisabilityType, &user.Address, &user.Birthdate, &user.Image, &user.Description)
+	rows.Scan(&user.Id, &user.Name, &user.Username, &user.Email, &user.Role, &user.Phone, &user.Gender, &user.DisabilityType, &user.Address, &user.Birthdate, &user.Image, &user.Description)
 	return user, nil
 }
 
-// GetUser is a function to get all users from the database
-func (repository *userRepository) ListUser(ctx context.Context, tx *sql.Tx) ([]entity.Users, error) {
-	rows, err := tx.QueryContext(ctx, "SELECT users.id, users.name, users.username, users.role, user_details.phone, user_details.gender, user_details.type_of_disability, user_details.address, user_details.birthdate, user_details.image, user_details.description FROM users INNER JOIN user_details ON user_details.user_id = users.id")
+var userList = listSpec{
+	Sorts: map[string]string{
+		"id":       "users.id",
+		"name":     "users.name",
+		"username": "users.username",
+	},
+	DefaultSort: "id",
+	Tiebreak:    "users.id",
+	Filters: map[string]listFilter{
+		"role": {Column: "users.role", Parse: parseInt},
+	},
+}
 
+// ListUser is a function to get a page of users from the database
+func (repository *userRepository) ListUser(ctx context.Context, tx *sql.Tx, query model.ListQuery) ([]entity.Users, int, error) {
+	where, args, err := userList.where(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	orderBy, err := userList.orderBy(query)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	var total int
+	err = tx.QueryRowContext(ctx, bind(`SELECT COUNT(*) FROM users INNER JOIN user_details ON user_details.user_id = users.id`+where), args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	statement := `SELECT users.id, users.name, users.username, users.role, user_details.phone, user_details.gender, user_details.type_of_disability, user_details.address, user_details.birthdate, user_details.image, user_details.description FROM users INNER JOIN user_details ON user_details.user_id = users.id` + where + orderBy
+	rows, err := tx.QueryContext(ctx, bind(statement), append(args, query.Limit, query.Offset)...)
+
+	if err != nil {
+		return nil, 0, err
 	}
 
 	defer rows.Close()
@@ -150,34 +156,23 @@ func (repository *userRepository) ListUser(ctx context.Context, tx *sql.Tx) ([]e
 
 		err := rows.Scan(&user.Id, &user.Name, &user.Username, &user.Role, &user.Phone, &user.Gender, &user.DisabilityType, &user.Address, &user.Birthdate, &user.Image, &user.Description)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		users = append(users, user)
 	}
 
-	return users, nil
-}
-
-// GetLastInsertUser is a function to get the last inserted user from the database
-func (repository *userRepository) GetLastInsertUser(ctx context.Context, tx *sql.Tx) (entity.Users, error) {
-
-	var user entity.Users
-
-	rows := tx.QueryRowContext(ctx, "SELECT users.id, users.name, users.username, users.email, users.password, users.role, user_details.phone, user_details.gender, user_details.type_of_disability, user_details.birthdate, users.email_verification, users.created_at, users.updated_at FROM users INNER JOIN user_details ON user_details.user_id = users.id WHERE users.id = (SELECT MAX(id) FROM users)")
-
-	rows.Scan(&user.Id, &user.Name, &user.Username, &user.Email, &user.Password, &user.Role, &user.Phone, &user.Gender, &user.DisabilityType, &user.Birthdate, &user.EmailVerification, &user.CreatedAt, &user.UpdatedAt)
-	return user, nil
+	return users, total, nil
 }
 
 // Update is a function to update a user by id to database
 func (repository *userRepository) Update(ctx context.Context, tx *sql.Tx, user entity.Users) error {
-	_, err := tx.ExecContext(ctx, "UPDATE users SET name = ?, username = ?, role = ?, updated_at = ? WHERE id = ?", user.Name, user.Username, user.Role, user.UpdatedAt, user.Id)
+	_, err := tx.ExecContext(ctx, bind("UPDATE users SET name = ?, username = ?, updated_at = ? WHERE id = ?"), user.Name, user.Username, user.UpdatedAt, user.Id)
 
 	if err != nil {
 		return err
 	}
 
-	_, err = tx.ExecContext(ctx, "UPDATE user_details SET phone = ?, gender = ?, type_of_disability = ?, address = ?, birthdate = ?, image = ?, description = ? WHERE user_id = ?", user.Phone, user.Gender, user.DisabilityType, user.Address, user.Birthdate, user.Image, user.Description, user.Id)
+	_, err = tx.ExecContext(ctx, bind("UPDATE user_details SET phone = ?, gender = ?, type_of_disability = ?, address = ?, birthdate = ?, image = ?, description = ? WHERE user_id = ?"), user.Phone, user.Gender, user.DisabilityType, user.Address, user.Birthdate, user.Image, user.Description, user.Id)
 
 	if err != nil {
 		return err
@@ -190,7 +185,7 @@ func (repository *userRepository) Update(ctx context.Context, tx *sql.Tx, user e
 func (repository *userRepository) Delete(ctx context.Context, tx *sql.Tx, id int) error {
 	var user entity.Users
 
-	rows := tx.QueryRowContext(ctx, "SELECT name FROM users WHERE id = ?", id)
+	rows := tx.QueryRowContext(ctx, bind("SELECT name FROM users WHERE id = ?"), id)
 
 	rows.Scan(&user.Name)
 
@@ -198,13 +193,13 @@ func (repository *userRepository) Delete(ctx context.Context, tx *sql.Tx, id int
 		return fmt.Errorf("user not found")
 	}
 
-	_, err := tx.ExecContext(ctx, "DELETE FROM users WHERE id = ?", id)
+	_, err := tx.ExecContext(ctx, bind("DELETE FROM users WHERE id = ?"), id)
 
 	if err != nil {
 		return err
 	}
 
-	_, err = tx.ExecContext(ctx, "DELETE FROM user_details WHERE user_id = ?", id)
+	_, err = tx.ExecContext(ctx, bind("DELETE FROM user_details WHERE user_id = ?"), id)
 
 	if err != nil {
 		return err
@@ -214,8 +209,8 @@ func (repository *userRepository) Delete(ctx context.Context, tx *sql.Tx, id int
 }
 
 func (repository *userRepository) CheckUserByEmail(ctx context.Context, tx *sql.Tx, email string) error {
-	query := "SELECT * FROM users WHERE email = ?"
-	queryContext, err := tx.QueryContext(ctx, query, email)
+	query := "SELECT id FROM users WHERE email = ?"
+	queryContext, err := tx.QueryContext(ctx, bind(query), email)
 	if err != nil {
 		return err
 	}
@@ -235,7 +230,104 @@ func (repository *userRepository) CheckUserByEmail(ctx context.Context, tx *sql.
 
 func (repository *userRepository) UpdateVerifiedAt(ctx context.Context, tx *sql.Tx, timeVerifiedAt time.Time, email string) error {
 	query := "UPDATE users SET email_verification = ? WHERE email = ?"
-	_, err := tx.ExecContext(ctx, query, timeVerifiedAt, email)
+	_, err := tx.ExecContext(ctx, bind(query), timeVerifiedAt, email)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CheckRegistered returns an error when the username or the email already belongs to a user, the username is checked
+// first
+func (repository *userRepository) CheckRegistered(ctx context.Context, tx *sql.Tx, username string, email string) error {
+	queryContext, err := tx.QueryContext(ctx, bind("SELECT username, email FROM users WHERE username = ? OR email = ?"), username, email)
+	if err != nil {
+		return err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	emailRegistered := false
+	for queryContext.Next() {
+		var registeredUsername, registeredEmail string
+		err := queryContext.Scan(&registeredUsername, &registeredEmail)
+		if err != nil {
+			return err
+		}
+
+		if registeredUsername == username {
+			return fmt.Errorf("username has been registered")
+		}
+		if registeredEmail == email {
+			emailRegistered = true
+		}
+	}
+
+	if emailRegistered {
+		return fmt.Errorf("email has been registered")
+	}
+
+	return nil
+}
+
+// FindByEmail returns the user with the email, or an empty user when there is none
+func (repository *userRepository) FindByEmail(ctx context.Context, tx *sql.Tx, email string) (entity.Users, error) {
+	queryContext, err := tx.QueryContext(ctx, bind("SELECT id, name, username, email, role, email_verification FROM users WHERE email = ?"), email)
+	if err != nil {
+		return entity.Users{}, err
+	}
+	defer func(queryContext *sql.Rows) {
+		err := queryContext.Close()
+		if err != nil {
+			return
+		}
+	}(queryContext)
+
+	var user entity.Users
+	if queryContext.Next() {
+		err := queryContext.Scan(&user.Id, &user.Name, &user.Username, &user.Email, &user.Role, &user.EmailVerification)
+		if err != nil {
+			return entity.Users{}, err
+		}
+	}
+
+	return user, nil
+}
+
+// UpdatePassword hashes the password and saves it as the new password of the user
+func (repository *userRepository) UpdatePassword(ctx context.Context, tx *sql.Tx, id int, password string, updatedAt time.Time) error {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), 12)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, bind("UPDATE users SET password = ?, updated_at = ? WHERE id = ?"), string(hashed), updatedAt, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CheckPassword returns an error when the password is not the password of the user
+func (repository *userRepository) CheckPassword(ctx context.Context, tx *sql.Tx, id int, password string) error {
+	var hashed string
+	err := tx.QueryRowContext(ctx, bind("SELECT password FROM users WHERE id = ?"), id).Scan(&hashed)
+	if err != nil {
+		return err
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(password))
+}
+
+// UpdateEmail replaces the email of the user with an email that was verified at verifiedAt
+func (repository *userRepository) UpdateEmail(ctx context.Context, tx *sql.Tx, id int, email string, verifiedAt time.Time) error {
+	_, err := tx.ExecContext(ctx, bind("UPDATE users SET email = ?, email_verification = ?, updated_at = ? WHERE id = ?"), email, verifiedAt, verifiedAt, id)
 	if err != nil {
 		return err
 	}
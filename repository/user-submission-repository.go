@@ -13,6 +13,8 @@ type UserSubmissionsRepository interface {
 	UpdateGrade(ctx context.Context, tx *sql.Tx, userSubmission entity.UserSubmissions) error
 	FindUserSubmissionByOther(ctx context.Context, tx *sql.Tx, userSubmission entity.UserSubmissions) (entity.UserSubmissions, error)
 	FindUserSubmissionById(ctx context.Context, tx *sql.Tx, id int) (entity.UserSubmissions, error)
+	UpdateGradedAttempt(ctx context.Context, tx *sql.Tx, id int, attemptId int) error
+	ReplaceFile(ctx context.Context, tx *sql.Tx, oldFile string, newFile string) (int64, error)
 }
 
 type userSubmissionsRepository struct {
@@ -22,11 +24,16 @@ func NewUserSubmissionsRepository() UserSubmissionsRepository {
 	return &userSubmissionsRepository{}
 }
 
+// OnlyCreate adds the empty submission of a user, users who already have one keep it so re-enrolling does not
+// duplicate it
 func (repository *userSubmissionsRepository) OnlyCreate(ctx context.Context, tx *sql.Tx, userId int, moduleSubmissionId int) error {
-	query := `INSERT INTO user_submissions(user_id, module_submission_id) VALUES(?,?)`
+	query := `INSERT INTO user_submissions(user_id, module_submission_id) SELECT CAST(? AS INTEGER), CAST(? AS INTEGER)
+			  WHERE NOT EXISTS (SELECT 1 FROM user_submissions WHERE user_id = ? AND module_submission_id = ?)`
 	_, err := tx.ExecContext(
 		ctx,
-		query,
+		bind(query),
+		userId,
+		moduleSubmissionId,
 		userId,
 		moduleSubmissionId,
 	)
@@ -41,7 +48,7 @@ func (repository *userSubmissionsRepository) UpdateFile(ctx context.Context, tx
 	query := `UPDATE user_submissions SET file = ? WHERE user_id = ? AND module_submission_id = ?`
 	_, err := tx.ExecContext(
 		ctx,
-		query,
+		bind(query),
 		userSubmission.File,
 		userSubmission.UserId,
 		userSubmission.ModuleSubmissionId,
@@ -54,11 +61,12 @@ func (repository *userSubmissionsRepository) UpdateFile(ctx context.Context, tx
 }
 
 func (repository *userSubmissionsRepository) UpdateGrade(ctx context.Context, tx *sql.Tx, userSubmission entity.UserSubmissions) error {
-	query := `UPDATE user_submissions SET grade = ? WHERE id = ?`
+	query := `UPDATE user_submissions SET grade = ?, feedback = ? WHERE id = ?`
 	_, err := tx.ExecContext(
 		ctx,
-		query,
+		bind(query),
 		userSubmission.Grade,
+		userSubmission.Feedback,
 		userSubmission.Id,
 	)
 	if err != nil {
@@ -69,8 +77,8 @@ func (repository *userSubmissionsRepository) UpdateGrade(ctx context.Context, tx
 }
 
 func (repository *userSubmissionsRepository) FindUserSubmissionByOther(ctx context.Context, tx *sql.Tx, userSubmission entity.UserSubmissions) (entity.UserSubmissions, error) {
-	query := `SELECT * FROM user_submissions WHERE user_id = ? AND module_submission_id = ?`
-	queryContext, err := tx.QueryContext(ctx, query, userSubmission.UserId, userSubmission.ModuleSubmissionId)
+	query := `SELECT id, user_id, module_submission_id, file, grade, graded_attempt_id, feedback FROM user_submissions WHERE user_id = ? AND module_submission_id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), userSubmission.UserId, userSubmission.ModuleSubmissionId)
 	if err != nil {
 		return entity.UserSubmissions{}, err
 	}
@@ -89,6 +97,8 @@ func (repository *userSubmissionsRepository) FindUserSubmissionByOther(ctx conte
 			&modsub.ModuleSubmissionId,
 			&modsub.File,
 			&modsub.Grade,
+			&modsub.GradedAttemptId,
+			&modsub.Feedback,
 		)
 		if err != nil {
 			return entity.UserSubmissions{}, err
@@ -101,8 +111,8 @@ func (repository *userSubmissionsRepository) FindUserSubmissionByOther(ctx conte
 }
 
 func (repository *userSubmissionsRepository) FindUserSubmissionById(ctx context.Context, tx *sql.Tx, id int) (entity.UserSubmissions, error) {
-	query := `SELECT * FROM user_submissions WHERE id = ?`
-	queryContext, err := tx.QueryContext(ctx, query, id)
+	query := `SELECT id, user_id, module_submission_id, file, grade, graded_attempt_id, feedback FROM user_submissions WHERE id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), id)
 	if err != nil {
 		return entity.UserSubmissions{}, err
 	}
@@ -121,6 +131,8 @@ func (repository *userSubmissionsRepository) FindUserSubmissionById(ctx context.
 			&modsub.ModuleSubmissionId,
 			&modsub.File,
 			&modsub.Grade,
+			&modsub.GradedAttemptId,
+			&modsub.Feedback,
 		)
 		if err != nil {
 			return entity.UserSubmissions{}, err
@@ -131,3 +143,23 @@ func (repository *userSubmissionsRepository) FindUserSubmissionById(ctx context.
 
 	return modsub, errors.New("user submission not found")
 }
+
+func (repository *userSubmissionsRepository) UpdateGradedAttempt(ctx context.Context, tx *sql.Tx, id int, attemptId int) error {
+	query := `UPDATE user_submissions SET graded_attempt_id = ? WHERE id = ?`
+	_, err := tx.ExecContext(ctx, bind(query), attemptId, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repository *userSubmissionsRepository) ReplaceFile(ctx context.Context, tx *sql.Tx, oldFile string, newFile string) (int64, error) {
+	query := `UPDATE user_submissions SET file = ? WHERE file = ?`
+	result, err := tx.ExecContext(ctx, bind(query), newFile, oldFile)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -6,10 +6,11 @@ import (
 	"errors"
 
 	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
 )
 
 type UserCourseRepository interface {
-	FindAll(ctx context.Context, tx *sql.Tx) ([]entity.UserCourse, error)
+	FindAll(ctx context.Context, tx *sql.Tx, query model.ListQuery) ([]entity.UserCourse, int, error)
 	FindAllCourseByUserId(ctx context.Context, tx *sql.Tx, userId int) ([]entity.StudentCourse, error)
 	FindAllUserByCourseId(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.UserTeacherCourse, error)
 	FindByUserCourse(ctx context.Context, tx *sql.Tx, id string, course string) (entity.UserCourse, error)
@@ -26,11 +27,39 @@ func NewUserCourseRepository() UserCourseRepository {
 	return &usercourseRepository{}
 }
 
-func (repository *usercourseRepository) FindAll(ctx context.Context, tx *sql.Tx) ([]entity.UserCourse, error) {
-	query := `SELECT * FROM user_course ORDER BY user_id DESC`
-	queryContext, err := tx.QueryContext(ctx, query)
+var usercourseList = listSpec{
+	Sorts: map[string]string{
+		"user_id":   "user_id",
+		"course_id": "course_id",
+	},
+	DefaultSort: "-user_id",
+	Tiebreak:    "course_id",
+	Filters: map[string]listFilter{
+		"user_id":   {Column: "user_id", Parse: parseInt},
+		"course_id": {Column: "course_id", Parse: parseInt},
+	},
+}
+
+func (repository *usercourseRepository) FindAll(ctx context.Context, tx *sql.Tx, query model.ListQuery) ([]entity.UserCourse, int, error) {
+	where, args, err := usercourseList.where(query)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+	orderBy, err := usercourseList.orderBy(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	err = tx.QueryRowContext(ctx, bind(`SELECT COUNT(*) FROM user_course`+where), args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	statement := `SELECT user_id, course_id FROM user_course` + where + orderBy
+	queryContext, err := tx.QueryContext(ctx, bind(statement), append(args, query.Limit, query.Offset)...)
+	if err != nil {
+		return nil, 0, err
 	}
 	defer func(queryContext *sql.Rows) {
 		err := queryContext.Close()
@@ -47,20 +76,20 @@ func (repository *usercourseRepository) FindAll(ctx context.Context, tx *sql.Tx)
 			&usercourse.CourseId,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		usercourses = append(usercourses, usercourse)
 	}
 
-	return usercourses, nil
+	return usercourses, total, nil
 }
 
 func (repository *usercourseRepository) FindAllCourseByUserId(ctx context.Context, tx *sql.Tx, userId int) ([]entity.StudentCourse, error) {
 	query := `SELECT c.id,c.name,c.code_course,c.class FROM user_course uc
 			  LEFT JOIN courses c on c.id = uc.course_id
 			  WHERE uc.user_id = ?`
-	queryContext, err := tx.QueryContext(ctx, query, userId)
+	queryContext, err := tx.QueryContext(ctx, bind(query), userId)
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +123,7 @@ func (repository *usercourseRepository) FindAllUserByCourseId(ctx context.Contex
 	query := `SELECT u.id,u.name,u.username,u.email FROM user_course uc
 			  LEFT JOIN users u on u.id = uc.user_id
 			  WHERE uc.course_id = ?`
-	queryContext, err := tx.QueryContext(ctx, query, courseId)
+	queryContext, err := tx.QueryContext(ctx, bind(query), courseId)
 	if err != nil {
 		return nil, err
 	}
@@ -125,8 +154,8 @@ func (repository *usercourseRepository) FindAllUserByCourseId(ctx context.Contex
 }
 
 func (repository *usercourseRepository) FindByUserCourse(ctx context.Context, tx *sql.Tx, id string, course string) (entity.UserCourse, error) {
-	query := `SELECT * FROM user_course WHERE user_id = ? AND course_id = ?`
-	queryContext, err := tx.QueryContext(ctx, query, id, course)
+	query := `SELECT user_id, course_id FROM user_course WHERE user_id = ? AND course_id = ?`
+	queryContext, err := tx.QueryContext(ctx, bind(query), id, course)
 	if err != nil {
 		return entity.UserCourse{}, err
 	}
@@ -154,7 +183,7 @@ func (repository *usercourseRepository) FindByUserCourse(ctx context.Context, tx
 }
 
 func (repository *usercourseRepository) Create(ctx context.Context, tx *sql.Tx, usercourses entity.UserCourse) (entity.UserCourse, error) {
-	_, err := tx.ExecContext(ctx, "INSERT INTO user_course (user_id, course_id) VALUES(?,?)", usercourses.UserId, usercourses.CourseId)
+	_, err := tx.ExecContext(ctx, bind("INSERT INTO user_course (user_id, course_id) VALUES(?,?)"), usercourses.UserId, usercourses.CourseId)
 	if err != nil {
 		return entity.UserCourse{}, err
 	}
@@ -164,7 +193,7 @@ func (repository *usercourseRepository) Create(ctx context.Context, tx *sql.Tx,
 
 func (repository *usercourseRepository) Delete(ctx context.Context, tx *sql.Tx, code1 int, code2 int) error {
 	query := "DELETE FROM user_course WHERE user_id = ? AND course_id = ?"
-	_, err := tx.ExecContext(ctx, query, code1, code2)
+	_, err := tx.ExecContext(ctx, bind(query), code1, code2)
 	if err != nil {
 		return err
 	}
@@ -173,14 +202,16 @@ func (repository *usercourseRepository) Delete(ctx context.Context, tx *sql.Tx,
 }
 
 func (repository *usercourseRepository) FindAllStudentSubmissions(ctx context.Context, tx *sql.Tx, userId int, limit int) ([]entity.StudentSubmissions, error) {
-	query := `SELECT ms.id,c.name,c.code_course,ms.name,us.grade,us.file FROM user_course uc
+	query := `SELECT ms.id,us.id,c.name,c.code_course,ms.name,us.grade,us.file,sa.created_at,ms.deadline,se.deadline,ms.late_policy,ms.grace_period,ms.penalty_per_day,us.feedback FROM user_course uc
 				LEFT JOIN courses c on c.id = uc.course_id
 				LEFT JOIN module_submissions ms on c.id = ms.course_id
 				LEFT JOIN user_submissions us on ms.id = us.module_submission_id
+				LEFT JOIN submission_attempts sa on sa.id = COALESCE(us.graded_attempt_id, (SELECT MAX(id) FROM submission_attempts WHERE user_submission_id = us.id))
+				LEFT JOIN submission_extensions se on se.module_submission_id = ms.id AND se.user_id = us.user_id
 				WHERE uc.user_id = ? AND us.user_id = ?
 				ORDER BY us.file
 			  LIMIT ?`
-	queryContext, err := tx.QueryContext(ctx, query, userId, userId, limit)
+	queryContext, err := tx.QueryContext(ctx, bind(query), userId, userId, limitArg(limit))
 	if err != nil {
 		return nil, err
 	}
@@ -196,11 +227,19 @@ func (repository *usercourseRepository) FindAllStudentSubmissions(ctx context.Co
 		var studentSubmission entity.StudentSubmissions
 		err := queryContext.Scan(
 			&studentSubmission.IdModuleSubmission,
+			&studentSubmission.IdUserSubmission,
 			&studentSubmission.CourseName,
 			&studentSubmission.CodeCourse,
 			&studentSubmission.ModuleSubmissionName,
 			&studentSubmission.Grade,
 			&studentSubmission.File,
+			&studentSubmission.SubmittedAt,
+			&studentSubmission.Deadline,
+			&studentSubmission.ExtendedDeadline,
+			&studentSubmission.LatePolicy,
+			&studentSubmission.GracePeriod,
+			&studentSubmission.PenaltyPerDay,
+			&studentSubmission.Feedback,
 		)
 		if err != nil {
 			return nil, err
@@ -213,13 +252,15 @@ func (repository *usercourseRepository) FindAllStudentSubmissions(ctx context.Co
 }
 
 func (repository *usercourseRepository) FindAllTeacherSubmissions(ctx context.Context, tx *sql.Tx, courseId int, moduleSubmissionId int) ([]entity.TeacherSubmissions, error) {
-	query := `SELECT us.id,u.name,ms.name,us.grade,us.file FROM user_course uc
+	query := `SELECT us.id,u.name,ms.name,us.grade,us.file,sa.created_at,ms.deadline,se.deadline,ms.late_policy,ms.grace_period,ms.penalty_per_day FROM user_course uc
 			  LEFT JOIN users u on u.id = uc.user_id
 			  LEFT JOIN courses c on c.id = uc.course_id
 			  LEFT JOIN module_submissions ms on c.id = ms.course_id
-			  LEFT JOIN user_submissions us on u.id = us.user_id
+			  LEFT JOIN user_submissions us on u.id = us.user_id AND ms.id = us.module_submission_id
+			  LEFT JOIN submission_attempts sa on sa.id = COALESCE(us.graded_attempt_id, (SELECT MAX(id) FROM submission_attempts WHERE user_submission_id = us.id))
+			  LEFT JOIN submission_extensions se on se.module_submission_id = ms.id AND se.user_id = u.id
 			  WHERE c.id = ? AND ms.id = ?`
-	queryContext, err := tx.QueryContext(ctx, query, courseId, moduleSubmissionId)
+	queryContext, err := tx.QueryContext(ctx, bind(query), courseId, moduleSubmissionId)
 	if err != nil {
 		return nil, err
 	}
@@ -239,6 +280,12 @@ func (repository *usercourseRepository) FindAllTeacherSubmissions(ctx context.Co
 			&studentSubmission.ModuleSubmissionName,
 			&studentSubmission.Grade,
 			&studentSubmission.File,
+			&studentSubmission.SubmittedAt,
+			&studentSubmission.Deadline,
+			&studentSubmission.ExtendedDeadline,
+			&studentSubmission.LatePolicy,
+			&studentSubmission.GracePeriod,
+			&studentSubmission.PenaltyPerDay,
 		)
 		if err != nil {
 			return nil, err
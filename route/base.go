@@ -1,19 +1,27 @@
 package route
 
 import (
+	"context"
+	"strconv"
+	"time"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/rg-km/final-project-engineering-12/backend/config"
 	"github.com/rg-km/final-project-engineering-12/backend/controller"
+	"github.com/rg-km/final-project-engineering-12/backend/mail"
+	"github.com/rg-km/final-project-engineering-12/backend/middleware"
 	"github.com/rg-km/final-project-engineering-12/backend/repository"
 	"github.com/rg-km/final-project-engineering-12/backend/service"
+	"github.com/rg-km/final-project-engineering-12/backend/storage"
 )
 
 func NewInitializedServer(configuration config.Config) *gin.Engine {
 	// Configuration
 	router := gin.Default()
-	database := config.NewSQLite(configuration)
+	database := config.NewDatabase(configuration)
+	repository.UseDriver(configuration.Get("DB_CONNECTION"))
 
 	// setup gin cors
 	router.Use(cors.New(cors.Config{
@@ -32,30 +40,121 @@ func NewInitializedServer(configuration config.Config) *gin.Engine {
 		panic(err)
 	}
 
+	// Session Setup
+	sessionRepository := repository.NewSessionRepository()
+	sessionStore := service.NewSQLSessionStore(&sessionRepository, database)
+	service.UseSessionStore(sessionStore)
+
+	purgeInterval, err := strconv.Atoi(configuration.Get("SESSION_PURGE_INTERVAL_MINUTE"))
+	if err != nil || purgeInterval <= 0 {
+		purgeInterval = 10
+	}
+	service.StartSessionPurger(context.Background(), sessionStore, time.Duration(purgeInterval)*time.Minute)
+
+	// Mail Setup
+	transport, err := mail.New(configuration)
+	if err != nil {
+		panic(err)
+	}
+	mailLanguage := configuration.Get("MAIL_LANGUAGE")
+	if mailLanguage == "" {
+		mailLanguage = "en"
+	}
+	templates, err := mail.NewTemplates(mailLanguage)
+	if err != nil {
+		panic(err)
+	}
+
+	maxAttempts, err := strconv.Atoi(configuration.Get("MAIL_MAX_ATTEMPTS"))
+	if err != nil || maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+	retryDelay, err := strconv.Atoi(configuration.Get("MAIL_RETRY_DELAY_SECOND"))
+	if err != nil || retryDelay <= 0 {
+		retryDelay = 60
+	}
+	mailInterval, err := strconv.Atoi(configuration.Get("MAIL_WORKER_INTERVAL_SECOND"))
+	if err != nil || mailInterval <= 0 {
+		mailInterval = 5
+	}
+	emailOutboxRepository := repository.NewEmailOutboxRepository()
+	emailWorker := service.NewEmailWorker(&emailOutboxRepository, transport, maxAttempts, time.Duration(retryDelay)*time.Second, database)
+	service.StartEmailWorker(context.Background(), emailWorker, time.Duration(mailInterval)*time.Second)
+
+	// Storage Setup
+	store, err := storage.New(configuration)
+	if err != nil {
+		panic(err)
+	}
+	fileController := controller.NewFileController(store)
+	uploadLimits := storage.Limits(configuration)
+
 	// Course Setup
 	courseRepository := repository.NewCourseRepository()
 	courseService := service.NewCourseService(&courseRepository, database)
 
+	// Curriculum Setup
+	curriculumRepository := repository.NewCurriculumRepository()
+	curriculumService := service.NewCurriculumService(&curriculumRepository, &courseRepository, database)
+	curriculumController := controller.NewCurriculumController(&curriculumService)
+
 	// Module Articles Setup
 	moduleArticlesRepository := repository.NewModuleArticlesRepository()
-	moduleArticlesService := service.NewModuleArticlesService(&moduleArticlesRepository, &courseRepository, database)
-	moduleArticlesController := controller.NewModuleArticlesController(&moduleArticlesService)
+	moduleArticlesService := service.NewModuleArticlesService(&moduleArticlesRepository, &courseRepository, &curriculumRepository, database)
 
 	// Module Submission Setup
 	moduleSubmissionRepository := repository.NewModuleSubmissionsRepository()
 
 	// User Submission Setup
 	userSubmissionRepository := repository.NewUserSubmissionsRepository()
-	userSubmissionService := service.NewUserSubmissionsService(&userSubmissionRepository, &moduleSubmissionRepository, &courseRepository, database)
-	userSubmissionController := controller.NewUserSubmissionsController(&userSubmissionService)
+	submissionAttemptRepository := repository.NewSubmissionAttemptRepository()
+	submissionExtensionRepository := repository.NewSubmissionExtensionRepository()
+	userSubmissionService := service.NewUserSubmissionsService(&userSubmissionRepository, &submissionAttemptRepository, &moduleSubmissionRepository, &submissionExtensionRepository, &courseRepository, database, store, storage.URLLifetime(configuration), uploadLimits)
+	userSubmissionController := controller.NewUserSubmissionsController(&userSubmissionService, uploadLimits.MaxSize)
+
+	// Rubric Setup
+	rubricRepository := repository.NewRubricRepository()
+	rubricService := service.NewRubricService(&rubricRepository, &userSubmissionRepository, &moduleSubmissionRepository, &courseRepository, database)
+	rubricController := controller.NewRubricController(&rubricService)
 
 	// UserCourse Setup
 	userCourseRepository := repository.NewUserCourseRepository()
-	userCourseService := service.NewUserCourseService(&userCourseRepository, &courseRepository, &moduleSubmissionRepository, &userSubmissionRepository, database)
+	quizRepository := repository.NewQuizRepository()
+	userQuizRepository := repository.NewUserQuizRepository()
+	progressRepository := repository.NewProgressRepository()
+	enrollmentRepository := repository.NewEnrollmentRepository()
+	userCourseService := service.NewUserCourseService(&userCourseRepository, &courseRepository, &moduleSubmissionRepository, &userSubmissionRepository, &rubricRepository, &quizRepository, &userQuizRepository, &curriculumRepository, &progressRepository, &enrollmentRepository, database)
+	progressService := service.NewProgressService(&progressRepository, &curriculumRepository, &userCourseRepository, &courseRepository, database)
+	progressController := controller.NewProgressController(&progressService)
 	userCourseController := controller.NewUserCourseController(&userCourseService)
 
+	// Enrollment Setup
+	enrollmentService := service.NewEnrollmentService(&enrollmentRepository, &userCourseRepository, &courseRepository, &moduleSubmissionRepository, &userSubmissionRepository, &quizRepository, &userQuizRepository, database)
+	enrollmentController := controller.NewEnrollmentController(&enrollmentService)
+
+	// Gradebook Setup
+	gradebookRepository := repository.NewGradebookRepository()
+	gradebookService := service.NewGradebookService(&gradebookRepository, &userCourseRepository, &moduleSubmissionRepository, &rubricRepository, &courseRepository, database)
+	gradebookController := controller.NewGradebookController(&gradebookService)
+
+	// Certificate Setup
+	passingScore, err := strconv.ParseFloat(configuration.Get("CERTIFICATE_PASSING_SCORE"), 64)
+	if err != nil || passingScore < 0 {
+		passingScore = 60
+	}
+	certificateRepository := repository.NewCertificateRepository()
+	certificateService := service.NewCertificateService(&certificateRepository, &progressRepository, &curriculumRepository, &userCourseRepository, &courseRepository, &gradebookRepository, &moduleSubmissionRepository, &rubricRepository, database, passingScore, configuration.Get("CERTIFICATE_PUBLIC_URL"))
+	certificateController := controller.NewCertificateController(&certificateService)
+
+	// Quiz Setup
+	quizAttemptRepository := repository.NewQuizAttemptRepository()
+	quizService := service.NewQuizService(&quizRepository, &quizAttemptRepository, &userQuizRepository, &userCourseRepository, &courseRepository, &curriculumRepository, database)
+	quizController := controller.NewQuizController(&quizService)
+
+	// ---  Module Articles Setup
+	moduleArticlesController := controller.NewModuleArticlesController(&moduleArticlesService, &progressService)
 	// ---  Module Submission Setup
-	moduleSubmissionService := service.NewModuleSubmissionsService(&moduleSubmissionRepository, &courseRepository, &userCourseRepository, &userSubmissionRepository, database)
+	moduleSubmissionService := service.NewModuleSubmissionsService(&moduleSubmissionRepository, &courseRepository, &userCourseRepository, &userSubmissionRepository, &submissionExtensionRepository, &gradebookRepository, &curriculumRepository, database)
 	moduleSubmissionController := controller.NewModuleSubmissionsController(&moduleSubmissionService, &userCourseService)
 	// ---  Course Setup
 	courseController := controller.NewCourseController(&courseService, &userCourseService)
@@ -73,22 +172,125 @@ func NewInitializedServer(configuration config.Config) *gin.Engine {
 	answerController := controller.NewAnswerController(&answerService)
 
 	// Email Verification Setup
+	verificationUrl := configuration.Get("VERIFICATION_URL")
+	if verificationUrl == "" {
+		verificationUrl = "http://localhost:8080/verification"
+	}
+	requireVerified, err := strconv.ParseBool(configuration.Get("AUTH_REQUIRE_VERIFIED_EMAIL"))
+	if err != nil {
+		requireVerified = false
+	}
+	resendInterval, err := strconv.Atoi(configuration.Get("VERIFICATION_RESEND_INTERVAL_SECOND"))
+	if err != nil || resendInterval <= 0 {
+		resendInterval = 60
+	}
+	resendLimit, err := strconv.Atoi(configuration.Get("VERIFICATION_RESEND_LIMIT"))
+	if err != nil || resendLimit <= 0 {
+		resendLimit = 5
+	}
+	resendWindow, err := strconv.Atoi(configuration.Get("VERIFICATION_RESEND_WINDOW_MINUTE"))
+	if err != nil || resendWindow <= 0 {
+		resendWindow = 15
+	}
 	emailVerificationRepository := repository.NewEmailVerificationRepository()
-	emailVerificationService := service.NewEmailService(&emailVerificationRepository, &userRepository, database)
+	emailVerificationService := service.NewEmailService(&emailVerificationRepository, &userRepository, database, &emailOutboxRepository, templates, verificationUrl, time.Duration(resendInterval)*time.Second)
+	resendLimiter := middleware.NewRateLimiter(resendLimit, time.Duration(resendWindow)*time.Minute)
+
+	// Refresh Token Setup
+	refreshTokenLifetime, err := strconv.Atoi(configuration.Get("REFRESH_TOKEN_LIFETIME_DAY"))
+	if err != nil || refreshTokenLifetime <= 0 {
+		refreshTokenLifetime = 30
+	}
+	refreshTokenRepository := repository.NewRefreshTokenRepository()
+	refreshTokenService := service.NewRefreshTokenService(&refreshTokenRepository, &userRepository, database, time.Duration(refreshTokenLifetime)*24*time.Hour)
+
+	// Password Reset Setup
+	passwordResetUrl := configuration.Get("PASSWORD_RESET_URL")
+	if passwordResetUrl == "" {
+		passwordResetUrl = "http://localhost:8080/reset-password"
+	}
+	passwordResetLifetime, err := strconv.Atoi(configuration.Get("PASSWORD_RESET_LIFETIME_MINUTE"))
+	if err != nil || passwordResetLifetime <= 0 {
+		passwordResetLifetime = 60
+	}
+	passwordResetLimit, err := strconv.Atoi(configuration.Get("PASSWORD_RESET_LIMIT"))
+	if err != nil || passwordResetLimit <= 0 {
+		passwordResetLimit = 5
+	}
+	passwordResetWindow, err := strconv.Atoi(configuration.Get("PASSWORD_RESET_WINDOW_MINUTE"))
+	if err != nil || passwordResetWindow <= 0 {
+		passwordResetWindow = 15
+	}
+	passwordResetRepository := repository.NewPasswordResetRepository()
+	passwordResetService := service.NewPasswordResetService(&passwordResetRepository, &userRepository, &refreshTokenRepository, sessionStore, &emailOutboxRepository, templates, passwordResetUrl, time.Duration(passwordResetLifetime)*time.Minute, database)
+	passwordResetController := controller.NewPasswordResetController(&passwordResetService, middleware.NewRateLimiter(passwordResetLimit, time.Duration(passwordResetWindow)*time.Minute))
+
+	// Account Setup
+	emailChangeUrl := configuration.Get("EMAIL_CHANGE_URL")
+	if emailChangeUrl == "" {
+		emailChangeUrl = "http://localhost:8080/confirm-email"
+	}
+	emailChangeRepository := repository.NewEmailChangeRepository()
+	accountService := service.NewAccountService(&userRepository, &emailChangeRepository, &refreshTokenRepository, sessionStore, &emailOutboxRepository, templates, emailChangeUrl, database)
+	accountController := controller.NewAccountController(&accountService)
+
+	// Two-Factor Setup
+	twoFactorIssuer := configuration.Get("TWO_FACTOR_ISSUER")
+	if twoFactorIssuer == "" {
+		twoFactorIssuer = "Teenager"
+	}
+	twoFactorLimit, err := strconv.Atoi(configuration.Get("TWO_FACTOR_LIMIT"))
+	if err != nil || twoFactorLimit <= 0 {
+		twoFactorLimit = 10
+	}
+	twoFactorWindow, err := strconv.Atoi(configuration.Get("TWO_FACTOR_WINDOW_MINUTE"))
+	if err != nil || twoFactorWindow <= 0 {
+		twoFactorWindow = 15
+	}
+	twoFactorRepository := repository.NewTwoFactorRepository()
+	twoFactorService := service.NewTwoFactorService(&twoFactorRepository, &userRepository, &refreshTokenRepository, sessionStore, twoFactorIssuer, database)
+	twoFactorController := controller.NewTwoFactorController(&twoFactorService, &refreshTokenService, middleware.NewRateLimiter(twoFactorLimit, time.Duration(twoFactorWindow)*time.Minute))
+
+	// Teaching Assignment Setup
+	teachingAssignmentRepository := repository.NewTeachingAssignmentRepository()
+	teachingAssignmentService := service.NewTeachingAssignmentService(&teachingAssignmentRepository, &courseRepository, &userRepository, database)
+	teachingAssignmentController := controller.NewTeachingAssignmentController(&teachingAssignmentService)
+	middleware.UseCourseScope(teachingAssignmentService)
+
+	// Search Setup
+	searchRepository := repository.NewSearchRepository()
+	searchService := service.NewSearchService(&searchRepository, database)
+	searchController := controller.NewSearchController(&searchService)
 
 	// User Setup
-	userService := service.NewUserService(&userRepository, database, &emailVerificationRepository)
-	userController := controller.NewUserController(&userService, &userCourseService, &emailVerificationService)
+	userService := service.NewUserService(&userRepository, database, &emailVerificationRepository, &emailOutboxRepository, templates, verificationUrl, requireVerified)
+	userController := controller.NewUserController(&userService, &userCourseService, &emailVerificationService, &refreshTokenService, &twoFactorService, resendLimiter)
+	userImportService := service.NewUserImportService(&userRepository, &emailVerificationRepository, &courseRepository, &enrollmentRepository, &userCourseRepository, &moduleSubmissionRepository, &userSubmissionRepository, &quizRepository, &userQuizRepository, &emailOutboxRepository, templates, verificationUrl, database)
+	userImportController := controller.NewUserImportController(&userImportService)
 
 	// Routing
 	userController.Route(router)
+	userImportController.Route(router)
+	passwordResetController.Route(router)
+	accountController.Route(router)
+	twoFactorController.Route(router)
 	courseController.Route(router)
 	moduleArticlesController.Route(router)
 	moduleSubmissionController.Route(router)
 	userSubmissionController.Route(router)
+	rubricController.Route(router)
+	gradebookController.Route(router)
+	quizController.Route(router)
+	curriculumController.Route(router)
+	progressController.Route(router)
+	certificateController.Route(router)
+	enrollmentController.Route(router)
 	userCourseController.Route(router)
 	questionController.Route(router)
 	answerController.Route(router)
+	teachingAssignmentController.Route(router)
+	searchController.Route(router)
+	fileController.Route(router)
 
 	return router
 }
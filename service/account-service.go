@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/mail"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/repository"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var (
+	ErrPasswordIncorrect  = errors.New("the current password is incorrect")
+	ErrEmailUnchanged     = errors.New("the email is already the email of the account")
+	ErrEmailTaken         = errors.New("email has been registered")
+	ErrEmailChangeInvalid = errors.New("the email confirmation link is invalid or expired")
+)
+
+type AccountService interface {
+	// ChangePassword sets a new password for the user once the current password is confirmed and logs the user out everywhere
+	ChangePassword(ctx context.Context, userId int, request model.ChangePasswordRequest) error
+	// ChangeEmail emails a confirmation link to the new email, the email of the user only changes once it is confirmed
+	ChangeEmail(ctx context.Context, userId int, request model.ChangeEmailRequest, languages string) error
+	// ConfirmEmailChange replaces the email of the user with the email the token was sent to
+	ConfirmEmailChange(ctx context.Context, token string) error
+}
+
+type accountService struct {
+	UserRepository         repository.UserRepository
+	EmailChangeRepository  repository.EmailChangeRepository
+	RefreshTokenRepository repository.RefreshTokenRepository
+	SessionStore           SessionStore
+	Mailer                 *mailer
+	EmailChangeUrl         string
+	DB                     *sql.DB
+}
+
+// NewAccountService links the confirmation emails of email changes to emailChangeUrl
+func NewAccountService(userRepository *repository.UserRepository, emailChangeRepository *repository.EmailChangeRepository, refreshTokenRepository *repository.RefreshTokenRepository, sessionStore SessionStore, emailOutboxRepository *repository.EmailOutboxRepository, templates *mail.Templates, emailChangeUrl string, db *sql.DB) AccountService {
+	return &accountService{
+		UserRepository:         *userRepository,
+		EmailChangeRepository:  *emailChangeRepository,
+		RefreshTokenRepository: *refreshTokenRepository,
+		SessionStore:           sessionStore,
+		Mailer:                 newMailer(emailOutboxRepository, templates, ""),
+		EmailChangeUrl:         emailChangeUrl,
+		DB:                     db,
+	}
+}
+
+func (service *accountService) ChangePassword(ctx context.Context, userId int, request model.ChangePasswordRequest) error {
+	err := service.changePassword(ctx, userId, request)
+	if err != nil {
+		return err
+	}
+
+	return service.SessionStore.DeleteByUser(ctx, userId)
+}
+
+// changePassword saves the password and revokes the refresh tokens of the user
+func (service *accountService) changePassword(ctx context.Context, userId int, request model.ChangePasswordRequest) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	err = service.UserRepository.CheckPassword(ctx, tx, userId, request.CurrentPassword)
+	if err != nil {
+		return ErrPasswordIncorrect
+	}
+
+	now := time.Now()
+	err = service.UserRepository.UpdatePassword(ctx, tx, userId, request.NewPassword, now)
+	if err != nil {
+		return err
+	}
+
+	return service.RefreshTokenRepository.RevokeFamiliesByUser(ctx, tx, userId, now.Unix())
+}
+
+func (service *accountService) ChangeEmail(ctx context.Context, userId int, request model.ChangeEmailRequest, languages string) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	err = service.UserRepository.CheckPassword(ctx, tx, userId, request.Password)
+	if err != nil {
+		return ErrPasswordIncorrect
+	}
+
+	user, err := service.UserRepository.GetUserByID(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(user.Email, request.Email) {
+		return ErrEmailUnchanged
+	}
+
+	err = service.UserRepository.CheckRegistered(ctx, tx, "", request.Email)
+	if err != nil {
+		return ErrEmailTaken
+	}
+
+	token, err := utils.RandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	// Only the hash is stored, the token itself is only in the email
+	now := time.Now()
+	_, err = service.EmailChangeRepository.Create(ctx, tx, entity.EmailChanges{
+		UserId:    userId,
+		Email:     request.Email,
+		TokenHash: utils.HashToken(token),
+		ExpiredAt: now.Add(verificationLifetime).Unix(),
+		CreatedAt: now.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("token", token)
+
+	return service.Mailer.queue(ctx, tx, request.Email, "email-change", languages, struct {
+		Name    string
+		Url     string
+		Minutes int
+	}{
+		Name:    user.Name,
+		Url:     service.EmailChangeUrl + "?" + query.Encode(),
+		Minutes: int(verificationLifetime.Minutes()),
+	})
+}
+
+func (service *accountService) ConfirmEmailChange(ctx context.Context, token string) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	now := time.Now()
+
+	emailChange, err := service.EmailChangeRepository.FindByHash(ctx, tx, utils.HashToken(token))
+	if err != nil {
+		return ErrEmailChangeInvalid
+	}
+	if emailChange.UsedAt != nil || now.Unix() >= emailChange.ExpiredAt {
+		return ErrEmailChangeInvalid
+	}
+
+	// Someone may have registered with the email since the link was sent
+	err = service.UserRepository.CheckRegistered(ctx, tx, "", emailChange.Email)
+	if err != nil {
+		return ErrEmailTaken
+	}
+
+	unused, err := service.EmailChangeRepository.MarkUsed(ctx, tx, emailChange.Id, now.Unix())
+	if err != nil {
+		return err
+	}
+	if !unused {
+		return ErrEmailChangeInvalid
+	}
+
+	// Opening the link proves the user owns the email, so it is verified as well
+	err = service.UserRepository.UpdateEmail(ctx, tx, emailChange.UserId, emailChange.Email, now)
+	if err != nil {
+		return err
+	}
+
+	// Links sent for other emails before can not change the email again
+	return service.EmailChangeRepository.MarkUsedByUser(ctx, tx, emailChange.UserId, now.Unix())
+}
@@ -11,7 +11,7 @@ import (
 )
 
 type AnswerService interface {
-	FindAll(ctx context.Context) ([]model.GetAnswerResponse, error)
+	FindAll(ctx context.Context, query model.ListQuery) ([]model.GetAnswerResponse, model.ListPage, error)
 	Create(ctx context.Context, request model.CreateAnswerRequest) (model.GetAnswerResponse, error)
 	Delete(ctx context.Context, answerId int) error
 	Update(ctx context.Context, request model.UpdateAnswerRequest, answerId int) (model.GetAnswerResponse, error)
@@ -59,16 +59,16 @@ func (service *answerService) Create(ctx context.Context, request model.CreateAn
 	return utils.ToAnswerResponse(answer), nil
 }
 
-func (service *answerService) FindAll(ctx context.Context) ([]model.GetAnswerResponse, error) {
+func (service *answerService) FindAll(ctx context.Context, query model.ListQuery) ([]model.GetAnswerResponse, model.ListPage, error) {
 	tx, err := service.DB.Begin()
 	if err != nil {
-		return []model.GetAnswerResponse{}, err
+		return []model.GetAnswerResponse{}, model.ListPage{}, err
 	}
 	defer utils.CommitOrRollback(tx)
 
-	courses, err := service.AnswerRepository.FindAll(ctx, tx)
+	courses, total, err := service.AnswerRepository.FindAll(ctx, tx, query)
 	if err != nil {
-		return []model.GetAnswerResponse{}, err
+		return []model.GetAnswerResponse{}, model.ListPage{}, err
 	}
 
 	var courseResponses []model.GetAnswerResponse
@@ -76,7 +76,7 @@ func (service *answerService) FindAll(ctx context.Context) ([]model.GetAnswerRes
 		courseResponses = append(courseResponses, utils.ToAnswerResponse(answer))
 	}
 
-	return courseResponses, nil
+	return courseResponses, utils.ToListPage(query, len(courses), total), nil
 }
 
 func (service *answerService) Delete(ctx context.Context, answerId int) error {
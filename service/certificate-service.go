@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/pdf"
+	"github.com/rg-km/final-project-engineering-12/backend/repository"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var (
+	ErrCourseNotCompleted  = errors.New("complete every item of the course first")
+	ErrNotPassed           = errors.New("the final score of the course is below the passing score")
+	ErrCertificateNotFound = errors.New("certificate not found")
+)
+
+type CertificateService interface {
+	Issue(ctx context.Context, code string, userId int) (model.GetCertificateResponse, error)
+	Certificate(ctx context.Context, code string, userId int) (model.GetCertificateResponse, error)
+	Download(ctx context.Context, writer io.Writer, code string, userId int) error
+	Verify(ctx context.Context, certificateCode string) (model.GetCertificateResponse, error)
+}
+
+// certificateService issues certificates to the students who completed every curriculum item of a course and, when
+// the course has graded module submissions, reached PassingScore in the gradebook. PublicUrl prefixes the
+// verification links printed on the certificates, they are relative when it is empty.
+type certificateService struct {
+	CertificateRepository repository.CertificateRepository
+	ProgressRepository    repository.ProgressRepository
+	CurriculumRepository  repository.CurriculumRepository
+	UserCourseRepository  repository.UserCourseRepository
+	CourseRepository      repository.CourseRepository
+	Gradebook             *gradebookService
+	DB                    *sql.DB
+	PassingScore          float64
+	PublicUrl             string
+}
+
+func NewCertificateService(certificateRepository *repository.CertificateRepository, progressRepository *repository.ProgressRepository, curriculumRepository *repository.CurriculumRepository, userCourseRepository *repository.UserCourseRepository, courseRepository *repository.CourseRepository, gradebookRepository *repository.GradebookRepository, moduleSubmissionsRepository *repository.ModuleSubmissionsRepository, rubricRepository *repository.RubricRepository, db *sql.DB, passingScore float64, publicUrl string) CertificateService {
+	return &certificateService{
+		CertificateRepository: *certificateRepository,
+		ProgressRepository:    *progressRepository,
+		CurriculumRepository:  *curriculumRepository,
+		UserCourseRepository:  *userCourseRepository,
+		CourseRepository:      *courseRepository,
+		Gradebook: &gradebookService{
+			GradebookRepository:         *gradebookRepository,
+			UserCourseRepository:        *userCourseRepository,
+			ModuleSubmissionsRepository: *moduleSubmissionsRepository,
+			RubricRepository:            *rubricRepository,
+			CourseRepository:            *courseRepository,
+			DB:                          db,
+		},
+		DB:           db,
+		PassingScore: passingScore,
+		PublicUrl:    strings.TrimSuffix(publicUrl, "/"),
+	}
+}
+
+// Issue issues the certificate of the course to the user, a user who already has it gets the same certificate back
+func (service *certificateService) Issue(ctx context.Context, code string, userId int) (model.GetCertificateResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetCertificateResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return model.GetCertificateResponse{}, err
+	}
+
+	_, err = service.UserCourseRepository.FindByUserCourse(ctx, tx, strconv.Itoa(userId), strconv.Itoa(course.Id))
+	if err != nil {
+		return model.GetCertificateResponse{}, ErrNotEnrolled
+	}
+
+	certificate, err := service.CertificateRepository.FindByUserCourse(ctx, tx, userId, course.Id)
+	if err == nil {
+		return utils.ToCertificateResponse(certificate, service.verifyUrl(certificate.Code)), nil
+	}
+
+	items, err := service.CurriculumRepository.FindAllItems(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetCertificateResponse{}, err
+	}
+
+	progresses, err := findAllProgress(ctx, tx, service.ProgressRepository, course.Id)
+	if err != nil {
+		return model.GetCertificateResponse{}, err
+	}
+
+	progress := courseProgress(course, items, progresses, userId)
+	if progress.Total == 0 || progress.Completed < progress.Total {
+		return model.GetCertificateResponse{}, ErrCourseNotCompleted
+	}
+
+	gradebook, err := service.Gradebook.gradebook(ctx, tx, code)
+	if err != nil {
+		return model.GetCertificateResponse{}, err
+	}
+
+	var student *model.GetGradebookStudentResponse
+	for i := range gradebook.Students {
+		if gradebook.Students[i].UserId == userId {
+			student = &gradebook.Students[i]
+		}
+	}
+	if student == nil {
+		return model.GetCertificateResponse{}, ErrNotEnrolled
+	}
+
+	if len(gradebook.Assignments) > 0 && (student.Score == nil || *student.Score < service.PassingScore) {
+		return model.GetCertificateResponse{}, ErrNotPassed
+	}
+
+	certificateCode, err := newCertificateCode()
+	if err != nil {
+		return model.GetCertificateResponse{}, err
+	}
+
+	certificate, err = service.CertificateRepository.Create(ctx, tx, entity.Certificates{
+		UserId:      userId,
+		CourseId:    course.Id,
+		CodeCourse:  course.CodeCourse,
+		Code:        certificateCode,
+		StudentName: student.Name,
+		CourseName:  course.Name,
+		Score:       student.Score,
+		IssuedAt:    time.Now().UTC(),
+	})
+	if err != nil {
+		return model.GetCertificateResponse{}, err
+	}
+
+	return utils.ToCertificateResponse(certificate, service.verifyUrl(certificate.Code)), nil
+}
+
+func (service *certificateService) Certificate(ctx context.Context, code string, userId int) (model.GetCertificateResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetCertificateResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	certificate, err := service.findByUserCourse(ctx, tx, code, userId)
+	if err != nil {
+		return model.GetCertificateResponse{}, err
+	}
+
+	return utils.ToCertificateResponse(certificate, service.verifyUrl(certificate.Code)), nil
+}
+
+// Download writes the certificate of the user as a PDF
+func (service *certificateService) Download(ctx context.Context, writer io.Writer, code string, userId int) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	certificate, err := service.findByUserCourse(ctx, tx, code, userId)
+	if err != nil {
+		return err
+	}
+
+	return writeCertificate(writer, utils.ToCertificateResponse(certificate, service.verifyUrl(certificate.Code)))
+}
+
+// Verify looks a certificate up by its code, the code is not case sensitive
+func (service *certificateService) Verify(ctx context.Context, certificateCode string) (model.GetCertificateResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetCertificateResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	certificate, err := service.CertificateRepository.FindByCode(ctx, tx, strings.ToUpper(strings.TrimSpace(certificateCode)))
+	if err != nil {
+		return model.GetCertificateResponse{}, ErrCertificateNotFound
+	}
+
+	return utils.ToCertificateResponse(certificate, service.verifyUrl(certificate.Code)), nil
+}
+
+func (service *certificateService) findByUserCourse(ctx context.Context, tx *sql.Tx, code string, userId int) (entity.Certificates, error) {
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return entity.Certificates{}, err
+	}
+
+	certificate, err := service.CertificateRepository.FindByUserCourse(ctx, tx, userId, course.Id)
+	if err != nil {
+		return entity.Certificates{}, ErrCertificateNotFound
+	}
+
+	return certificate, nil
+}
+
+func (service *certificateService) verifyUrl(certificateCode string) string {
+	return fmt.Sprintf("%v/api/certificates/%v/verify", service.PublicUrl, certificateCode)
+}
+
+// newCertificateCode returns 12 random hexadecimal digits in groups of four, like 3F9A-0C21-B7E4
+func newCertificateCode() (string, error) {
+	token, err := utils.RandomToken(6)
+	if err != nil {
+		return "", err
+	}
+	token = strings.ToUpper(token)
+
+	return fmt.Sprintf("%v-%v-%v", token[0:4], token[4:8], token[8:12]), nil
+}
+
+// writeCertificate draws the certificate on a landscape A4 page
+func writeCertificate(writer io.Writer, certificate model.GetCertificateResponse) error {
+	document := pdf.New(pdf.A4Height, pdf.A4Width)
+	width := document.Width()
+	height := document.Height()
+
+	document.Rect(20, 20, width-40, height-40, 3)
+	document.Rect(30, 30, width-60, height-60, 1)
+
+	document.CenteredText(470, 36, true, "Certificate of Completion")
+	document.CenteredText(410, 16, false, "This certifies that")
+	document.CenteredText(360, fitSize(certificate.StudentName, 30, true, width-120), true, certificate.StudentName)
+	document.Line(width/2-200, 348, width/2+200, 348, 1)
+	document.CenteredText(310, 16, false, "has successfully completed the course")
+	document.CenteredText(265, fitSize(certificate.CourseName, 24, true, width-120), true, certificate.CourseName)
+	if certificate.Score != nil {
+		score := strconv.FormatFloat(math.Round(*certificate.Score*100)/100, 'f', -1, 64)
+		document.CenteredText(230, 14, false, fmt.Sprintf("with a final score of %v", score))
+	}
+	document.CenteredText(150, 12, false, fmt.Sprintf("Issued on %v", certificate.IssuedAt.Format("2 January 2006")))
+	document.CenteredText(80, 11, true, fmt.Sprintf("Verification code: %v", certificate.Code))
+	document.CenteredText(62, 10, false, fmt.Sprintf("Verify at %v", certificate.VerifyUrl))
+
+	return document.Write(writer)
+}
+
+// fitSize shrinks the font size until the text fits in width
+func fitSize(text string, size float64, bold bool, width float64) float64 {
+	for size > 8 && pdf.TextWidth(text, size, bold) > width {
+		size--
+	}
+
+	return size
+}
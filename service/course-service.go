@@ -10,7 +10,7 @@ import (
 )
 
 type CourseService interface {
-	FindAll(ctx context.Context, status bool, limit int) ([]model.GetCourseResponse, error)
+	FindAll(ctx context.Context, query model.ListQuery) ([]model.GetCourseResponse, model.ListPage, error)
 	FindByCode(ctx context.Context, code string) (model.GetCourseResponse, error)
 	Create(ctx context.Context, request model.CreateCourseRequest) (model.GetCourseResponse, error)
 	Update(ctx context.Context, request model.UpdateCourseRequest, code string) (model.GetCourseResponse, error)
@@ -30,16 +30,16 @@ func NewCourseService(courseRepository *repository.CourseRepository, db *sql.DB)
 	}
 }
 
-func (service *courseService) FindAll(ctx context.Context, status bool, limit int) ([]model.GetCourseResponse, error) {
+func (service *courseService) FindAll(ctx context.Context, query model.ListQuery) ([]model.GetCourseResponse, model.ListPage, error) {
 	tx, err := service.DB.Begin()
 	if err != nil {
-		return []model.GetCourseResponse{}, err
+		return []model.GetCourseResponse{}, model.ListPage{}, err
 	}
 	defer utils.CommitOrRollback(tx)
 
-	courses, err := service.CourseRepository.FindAll(ctx, tx, status, limit)
+	courses, total, err := service.CourseRepository.FindAll(ctx, tx, query)
 	if err != nil {
-		return []model.GetCourseResponse{}, err
+		return []model.GetCourseResponse{}, model.ListPage{}, err
 	}
 
 	var courseResponses []model.GetCourseResponse
@@ -47,7 +47,7 @@ func (service *courseService) FindAll(ctx context.Context, status bool, limit in
 		courseResponses = append(courseResponses, utils.ToCourseResponse(course))
 	}
 
-	return courseResponses, nil
+	return courseResponses, utils.ToListPage(query, len(courses), total), nil
 }
 
 func (service *courseService) FindByCode(ctx context.Context, code string) (model.GetCourseResponse, error) {
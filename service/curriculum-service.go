@@ -0,0 +1,411 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/repository"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var (
+	ErrSectionNotFound        = errors.New("section not found")
+	ErrCurriculumItemNotFound = errors.New("curriculum item not found")
+	ErrCurriculumMismatch     = errors.New("the curriculum must list every section and item of the course once")
+)
+
+type CurriculumService interface {
+	Curriculum(ctx context.Context, code string) (model.GetCurriculumResponse, error)
+	Reorder(ctx context.Context, code string, request model.ReorderCurriculumRequest) (model.GetCurriculumResponse, error)
+	CreateSection(ctx context.Context, code string, request model.SaveCourseSectionRequest) (model.GetCourseSectionResponse, error)
+	UpdateSection(ctx context.Context, code string, request model.SaveCourseSectionRequest) (model.GetCourseSectionResponse, error)
+	DeleteSection(ctx context.Context, code string, id int) error
+	Next(ctx context.Context, code string, itemType string, itemId int) (model.GetNextPreviousCurriculumResponse, error)
+	Previous(ctx context.Context, code string, itemType string, itemId int) (model.GetNextPreviousCurriculumResponse, error)
+}
+
+type curriculumService struct {
+	CurriculumRepository repository.CurriculumRepository
+	CourseRepository     repository.CourseRepository
+	DB                   *sql.DB
+}
+
+func NewCurriculumService(curriculumRepository *repository.CurriculumRepository, courseRepository *repository.CourseRepository, db *sql.DB) CurriculumService {
+	return &curriculumService{
+		CurriculumRepository: *curriculumRepository,
+		CourseRepository:     *courseRepository,
+		DB:                   db,
+	}
+}
+
+func (service *curriculumService) Curriculum(ctx context.Context, code string) (model.GetCurriculumResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetCurriculumResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return model.GetCurriculumResponse{}, err
+	}
+
+	return service.curriculum(ctx, tx, course)
+}
+
+// Reorder moves the sections and items of the course to the order of the request, which has to list all of them
+func (service *curriculumService) Reorder(ctx context.Context, code string, request model.ReorderCurriculumRequest) (model.GetCurriculumResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetCurriculumResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return model.GetCurriculumResponse{}, err
+	}
+
+	sections, err := service.CurriculumRepository.FindAllSections(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetCurriculumResponse{}, err
+	}
+
+	items, err := service.CurriculumRepository.FindAllItems(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetCurriculumResponse{}, err
+	}
+
+	sectionsById := map[int]entity.CourseSections{}
+	for _, section := range sections {
+		sectionsById[section.Id] = section
+	}
+	itemsByKey := map[string]entity.CurriculumItems{}
+	for _, item := range items {
+		itemsByKey[curriculumKey(item.Type, item.ItemId)] = item
+	}
+
+	var moved []entity.CurriculumItems
+	place := func(sectionId *int, requests []model.CurriculumItemRequest) error {
+		for i, itemRequest := range requests {
+			item, ok := itemsByKey[curriculumKey(itemRequest.Type, itemRequest.Id)]
+			if !ok {
+				return ErrCurriculumMismatch
+			}
+			delete(itemsByKey, curriculumKey(itemRequest.Type, itemRequest.Id))
+
+			item.SectionId = sectionId
+			item.Position = i + 1
+			moved = append(moved, item)
+		}
+
+		return nil
+	}
+
+	err = place(nil, request.Items)
+	if err != nil {
+		return model.GetCurriculumResponse{}, err
+	}
+
+	var ordered []entity.CourseSections
+	for i, sectionRequest := range request.Sections {
+		section, ok := sectionsById[sectionRequest.Id]
+		if !ok {
+			return model.GetCurriculumResponse{}, ErrCurriculumMismatch
+		}
+		delete(sectionsById, sectionRequest.Id)
+
+		section.Position = i + 1
+		ordered = append(ordered, section)
+
+		sectionId := section.Id
+		err = place(&sectionId, sectionRequest.Items)
+		if err != nil {
+			return model.GetCurriculumResponse{}, err
+		}
+	}
+
+	if len(sectionsById) > 0 || len(itemsByKey) > 0 {
+		return model.GetCurriculumResponse{}, ErrCurriculumMismatch
+	}
+
+	for _, section := range ordered {
+		_, err = service.CurriculumRepository.UpdateSection(ctx, tx, section)
+		if err != nil {
+			return model.GetCurriculumResponse{}, err
+		}
+	}
+
+	for _, item := range moved {
+		err = service.CurriculumRepository.UpdateItem(ctx, tx, item)
+		if err != nil {
+			return model.GetCurriculumResponse{}, err
+		}
+	}
+
+	return service.curriculum(ctx, tx, course)
+}
+
+// CreateSection adds an empty section at the end of the curriculum
+func (service *curriculumService) CreateSection(ctx context.Context, code string, request model.SaveCourseSectionRequest) (model.GetCourseSectionResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetCourseSectionResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return model.GetCourseSectionResponse{}, err
+	}
+
+	sections, err := service.CurriculumRepository.FindAllSections(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetCourseSectionResponse{}, err
+	}
+
+	position := 1
+	if len(sections) > 0 {
+		position = sections[len(sections)-1].Position + 1
+	}
+
+	section, err := service.CurriculumRepository.CreateSection(ctx, tx, entity.CourseSections{
+		CourseId: course.Id,
+		Name:     request.Name,
+		Position: position,
+	})
+	if err != nil {
+		return model.GetCourseSectionResponse{}, err
+	}
+
+	return utils.ToCourseSectionResponse(section), nil
+}
+
+func (service *curriculumService) UpdateSection(ctx context.Context, code string, request model.SaveCourseSectionRequest) (model.GetCourseSectionResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetCourseSectionResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return model.GetCourseSectionResponse{}, err
+	}
+
+	section, err := service.CurriculumRepository.FindSectionById(ctx, tx, course.Id, request.Id)
+	if err != nil {
+		return model.GetCourseSectionResponse{}, ErrSectionNotFound
+	}
+
+	section.Name = request.Name
+	section, err = service.CurriculumRepository.UpdateSection(ctx, tx, section)
+	if err != nil {
+		return model.GetCourseSectionResponse{}, err
+	}
+
+	response := utils.ToCourseSectionResponse(section)
+
+	items, err := service.CurriculumRepository.FindAllItems(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetCourseSectionResponse{}, err
+	}
+
+	for _, item := range items {
+		if item.SectionId != nil && *item.SectionId == section.Id {
+			response.Items = append(response.Items, utils.ToCurriculumItemResponse(item))
+		}
+	}
+
+	return response, nil
+}
+
+// DeleteSection moves the items of the section to the end of the section before it, or before the first section,
+// so the order of the curriculum stays the same
+func (service *curriculumService) DeleteSection(ctx context.Context, code string, id int) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.CurriculumRepository.FindSectionById(ctx, tx, course.Id, id)
+	if err != nil {
+		return ErrSectionNotFound
+	}
+
+	sections, err := service.CurriculumRepository.FindAllSections(ctx, tx, course.Id)
+	if err != nil {
+		return err
+	}
+
+	var target *int
+	for i, section := range sections {
+		if section.Id == id && i > 0 {
+			target = &sections[i-1].Id
+		}
+	}
+
+	items, err := service.CurriculumRepository.FindAllItems(ctx, tx, course.Id)
+	if err != nil {
+		return err
+	}
+
+	position := lastPosition(items, target)
+	for _, item := range items {
+		if item.SectionId == nil || *item.SectionId != id {
+			continue
+		}
+
+		position++
+		item.SectionId = target
+		item.Position = position
+		err = service.CurriculumRepository.UpdateItem(ctx, tx, item)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = service.CurriculumRepository.DeleteSection(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (service *curriculumService) Next(ctx context.Context, code string, itemType string, itemId int) (model.GetNextPreviousCurriculumResponse, error) {
+	return service.step(ctx, code, itemType, itemId, 1)
+}
+
+func (service *curriculumService) Previous(ctx context.Context, code string, itemType string, itemId int) (model.GetNextPreviousCurriculumResponse, error) {
+	return service.step(ctx, code, itemType, itemId, -1)
+}
+
+// step returns the item offset places from the given one in the curriculum of the course, whatever their types
+func (service *curriculumService) step(ctx context.Context, code string, itemType string, itemId int, offset int) (model.GetNextPreviousCurriculumResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetNextPreviousCurriculumResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return model.GetNextPreviousCurriculumResponse{}, err
+	}
+
+	items, err := service.CurriculumRepository.FindAllItems(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetNextPreviousCurriculumResponse{}, err
+	}
+
+	for i, item := range items {
+		if item.Type != itemType || item.ItemId != itemId {
+			continue
+		}
+
+		if i+offset < 0 || i+offset >= len(items) {
+			break
+		}
+
+		return utils.ToCurriculumNextPreviousResponse(items[i+offset], course.CodeCourse), nil
+	}
+
+	return model.GetNextPreviousCurriculumResponse{}, ErrCurriculumItemNotFound
+}
+
+// curriculum groups the items of the course by section, the items before the first section in a section without id
+func (service *curriculumService) curriculum(ctx context.Context, tx *sql.Tx, course entity.Courses) (model.GetCurriculumResponse, error) {
+	sections, err := service.CurriculumRepository.FindAllSections(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetCurriculumResponse{}, err
+	}
+
+	items, err := service.CurriculumRepository.FindAllItems(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetCurriculumResponse{}, err
+	}
+
+	response := model.GetCurriculumResponse{
+		CodeCourse: course.CodeCourse,
+		Sections:   []model.GetCourseSectionResponse{},
+	}
+
+	unsectioned := model.GetCourseSectionResponse{Items: []model.GetCurriculumItemResponse{}}
+	for _, item := range items {
+		if item.SectionId == nil {
+			unsectioned.Items = append(unsectioned.Items, utils.ToCurriculumItemResponse(item))
+		}
+	}
+	if len(unsectioned.Items) > 0 {
+		response.Sections = append(response.Sections, unsectioned)
+	}
+
+	for _, section := range sections {
+		sectionResponse := utils.ToCourseSectionResponse(section)
+		for _, item := range items {
+			if item.SectionId != nil && *item.SectionId == section.Id {
+				sectionResponse.Items = append(sectionResponse.Items, utils.ToCurriculumItemResponse(item))
+			}
+		}
+
+		response.Sections = append(response.Sections, sectionResponse)
+	}
+
+	return response, nil
+}
+
+// appendCurriculumItem puts a new module of the course at the end of its curriculum, in the last section if it has one
+func appendCurriculumItem(ctx context.Context, tx *sql.Tx, curriculumRepository repository.CurriculumRepository, courseId int, itemType string, itemId int) error {
+	sections, err := curriculumRepository.FindAllSections(ctx, tx, courseId)
+	if err != nil {
+		return err
+	}
+
+	var sectionId *int
+	if len(sections) > 0 {
+		sectionId = &sections[len(sections)-1].Id
+	}
+
+	items, err := curriculumRepository.FindAllItems(ctx, tx, courseId)
+	if err != nil {
+		return err
+	}
+
+	_, err = curriculumRepository.CreateItem(ctx, tx, entity.CurriculumItems{
+		CourseId:  courseId,
+		SectionId: sectionId,
+		Type:      itemType,
+		ItemId:    itemId,
+		Position:  lastPosition(items, sectionId) + 1,
+	})
+
+	return err
+}
+
+// lastPosition is the highest position of the items in the section, 0 when it is empty
+func lastPosition(items []entity.CurriculumItems, sectionId *int) int {
+	position := 0
+	for _, item := range items {
+		inSection := item.SectionId == nil && sectionId == nil || item.SectionId != nil && sectionId != nil && *item.SectionId == *sectionId
+		if inSection && item.Position > position {
+			position = item.Position
+		}
+	}
+
+	return position
+}
+
+func curriculumKey(itemType string, itemId int) string {
+	return fmt.Sprintf("%v:%v", itemType, itemId)
+}
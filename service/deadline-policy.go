@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+)
+
+var (
+	ErrInvalidLatePolicy = errors.New("late_policy must be accept, hard_close, grace or penalty, grace_period must not be negative and penalty_per_day must be between 0 and 100")
+	ErrSubmissionClosed  = errors.New("the deadline has passed, the submission is closed")
+)
+
+// deadlinePolicy decides what happens to a file submitted after the deadline of a module submission.
+// A zero Deadline means the submission never closes.
+type deadlinePolicy struct {
+	Deadline      time.Time
+	LatePolicy    string
+	GracePeriod   time.Duration
+	PenaltyPerDay int
+}
+
+// newDeadlinePolicy applies the extension of the student, if they have one, to the policy of the module submission
+func newDeadlinePolicy(moduleSubmission entity.ModuleSubmissions, extension *time.Time) deadlinePolicy {
+	policy := deadlinePolicy{
+		Deadline:      moduleSubmission.Deadline,
+		LatePolicy:    moduleSubmission.LatePolicy,
+		GracePeriod:   time.Duration(moduleSubmission.GracePeriod) * time.Minute,
+		PenaltyPerDay: moduleSubmission.PenaltyPerDay,
+	}
+	if extension != nil {
+		policy.Deadline = *extension
+	}
+
+	return policy
+}
+
+func extensionDeadline(extension *entity.SubmissionExtensions) *time.Time {
+	if extension == nil {
+		return nil
+	}
+
+	return &extension.Deadline
+}
+
+func (policy deadlinePolicy) Late(submittedAt time.Time) bool {
+	return !policy.Deadline.IsZero() && submittedAt.After(policy.Deadline)
+}
+
+// Accepts tells whether a file submitted at submittedAt is still taken
+func (policy deadlinePolicy) Accepts(submittedAt time.Time) bool {
+	switch policy.LatePolicy {
+	case entity.LatePolicyHardClose:
+		return !policy.Late(submittedAt)
+	case entity.LatePolicyGrace:
+		return policy.Deadline.IsZero() || !submittedAt.After(policy.Deadline.Add(policy.GracePeriod))
+	default:
+		return true
+	}
+}
+
+// Penalty returns the points taken off grade, PenaltyPerDay percent for every day or part of a day late
+func (policy deadlinePolicy) Penalty(grade int, submittedAt time.Time) int {
+	if policy.LatePolicy != entity.LatePolicyPenalty || !policy.Late(submittedAt) {
+		return 0
+	}
+
+	days := int(math.Ceil(submittedAt.Sub(policy.Deadline).Hours() / 24))
+	percent := days * policy.PenaltyPerDay
+	if percent > 100 {
+		percent = 100
+	}
+
+	return int(math.Round(float64(grade) * float64(percent) / 100))
+}
+
+// Grade tells whether the graded attempt was late and, once the teacher gave a grade, how many points it loses
+func (policy deadlinePolicy) Grade(grade *int, submittedAt *time.Time) (*int, bool) {
+	if submittedAt == nil {
+		return nil, false
+	}
+	if grade == nil {
+		return nil, policy.Late(*submittedAt)
+	}
+
+	penalty := policy.Penalty(*grade, *submittedAt)
+	return &penalty, policy.Late(*submittedAt)
+}
+
+func validLatePolicy(latePolicy string, gracePeriod int, penaltyPerDay int) bool {
+	switch latePolicy {
+	case entity.LatePolicyAccept, entity.LatePolicyHardClose, entity.LatePolicyGrace, entity.LatePolicyPenalty:
+	default:
+		return false
+	}
+
+	return gracePeriod >= 0 && penaltyPerDay >= 0 && penaltyPerDay <= 100
+}
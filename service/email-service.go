@@ -4,67 +4,43 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"net/url"
+	"time"
+
 	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/mail"
 	"github.com/rg-km/final-project-engineering-12/backend/model"
 	"github.com/rg-km/final-project-engineering-12/backend/repository"
 	"github.com/rg-km/final-project-engineering-12/backend/utils"
-	"gopkg.in/gomail.v2"
-	"os"
-	"strconv"
-	"time"
 )
 
+// verificationLifetime is how long the link of a verification email can be opened
+const verificationLifetime = time.Hour
+
 type EmailService interface {
-	SendEmailWithText(toEmail string, message string) error
 	VerifyEmail(ctx context.Context, request model.GetEmailVerificationRequest) error
+	ResendVerification(ctx context.Context, email string, languages string) error
 }
 
 type emailService struct {
 	EmailVerificationRepository repository.EmailVerificationRepository
 	UserRepository              repository.UserRepository
+	Mailer                      *mailer
+	ResendInterval              time.Duration
 	DB                          *sql.DB
 }
 
-func NewEmailService(verificationRepository *repository.EmailVerificationRepository, userRepository *repository.UserRepository, db *sql.DB) EmailService {
+// NewEmailService sends a verification email again once resendInterval has passed since the last one
+func NewEmailService(verificationRepository *repository.EmailVerificationRepository, userRepository *repository.UserRepository, db *sql.DB, emailOutboxRepository *repository.EmailOutboxRepository, templates *mail.Templates, verificationUrl string, resendInterval time.Duration) EmailService {
 	return &emailService{
 		EmailVerificationRepository: *verificationRepository,
 		UserRepository:              *userRepository,
+		Mailer:                      newMailer(emailOutboxRepository, templates, verificationUrl),
+		ResendInterval:              resendInterval,
 		DB:                          db,
 	}
 }
 
-func (service *emailService) SendEmailWithText(toEmail string, message string) error {
-	if os.Getenv("APP_ENV") == "local" || os.Getenv("APP_ENV") != "production" {
-		return nil
-	}
-	go func() {
-		mailer := gomail.NewMessage()
-		mailer.SetHeader("From", os.Getenv("MAIL_FROM_ADDRESS"))
-		mailer.SetHeader("To", toEmail)
-		mailer.SetHeader("Subject", "Test Bang")
-		mailer.SetBody("text/html", message)
-
-		port, err := strconv.Atoi(os.Getenv("MAIL_PORT"))
-		if err != nil {
-			panic(err)
-		}
-
-		dialer := gomail.NewDialer(
-			os.Getenv("MAIL_HOST"),
-			port,
-			os.Getenv("MAIL_USERNAME"),
-			os.Getenv("MAIL_PASSWORD"),
-		)
-
-		err = dialer.DialAndSend(mailer)
-		if err != nil {
-			panic(err)
-		}
-	}()
-
-	return nil
-}
-
 func (service *emailService) VerifyEmail(ctx context.Context, request model.GetEmailVerificationRequest) error {
 	tx, err := service.DB.Begin()
 	if err != nil {
@@ -108,7 +84,7 @@ func (service *emailService) VerifyEmail(ctx context.Context, request model.GetE
 		return err
 	}
 
-	// Delete data from table password_reset
+	// Delete data from table email_verifications
 	err = service.EmailVerificationRepository.Delete(ctx, tx, dataEmailVerification.Email)
 	if err != nil {
 		return err
@@ -116,3 +92,107 @@ func (service *emailService) VerifyEmail(ctx context.Context, request model.GetE
 
 	return nil
 }
+
+// ResendVerification sends a new verification email to the user with the email when the user is not verified yet.
+// Nothing is sent to unknown or verified emails, nor before the resend interval passed, without telling the caller so
+// the answer does not give away who has an account.
+func (service *emailService) ResendVerification(ctx context.Context, email string, languages string) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	user, err := service.UserRepository.FindByEmail(ctx, tx, email)
+	if err != nil {
+		return err
+	}
+	if user.Id == 0 || user.EmailVerification != nil {
+		return nil
+	}
+
+	now := time.Now()
+	verification, err := service.EmailVerificationRepository.FindByEmail(ctx, tx, email)
+	if err != nil {
+		return err
+	}
+	if verification.Email != "" && now.Before(time.Unix(int64(verification.SentAt), 0).Add(service.ResendInterval)) {
+		return nil
+	}
+
+	signature, err := utils.RandomToken(16)
+	if err != nil {
+		return err
+	}
+
+	renewed := entity.EmailVerification{
+		Email:     user.Email,
+		Signature: signature,
+		Expired:   int(now.Add(verificationLifetime).Unix()),
+		SentAt:    int(now.Unix()),
+	}
+	if verification.Email == "" {
+		_, err = service.EmailVerificationRepository.Create(ctx, tx, renewed)
+	} else {
+		_, err = service.EmailVerificationRepository.Update(ctx, tx, renewed)
+	}
+	if err != nil {
+		return err
+	}
+
+	return service.Mailer.queueVerification(ctx, tx, user.Name, user.Email, signature, languages)
+}
+
+// mailer adds emails to the outbox in the transaction of the change they are about, so they are only sent once the
+// change is committed. The links of verification emails point to VerificationUrl.
+type mailer struct {
+	EmailOutboxRepository repository.EmailOutboxRepository
+	Templates             *mail.Templates
+	VerificationUrl       string
+}
+
+func newMailer(emailOutboxRepository *repository.EmailOutboxRepository, templates *mail.Templates, verificationUrl string) *mailer {
+	return &mailer{
+		EmailOutboxRepository: *emailOutboxRepository,
+		Templates:             templates,
+		VerificationUrl:       verificationUrl,
+	}
+}
+
+// queue renders the template in the first of languages it has and adds the email to the outbox
+func (mailer *mailer) queue(ctx context.Context, tx *sql.Tx, to string, template string, languages string, data interface{}) error {
+	subject, body, err := mailer.Templates.Render(template, languages, data)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = mailer.EmailOutboxRepository.Create(ctx, tx, entity.EmailOutbox{
+		Recipient:     to,
+		Subject:       subject,
+		Body:          body,
+		Status:        entity.EmailPending,
+		NextAttemptAt: now.Unix(),
+		CreatedAt:     now,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// queueVerification queues the email that asks the user to verify the email with the signature
+func (mailer *mailer) queueVerification(ctx context.Context, tx *sql.Tx, name string, email string, signature string, languages string) error {
+	query := url.Values{}
+	query.Set("email", email)
+	query.Set("signature", signature)
+
+	return mailer.queue(ctx, tx, email, "verification", languages, struct {
+		Name string
+		Url  string
+	}{
+		Name: name,
+		Url:  mailer.VerificationUrl + "?" + query.Encode(),
+	})
+}
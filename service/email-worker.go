@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/mail"
+	"github.com/rg-km/final-project-engineering-12/backend/repository"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+const (
+	// emailBatchSize is the number of emails sent at most in one round
+	emailBatchSize = 50
+	// emailClaimLifetime is how long a worker has to send an email it claimed before the email may be sent again
+	emailClaimLifetime = 5 * time.Minute
+)
+
+type EmailWorker interface {
+	// Deliver sends the emails of the outbox that are due at now and returns how many were sent
+	Deliver(ctx context.Context, now time.Time) (int, error)
+}
+
+type emailWorker struct {
+	EmailOutboxRepository repository.EmailOutboxRepository
+	Transport             mail.Transport
+	MaxAttempts           int
+	RetryDelay            time.Duration
+	DB                    *sql.DB
+}
+
+// NewEmailWorker sends the outbox with transport. An email that can not be sent is tried again after retryDelay,
+// which doubles after every attempt, until it failed maxAttempts times.
+func NewEmailWorker(emailOutboxRepository *repository.EmailOutboxRepository, transport mail.Transport, maxAttempts int, retryDelay time.Duration, db *sql.DB) EmailWorker {
+	return &emailWorker{
+		EmailOutboxRepository: *emailOutboxRepository,
+		Transport:             transport,
+		MaxAttempts:           maxAttempts,
+		RetryDelay:            retryDelay,
+		DB:                    db,
+	}
+}
+
+func (worker *emailWorker) Deliver(ctx context.Context, now time.Time) (int, error) {
+	emails, err := worker.due(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, email := range emails {
+		claimed, err := worker.claim(ctx, email, now)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+
+		err = worker.Transport.Send(ctx, mail.Message{
+			To:      email.Recipient,
+			Subject: email.Subject,
+			Body:    email.Body,
+		})
+		if err == nil {
+			sent++
+		}
+
+		err = worker.record(ctx, email, err, time.Now())
+		if err != nil {
+			return sent, err
+		}
+	}
+
+	return sent, nil
+}
+
+func (worker *emailWorker) due(ctx context.Context, now time.Time) ([]entity.EmailOutbox, error) {
+	tx, err := worker.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	return worker.EmailOutboxRepository.FindDue(ctx, tx, now.Unix(), emailBatchSize)
+}
+
+func (worker *emailWorker) claim(ctx context.Context, email entity.EmailOutbox, now time.Time) (bool, error) {
+	tx, err := worker.DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	return worker.EmailOutboxRepository.Claim(ctx, tx, email.Id, now.Unix(), now.Add(emailClaimLifetime).Unix())
+}
+
+// record marks the email sent, or schedules the next attempt when sendErr is not nil. The email is marked failed once
+// it used up its attempts.
+func (worker *emailWorker) record(ctx context.Context, email entity.EmailOutbox, sendErr error, now time.Time) error {
+	tx, err := worker.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	if sendErr == nil {
+		return worker.EmailOutboxRepository.MarkSent(ctx, tx, email.Id, now)
+	}
+
+	// The claim counted this attempt already
+	attempts := email.Attempts + 1
+	if attempts >= worker.MaxAttempts {
+		return worker.EmailOutboxRepository.MarkFailed(ctx, tx, email.Id, entity.EmailFailed, now.Unix(), sendErr.Error())
+	}
+
+	delay := worker.RetryDelay << (attempts - 1)
+	return worker.EmailOutboxRepository.MarkFailed(ctx, tx, email.Id, entity.EmailPending, now.Add(delay).Unix(), sendErr.Error())
+}
+
+var (
+	emailWorkerMutex sync.Mutex
+	stopEmailWorker  context.CancelFunc
+)
+
+// StartEmailWorker sends the due emails every interval until ctx is done. A process runs one worker, starting a worker
+// stops the one started before.
+func StartEmailWorker(ctx context.Context, worker EmailWorker, interval time.Duration) {
+	emailWorkerMutex.Lock()
+	defer emailWorkerMutex.Unlock()
+
+	if stopEmailWorker != nil {
+		stopEmailWorker()
+	}
+	ctx, stopEmailWorker = context.WithCancel(ctx)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				_, err := worker.Deliver(ctx, now)
+				if err != nil {
+					log.Println("cannot deliver emails ", err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,597 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/repository"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var (
+	ErrEnrollmentClosed          = errors.New("enrollment in this course is closed")
+	ErrInvalidEnrollmentKey      = errors.New("the enrollment key is wrong")
+	ErrAlreadyEnrolled           = errors.New("you are already enrolled in this course")
+	ErrEnrollmentKeyRequired     = errors.New("a key is required to enroll with a key")
+	ErrEnrollmentDates           = errors.New("the enrollment dates must be dates and end after they start")
+	ErrEnrollmentRequestNotFound = errors.New("enrollment request not found")
+	ErrRequestNotPending         = errors.New("only requests pending approval can be approved")
+)
+
+// Enrollment statuses of a user that are not requests
+const (
+	enrollmentEnrolled = "enrolled"
+	enrollmentNone     = "none"
+)
+
+type EnrollmentService interface {
+	Enrollment(ctx context.Context, code string, userId int) (model.GetEnrollmentResponse, error)
+	Enroll(ctx context.Context, code string, userId int, request model.EnrollRequest) (model.GetEnrollmentResponse, error)
+	Leave(ctx context.Context, code string, userId int) error
+	Settings(ctx context.Context, code string) (model.GetEnrollmentSettingsResponse, error)
+	SaveSettings(ctx context.Context, code string, request model.SaveEnrollmentSettingsRequest) (model.GetEnrollmentSettingsResponse, error)
+	FindAllRequests(ctx context.Context, code string) ([]model.GetEnrollmentRequestResponse, error)
+	Approve(ctx context.Context, code string, id int) (model.GetEnrollmentRequestResponse, error)
+	Reject(ctx context.Context, code string, id int) error
+}
+
+type enrollmentService struct {
+	EnrollmentRepository repository.EnrollmentRepository
+	UserCourseRepository repository.UserCourseRepository
+	CourseRepository     repository.CourseRepository
+	Enroller             *enroller
+	DB                   *sql.DB
+}
+
+func NewEnrollmentService(enrollmentRepository *repository.EnrollmentRepository, userCourseRepository *repository.UserCourseRepository, courseRepository *repository.CourseRepository, moduleSubmissionRepository *repository.ModuleSubmissionsRepository, userSubmissionRepository *repository.UserSubmissionsRepository, quizRepository *repository.QuizRepository, userQuizRepository *repository.UserQuizRepository, db *sql.DB) EnrollmentService {
+	return &enrollmentService{
+		EnrollmentRepository: *enrollmentRepository,
+		UserCourseRepository: *userCourseRepository,
+		CourseRepository:     *courseRepository,
+		Enroller:             newEnroller(enrollmentRepository, userCourseRepository, moduleSubmissionRepository, userSubmissionRepository, quizRepository, userQuizRepository),
+		DB:                   db,
+	}
+}
+
+// Enrollment returns how the user can enroll in the course and where the user stands
+func (service *enrollmentService) Enrollment(ctx context.Context, code string, userId int) (model.GetEnrollmentResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetEnrollmentResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return model.GetEnrollmentResponse{}, err
+	}
+
+	return service.enrollment(ctx, tx, course, userId)
+}
+
+// Enroll enrolls the user when there is a seat left, waitlists the user when there is none and waits for the approval
+// of a teacher in the approval mode. Users who already asked get where they stand back.
+func (service *enrollmentService) Enroll(ctx context.Context, code string, userId int, request model.EnrollRequest) (model.GetEnrollmentResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetEnrollmentResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return model.GetEnrollmentResponse{}, err
+	}
+
+	_, err = service.UserCourseRepository.FindByUserCourse(ctx, tx, strconv.Itoa(userId), strconv.Itoa(course.Id))
+	if err == nil {
+		return model.GetEnrollmentResponse{}, ErrAlreadyEnrolled
+	}
+
+	_, err = service.EnrollmentRepository.FindRequestByUser(ctx, tx, course.Id, userId)
+	if err == nil {
+		return service.enrollment(ctx, tx, course, userId)
+	}
+
+	settings := service.Enroller.settings(ctx, tx, course.Id)
+	now := time.Now().UTC()
+	if !course.IsActive || !enrollmentOpen(settings, now) {
+		return model.GetEnrollmentResponse{}, ErrEnrollmentClosed
+	}
+
+	if settings.Mode == entity.EnrollmentKey && (settings.Key == nil || subtle.ConstantTimeCompare([]byte(request.Key), []byte(*settings.Key)) != 1) {
+		return model.GetEnrollmentResponse{}, ErrInvalidEnrollmentKey
+	}
+
+	if settings.Mode == entity.EnrollmentApproval {
+		_, err = service.EnrollmentRepository.CreateRequest(ctx, tx, entity.EnrollmentRequests{
+			UserId:    userId,
+			CourseId:  course.Id,
+			Status:    entity.EnrollmentPending,
+			CreatedAt: now,
+		})
+		if err != nil {
+			return model.GetEnrollmentResponse{}, err
+		}
+
+		return service.enrollment(ctx, tx, course, userId)
+	}
+
+	err = service.Enroller.enrollOrWaitlist(ctx, tx, settings, userId, course.Id, now)
+	if err != nil {
+		return model.GetEnrollmentResponse{}, err
+	}
+
+	return service.enrollment(ctx, tx, course, userId)
+}
+
+// Leave takes the user out of the course, or out of the waitlist, and gives the seat to the first one waitlisted
+func (service *enrollmentService) Leave(ctx context.Context, code string, userId int) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.UserCourseRepository.FindByUserCourse(ctx, tx, strconv.Itoa(userId), strconv.Itoa(course.Id))
+	if err == nil {
+		err = service.UserCourseRepository.Delete(ctx, tx, userId, course.Id)
+		if err != nil {
+			return err
+		}
+
+		return service.Enroller.promote(ctx, tx, course.Id)
+	}
+
+	request, err := service.EnrollmentRepository.FindRequestByUser(ctx, tx, course.Id, userId)
+	if err != nil {
+		return ErrNotEnrolled
+	}
+
+	return service.EnrollmentRepository.DeleteRequest(ctx, tx, request.Id)
+}
+
+func (service *enrollmentService) Settings(ctx context.Context, code string) (model.GetEnrollmentSettingsResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetEnrollmentSettingsResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return model.GetEnrollmentSettingsResponse{}, err
+	}
+
+	return service.settings(ctx, tx, course)
+}
+
+// SaveSettings replaces the enrollment settings, seats added by a larger capacity go to the waitlist right away
+func (service *enrollmentService) SaveSettings(ctx context.Context, code string, request model.SaveEnrollmentSettingsRequest) (model.GetEnrollmentSettingsResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetEnrollmentSettingsResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return model.GetEnrollmentSettingsResponse{}, err
+	}
+
+	settings := entity.EnrollmentSettings{
+		CourseId: course.Id,
+		Mode:     request.Mode,
+		Capacity: request.Capacity,
+	}
+
+	if request.Mode == entity.EnrollmentKey {
+		if request.Key == "" {
+			return model.GetEnrollmentSettingsResponse{}, ErrEnrollmentKeyRequired
+		}
+		settings.Key = &request.Key
+	}
+
+	settings.StartsAt, err = enrollmentDate(request.StartsAt)
+	if err != nil {
+		return model.GetEnrollmentSettingsResponse{}, err
+	}
+	settings.EndsAt, err = enrollmentDate(request.EndsAt)
+	if err != nil {
+		return model.GetEnrollmentSettingsResponse{}, err
+	}
+	if settings.StartsAt != nil && settings.EndsAt != nil && !settings.EndsAt.After(*settings.StartsAt) {
+		return model.GetEnrollmentSettingsResponse{}, ErrEnrollmentDates
+	}
+
+	err = service.EnrollmentRepository.SaveSettings(ctx, tx, settings)
+	if err != nil {
+		return model.GetEnrollmentSettingsResponse{}, err
+	}
+
+	err = service.Enroller.promote(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetEnrollmentSettingsResponse{}, err
+	}
+
+	return service.settings(ctx, tx, course)
+}
+
+// FindAllRequests lists the waitlist in the order it is served and then the requests pending approval
+func (service *enrollmentService) FindAllRequests(ctx context.Context, code string) ([]model.GetEnrollmentRequestResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := service.EnrollmentRepository.FindAllRequests(ctx, tx, course.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []model.GetEnrollmentRequestResponse{}
+	position := 0
+	for _, request := range requests {
+		response := utils.ToEnrollmentRequestResponse(request, nil)
+		if request.Status == entity.EnrollmentWaitlisted {
+			position++
+			waitlisted := position
+			response.Position = &waitlisted
+		}
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+// Approve enrolls the student of a pending request, or waitlists the student when the course is full
+func (service *enrollmentService) Approve(ctx context.Context, code string, id int) (model.GetEnrollmentRequestResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetEnrollmentRequestResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return model.GetEnrollmentRequestResponse{}, err
+	}
+
+	request, err := service.EnrollmentRepository.FindRequestById(ctx, tx, course.Id, id)
+	if err != nil {
+		return model.GetEnrollmentRequestResponse{}, ErrEnrollmentRequestNotFound
+	}
+	if request.Status != entity.EnrollmentPending {
+		return model.GetEnrollmentRequestResponse{}, ErrRequestNotPending
+	}
+
+	settings := service.Enroller.settings(ctx, tx, course.Id)
+	err = service.Enroller.enrollOrWaitlist(ctx, tx, settings, request.UserId, course.Id, time.Now().UTC())
+	if err != nil {
+		return model.GetEnrollmentRequestResponse{}, err
+	}
+
+	waitlisted, err := service.EnrollmentRepository.FindRequestById(ctx, tx, course.Id, id)
+	if err != nil {
+		response := utils.ToEnrollmentRequestResponse(request, nil)
+		response.Status = enrollmentEnrolled
+		return response, nil
+	}
+
+	response, err := service.enrollment(ctx, tx, course, request.UserId)
+	if err != nil {
+		return model.GetEnrollmentRequestResponse{}, err
+	}
+
+	return utils.ToEnrollmentRequestResponse(waitlisted, response.Position), nil
+}
+
+func (service *enrollmentService) Reject(ctx context.Context, code string, id int) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return err
+	}
+
+	request, err := service.EnrollmentRepository.FindRequestById(ctx, tx, course.Id, id)
+	if err != nil {
+		return ErrEnrollmentRequestNotFound
+	}
+
+	return service.EnrollmentRepository.DeleteRequest(ctx, tx, request.Id)
+}
+
+func (service *enrollmentService) enrollment(ctx context.Context, tx *sql.Tx, course entity.Courses, userId int) (model.GetEnrollmentResponse, error) {
+	settings := service.Enroller.settings(ctx, tx, course.Id)
+	response := model.GetEnrollmentResponse{
+		CodeCourse: course.CodeCourse,
+		Mode:       settings.Mode,
+		Open:       course.IsActive && enrollmentOpen(settings, time.Now().UTC()),
+		Capacity:   settings.Capacity,
+		StartsAt:   settings.StartsAt,
+		EndsAt:     settings.EndsAt,
+		Status:     enrollmentNone,
+	}
+
+	if settings.Capacity != nil {
+		users, err := service.UserCourseRepository.FindAllUserByCourseId(ctx, tx, course.Id)
+		if err != nil {
+			return model.GetEnrollmentResponse{}, err
+		}
+
+		seatsLeft := *settings.Capacity - len(users)
+		if seatsLeft < 0 {
+			seatsLeft = 0
+		}
+		response.SeatsLeft = &seatsLeft
+	}
+
+	_, err := service.UserCourseRepository.FindByUserCourse(ctx, tx, strconv.Itoa(userId), strconv.Itoa(course.Id))
+	if err == nil {
+		response.Status = enrollmentEnrolled
+		return response, nil
+	}
+
+	requests, err := service.EnrollmentRepository.FindAllRequests(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetEnrollmentResponse{}, err
+	}
+
+	position := 0
+	for _, request := range requests {
+		if request.Status == entity.EnrollmentWaitlisted {
+			position++
+		}
+		if request.UserId == userId {
+			response.Status = request.Status
+			if request.Status == entity.EnrollmentWaitlisted {
+				response.Position = &position
+			}
+		}
+	}
+
+	return response, nil
+}
+
+func (service *enrollmentService) settings(ctx context.Context, tx *sql.Tx, course entity.Courses) (model.GetEnrollmentSettingsResponse, error) {
+	settings := service.Enroller.settings(ctx, tx, course.Id)
+
+	users, err := service.UserCourseRepository.FindAllUserByCourseId(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetEnrollmentSettingsResponse{}, err
+	}
+
+	requests, err := service.EnrollmentRepository.FindAllRequests(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetEnrollmentSettingsResponse{}, err
+	}
+
+	response := utils.ToEnrollmentSettingsResponse(settings, course.CodeCourse)
+	response.Enrolled = len(users)
+	for _, request := range requests {
+		if request.Status == entity.EnrollmentWaitlisted {
+			response.Waitlisted++
+		} else {
+			response.Pending++
+		}
+	}
+
+	return response, nil
+}
+
+// enrollmentOpen tells if students can enroll themselves at the time
+func enrollmentOpen(settings entity.EnrollmentSettings, now time.Time) bool {
+	if settings.Mode == entity.EnrollmentClosed {
+		return false
+	}
+
+	return enrollmentDatesOpen(settings, now)
+}
+
+// enrollmentDatesOpen tells if the time is between the enrollment dates of the course, whatever its mode
+func enrollmentDatesOpen(settings entity.EnrollmentSettings, now time.Time) bool {
+	if settings.StartsAt != nil && now.Before(*settings.StartsAt) {
+		return false
+	}
+	if settings.EndsAt != nil && now.After(*settings.EndsAt) {
+		return false
+	}
+
+	return true
+}
+
+// enrollmentDate reads an enrollment date, an empty one is no limit
+func enrollmentDate(date string) (*time.Time, error) {
+	if date == "" {
+		return nil, nil
+	}
+
+	parsed := utils.ParseTime(date)
+	if parsed.IsZero() {
+		return nil, ErrEnrollmentDates
+	}
+	parsed = parsed.UTC()
+
+	return &parsed, nil
+}
+
+// enroller puts users into courses. It is shared by everything that enrolls users or frees seats so late joiners
+// always get the module submissions and quizzes of the course and the waitlist moves up.
+type enroller struct {
+	EnrollmentRepository       repository.EnrollmentRepository
+	UserCourseRepository       repository.UserCourseRepository
+	ModuleSubmissionRepository repository.ModuleSubmissionsRepository
+	UserSubmissionRepository   repository.UserSubmissionsRepository
+	QuizRepository             repository.QuizRepository
+	UserQuizRepository         repository.UserQuizRepository
+}
+
+func newEnroller(enrollmentRepository *repository.EnrollmentRepository, userCourseRepository *repository.UserCourseRepository, moduleSubmissionRepository *repository.ModuleSubmissionsRepository, userSubmissionRepository *repository.UserSubmissionsRepository, quizRepository *repository.QuizRepository, userQuizRepository *repository.UserQuizRepository) *enroller {
+	return &enroller{
+		EnrollmentRepository:       *enrollmentRepository,
+		UserCourseRepository:       *userCourseRepository,
+		ModuleSubmissionRepository: *moduleSubmissionRepository,
+		UserSubmissionRepository:   *userSubmissionRepository,
+		QuizRepository:             *quizRepository,
+		UserQuizRepository:         *userQuizRepository,
+	}
+}
+
+// settings returns the enrollment settings of the course, courses without any are closed
+func (enroller *enroller) settings(ctx context.Context, tx *sql.Tx, courseId int) entity.EnrollmentSettings {
+	settings, err := enroller.EnrollmentRepository.FindSettings(ctx, tx, courseId)
+	if err != nil {
+		return entity.EnrollmentSettings{CourseId: courseId, Mode: entity.EnrollmentClosed}
+	}
+
+	return settings
+}
+
+// enroll enrolls the user, drops any request of the user and back-fills the module submissions and quizzes
+func (enroller *enroller) enroll(ctx context.Context, tx *sql.Tx, userId int, courseId int) (entity.UserCourse, error) {
+	usercourse, err := enroller.UserCourseRepository.Create(ctx, tx, entity.UserCourse{
+		UserId:   userId,
+		CourseId: courseId,
+	})
+	if err != nil {
+		return entity.UserCourse{}, err
+	}
+
+	request, err := enroller.EnrollmentRepository.FindRequestByUser(ctx, tx, courseId, userId)
+	if err == nil {
+		err = enroller.EnrollmentRepository.DeleteRequest(ctx, tx, request.Id)
+		if err != nil {
+			return entity.UserCourse{}, err
+		}
+	}
+
+	// Create User Submission
+	allModuleSubmissions, _ := enroller.ModuleSubmissionRepository.FindAll(ctx, tx, courseId)
+	if allModuleSubmissions != nil {
+		for _, value := range allModuleSubmissions {
+			err := enroller.UserSubmissionRepository.OnlyCreate(ctx, tx, userId, value.Id)
+			if err != nil {
+				return entity.UserCourse{}, err
+			}
+		}
+	}
+
+	// Create User Quiz
+	quizzes, err := enroller.QuizRepository.FindAll(ctx, tx, courseId)
+	if err != nil {
+		return entity.UserCourse{}, err
+	}
+	for _, quiz := range quizzes {
+		err := enroller.UserQuizRepository.OnlyCreate(ctx, tx, userId, quiz.Id)
+		if err != nil {
+			return entity.UserCourse{}, err
+		}
+	}
+
+	return usercourse, nil
+}
+
+// enrollOrWaitlist enrolls the user when a seat is left and nobody is waitlisted before, otherwise the user joins the
+// end of the waitlist
+func (enroller *enroller) enrollOrWaitlist(ctx context.Context, tx *sql.Tx, settings entity.EnrollmentSettings, userId int, courseId int, now time.Time) error {
+	seats, waitlist, err := enroller.seats(ctx, tx, settings)
+	if err != nil {
+		return err
+	}
+
+	if seats < 0 || (seats > 0 && len(waitlist) == 0) {
+		_, err = enroller.enroll(ctx, tx, userId, courseId)
+		return err
+	}
+
+	request, err := enroller.EnrollmentRepository.FindRequestByUser(ctx, tx, courseId, userId)
+	if err != nil {
+		_, err = enroller.EnrollmentRepository.CreateRequest(ctx, tx, entity.EnrollmentRequests{
+			UserId:       userId,
+			CourseId:     courseId,
+			Status:       entity.EnrollmentWaitlisted,
+			CreatedAt:    now,
+			WaitlistedAt: &now,
+		})
+		return err
+	}
+
+	request.Status = entity.EnrollmentWaitlisted
+	request.WaitlistedAt = &now
+	return enroller.EnrollmentRepository.UpdateRequest(ctx, tx, request)
+}
+
+// promote enrolls the waitlisted students in order while there are seats left
+func (enroller *enroller) promote(ctx context.Context, tx *sql.Tx, courseId int) error {
+	seats, waitlist, err := enroller.seats(ctx, tx, enroller.settings(ctx, tx, courseId))
+	if err != nil {
+		return err
+	}
+
+	for _, request := range waitlist {
+		if seats == 0 {
+			break
+		}
+
+		_, err = enroller.enroll(ctx, tx, request.UserId, courseId)
+		if err != nil {
+			return err
+		}
+		seats--
+	}
+
+	return nil
+}
+
+// seats returns the seats left, -1 for courses without a capacity, and the waitlist in order
+func (enroller *enroller) seats(ctx context.Context, tx *sql.Tx, settings entity.EnrollmentSettings) (int, []entity.EnrollmentRequests, error) {
+	requests, err := enroller.EnrollmentRepository.FindAllRequests(ctx, tx, settings.CourseId)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var waitlist []entity.EnrollmentRequests
+	for _, request := range requests {
+		if request.Status == entity.EnrollmentWaitlisted {
+			waitlist = append(waitlist, request)
+		}
+	}
+
+	if settings.Capacity == nil {
+		return -1, waitlist, nil
+	}
+
+	users, err := enroller.UserCourseRepository.FindAllUserByCourseId(ctx, tx, settings.CourseId)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	seats := *settings.Capacity - len(users)
+	if seats < 0 {
+		seats = 0
+	}
+
+	return seats, waitlist, nil
+}
@@ -0,0 +1,558 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/repository"
+	"github.com/rg-km/final-project-engineering-12/backend/spreadsheet"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var (
+	ErrCategoryNotFound = errors.New("grade category not found")
+	ErrCategoryWeights  = errors.New("the weights of the grade categories of a course can not add up to more than 100")
+	ErrDuplicateBand    = errors.New("every letter band must have its own letter")
+)
+
+// defaultGradeBands are used until a course saves its own letter bands
+var defaultGradeBands = []entity.GradeBands{
+	{Letter: "A", MinScore: 90},
+	{Letter: "B", MinScore: 80},
+	{Letter: "C", MinScore: 70},
+	{Letter: "D", MinScore: 60},
+	{Letter: "E", MinScore: 0},
+}
+
+// defaultMaxPoints is what a module submission without a rubric is graded out of
+const defaultMaxPoints = 100
+
+type GradebookService interface {
+	Gradebook(ctx context.Context, code string) (model.GetGradebookResponse, error)
+	Export(ctx context.Context, writer io.Writer, code string, format string) error
+	FindAllCategories(ctx context.Context, code string) ([]model.GetGradeCategoryResponse, error)
+	CreateCategory(ctx context.Context, code string, request model.SaveGradeCategoryRequest) (model.GetGradeCategoryResponse, error)
+	UpdateCategory(ctx context.Context, code string, request model.SaveGradeCategoryRequest) (model.GetGradeCategoryResponse, error)
+	DeleteCategory(ctx context.Context, code string, id int) error
+	FindBands(ctx context.Context, code string) ([]model.GetGradeBandResponse, error)
+	SaveBands(ctx context.Context, code string, request model.SaveGradeBandsRequest) ([]model.GetGradeBandResponse, error)
+}
+
+type gradebookService struct {
+	GradebookRepository         repository.GradebookRepository
+	UserCourseRepository        repository.UserCourseRepository
+	ModuleSubmissionsRepository repository.ModuleSubmissionsRepository
+	RubricRepository            repository.RubricRepository
+	CourseRepository            repository.CourseRepository
+	DB                          *sql.DB
+}
+
+func NewGradebookService(gradebookRepository *repository.GradebookRepository, userCourseRepository *repository.UserCourseRepository, moduleSubmissionsRepository *repository.ModuleSubmissionsRepository, rubricRepository *repository.RubricRepository, courseRepository *repository.CourseRepository, db *sql.DB) GradebookService {
+	return &gradebookService{
+		GradebookRepository:         *gradebookRepository,
+		UserCourseRepository:        *userCourseRepository,
+		ModuleSubmissionsRepository: *moduleSubmissionsRepository,
+		RubricRepository:            *rubricRepository,
+		CourseRepository:            *courseRepository,
+		DB:                          db,
+	}
+}
+
+func (service *gradebookService) Gradebook(ctx context.Context, code string) (model.GetGradebookResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetGradebookResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	return service.gradebook(ctx, tx, code)
+}
+
+// Export writes the gradebook as a csv or xlsx sheet with a row for every student
+func (service *gradebookService) Export(ctx context.Context, writer io.Writer, code string, format string) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	if format != spreadsheet.FormatCSV && format != spreadsheet.FormatXLSX {
+		return spreadsheet.ErrUnknownFormat
+	}
+
+	gradebook, err := service.gradebook(ctx, tx, code)
+	if err != nil {
+		return err
+	}
+
+	header := []interface{}{"Name", "Username", "Email"}
+	for _, assignment := range gradebook.Assignments {
+		header = append(header, assignment.Name)
+	}
+	for _, category := range gradebook.Categories {
+		header = append(header, category.Name)
+	}
+	header = append(header, "Score", "Letter")
+
+	rows := [][]interface{}{header}
+	for _, student := range gradebook.Students {
+		row := []interface{}{student.Name, student.Username, student.Email}
+		for _, grade := range student.Grades {
+			row = append(row, sheetCell(grade.Grade))
+		}
+		for _, category := range student.Categories {
+			row = append(row, sheetCell(category.Score))
+		}
+		row = append(row, sheetCell(student.Score), student.Letter)
+
+		rows = append(rows, row)
+	}
+
+	return spreadsheet.Write(writer, format, gradebook.CodeCourse, rows)
+}
+
+func (service *gradebookService) FindAllCategories(ctx context.Context, code string) ([]model.GetGradeCategoryResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := service.GradebookRepository.FindAllCategories(ctx, tx, course.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryResponses := []model.GetGradeCategoryResponse{}
+	for _, category := range categories {
+		categoryResponses = append(categoryResponses, utils.ToGradeCategoryResponse(category))
+	}
+
+	return categoryResponses, nil
+}
+
+func (service *gradebookService) CreateCategory(ctx context.Context, code string, request model.SaveGradeCategoryRequest) (model.GetGradeCategoryResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetGradeCategoryResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return model.GetGradeCategoryResponse{}, err
+	}
+
+	err = service.checkWeights(ctx, tx, course.Id, 0, request.Weight)
+	if err != nil {
+		return model.GetGradeCategoryResponse{}, err
+	}
+
+	category, err := service.GradebookRepository.CreateCategory(ctx, tx, entity.GradeCategories{
+		CourseId:   course.Id,
+		Name:       request.Name,
+		Weight:     request.Weight,
+		DropLowest: request.DropLowest,
+	})
+	if err != nil {
+		return model.GetGradeCategoryResponse{}, err
+	}
+
+	return utils.ToGradeCategoryResponse(category), nil
+}
+
+func (service *gradebookService) UpdateCategory(ctx context.Context, code string, request model.SaveGradeCategoryRequest) (model.GetGradeCategoryResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetGradeCategoryResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return model.GetGradeCategoryResponse{}, err
+	}
+
+	category, err := service.GradebookRepository.FindCategoryById(ctx, tx, course.Id, request.Id)
+	if err != nil {
+		return model.GetGradeCategoryResponse{}, ErrCategoryNotFound
+	}
+
+	err = service.checkWeights(ctx, tx, course.Id, category.Id, request.Weight)
+	if err != nil {
+		return model.GetGradeCategoryResponse{}, err
+	}
+
+	category.Name = request.Name
+	category.Weight = request.Weight
+	category.DropLowest = request.DropLowest
+	category, err = service.GradebookRepository.UpdateCategory(ctx, tx, category)
+	if err != nil {
+		return model.GetGradeCategoryResponse{}, err
+	}
+
+	return utils.ToGradeCategoryResponse(category), nil
+}
+
+func (service *gradebookService) DeleteCategory(ctx context.Context, code string, id int) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.GradebookRepository.FindCategoryById(ctx, tx, course.Id, id)
+	if err != nil {
+		return ErrCategoryNotFound
+	}
+
+	return service.GradebookRepository.DeleteCategory(ctx, tx, id)
+}
+
+func (service *gradebookService) FindBands(ctx context.Context, code string) ([]model.GetGradeBandResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	bands, err := service.findBands(ctx, tx, course.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ToGradeBandResponses(bands), nil
+}
+
+func (service *gradebookService) SaveBands(ctx context.Context, code string, request model.SaveGradeBandsRequest) ([]model.GetGradeBandResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	letters := map[string]bool{}
+	var bands []entity.GradeBands
+	for _, band := range request.Bands {
+		if letters[band.Letter] {
+			return nil, ErrDuplicateBand
+		}
+		letters[band.Letter] = true
+
+		bands = append(bands, entity.GradeBands{CourseId: course.Id, Letter: band.Letter, MinScore: band.MinScore})
+	}
+
+	err = service.GradebookRepository.SaveBands(ctx, tx, course.Id, bands)
+	if err != nil {
+		return nil, err
+	}
+
+	bands, err = service.findBands(ctx, tx, course.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ToGradeBandResponses(bands), nil
+}
+
+// checkWeights makes sure the categories of a course, with the category id weighing weight, add up to 100 at most
+func (service *gradebookService) checkWeights(ctx context.Context, tx *sql.Tx, courseId int, id int, weight int) error {
+	categories, err := service.GradebookRepository.FindAllCategories(ctx, tx, courseId)
+	if err != nil {
+		return err
+	}
+
+	total := weight
+	for _, category := range categories {
+		if category.Id != id {
+			total += category.Weight
+		}
+	}
+	if total > 100 {
+		return ErrCategoryWeights
+	}
+
+	return nil
+}
+
+func (service *gradebookService) findBands(ctx context.Context, tx *sql.Tx, courseId int) ([]entity.GradeBands, error) {
+	bands, err := service.GradebookRepository.FindAllBands(ctx, tx, courseId)
+	if err != nil {
+		return nil, err
+	}
+	if len(bands) == 0 {
+		return defaultGradeBands, nil
+	}
+
+	return bands, nil
+}
+
+func (service *gradebookService) gradebook(ctx context.Context, tx *sql.Tx, code string) (model.GetGradebookResponse, error) {
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return model.GetGradebookResponse{}, err
+	}
+
+	students, err := service.UserCourseRepository.FindAllUserByCourseId(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetGradebookResponse{}, err
+	}
+
+	moduleSubmissions, err := service.ModuleSubmissionsRepository.FindAll(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetGradebookResponse{}, err
+	}
+	sort.Slice(moduleSubmissions, func(i, j int) bool {
+		return moduleSubmissions[i].Id < moduleSubmissions[j].Id
+	})
+
+	maxPoints := map[int]int{}
+	for _, moduleSubmission := range moduleSubmissions {
+		levels, err := service.RubricRepository.FindLevels(ctx, tx, moduleSubmission.Id)
+		if err != nil {
+			return model.GetGradebookResponse{}, err
+		}
+		maxPoints[moduleSubmission.Id] = rubricMaxPoints(levels)
+	}
+
+	categories, err := service.GradebookRepository.FindAllCategories(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetGradebookResponse{}, err
+	}
+
+	bands, err := service.findBands(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetGradebookResponse{}, err
+	}
+
+	grades, err := service.GradebookRepository.FindAllGrades(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetGradebookResponse{}, err
+	}
+
+	return newGradebook(course.CodeCourse, students, moduleSubmissions, maxPoints, categories, bands, grades), nil
+}
+
+// rubricMaxPoints adds up the best level of every criterion of a rubric, a module submission without one is out of 100
+func rubricMaxPoints(levels []entity.RubricLevels) int {
+	best := map[int]int{}
+	for _, level := range levels {
+		if level.Points > best[level.CriterionId] {
+			best[level.CriterionId] = level.Points
+		}
+	}
+
+	total := 0
+	for _, points := range best {
+		total += points
+	}
+	if total == 0 {
+		return defaultMaxPoints
+	}
+
+	return total
+}
+
+// newGradebook computes the matrix of a course. A cell is the grade after the late penalty as a percent of the
+// max points. A category averages the cells of a student that are graded, leaving out the drop_lowest lowest as
+// long as one remains. Module submissions without a category share what is left of the weight, or all of it when
+// the course has no categories. The final score is the weighted average of the categories the student has a score in.
+func newGradebook(codeCourse string, students []entity.UserTeacherCourse, moduleSubmissions []entity.ModuleSubmissions, maxPoints map[int]int, categories []entity.GradeCategories, bands []entity.GradeBands, grades []entity.GradebookGrades) model.GetGradebookResponse {
+	gradebook := model.GetGradebookResponse{
+		CodeCourse:  codeCourse,
+		Assignments: []model.GetGradebookAssignmentResponse{},
+		Categories:  []model.GetGradebookCategoryResponse{},
+		Bands:       utils.ToGradeBandResponses(bands),
+		Students:    []model.GetGradebookStudentResponse{},
+	}
+
+	known := map[int]bool{}
+	weights := 0
+	for _, category := range categories {
+		known[category.Id] = true
+		weights += category.Weight
+	}
+
+	uncategorized := false
+	for _, moduleSubmission := range moduleSubmissions {
+		categoryId := moduleSubmission.CategoryId
+		if categoryId != nil && !known[*categoryId] {
+			categoryId = nil
+		}
+		uncategorized = uncategorized || categoryId == nil
+
+		gradebook.Assignments = append(gradebook.Assignments, model.GetGradebookAssignmentResponse{
+			Id:         moduleSubmission.Id,
+			Name:       moduleSubmission.Name,
+			CategoryId: categoryId,
+			MaxPoints:  maxPoints[moduleSubmission.Id],
+		})
+	}
+
+	for i := range categories {
+		gradebook.Categories = append(gradebook.Categories, model.GetGradebookCategoryResponse{
+			Id:         &categories[i].Id,
+			Name:       categories[i].Name,
+			Weight:     categories[i].Weight,
+			DropLowest: categories[i].DropLowest,
+		})
+	}
+	if uncategorized {
+		gradebook.Categories = append(gradebook.Categories, model.GetGradebookCategoryResponse{
+			Name:   "Uncategorized",
+			Weight: 100 - weights,
+		})
+	}
+
+	byStudent := map[int]map[int]entity.GradebookGrades{}
+	for _, grade := range grades {
+		if byStudent[grade.UserId] == nil {
+			byStudent[grade.UserId] = map[int]entity.GradebookGrades{}
+		}
+		byStudent[grade.UserId][grade.ModuleSubmissionId] = grade
+	}
+
+	for _, student := range students {
+		studentResponse := model.GetGradebookStudentResponse{
+			UserId:     student.IdUser,
+			Name:       student.UserName,
+			Username:   student.UserUsername,
+			Email:      student.UserEmail,
+			Grades:     []model.GetGradebookGradeResponse{},
+			Categories: []model.GetGradebookCategoryScoreResponse{},
+		}
+
+		for i, moduleSubmission := range moduleSubmissions {
+			gradeResponse := model.GetGradebookGradeResponse{ModuleSubmissionId: moduleSubmission.Id}
+			grade, exists := byStudent[student.IdUser][moduleSubmission.Id]
+			if exists && grade.Grade != nil {
+				final := *grade.Grade
+				penalty, _ := newDeadlinePolicy(moduleSubmission, grade.ExtendedDeadline).Grade(grade.Grade, grade.SubmittedAt)
+				if penalty != nil {
+					final -= *penalty
+				}
+
+				score := roundScore(float64(final) / float64(gradebook.Assignments[i].MaxPoints) * 100)
+				gradeResponse.Grade = &final
+				gradeResponse.Score = &score
+			}
+
+			studentResponse.Grades = append(studentResponse.Grades, gradeResponse)
+		}
+
+		var weighted, total float64
+		for _, category := range gradebook.Categories {
+			score := categoryScore(category, gradebook.Assignments, studentResponse.Grades)
+			studentResponse.Categories = append(studentResponse.Categories, model.GetGradebookCategoryScoreResponse{
+				CategoryId: category.Id,
+				Score:      score,
+			})
+
+			if score != nil && category.Weight > 0 {
+				weighted += *score * float64(category.Weight)
+				total += float64(category.Weight)
+			}
+		}
+
+		if total > 0 {
+			score := roundScore(weighted / total)
+			studentResponse.Score = &score
+			studentResponse.Letter = bandLetter(bands, score)
+		}
+
+		gradebook.Students = append(gradebook.Students, studentResponse)
+	}
+
+	return gradebook
+}
+
+// categoryScore averages the graded cells of a category and marks the ones it drops
+func categoryScore(category model.GetGradebookCategoryResponse, assignments []model.GetGradebookAssignmentResponse, grades []model.GetGradebookGradeResponse) *float64 {
+	var cells []int
+	for i, assignment := range assignments {
+		inCategory := assignment.CategoryId == nil && category.Id == nil ||
+			assignment.CategoryId != nil && category.Id != nil && *assignment.CategoryId == *category.Id
+		if inCategory && grades[i].Score != nil {
+			cells = append(cells, i)
+		}
+	}
+	if len(cells) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(cells, func(i, j int) bool {
+		return *grades[cells[i]].Score < *grades[cells[j]].Score
+	})
+
+	dropped := category.DropLowest
+	if dropped > len(cells)-1 {
+		dropped = len(cells) - 1
+	}
+	for _, i := range cells[:dropped] {
+		grades[i].Dropped = true
+	}
+
+	var sum float64
+	for _, i := range cells[dropped:] {
+		sum += *grades[i].Score
+	}
+	score := roundScore(sum / float64(len(cells)-dropped))
+
+	return &score
+}
+
+// bandLetter returns the band of score, bands are sorted with the highest first
+func bandLetter(bands []entity.GradeBands, score float64) string {
+	for _, band := range bands {
+		if score >= float64(band.MinScore) {
+			return band.Letter
+		}
+	}
+
+	return ""
+}
+
+func roundScore(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func sheetCell(value interface{}) interface{} {
+	switch value := value.(type) {
+	case *int:
+		if value != nil {
+			return *value
+		}
+	case *float64:
+		if value != nil {
+			return *value
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -8,15 +10,18 @@ import (
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
 )
 
 //jwt service
 type JWTService interface {
-	GenerateToken(user entity.Users) string
+	GenerateToken(ctx context.Context, user entity.Users, clientIp string, userAgent string) (string, error)
 	ValidateToken(token string) (*jwt.Token, error)
 	ParseToken(encodedToken string) (*jwt.Token, error)
-	DeleteToken(token string) error
-	CheckToken(token string) error
+	DeleteToken(ctx context.Context, token string) error
+	CheckToken(ctx context.Context, token string) error
+	Authenticate(ctx context.Context, token string) (model.Principal, error)
 }
 type authCustomClaims struct {
 	Id   int    `json:"id"`
@@ -28,15 +33,22 @@ type authCustomClaims struct {
 type jwtServices struct {
 	secretKey string
 	issuer    string
+	store     SessionStore
 }
 
-var whiteListTokens = make([]string, 0, 10000)
+var sessionStore SessionStore
+
+// UseSessionStore sets the store that GenerateToken, CheckToken and DeleteToken work against.
+func UseSessionStore(store SessionStore) {
+	sessionStore = store
+}
 
 //auth-jwt
 func JWTAuthService() JWTService {
 	return &jwtServices{
 		secretKey: getSecretKey(),
 		issuer:    "teenager",
+		store:     sessionStore,
 	}
 }
 
@@ -48,13 +60,25 @@ func getSecretKey() string {
 	return secret
 }
 
-func (service *jwtServices) GenerateToken(user entity.Users) string {
+func (service *jwtServices) GenerateToken(ctx context.Context, user entity.Users, clientIp string, userAgent string) (string, error) {
+	if service.store == nil {
+		return "", errors.New("session store is not configured")
+	}
+
+	tokenId, err := utils.RandomToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
 	claims := &authCustomClaims{
 		Id:   user.Id,
 		Name: user.Name,
 		Role: strconv.Itoa(user.Role),
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 1).Unix(),
+			Id:        tokenId,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(time.Hour * 1).Unix(),
 			Issuer:    service.issuer,
 		},
 	}
@@ -63,18 +87,28 @@ func (service *jwtServices) GenerateToken(user entity.Users) string {
 	//encoded string
 	tokenstring, err := token.SignedString([]byte(service.secretKey))
 	if err != nil {
-		panic(err)
+		return "", err
 	}
 
-	whiteListTokens = append(whiteListTokens, tokenstring)
+	err = service.store.Save(ctx, entity.Sessions{
+		Id:        tokenId,
+		UserId:    user.Id,
+		IssuedAt:  claims.IssuedAt,
+		ExpiredAt: claims.ExpiresAt,
+		ClientIp:  clientIp,
+		UserAgent: userAgent,
+	})
+	if err != nil {
+		return "", err
+	}
 
-	return tokenstring
+	return tokenstring, nil
 }
 
 func (service *jwtServices) ValidateToken(encodedToken string) (*jwt.Token, error) {
 	return jwt.Parse(encodedToken, func(token *jwt.Token) (interface{}, error) {
 		if _, isvalid := token.Method.(*jwt.SigningMethodHMAC); !isvalid {
-			return nil, fmt.Errorf("Invalid token", token.Header["alg"])
+			return nil, fmt.Errorf("invalid token algorithm %v", token.Header["alg"])
 		}
 		return []byte(service.secretKey), nil
 	})
@@ -84,32 +118,88 @@ func (service *jwtServices) ValidateToken(encodedToken string) (*jwt.Token, erro
 func (service *jwtServices) ParseToken(encodedToken string) (*jwt.Token, error) {
 	return jwt.Parse(encodedToken, func(token *jwt.Token) (interface{}, error) {
 		if _, isvalid := token.Method.(*jwt.SigningMethodHMAC); !isvalid {
-			return nil, fmt.Errorf("Invalid token", token.Header["alg"])
+			return nil, fmt.Errorf("invalid token algorithm %v", token.Header["alg"])
 		}
 		return []byte(service.secretKey), nil
 	})
 }
 
-func (service *jwtServices) CheckToken(token string) error {
-	for _, v := range whiteListTokens {
-		if v == token {
-			return nil
+func (service *jwtServices) CheckToken(ctx context.Context, token string) error {
+	_, err := service.Authenticate(ctx, token)
+	return err
+}
+
+// Authenticate verifies the token and its session and returns the caller it belongs to
+func (service *jwtServices) Authenticate(ctx context.Context, token string) (model.Principal, error) {
+	if service.store == nil {
+		return model.Principal{}, errors.New("session store is not configured")
+	}
+
+	claims := &authCustomClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, isvalid := token.Method.(*jwt.SigningMethodHMAC); !isvalid {
+			return nil, fmt.Errorf("invalid token algorithm %v", token.Header["alg"])
 		}
+		return []byte(service.secretKey), nil
+	})
+	if err != nil {
+		return model.Principal{}, err
+	}
+	if !parsed.Valid || claims.StandardClaims.Id == "" {
+		return model.Principal{}, fmt.Errorf("invalid token")
+	}
+
+	session, err := service.store.Find(ctx, claims.StandardClaims.Id)
+	if err != nil {
+		return model.Principal{}, fmt.Errorf("token not found")
+	}
+
+	if time.Now().Unix() >= session.ExpiredAt {
+		return model.Principal{}, fmt.Errorf("token expired")
+	}
+
+	role, err := strconv.Atoi(claims.Role)
+	if err != nil {
+		return model.Principal{}, fmt.Errorf("invalid token role")
 	}
-	return fmt.Errorf("token not found")
+
+	return model.Principal{
+		Id:      claims.Id,
+		Name:    claims.Name,
+		Role:    role,
+		TokenId: session.Id,
+	}, nil
 }
 
-func (service *jwtServices) DeleteToken(token string) error {
-	whiteListTokens = remove(whiteListTokens, token)
-	return nil
+func (service *jwtServices) DeleteToken(ctx context.Context, token string) error {
+	if service.store == nil {
+		return errors.New("session store is not configured")
+	}
+
+	tokenId, err := service.tokenId(token)
+	if err != nil {
+		return err
+	}
+
+	return service.store.Delete(ctx, tokenId)
 }
 
-func remove(slice []string, token string) []string {
-	for i, t := range slice {
-		if t == token {
-			slice = append(slice[:i], slice[i+1:]...)
-			break
-		}
+// tokenId verifies the signature of the token and returns its jti claim
+func (service *jwtServices) tokenId(encodedToken string) (string, error) {
+	token, err := service.ValidateToken(encodedToken)
+	if err != nil {
+		return "", err
 	}
-	return slice
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return "", fmt.Errorf("invalid token")
+	}
+
+	tokenId, ok := claims["jti"].(string)
+	if !ok || tokenId == "" {
+		return "", fmt.Errorf("token not found")
+	}
+
+	return tokenId, nil
 }
@@ -23,13 +23,15 @@ type ModuleArticlesService interface {
 type moduleArticlesService struct {
 	ModuleArticlesRepository repository.ModuleArticlesRepository
 	CourseRepository         repository.CourseRepository
+	CurriculumRepository     repository.CurriculumRepository
 	DB                       *sql.DB
 }
 
-func NewModuleArticlesService(moduleArticlesRepository *repository.ModuleArticlesRepository, courseRepository *repository.CourseRepository, db *sql.DB) ModuleArticlesService {
+func NewModuleArticlesService(moduleArticlesRepository *repository.ModuleArticlesRepository, courseRepository *repository.CourseRepository, curriculumRepository *repository.CurriculumRepository, db *sql.DB) ModuleArticlesService {
 	return &moduleArticlesService{
 		ModuleArticlesRepository: *moduleArticlesRepository,
 		CourseRepository:         *courseRepository,
+		CurriculumRepository:     *curriculumRepository,
 		DB:                       db,
 	}
 }
@@ -103,6 +105,11 @@ func (service *moduleArticlesService) Create(ctx context.Context, request model.
 		return model.GetModuleArticlesResponse{}, err
 	}
 
+	err = appendCurriculumItem(ctx, tx, service.CurriculumRepository, course.Id, entity.CurriculumArticle, ModAr.Id)
+	if err != nil {
+		return model.GetModuleArticlesResponse{}, err
+	}
+
 	return utils.ToModuleArticlesResponse(ModAr), nil
 }
 
@@ -160,6 +167,11 @@ func (service *moduleArticlesService) Delete(ctx context.Context, code string, i
 		return err
 	}
 
+	err = service.CurriculumRepository.DeleteItem(ctx, tx, entity.CurriculumArticle, idArticle)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
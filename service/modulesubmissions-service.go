@@ -3,10 +3,18 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"github.com/rg-km/final-project-engineering-12/backend/entity"
 	"github.com/rg-km/final-project-engineering-12/backend/model"
 	"github.com/rg-km/final-project-engineering-12/backend/repository"
+	"github.com/rg-km/final-project-engineering-12/backend/storage"
 	"github.com/rg-km/final-project-engineering-12/backend/utils"
+	"strings"
+)
+
+var (
+	ErrInvalidMaxSize  = errors.New("max_size can not be negative")
+	ErrInvalidDeadline = errors.New("deadline must be an RFC 3339 timestamp or a date")
 )
 
 type ModuleSubmissionsService interface {
@@ -17,6 +25,9 @@ type ModuleSubmissionsService interface {
 	Delete(ctx context.Context, code string, idSubmission int) error
 	Next(ctx context.Context, code string, idSubmission int) (model.GetNextPreviousSubmissionsResponse, error)
 	Previous(ctx context.Context, code string, idSubmission int) (model.GetNextPreviousSubmissionsResponse, error)
+	FindAllExtensions(ctx context.Context, code string, idSubmission int) ([]model.GetExtensionResponse, error)
+	SaveExtension(ctx context.Context, code string, request model.SaveExtensionRequest) error
+	DeleteExtension(ctx context.Context, code string, idSubmission int, userId int) error
 }
 
 type moduleSubmissionsService struct {
@@ -24,15 +35,21 @@ type moduleSubmissionsService struct {
 	CourseRepository            repository.CourseRepository
 	UserCourseService           repository.UserCourseRepository
 	UserSubmissionService       repository.UserSubmissionsRepository
+	ExtensionRepository         repository.SubmissionExtensionRepository
+	GradebookRepository         repository.GradebookRepository
+	CurriculumRepository        repository.CurriculumRepository
 	DB                          *sql.DB
 }
 
-func NewModuleSubmissionsService(moduleSubmissionsRepository *repository.ModuleSubmissionsRepository, courseRepository *repository.CourseRepository, userCourseService *repository.UserCourseRepository, userSubmissionService *repository.UserSubmissionsRepository, db *sql.DB) ModuleSubmissionsService {
+func NewModuleSubmissionsService(moduleSubmissionsRepository *repository.ModuleSubmissionsRepository, courseRepository *repository.CourseRepository, userCourseService *repository.UserCourseRepository, userSubmissionService *repository.UserSubmissionsRepository, extensionRepository *repository.SubmissionExtensionRepository, gradebookRepository *repository.GradebookRepository, curriculumRepository *repository.CurriculumRepository, db *sql.DB) ModuleSubmissionsService {
 	return &moduleSubmissionsService{
 		ModuleSubmissionsRepository: *moduleSubmissionsRepository,
 		CourseRepository:            *courseRepository,
 		UserCourseService:           *userCourseService,
 		UserSubmissionService:       *userSubmissionService,
+		ExtensionRepository:         *extensionRepository,
+		GradebookRepository:         *gradebookRepository,
+		CurriculumRepository:        *curriculumRepository,
 		DB:                          db,
 	}
 }
@@ -83,6 +100,12 @@ func (service *moduleSubmissionsService) FindByModId(ctx context.Context, code s
 }
 
 func (service *moduleSubmissionsService) Create(ctx context.Context, request model.CreateModuleSubmissionsRequest, code string) (model.GetModuleSubmissionsResponse, error) {
+	// An empty deadline leaves the zero time, the submission then never closes
+	deadline := utils.ParseDeadline(request.Deadline)
+	if request.Deadline != "" && deadline.IsZero() {
+		return model.GetModuleSubmissionsResponse{}, ErrInvalidDeadline
+	}
+
 	tx, err := service.DB.Begin()
 	if err != nil {
 		return model.GetModuleSubmissionsResponse{}, err
@@ -94,11 +117,37 @@ func (service *moduleSubmissionsService) Create(ctx context.Context, request mod
 		return model.GetModuleSubmissionsResponse{}, err
 	}
 
+	allowedTypes, err := uploadRules(request.AllowedTypes, request.MaxSize)
+	if err != nil {
+		return model.GetModuleSubmissionsResponse{}, err
+	}
+
+	latePolicy := request.LatePolicy
+	if latePolicy == "" {
+		latePolicy = entity.LatePolicyAccept
+	}
+	if !validLatePolicy(latePolicy, request.GracePeriod, request.PenaltyPerDay) {
+		return model.GetModuleSubmissionsResponse{}, ErrInvalidLatePolicy
+	}
+
+	if request.CategoryId != nil {
+		_, err = service.GradebookRepository.FindCategoryById(ctx, tx, course.Id, *request.CategoryId)
+		if err != nil {
+			return model.GetModuleSubmissionsResponse{}, ErrCategoryNotFound
+		}
+	}
+
 	newModsub := entity.ModuleSubmissions{
-		CourseId:    course.Id,
-		Name:        request.Name,
-		Description: request.Description,
-		Deadline:    utils.ParseTime(request.Deadline),
+		CourseId:      course.Id,
+		Name:          request.Name,
+		Description:   request.Description,
+		Deadline:      deadline,
+		AllowedTypes:  allowedTypes,
+		MaxSize:       request.MaxSize,
+		LatePolicy:    latePolicy,
+		GracePeriod:   request.GracePeriod,
+		PenaltyPerDay: request.PenaltyPerDay,
+		CategoryId:    request.CategoryId,
 	}
 
 	modsub, err := service.ModuleSubmissionsRepository.Create(ctx, tx, newModsub)
@@ -106,6 +155,11 @@ func (service *moduleSubmissionsService) Create(ctx context.Context, request mod
 		return model.GetModuleSubmissionsResponse{}, err
 	}
 
+	err = appendCurriculumItem(ctx, tx, service.CurriculumRepository, course.Id, entity.CurriculumSubmission, modsub.Id)
+	if err != nil {
+		return model.GetModuleSubmissionsResponse{}, err
+	}
+
 	// Insert to user submissions
 	findAllUser, err := service.UserCourseService.FindAllUserByCourseId(ctx, tx, modsub.CourseId)
 	if err != nil {
@@ -122,7 +176,14 @@ func (service *moduleSubmissionsService) Create(ctx context.Context, request mod
 	return utils.ToModuleSubmissionsResponse(modsub), nil
 }
 
+// Update replaces the module submission, the late policy is kept when the request leaves it out
 func (service *moduleSubmissionsService) Update(ctx context.Context, request model.UpdateModuleSubmissionsRequest, code string, idSubmission int) (model.GetModuleSubmissionsResponse, error) {
+	// An empty deadline leaves the zero time, the submission then never closes
+	deadline := utils.ParseDeadline(request.Deadline)
+	if request.Deadline != "" && deadline.IsZero() {
+		return model.GetModuleSubmissionsResponse{}, ErrInvalidDeadline
+	}
+
 	tx, err := service.DB.Begin()
 	if err != nil {
 		return model.GetModuleSubmissionsResponse{}, err
@@ -134,16 +195,42 @@ func (service *moduleSubmissionsService) Update(ctx context.Context, request mod
 		return model.GetModuleSubmissionsResponse{}, err
 	}
 
-	_, err = service.ModuleSubmissionsRepository.FindByModId(ctx, tx, course.Id, idSubmission)
+	current, err := service.ModuleSubmissionsRepository.FindByModId(ctx, tx, course.Id, idSubmission)
 	if err != nil {
 		return model.GetModuleSubmissionsResponse{}, err
 	}
 
+	allowedTypes, err := uploadRules(request.AllowedTypes, request.MaxSize)
+	if err != nil {
+		return model.GetModuleSubmissionsResponse{}, err
+	}
+
+	latePolicy, gracePeriod, penaltyPerDay := request.LatePolicy, request.GracePeriod, request.PenaltyPerDay
+	if latePolicy == "" {
+		latePolicy, gracePeriod, penaltyPerDay = current.LatePolicy, current.GracePeriod, current.PenaltyPerDay
+	}
+	if !validLatePolicy(latePolicy, gracePeriod, penaltyPerDay) {
+		return model.GetModuleSubmissionsResponse{}, ErrInvalidLatePolicy
+	}
+
+	if request.CategoryId != nil {
+		_, err = service.GradebookRepository.FindCategoryById(ctx, tx, course.Id, *request.CategoryId)
+		if err != nil {
+			return model.GetModuleSubmissionsResponse{}, ErrCategoryNotFound
+		}
+	}
+
 	newModsub := entity.ModuleSubmissions{
-		CourseId:    course.Id,
-		Name:        request.Name,
-		Description: request.Description,
-		Deadline:    utils.ParseTime(request.Deadline),
+		CourseId:      course.Id,
+		Name:          request.Name,
+		Description:   request.Description,
+		Deadline:      deadline,
+		AllowedTypes:  allowedTypes,
+		MaxSize:       request.MaxSize,
+		LatePolicy:    latePolicy,
+		GracePeriod:   gracePeriod,
+		PenaltyPerDay: penaltyPerDay,
+		CategoryId:    request.CategoryId,
 	}
 
 	modsub, err := service.ModuleSubmissionsRepository.Update(ctx, tx, newModsub, idSubmission)
@@ -176,6 +263,11 @@ func (service *moduleSubmissionsService) Delete(ctx context.Context, code string
 		return err
 	}
 
+	err = service.CurriculumRepository.DeleteItem(ctx, tx, entity.CurriculumSubmission, idSubmission)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -228,3 +320,118 @@ func (service *moduleSubmissionsService) Previous(ctx context.Context, code stri
 
 	return utils.ToModuleSubmissionsNextPreviousResponse(previous), nil
 }
+
+// FindAllExtensions lists the students who got more time for a module submission
+func (service *moduleSubmissionsService) FindAllExtensions(ctx context.Context, code string, idSubmission int) ([]model.GetExtensionResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = service.ModuleSubmissionsRepository.FindByModId(ctx, tx, course.Id, idSubmission)
+	if err != nil {
+		return nil, err
+	}
+
+	extensions, err := service.ExtensionRepository.FindAllByModuleSubmission(ctx, tx, idSubmission)
+	if err != nil {
+		return nil, err
+	}
+
+	extensionResponses := []model.GetExtensionResponse{}
+	for _, extension := range extensions {
+		extensionResponses = append(extensionResponses, utils.ToExtensionResponse(extension))
+	}
+
+	return extensionResponses, nil
+}
+
+// SaveExtension gives a student of the course their own deadline for a module submission
+func (service *moduleSubmissionsService) SaveExtension(ctx context.Context, code string, request model.SaveExtensionRequest) error {
+	deadline := utils.ParseDeadline(request.Deadline)
+	if deadline.IsZero() {
+		return ErrInvalidDeadline
+	}
+
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.ModuleSubmissionsRepository.FindByModId(ctx, tx, course.Id, request.ModuleSubmissionId)
+	if err != nil {
+		return err
+	}
+
+	// Every student of the course has a user submission for each module submission
+	_, err = service.UserSubmissionService.FindUserSubmissionByOther(ctx, tx, entity.UserSubmissions{
+		UserId:             request.UserId,
+		ModuleSubmissionId: request.ModuleSubmissionId,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = service.ExtensionRepository.Save(ctx, tx, entity.SubmissionExtensions{
+		ModuleSubmissionId: request.ModuleSubmissionId,
+		UserId:             request.UserId,
+		Deadline:           deadline,
+		CreatedAt:          utils.TimeNow(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (service *moduleSubmissionsService) DeleteExtension(ctx context.Context, code string, idSubmission int, userId int) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.ModuleSubmissionsRepository.FindByModId(ctx, tx, course.Id, idSubmission)
+	if err != nil {
+		return err
+	}
+
+	err = service.ExtensionRepository.Delete(ctx, tx, idSubmission, userId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// uploadRules validates the upload restrictions of a module submission and returns the allowed types as stored
+func uploadRules(allowedTypes []string, maxSize int64) (string, error) {
+	if maxSize < 0 {
+		return "", ErrInvalidMaxSize
+	}
+
+	types, err := storage.ParseFileTypes(allowedTypes)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(types, ","), nil
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/mail"
+	"github.com/rg-km/final-project-engineering-12/backend/repository"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var ErrPasswordResetInvalid = errors.New("the password reset link is invalid or expired")
+
+type PasswordResetService interface {
+	// Forgot emails a password reset link to the user with the email, nothing happens for unknown emails
+	Forgot(ctx context.Context, email string, languages string) error
+	// Reset sets the password of the user the token was sent to and logs the user out everywhere
+	Reset(ctx context.Context, token string, password string) error
+}
+
+type passwordResetService struct {
+	PasswordResetRepository repository.PasswordResetRepository
+	UserRepository          repository.UserRepository
+	RefreshTokenRepository  repository.RefreshTokenRepository
+	SessionStore            SessionStore
+	Mailer                  *mailer
+	ResetUrl                string
+	Lifetime                time.Duration
+	DB                      *sql.DB
+}
+
+// NewPasswordResetService links the reset emails to resetUrl, a link can be used once within lifetime
+func NewPasswordResetService(passwordResetRepository *repository.PasswordResetRepository, userRepository *repository.UserRepository, refreshTokenRepository *repository.RefreshTokenRepository, sessionStore SessionStore, emailOutboxRepository *repository.EmailOutboxRepository, templates *mail.Templates, resetUrl string, lifetime time.Duration, db *sql.DB) PasswordResetService {
+	return &passwordResetService{
+		PasswordResetRepository: *passwordResetRepository,
+		UserRepository:          *userRepository,
+		RefreshTokenRepository:  *refreshTokenRepository,
+		SessionStore:            sessionStore,
+		Mailer:                  newMailer(emailOutboxRepository, templates, ""),
+		ResetUrl:                resetUrl,
+		Lifetime:                lifetime,
+		DB:                      db,
+	}
+}
+
+func (service *passwordResetService) Forgot(ctx context.Context, email string, languages string) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	user, err := service.UserRepository.FindByEmail(ctx, tx, email)
+	if err != nil {
+		return err
+	}
+	if user.Id == 0 {
+		return nil
+	}
+
+	token, err := utils.RandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	// Only the hash is stored, the token itself is only in the email
+	now := time.Now()
+	_, err = service.PasswordResetRepository.Create(ctx, tx, entity.PasswordResets{
+		UserId:    user.Id,
+		TokenHash: utils.HashToken(token),
+		ExpiredAt: now.Add(service.Lifetime).Unix(),
+		CreatedAt: now.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("token", token)
+
+	return service.Mailer.queue(ctx, tx, user.Email, "password-reset", languages, struct {
+		Name    string
+		Url     string
+		Minutes int
+	}{
+		Name:    user.Name,
+		Url:     service.ResetUrl + "?" + query.Encode(),
+		Minutes: int(service.Lifetime.Minutes()),
+	})
+}
+
+func (service *passwordResetService) Reset(ctx context.Context, token string, password string) error {
+	userId, err := service.reset(ctx, token, password)
+	if err != nil {
+		return err
+	}
+
+	// The sessions are kept by the session store, which works in its own transaction
+	return service.SessionStore.DeleteByUser(ctx, userId)
+}
+
+// reset uses up the token, saves the password and revokes the refresh tokens of the user. It returns the id of the user.
+func (service *passwordResetService) reset(ctx context.Context, token string, password string) (int, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	now := time.Now()
+
+	passwordReset, err := service.PasswordResetRepository.FindByHash(ctx, tx, utils.HashToken(token))
+	if err != nil {
+		return 0, ErrPasswordResetInvalid
+	}
+	if passwordReset.UsedAt != nil || now.Unix() >= passwordReset.ExpiredAt {
+		return 0, ErrPasswordResetInvalid
+	}
+
+	unused, err := service.PasswordResetRepository.MarkUsed(ctx, tx, passwordReset.Id, now.Unix())
+	if err != nil {
+		return 0, err
+	}
+	if !unused {
+		return 0, ErrPasswordResetInvalid
+	}
+
+	err = service.UserRepository.UpdatePassword(ctx, tx, passwordReset.UserId, password, now)
+	if err != nil {
+		return 0, err
+	}
+
+	// Links sent before this one can not change the password again
+	err = service.PasswordResetRepository.MarkUsedByUser(ctx, tx, passwordReset.UserId, now.Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	err = service.RefreshTokenRepository.RevokeFamiliesByUser(ctx, tx, passwordReset.UserId, now.Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	return passwordReset.UserId, nil
+}
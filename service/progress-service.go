@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/repository"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+type ProgressService interface {
+	Open(ctx context.Context, code string, itemType string, itemId int, userId int) error
+	Complete(ctx context.Context, code string, itemType string, itemId int, userId int) (model.GetCourseProgressResponse, error)
+	Progress(ctx context.Context, code string, userId int) (model.GetCourseProgressResponse, error)
+	FindAll(ctx context.Context, code string) ([]model.GetStudentProgressResponse, error)
+}
+
+type progressService struct {
+	ProgressRepository   repository.ProgressRepository
+	CurriculumRepository repository.CurriculumRepository
+	UserCourseRepository repository.UserCourseRepository
+	CourseRepository     repository.CourseRepository
+	DB                   *sql.DB
+}
+
+func NewProgressService(progressRepository *repository.ProgressRepository, curriculumRepository *repository.CurriculumRepository, userCourseRepository *repository.UserCourseRepository, courseRepository *repository.CourseRepository, db *sql.DB) ProgressService {
+	return &progressService{
+		ProgressRepository:   *progressRepository,
+		CurriculumRepository: *curriculumRepository,
+		UserCourseRepository: *userCourseRepository,
+		CourseRepository:     *courseRepository,
+		DB:                   db,
+	}
+}
+
+// Open records that the user opened the item, users not enrolled in the course are not tracked
+func (service *progressService) Open(ctx context.Context, code string, itemType string, itemId int, userId int) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.UserCourseRepository.FindByUserCourse(ctx, tx, strconv.Itoa(userId), strconv.Itoa(course.Id))
+	if err != nil {
+		return nil
+	}
+
+	return service.ProgressRepository.Open(ctx, tx, entity.LearningProgress{
+		UserId:    userId,
+		CourseId:  course.Id,
+		Type:      itemType,
+		ItemId:    itemId,
+		VisitedAt: time.Now().UTC(),
+	})
+}
+
+func (service *progressService) Complete(ctx context.Context, code string, itemType string, itemId int, userId int) (model.GetCourseProgressResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetCourseProgressResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return model.GetCourseProgressResponse{}, err
+	}
+
+	_, err = service.UserCourseRepository.FindByUserCourse(ctx, tx, strconv.Itoa(userId), strconv.Itoa(course.Id))
+	if err != nil {
+		return model.GetCourseProgressResponse{}, ErrNotEnrolled
+	}
+
+	items, err := service.CurriculumRepository.FindAllItems(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetCourseProgressResponse{}, err
+	}
+
+	found := false
+	for _, item := range items {
+		if item.Type == itemType && item.ItemId == itemId {
+			found = true
+		}
+	}
+	if !found {
+		return model.GetCourseProgressResponse{}, ErrCurriculumItemNotFound
+	}
+
+	now := time.Now().UTC()
+	err = service.ProgressRepository.Complete(ctx, tx, entity.LearningProgress{
+		UserId:      userId,
+		CourseId:    course.Id,
+		Type:        itemType,
+		ItemId:      itemId,
+		VisitedAt:   now,
+		CompletedAt: &now,
+	})
+	if err != nil {
+		return model.GetCourseProgressResponse{}, err
+	}
+
+	progresses, err := findAllProgress(ctx, tx, service.ProgressRepository, course.Id)
+	if err != nil {
+		return model.GetCourseProgressResponse{}, err
+	}
+
+	return courseProgress(course, items, progresses, userId), nil
+}
+
+// Progress returns the progress of the user through the curriculum with every item
+func (service *progressService) Progress(ctx context.Context, code string, userId int) (model.GetCourseProgressResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetCourseProgressResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return model.GetCourseProgressResponse{}, err
+	}
+
+	_, err = service.UserCourseRepository.FindByUserCourse(ctx, tx, strconv.Itoa(userId), strconv.Itoa(course.Id))
+	if err != nil {
+		return model.GetCourseProgressResponse{}, ErrNotEnrolled
+	}
+
+	items, err := service.CurriculumRepository.FindAllItems(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetCourseProgressResponse{}, err
+	}
+
+	progresses, err := findAllProgress(ctx, tx, service.ProgressRepository, course.Id)
+	if err != nil {
+		return model.GetCourseProgressResponse{}, err
+	}
+
+	return courseProgress(course, items, progresses, userId), nil
+}
+
+// FindAll returns the progress of every student of the course without the items
+func (service *progressService) FindAll(ctx context.Context, code string) ([]model.GetStudentProgressResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := service.UserCourseRepository.FindAllUserByCourseId(ctx, tx, course.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := service.CurriculumRepository.FindAllItems(ctx, tx, course.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	progresses, err := findAllProgress(ctx, tx, service.ProgressRepository, course.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []model.GetStudentProgressResponse{}
+	for _, user := range users {
+		progress := courseProgress(course, items, progresses, user.IdUser)
+		responses = append(responses, model.GetStudentProgressResponse{
+			UserId:        user.IdUser,
+			Name:          user.UserName,
+			Username:      user.UserUsername,
+			Completed:     progress.Completed,
+			Total:         progress.Total,
+			Progress:      progress.Progress,
+			TimeRemaining: progress.TimeRemaining,
+			LastVisited:   progress.LastVisited,
+		})
+	}
+
+	return responses, nil
+}
+
+// findAllProgress returns the opened and completed items of every user of the course with the submitted module
+// submissions and quizzes as completed
+func findAllProgress(ctx context.Context, tx *sql.Tx, progressRepository repository.ProgressRepository, courseId int) ([]entity.LearningProgress, error) {
+	progresses, err := progressRepository.FindAll(ctx, tx, courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	submitted, err := progressRepository.FindAllSubmitted(ctx, tx, courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(progresses, submitted...), nil
+}
+
+// courseProgress counts the items of the curriculum the user completed. An item is completed from the first time it
+// was, the minutes of the articles left make the time remaining and the last visited item is the last one opened or
+// marked complete.
+func courseProgress(course entity.Courses, items []entity.CurriculumItems, progresses []entity.LearningProgress, userId int) model.GetCourseProgressResponse {
+	response := model.GetCourseProgressResponse{
+		UserId:     userId,
+		CodeCourse: course.CodeCourse,
+		Total:      len(items),
+		Items:      []model.GetItemProgressResponse{},
+	}
+
+	for _, item := range items {
+		itemResponse := model.GetItemProgressResponse{
+			Type:     item.Type,
+			Id:       item.ItemId,
+			Name:     item.Name,
+			Estimate: item.Estimate,
+		}
+
+		for _, progress := range progresses {
+			if progress.UserId != userId || progress.Type != item.Type || progress.ItemId != item.ItemId {
+				continue
+			}
+
+			itemResponse.Opens += progress.Opens
+			if !progress.VisitedAt.IsZero() {
+				visitedAt := progress.VisitedAt
+				itemResponse.VisitedAt = &visitedAt
+			}
+			if progress.CompletedAt != nil && (itemResponse.CompletedAt == nil || progress.CompletedAt.Before(*itemResponse.CompletedAt)) {
+				itemResponse.CompletedAt = progress.CompletedAt
+			}
+		}
+
+		itemResponse.Completed = itemResponse.CompletedAt != nil
+		if itemResponse.Completed {
+			response.Completed++
+		} else {
+			response.TimeRemaining += item.Estimate
+		}
+
+		if itemResponse.VisitedAt != nil && (response.LastVisited == nil || itemResponse.VisitedAt.After(response.LastVisited.VisitedAt)) {
+			response.LastVisited = &model.GetLastVisitedResponse{
+				Type:      item.Type,
+				Id:        item.ItemId,
+				Name:      item.Name,
+				VisitedAt: *itemResponse.VisitedAt,
+			}
+		}
+
+		response.Items = append(response.Items, itemResponse)
+	}
+
+	if response.Total > 0 {
+		response.Progress = response.Completed * 100 / response.Total
+	}
+
+	return response
+}
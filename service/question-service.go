@@ -11,7 +11,7 @@ import (
 )
 
 type QuestionService interface {
-	FindAll(ctx context.Context) ([]model.GetQuestionRelationResponse, error)
+	FindAll(ctx context.Context, query model.ListQuery) ([]model.GetQuestionRelationResponse, model.ListPage, error)
 	Create(ctx context.Context, request model.CreateQuestionRequest) (model.GetQuestionResponse, error)
 	Delete(ctx context.Context, questionId int) error
 	Update(ctx context.Context, request model.UpdateQuestionRequest, questionId int) (model.GetQuestionRelationResponse, error)
@@ -67,16 +67,16 @@ func (service *questionService) Create(ctx context.Context, request model.Create
 	return utils.ToQuestionResponse(question), nil
 }
 
-func (service *questionService) FindAll(ctx context.Context) ([]model.GetQuestionRelationResponse, error) {
+func (service *questionService) FindAll(ctx context.Context, query model.ListQuery) ([]model.GetQuestionRelationResponse, model.ListPage, error) {
 	tx, err := service.DB.Begin()
 	if err != nil {
-		return []model.GetQuestionRelationResponse{}, err
+		return []model.GetQuestionRelationResponse{}, model.ListPage{}, err
 	}
 	defer utils.CommitOrRollback(tx)
 
-	courses, err := service.QuestionRepository.FindAll(ctx, tx)
+	courses, total, err := service.QuestionRepository.FindAll(ctx, tx, query)
 	if err != nil {
-		return []model.GetQuestionRelationResponse{}, err
+		return []model.GetQuestionRelationResponse{}, model.ListPage{}, err
 	}
 
 	var courseResponses []model.GetQuestionRelationResponse
@@ -84,7 +84,7 @@ func (service *questionService) FindAll(ctx context.Context) ([]model.GetQuestio
 		courseResponses = append(courseResponses, utils.ToQuestionRelationResponse(question))
 	}
 
-	return courseResponses, nil
+	return courseResponses, utils.ToListPage(query, len(courses), total), nil
 }
 
 func (service *questionService) Delete(ctx context.Context, questionId int) error {
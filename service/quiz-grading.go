@@ -0,0 +1,167 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var ErrInvalidQuizQuestion = errors.New("multiple_choice needs two choices with one correct, multiple_select two choices with at least one correct, true_false answers with true or false, short_answer at least one answer and numeric a numeric_answer")
+
+// newQuizQuestion turns a request into a question and its choices, a question without points is worth one
+func newQuizQuestion(request model.QuizQuestionRequest, quizId int, position int) (entity.QuizQuestions, []entity.QuizChoices, error) {
+	question := entity.QuizQuestions{
+		QuizId:    quizId,
+		Type:      request.Type,
+		Prompt:    request.Prompt,
+		Points:    request.Points,
+		Position:  position,
+		Tolerance: request.Tolerance,
+	}
+	if question.Points == 0 {
+		question.Points = 1
+	}
+
+	var choices []entity.QuizChoices
+	correct := 0
+	for _, choice := range request.Choices {
+		choices = append(choices, entity.QuizChoices{Content: choice.Content, Correct: choice.Correct})
+		if choice.Correct {
+			correct++
+		}
+	}
+
+	switch request.Type {
+	case entity.QuestionMultipleChoice:
+		if len(choices) < 2 || correct != 1 {
+			return entity.QuizQuestions{}, nil, ErrInvalidQuizQuestion
+		}
+	case entity.QuestionMultipleSelect:
+		if len(choices) < 2 || correct < 1 {
+			return entity.QuizQuestions{}, nil, ErrInvalidQuizQuestion
+		}
+	case entity.QuestionTrueFalse:
+		if len(request.Answers) != 1 {
+			return entity.QuizQuestions{}, nil, ErrInvalidQuizQuestion
+		}
+		answer, err := strconv.ParseBool(request.Answers[0])
+		if err != nil {
+			return entity.QuizQuestions{}, nil, ErrInvalidQuizQuestion
+		}
+		choices = []entity.QuizChoices{{Content: "True", Correct: answer}, {Content: "False", Correct: !answer}}
+	case entity.QuestionShortAnswer:
+		choices = nil
+		for _, answer := range request.Answers {
+			if normalizeAnswer(answer) != "" {
+				choices = append(choices, entity.QuizChoices{Content: answer, Correct: true})
+			}
+		}
+		if len(choices) == 0 {
+			return entity.QuizQuestions{}, nil, ErrInvalidQuizQuestion
+		}
+	case entity.QuestionNumeric:
+		if request.NumericAnswer == nil {
+			return entity.QuizQuestions{}, nil, ErrInvalidQuizQuestion
+		}
+		question.NumericAnswer = request.NumericAnswer
+		choices = nil
+	}
+
+	for i := range choices {
+		choices[i].Position = i
+	}
+
+	return question, choices, nil
+}
+
+// gradeAnswer marks an answer right only when it is fully right: the one correct choice, exactly the correct choices
+// of a multiple_select, an accepted short answer ignoring case and spaces, or a number within the tolerance
+func gradeAnswer(question entity.QuizQuestions, choices []entity.QuizChoices, answer model.QuizAnswerRequest) entity.QuizResponses {
+	response := entity.QuizResponses{QuestionId: question.Id}
+
+	switch question.Type {
+	case entity.QuestionMultipleChoice, entity.QuestionTrueFalse, entity.QuestionMultipleSelect:
+		picked := map[int]bool{}
+		var ids []int
+		for _, id := range answer.ChoiceIds {
+			if !picked[id] {
+				picked[id] = true
+				ids = append(ids, id)
+			}
+		}
+		sort.Ints(ids)
+		response.Answer = joinIds(ids)
+
+		response.Correct = len(ids) > 0 && (question.Type == entity.QuestionMultipleSelect || len(ids) == 1)
+		matched := 0
+		for _, choice := range choices {
+			if choice.Correct != picked[choice.Id] {
+				response.Correct = false
+			}
+			if picked[choice.Id] {
+				matched++
+			}
+		}
+		if matched != len(ids) {
+			response.Correct = false
+		}
+	case entity.QuestionShortAnswer:
+		response.Answer = answer.Text
+		for _, choice := range choices {
+			if normalizeAnswer(answer.Text) != "" && normalizeAnswer(answer.Text) == normalizeAnswer(choice.Content) {
+				response.Correct = true
+			}
+		}
+	case entity.QuestionNumeric:
+		response.Answer = answer.Text
+		value, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(answer.Text), ",", ".", 1), 64)
+		response.Correct = err == nil && question.NumericAnswer != nil && math.Abs(value-*question.NumericAnswer) <= question.Tolerance+1e-9
+	}
+
+	if response.Correct {
+		response.Points = question.Points
+	}
+
+	return response
+}
+
+func normalizeAnswer(answer string) string {
+	return strings.ToLower(strings.Join(strings.Fields(answer), " "))
+}
+
+func joinIds(ids []int) string {
+	var list []string
+	for _, id := range ids {
+		list = append(list, strconv.Itoa(id))
+	}
+
+	return strings.Join(list, ",")
+}
+
+// splitIds reads a list written by joinIds, ids that are not numbers are skipped
+func splitIds(list string) []int {
+	var ids []int
+	for _, item := range utils.SplitList(list) {
+		id, err := strconv.Atoi(item)
+		if err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// quizPercent is the grade of an attempt as a percent of what the quiz can give
+func quizPercent(score int, maxScore int) int {
+	if maxScore == 0 {
+		return 0
+	}
+
+	return int(math.Round(float64(score) * 100 / float64(maxScore)))
+}
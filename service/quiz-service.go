@@ -0,0 +1,595 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/repository"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var (
+	ErrQuizInUse        = errors.New("students already started the quiz, its questions can not change")
+	ErrEmptyQuiz        = errors.New("the quiz has no questions")
+	ErrNotEnrolled      = errors.New("you are not enrolled in this course")
+	ErrNoAttemptsLeft   = errors.New("there are no attempts left at this quiz")
+	ErrAttemptSubmitted = errors.New("the attempt is already submitted")
+	ErrAttemptExpired   = errors.New("the time limit of the attempt has passed")
+	ErrInvalidAnswer    = errors.New("answer every question of the attempt at most once")
+)
+
+type QuizService interface {
+	FindAll(ctx context.Context, code string) ([]model.GetQuizResponse, error)
+	FindById(ctx context.Context, code string, id int, withAnswers bool) (model.GetQuizResponse, error)
+	Create(ctx context.Context, code string, request model.SaveQuizRequest) (model.GetQuizResponse, error)
+	Update(ctx context.Context, code string, request model.SaveQuizRequest) (model.GetQuizResponse, error)
+	Delete(ctx context.Context, code string, id int) error
+	Next(ctx context.Context, code string, id int) (model.GetNextPreviousQuizzesResponse, error)
+	Previous(ctx context.Context, code string, id int) (model.GetNextPreviousQuizzesResponse, error)
+	StartAttempt(ctx context.Context, code string, id int, userId int) (model.GetQuizAttemptResponse, error)
+	SubmitAttempt(ctx context.Context, code string, request model.SubmitQuizAttemptRequest) (model.GetQuizAttemptResponse, error)
+	FindAllAttempts(ctx context.Context, code string, id int, userId int) ([]model.GetQuizAttemptResponse, error)
+	FindAllResults(ctx context.Context, code string, id int) ([]model.GetUserQuizResponse, error)
+}
+
+type quizService struct {
+	QuizRepository        repository.QuizRepository
+	QuizAttemptRepository repository.QuizAttemptRepository
+	UserQuizRepository    repository.UserQuizRepository
+	UserCourseRepository  repository.UserCourseRepository
+	CourseRepository      repository.CourseRepository
+	CurriculumRepository  repository.CurriculumRepository
+	DB                    *sql.DB
+}
+
+func NewQuizService(quizRepository *repository.QuizRepository, quizAttemptRepository *repository.QuizAttemptRepository, userQuizRepository *repository.UserQuizRepository, userCourseRepository *repository.UserCourseRepository, courseRepository *repository.CourseRepository, curriculumRepository *repository.CurriculumRepository, db *sql.DB) QuizService {
+	return &quizService{
+		QuizRepository:        *quizRepository,
+		QuizAttemptRepository: *quizAttemptRepository,
+		UserQuizRepository:    *userQuizRepository,
+		UserCourseRepository:  *userCourseRepository,
+		CourseRepository:      *courseRepository,
+		CurriculumRepository:  *curriculumRepository,
+		DB:                    db,
+	}
+}
+
+func (service *quizService) FindAll(ctx context.Context, code string) ([]model.GetQuizResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	quizzes, err := service.QuizRepository.FindAll(ctx, tx, course.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	quizResponses := []model.GetQuizResponse{}
+	for _, quiz := range quizzes {
+		questions, err := service.QuizRepository.FindQuestions(ctx, tx, quiz.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		quizResponses = append(quizResponses, utils.ToQuizResponse(quiz, maxScore(questions), nil))
+	}
+
+	return quizResponses, nil
+}
+
+// FindById returns the quiz with its questions, the answers are only shown withAnswers
+func (service *quizService) FindById(ctx context.Context, code string, id int, withAnswers bool) (model.GetQuizResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetQuizResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	quiz, err := service.findQuiz(ctx, tx, code, id)
+	if err != nil {
+		return model.GetQuizResponse{}, err
+	}
+
+	return service.quizResponse(ctx, tx, quiz, withAnswers)
+}
+
+func (service *quizService) Create(ctx context.Context, code string, request model.SaveQuizRequest) (model.GetQuizResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetQuizResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return model.GetQuizResponse{}, err
+	}
+
+	quiz, err := service.QuizRepository.Create(ctx, tx, entity.Quizzes{
+		CourseId:         course.Id,
+		Name:             request.Name,
+		Description:      request.Description,
+		TimeLimit:        request.TimeLimit,
+		MaxAttempts:      request.MaxAttempts,
+		ShuffleQuestions: request.ShuffleQuestions,
+	})
+	if err != nil {
+		return model.GetQuizResponse{}, err
+	}
+
+	err = service.saveQuestions(ctx, tx, quiz.Id, request.Questions)
+	if err != nil {
+		return model.GetQuizResponse{}, err
+	}
+
+	err = appendCurriculumItem(ctx, tx, service.CurriculumRepository, course.Id, entity.CurriculumQuiz, quiz.Id)
+	if err != nil {
+		return model.GetQuizResponse{}, err
+	}
+
+	// Insert to user quizzes
+	users, err := service.UserCourseRepository.FindAllUserByCourseId(ctx, tx, course.Id)
+	if err != nil {
+		return model.GetQuizResponse{}, err
+	}
+
+	for _, user := range users {
+		err := service.UserQuizRepository.OnlyCreate(ctx, tx, user.IdUser, quiz.Id)
+		if err != nil {
+			return model.GetQuizResponse{}, err
+		}
+	}
+
+	return service.quizResponse(ctx, tx, quiz, true)
+}
+
+// Update changes the settings of a quiz, its questions are replaced when the request has some
+func (service *quizService) Update(ctx context.Context, code string, request model.SaveQuizRequest) (model.GetQuizResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetQuizResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	quiz, err := service.findQuiz(ctx, tx, code, request.Id)
+	if err != nil {
+		return model.GetQuizResponse{}, err
+	}
+
+	if request.Questions != nil {
+		attempts, err := service.QuizAttemptRepository.Count(ctx, tx, quiz.Id)
+		if err != nil {
+			return model.GetQuizResponse{}, err
+		}
+		if attempts > 0 {
+			return model.GetQuizResponse{}, ErrQuizInUse
+		}
+
+		err = service.QuizRepository.DeleteQuestions(ctx, tx, quiz.Id)
+		if err != nil {
+			return model.GetQuizResponse{}, err
+		}
+
+		err = service.saveQuestions(ctx, tx, quiz.Id, request.Questions)
+		if err != nil {
+			return model.GetQuizResponse{}, err
+		}
+	}
+
+	quiz.Name = request.Name
+	quiz.Description = request.Description
+	quiz.TimeLimit = request.TimeLimit
+	quiz.MaxAttempts = request.MaxAttempts
+	quiz.ShuffleQuestions = request.ShuffleQuestions
+	quiz, err = service.QuizRepository.Update(ctx, tx, quiz)
+	if err != nil {
+		return model.GetQuizResponse{}, err
+	}
+
+	return service.quizResponse(ctx, tx, quiz, true)
+}
+
+func (service *quizService) Delete(ctx context.Context, code string, id int) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	quiz, err := service.findQuiz(ctx, tx, code, id)
+	if err != nil {
+		return err
+	}
+
+	err = service.QuizRepository.Delete(ctx, tx, quiz.Id)
+	if err != nil {
+		return err
+	}
+
+	return service.CurriculumRepository.DeleteItem(ctx, tx, entity.CurriculumQuiz, quiz.Id)
+}
+
+func (service *quizService) Next(ctx context.Context, code string, id int) (model.GetNextPreviousQuizzesResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetNextPreviousQuizzesResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return model.GetNextPreviousQuizzesResponse{}, err
+	}
+
+	quiz, err := service.QuizRepository.Next(ctx, tx, course.Id, id)
+	if err != nil {
+		return model.GetNextPreviousQuizzesResponse{}, err
+	}
+
+	return utils.ToQuizNextPreviousResponse(quiz), nil
+}
+
+func (service *quizService) Previous(ctx context.Context, code string, id int) (model.GetNextPreviousQuizzesResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetNextPreviousQuizzesResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return model.GetNextPreviousQuizzesResponse{}, err
+	}
+
+	quiz, err := service.QuizRepository.Previous(ctx, tx, course.Id, id)
+	if err != nil {
+		return model.GetNextPreviousQuizzesResponse{}, err
+	}
+
+	return utils.ToQuizNextPreviousResponse(quiz), nil
+}
+
+// StartAttempt gives the student the questions of the quiz, shuffled when the quiz asks for it.
+// An attempt that is still open is handed back instead of starting a new one.
+func (service *quizService) StartAttempt(ctx context.Context, code string, id int, userId int) (model.GetQuizAttemptResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetQuizAttemptResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	quiz, err := service.findQuiz(ctx, tx, code, id)
+	if err != nil {
+		return model.GetQuizAttemptResponse{}, err
+	}
+
+	_, err = service.UserQuizRepository.FindByUser(ctx, tx, userId, quiz.Id)
+	if err != nil {
+		return model.GetQuizAttemptResponse{}, ErrNotEnrolled
+	}
+
+	questions, err := service.QuizRepository.FindQuestions(ctx, tx, quiz.Id)
+	if err != nil {
+		return model.GetQuizAttemptResponse{}, err
+	}
+	if len(questions) == 0 {
+		return model.GetQuizAttemptResponse{}, ErrEmptyQuiz
+	}
+
+	choices, err := service.QuizRepository.FindChoices(ctx, tx, quiz.Id)
+	if err != nil {
+		return model.GetQuizAttemptResponse{}, err
+	}
+
+	attempts, err := service.QuizAttemptRepository.FindAll(ctx, tx, quiz.Id, userId)
+	if err != nil {
+		return model.GetQuizAttemptResponse{}, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, attempt := range attempts {
+		if attempt.SubmittedAt == nil && (attempt.Deadline == nil || now.Before(*attempt.Deadline)) {
+			return utils.ToQuizAttemptResponse(attempt, attemptQuestions(attempt, questions, choices), nil), nil
+		}
+	}
+	if quiz.MaxAttempts > 0 && len(attempts) >= quiz.MaxAttempts {
+		return model.GetQuizAttemptResponse{}, ErrNoAttemptsLeft
+	}
+
+	var order []int
+	for _, question := range questions {
+		order = append(order, question.Id)
+	}
+	if quiz.ShuffleQuestions {
+		random := rand.New(rand.NewSource(time.Now().UnixNano()))
+		random.Shuffle(len(order), func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+	}
+
+	attempt := entity.QuizAttempts{
+		QuizId:        quiz.Id,
+		UserId:        userId,
+		QuestionOrder: joinIds(order),
+		StartedAt:     now,
+		MaxScore:      maxScore(questions),
+	}
+	if quiz.TimeLimit > 0 {
+		deadline := now.Add(time.Duration(quiz.TimeLimit) * time.Minute)
+		attempt.Deadline = &deadline
+	}
+
+	attempt, err = service.QuizAttemptRepository.Create(ctx, tx, attempt)
+	if err != nil {
+		return model.GetQuizAttemptResponse{}, err
+	}
+
+	return utils.ToQuizAttemptResponse(attempt, attemptQuestions(attempt, questions, choices), nil), nil
+}
+
+// SubmitAttempt grades the answers of an open attempt, a question without an answer gets no points.
+// The grade record of the student keeps the best attempt.
+func (service *quizService) SubmitAttempt(ctx context.Context, code string, request model.SubmitQuizAttemptRequest) (model.GetQuizAttemptResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetQuizAttemptResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	quiz, err := service.findQuiz(ctx, tx, code, request.QuizId)
+	if err != nil {
+		return model.GetQuizAttemptResponse{}, err
+	}
+
+	attempt, err := service.QuizAttemptRepository.FindById(ctx, tx, quiz.Id, request.AttemptId)
+	if err != nil {
+		return model.GetQuizAttemptResponse{}, err
+	}
+	if attempt.UserId != request.UserId {
+		return model.GetQuizAttemptResponse{}, errors.New("quiz attempt not found")
+	}
+	if attempt.SubmittedAt != nil {
+		return model.GetQuizAttemptResponse{}, ErrAttemptSubmitted
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if attempt.Deadline != nil && now.After(*attempt.Deadline) {
+		return model.GetQuizAttemptResponse{}, ErrAttemptExpired
+	}
+
+	questions, err := service.QuizRepository.FindQuestions(ctx, tx, quiz.Id)
+	if err != nil {
+		return model.GetQuizAttemptResponse{}, err
+	}
+
+	choices, err := service.QuizRepository.FindChoices(ctx, tx, quiz.Id)
+	if err != nil {
+		return model.GetQuizAttemptResponse{}, err
+	}
+
+	answers := map[int]model.QuizAnswerRequest{}
+	for _, answer := range request.Answers {
+		if _, exists := answers[answer.QuestionId]; exists {
+			return model.GetQuizAttemptResponse{}, ErrInvalidAnswer
+		}
+		answers[answer.QuestionId] = answer
+	}
+
+	score := 0
+	var responses []entity.QuizResponses
+	for _, question := range orderedQuestions(attempt, questions) {
+		answer, exists := answers[question.Id]
+		if !exists {
+			answer = model.QuizAnswerRequest{QuestionId: question.Id}
+		}
+		delete(answers, question.Id)
+
+		response := gradeAnswer(question, questionChoices(question.Id, choices), answer)
+		response.AttemptId = attempt.Id
+		score += response.Points
+		responses = append(responses, response)
+	}
+	// Every answer is checked before anything is written, the transaction commits on errors too
+	if len(answers) > 0 {
+		return model.GetQuizAttemptResponse{}, ErrInvalidAnswer
+	}
+
+	userQuiz, err := service.UserQuizRepository.FindByUser(ctx, tx, attempt.UserId, quiz.Id)
+	if err != nil {
+		return model.GetQuizAttemptResponse{}, ErrNotEnrolled
+	}
+
+	for _, response := range responses {
+		err := service.QuizAttemptRepository.SaveResponse(ctx, tx, response)
+		if err != nil {
+			return model.GetQuizAttemptResponse{}, err
+		}
+	}
+
+	attempt.SubmittedAt = &now
+	attempt.Score = &score
+	err = service.QuizAttemptRepository.Submit(ctx, tx, attempt)
+	if err != nil {
+		return model.GetQuizAttemptResponse{}, err
+	}
+
+	grade := quizPercent(score, attempt.MaxScore)
+	if userQuiz.Grade == nil || grade > *userQuiz.Grade {
+		userQuiz.Grade = &grade
+		userQuiz.GradedAttemptId = &attempt.Id
+		err = service.UserQuizRepository.UpdateGrade(ctx, tx, userQuiz)
+		if err != nil {
+			return model.GetQuizAttemptResponse{}, err
+		}
+	}
+
+	return utils.ToQuizAttemptResponse(attempt, nil, responses), nil
+}
+
+// FindAllAttempts returns the attempts of the student with the graded answers of the submitted ones
+func (service *quizService) FindAllAttempts(ctx context.Context, code string, id int, userId int) ([]model.GetQuizAttemptResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	quiz, err := service.findQuiz(ctx, tx, code, id)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := service.QuizAttemptRepository.FindAll(ctx, tx, quiz.Id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	attemptResponses := []model.GetQuizAttemptResponse{}
+	for _, attempt := range attempts {
+		responses, err := service.QuizAttemptRepository.FindResponses(ctx, tx, attempt.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		attemptResponses = append(attemptResponses, utils.ToQuizAttemptResponse(attempt, nil, responses))
+	}
+
+	return attemptResponses, nil
+}
+
+// FindAllResults returns the grade record of every student of the course for the quiz
+func (service *quizService) FindAllResults(ctx context.Context, code string, id int) ([]model.GetUserQuizResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	quiz, err := service.findQuiz(ctx, tx, code, id)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := service.UserQuizRepository.FindAll(ctx, tx, quiz.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	resultResponses := []model.GetUserQuizResponse{}
+	for _, result := range results {
+		resultResponses = append(resultResponses, utils.ToUserQuizResponse(result))
+	}
+
+	return resultResponses, nil
+}
+
+func (service *quizService) findQuiz(ctx context.Context, tx *sql.Tx, code string, id int) (entity.Quizzes, error) {
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return entity.Quizzes{}, err
+	}
+
+	return service.QuizRepository.FindById(ctx, tx, course.Id, id)
+}
+
+func (service *quizService) saveQuestions(ctx context.Context, tx *sql.Tx, quizId int, requests []model.QuizQuestionRequest) error {
+	for position, request := range requests {
+		question, choices, err := newQuizQuestion(request, quizId, position)
+		if err != nil {
+			return err
+		}
+
+		question, err = service.QuizRepository.CreateQuestion(ctx, tx, question)
+		if err != nil {
+			return err
+		}
+
+		for _, choice := range choices {
+			choice.QuestionId = question.Id
+			_, err = service.QuizRepository.CreateChoice(ctx, tx, choice)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (service *quizService) quizResponse(ctx context.Context, tx *sql.Tx, quiz entity.Quizzes, withAnswers bool) (model.GetQuizResponse, error) {
+	questions, err := service.QuizRepository.FindQuestions(ctx, tx, quiz.Id)
+	if err != nil {
+		return model.GetQuizResponse{}, err
+	}
+
+	choices, err := service.QuizRepository.FindChoices(ctx, tx, quiz.Id)
+	if err != nil {
+		return model.GetQuizResponse{}, err
+	}
+
+	questionResponses := []model.GetQuizQuestionResponse{}
+	for _, question := range questions {
+		questionResponses = append(questionResponses, utils.ToQuizQuestionResponse(question, questionChoices(question.Id, choices), withAnswers))
+	}
+
+	return utils.ToQuizResponse(quiz, maxScore(questions), questionResponses), nil
+}
+
+// attemptQuestions returns the questions in the order of the attempt, without their answers
+func attemptQuestions(attempt entity.QuizAttempts, questions []entity.QuizQuestions, choices []entity.QuizChoices) []model.GetQuizQuestionResponse {
+	var responses []model.GetQuizQuestionResponse
+	for _, question := range orderedQuestions(attempt, questions) {
+		responses = append(responses, utils.ToQuizQuestionResponse(question, questionChoices(question.Id, choices), false))
+	}
+
+	return responses
+}
+
+// orderedQuestions puts the questions in the order the attempt showed them
+func orderedQuestions(attempt entity.QuizAttempts, questions []entity.QuizQuestions) []entity.QuizQuestions {
+	byId := map[int]entity.QuizQuestions{}
+	for _, question := range questions {
+		byId[question.Id] = question
+	}
+
+	var ordered []entity.QuizQuestions
+	for _, id := range splitIds(attempt.QuestionOrder) {
+		if question, exists := byId[id]; exists {
+			ordered = append(ordered, question)
+		}
+	}
+
+	return ordered
+}
+
+func questionChoices(questionId int, choices []entity.QuizChoices) []entity.QuizChoices {
+	var questionChoices []entity.QuizChoices
+	for _, choice := range choices {
+		if choice.QuestionId == questionId {
+			questionChoices = append(questionChoices, choice)
+		}
+	}
+
+	return questionChoices
+}
+
+func maxScore(questions []entity.QuizQuestions) int {
+	total := 0
+	for _, question := range questions {
+		total += question.Points
+	}
+
+	return total
+}
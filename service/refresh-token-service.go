@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/repository"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var ErrRefreshTokenInvalid = errors.New("refresh token is invalid")
+var ErrRefreshTokenReused = errors.New("refresh token has already been used, please login again")
+
+type RefreshTokenService interface {
+	Issue(ctx context.Context, userId int) (string, error)
+	Refresh(ctx context.Context, refreshToken string, clientIp string, userAgent string) (model.TokenResponse, error)
+	Revoke(ctx context.Context, refreshToken string, userId int) error
+}
+
+type refreshTokenService struct {
+	RefreshTokenRepository repository.RefreshTokenRepository
+	UserRepository         repository.UserRepository
+	DB                     *sql.DB
+	Lifetime               time.Duration
+}
+
+func NewRefreshTokenService(refreshTokenRepository *repository.RefreshTokenRepository, userRepository *repository.UserRepository, db *sql.DB, lifetime time.Duration) RefreshTokenService {
+	return &refreshTokenService{
+		RefreshTokenRepository: *refreshTokenRepository,
+		UserRepository:         *userRepository,
+		DB:                     db,
+		Lifetime:               lifetime,
+	}
+}
+
+// Issue starts a new token family for the user and returns its first refresh token
+func (service *refreshTokenService) Issue(ctx context.Context, userId int) (string, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	familyId, err := utils.RandomToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	family, err := service.RefreshTokenRepository.CreateFamily(ctx, tx, entity.RefreshTokenFamilies{
+		Id:        familyId,
+		UserId:    userId,
+		CreatedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return service.create(ctx, tx, family.Id)
+}
+
+// Refresh exchanges a refresh token for a new access token and a new refresh token of the same family.
+// Presenting a token that was already exchanged revokes the whole family.
+func (service *refreshTokenService) Refresh(ctx context.Context, refreshToken string, clientIp string, userAgent string) (model.TokenResponse, error) {
+	user, nextRefreshToken, err := service.rotate(ctx, refreshToken)
+	if err != nil {
+		return model.TokenResponse{}, err
+	}
+
+	// The session is saved in its own transaction, so it has to wait until the rotation is committed
+	accessToken, err := JWTAuthService().GenerateToken(ctx, user, clientIp, userAgent)
+	if err != nil {
+		return model.TokenResponse{}, err
+	}
+
+	return model.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: nextRefreshToken,
+	}, nil
+}
+
+func (service *refreshTokenService) rotate(ctx context.Context, refreshToken string) (entity.Users, string, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return entity.Users{}, "", err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	now := time.Now().Unix()
+
+	current, err := service.RefreshTokenRepository.FindByHash(ctx, tx, utils.HashToken(refreshToken))
+	if err != nil {
+		return entity.Users{}, "", ErrRefreshTokenInvalid
+	}
+
+	family, err := service.RefreshTokenRepository.FindFamilyById(ctx, tx, current.FamilyId)
+	if err != nil {
+		return entity.Users{}, "", err
+	}
+	if family.RevokedAt != nil {
+		return entity.Users{}, "", ErrRefreshTokenInvalid
+	}
+
+	unused, err := service.RefreshTokenRepository.MarkUsed(ctx, tx, current.Id, now)
+	if err != nil {
+		return entity.Users{}, "", err
+	}
+	if !unused {
+		err = service.RefreshTokenRepository.RevokeFamily(ctx, tx, family.Id, now)
+		if err != nil {
+			return entity.Users{}, "", err
+		}
+		return entity.Users{}, "", ErrRefreshTokenReused
+	}
+
+	if now >= current.ExpiredAt {
+		return entity.Users{}, "", ErrRefreshTokenInvalid
+	}
+
+	user, err := service.UserRepository.GetUserByID(ctx, tx, family.UserId)
+	if err != nil {
+		return entity.Users{}, "", err
+	}
+	if user.Id == 0 {
+		return entity.Users{}, "", ErrRefreshTokenInvalid
+	}
+
+	nextRefreshToken, err := service.create(ctx, tx, family.Id)
+	if err != nil {
+		return entity.Users{}, "", err
+	}
+
+	return user, nextRefreshToken, nil
+}
+
+// Revoke revokes the family of a refresh token of the user, so none of its tokens can be exchanged anymore.
+// Unknown tokens and tokens of other users are ignored.
+func (service *refreshTokenService) Revoke(ctx context.Context, refreshToken string, userId int) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	current, err := service.RefreshTokenRepository.FindByHash(ctx, tx, utils.HashToken(refreshToken))
+	if err != nil {
+		return nil
+	}
+
+	family, err := service.RefreshTokenRepository.FindFamilyById(ctx, tx, current.FamilyId)
+	if err != nil {
+		return err
+	}
+	if family.UserId != userId || family.RevokedAt != nil {
+		return nil
+	}
+
+	return service.RefreshTokenRepository.RevokeFamily(ctx, tx, family.Id, time.Now().Unix())
+}
+
+func (service *refreshTokenService) create(ctx context.Context, tx *sql.Tx, familyId string) (string, error) {
+	token, err := utils.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	_, err = service.RefreshTokenRepository.Create(ctx, tx, entity.RefreshTokens{
+		FamilyId:  familyId,
+		TokenHash: utils.HashToken(token),
+		ExpiredAt: now.Add(service.Lifetime).Unix(),
+		CreatedAt: now.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
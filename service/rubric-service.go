@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/repository"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var (
+	ErrNoRubric            = errors.New("the module submission has no rubric")
+	ErrRubricInUse         = errors.New("submissions are already scored with the rubric, delete it to start over")
+	ErrInvalidRubricScores = errors.New("score every criterion of the rubric once, with one of its levels")
+)
+
+type RubricService interface {
+	FindRubric(ctx context.Context, code string, moduleSubmissionId int) (model.GetRubricResponse, error)
+	SaveRubric(ctx context.Context, code string, request model.SaveRubricRequest) (model.GetRubricResponse, error)
+	DeleteRubric(ctx context.Context, code string, moduleSubmissionId int) error
+	FindScores(ctx context.Context, code string, moduleSubmissionId int, userSubmissionId int) (model.GetRubricScoresResponse, error)
+	SaveScores(ctx context.Context, code string, moduleSubmissionId int, request model.SaveRubricScoresRequest) (model.GetRubricScoresResponse, error)
+}
+
+type rubricService struct {
+	RubricRepository            repository.RubricRepository
+	UserSubmissionRepository    repository.UserSubmissionsRepository
+	ModuleSubmissionsRepository repository.ModuleSubmissionsRepository
+	CourseRepository            repository.CourseRepository
+	DB                          *sql.DB
+}
+
+func NewRubricService(rubricRepository *repository.RubricRepository, userSubmissionRepository *repository.UserSubmissionsRepository, moduleSubmissionsRepository *repository.ModuleSubmissionsRepository, courseRepository *repository.CourseRepository, db *sql.DB) RubricService {
+	return &rubricService{
+		RubricRepository:            *rubricRepository,
+		UserSubmissionRepository:    *userSubmissionRepository,
+		ModuleSubmissionsRepository: *moduleSubmissionsRepository,
+		CourseRepository:            *courseRepository,
+		DB:                          db,
+	}
+}
+
+func (service *rubricService) FindRubric(ctx context.Context, code string, moduleSubmissionId int) (model.GetRubricResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetRubricResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	err = service.findModuleSubmission(ctx, tx, code, moduleSubmissionId)
+	if err != nil {
+		return model.GetRubricResponse{}, err
+	}
+
+	return service.findRubric(ctx, tx, moduleSubmissionId)
+}
+
+// SaveRubric replaces the criteria of a module submission, as long as no submission was scored with them
+func (service *rubricService) SaveRubric(ctx context.Context, code string, request model.SaveRubricRequest) (model.GetRubricResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetRubricResponse{}, err
+	}
+	// utils.CommitOrRollback commits what was written before an error, the rubric is committed explicitly once every
+	// criterion is written so the old one is kept otherwise
+	defer func(tx *sql.Tx) {
+		_ = tx.Rollback()
+	}(tx)
+
+	err = service.findModuleSubmission(ctx, tx, code, request.ModuleSubmissionId)
+	if err != nil {
+		return model.GetRubricResponse{}, err
+	}
+
+	scored, err := service.RubricRepository.CountScores(ctx, tx, request.ModuleSubmissionId)
+	if err != nil {
+		return model.GetRubricResponse{}, err
+	}
+	if scored > 0 {
+		return model.GetRubricResponse{}, ErrRubricInUse
+	}
+
+	err = service.RubricRepository.Delete(ctx, tx, request.ModuleSubmissionId)
+	if err != nil {
+		return model.GetRubricResponse{}, err
+	}
+
+	for i, criterionRequest := range request.Criteria {
+		criterion, err := service.RubricRepository.CreateCriterion(ctx, tx, entity.RubricCriteria{
+			ModuleSubmissionId: request.ModuleSubmissionId,
+			Name:               criterionRequest.Name,
+			Description:        criterionRequest.Description,
+			Position:           i + 1,
+		})
+		if err != nil {
+			return model.GetRubricResponse{}, err
+		}
+
+		for _, levelRequest := range criterionRequest.Levels {
+			_, err = service.RubricRepository.CreateLevel(ctx, tx, entity.RubricLevels{
+				CriterionId: criterion.Id,
+				Name:        levelRequest.Name,
+				Description: levelRequest.Description,
+				Points:      levelRequest.Points,
+			})
+			if err != nil {
+				return model.GetRubricResponse{}, err
+			}
+		}
+	}
+
+	response, err := service.findRubric(ctx, tx, request.ModuleSubmissionId)
+	if err != nil {
+		return model.GetRubricResponse{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return model.GetRubricResponse{}, err
+	}
+
+	return response, nil
+}
+
+// DeleteRubric removes the rubric with the scores given with it, the grades stay
+func (service *rubricService) DeleteRubric(ctx context.Context, code string, moduleSubmissionId int) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	err = service.findModuleSubmission(ctx, tx, code, moduleSubmissionId)
+	if err != nil {
+		return err
+	}
+
+	err = service.RubricRepository.Delete(ctx, tx, moduleSubmissionId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (service *rubricService) FindScores(ctx context.Context, code string, moduleSubmissionId int, userSubmissionId int) (model.GetRubricScoresResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetRubricScoresResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	userSubmission, err := service.findUserSubmission(ctx, tx, code, moduleSubmissionId, userSubmissionId)
+	if err != nil {
+		return model.GetRubricScoresResponse{}, err
+	}
+
+	scores, err := service.RubricRepository.FindScores(ctx, tx, userSubmission.Id)
+	if err != nil {
+		return model.GetRubricScoresResponse{}, err
+	}
+
+	return utils.ToRubricScoresResponse(userSubmission, userSubmission.Feedback, scores), nil
+}
+
+// SaveScores grades a user submission with the rubric, the grade is the sum of the points of the picked levels
+func (service *rubricService) SaveScores(ctx context.Context, code string, moduleSubmissionId int, request model.SaveRubricScoresRequest) (model.GetRubricScoresResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetRubricScoresResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	userSubmission, err := service.findUserSubmission(ctx, tx, code, moduleSubmissionId, request.UserSubmissionId)
+	if err != nil {
+		return model.GetRubricScoresResponse{}, err
+	}
+
+	criteria, err := service.RubricRepository.FindCriteria(ctx, tx, moduleSubmissionId)
+	if err != nil {
+		return model.GetRubricScoresResponse{}, err
+	}
+	if len(criteria) == 0 {
+		return model.GetRubricScoresResponse{}, ErrNoRubric
+	}
+	levels, err := service.RubricRepository.FindLevels(ctx, tx, moduleSubmissionId)
+	if err != nil {
+		return model.GetRubricScoresResponse{}, err
+	}
+
+	scores, err := rubricScores(userSubmission.Id, criteria, levels, request.Scores)
+	if err != nil {
+		return model.GetRubricScoresResponse{}, err
+	}
+
+	err = service.RubricRepository.DeleteScores(ctx, tx, userSubmission.Id)
+	if err != nil {
+		return model.GetRubricScoresResponse{}, err
+	}
+
+	total := 0
+	for _, score := range scores {
+		err = service.RubricRepository.SaveScore(ctx, tx, score)
+		if err != nil {
+			return model.GetRubricScoresResponse{}, err
+		}
+		total += score.Points
+	}
+
+	err = service.UserSubmissionRepository.UpdateGrade(ctx, tx, entity.UserSubmissions{
+		Id:       userSubmission.Id,
+		Grade:    &total,
+		Feedback: request.Feedback,
+	})
+	if err != nil {
+		return model.GetRubricScoresResponse{}, err
+	}
+
+	criterionScores, err := service.RubricRepository.FindScores(ctx, tx, userSubmission.Id)
+	if err != nil {
+		return model.GetRubricScoresResponse{}, err
+	}
+
+	return utils.ToRubricScoresResponse(userSubmission, request.Feedback, criterionScores), nil
+}
+
+// rubricScores checks that every criterion is scored exactly once with one of its own levels
+func rubricScores(userSubmissionId int, criteria []entity.RubricCriteria, levels []entity.RubricLevels, requests []model.SaveCriterionScoreRequest) ([]entity.RubricScores, error) {
+	if len(requests) != len(criteria) {
+		return nil, ErrInvalidRubricScores
+	}
+
+	var scores []entity.RubricScores
+	scored := map[int]bool{}
+	for _, request := range requests {
+		if scored[request.CriterionId] {
+			return nil, ErrInvalidRubricScores
+		}
+		scored[request.CriterionId] = true
+
+		found := false
+		for _, level := range levels {
+			if level.Id == request.LevelId && level.CriterionId == request.CriterionId {
+				scores = append(scores, entity.RubricScores{
+					UserSubmissionId: userSubmissionId,
+					CriterionId:      request.CriterionId,
+					LevelId:          level.Id,
+					Points:           level.Points,
+					Comment:          request.Comment,
+				})
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, ErrInvalidRubricScores
+		}
+	}
+
+	return scores, nil
+}
+
+func (service *rubricService) findRubric(ctx context.Context, tx *sql.Tx, moduleSubmissionId int) (model.GetRubricResponse, error) {
+	criteria, err := service.RubricRepository.FindCriteria(ctx, tx, moduleSubmissionId)
+	if err != nil {
+		return model.GetRubricResponse{}, err
+	}
+
+	levels, err := service.RubricRepository.FindLevels(ctx, tx, moduleSubmissionId)
+	if err != nil {
+		return model.GetRubricResponse{}, err
+	}
+
+	return utils.ToRubricResponse(moduleSubmissionId, criteria, levels), nil
+}
+
+func (service *rubricService) findModuleSubmission(ctx context.Context, tx *sql.Tx, code string, moduleSubmissionId int) error {
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.ModuleSubmissionsRepository.FindByModId(ctx, tx, course.Id, moduleSubmissionId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (service *rubricService) findUserSubmission(ctx context.Context, tx *sql.Tx, code string, moduleSubmissionId int, id int) (entity.UserSubmissions, error) {
+	err := service.findModuleSubmission(ctx, tx, code, moduleSubmissionId)
+	if err != nil {
+		return entity.UserSubmissions{}, err
+	}
+
+	userSubmission, err := service.UserSubmissionRepository.FindUserSubmissionById(ctx, tx, id)
+	if err != nil {
+		return entity.UserSubmissions{}, err
+	}
+	if userSubmission.ModuleSubmissionId != moduleSubmissionId {
+		return entity.UserSubmissions{}, errors.New("user submission not found")
+	}
+
+	return userSubmission, nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/repository"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var (
+	ErrEmptySearch       = errors.New("search query must not be empty")
+	ErrSearchUnavailable = errors.New("search is not available, the SQLite driver must be built with -tags sqlite_fts5")
+)
+
+type SearchService interface {
+	Search(ctx context.Context, principal model.Principal, text string, query model.ListQuery) ([]model.SearchResultResponse, model.ListPage, error)
+}
+
+type searchService struct {
+	SearchRepository repository.SearchRepository
+	DB               *sql.DB
+}
+
+func NewSearchService(searchRepository *repository.SearchRepository, db *sql.DB) SearchService {
+	return &searchService{
+		SearchRepository: *searchRepository,
+		DB:               db,
+	}
+}
+
+// Search finds the courses, articles, questions and answers matching text.
+// Admins see everything, other users only see articles and Q&A of the courses they are enrolled in or teach.
+func (service *searchService) Search(ctx context.Context, principal model.Principal, text string, query model.ListQuery) ([]model.SearchResultResponse, model.ListPage, error) {
+	if strings.TrimSpace(text) == "" {
+		return []model.SearchResultResponse{}, model.ListPage{}, ErrEmptySearch
+	}
+	if !service.SearchRepository.Available() {
+		return []model.SearchResultResponse{}, model.ListPage{}, ErrSearchUnavailable
+	}
+
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return []model.SearchResultResponse{}, model.ListPage{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	visibleTo := principal.Id
+	if principal.Role == entity.RoleAdmin {
+		visibleTo = 0
+	}
+
+	results, total, err := service.SearchRepository.Search(ctx, tx, text, visibleTo, query)
+	if err != nil {
+		return []model.SearchResultResponse{}, model.ListPage{}, err
+	}
+
+	var searchResponses []model.SearchResultResponse
+	for _, result := range results {
+		searchResponses = append(searchResponses, utils.ToSearchResultResponse(result))
+	}
+
+	return searchResponses, utils.ToListPage(query, len(results), total), nil
+}
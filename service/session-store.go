@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/repository"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+// SessionStore keeps track of every access token that is still allowed to be used.
+// A token is only accepted by CheckToken while its session exists in the store.
+type SessionStore interface {
+	Save(ctx context.Context, session entity.Sessions) error
+	Find(ctx context.Context, id string) (entity.Sessions, error)
+	Delete(ctx context.Context, id string) error
+	DeleteByUser(ctx context.Context, userId int) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type sqlSessionStore struct {
+	SessionRepository repository.SessionRepository
+	DB                *sql.DB
+}
+
+func NewSQLSessionStore(sessionRepository *repository.SessionRepository, db *sql.DB) SessionStore {
+	return &sqlSessionStore{
+		SessionRepository: *sessionRepository,
+		DB:                db,
+	}
+}
+
+func (store *sqlSessionStore) Save(ctx context.Context, session entity.Sessions) error {
+	tx, err := store.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	_, err = store.SessionRepository.Create(ctx, tx, session)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (store *sqlSessionStore) Find(ctx context.Context, id string) (entity.Sessions, error) {
+	tx, err := store.DB.Begin()
+	if err != nil {
+		return entity.Sessions{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	session, err := store.SessionRepository.FindById(ctx, tx, id)
+	if err != nil {
+		return entity.Sessions{}, err
+	}
+
+	return session, nil
+}
+
+func (store *sqlSessionStore) Delete(ctx context.Context, id string) error {
+	tx, err := store.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	err = store.SessionRepository.Delete(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (store *sqlSessionStore) DeleteByUser(ctx context.Context, userId int) error {
+	tx, err := store.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	return store.SessionRepository.DeleteByUser(ctx, tx, userId)
+}
+
+func (store *sqlSessionStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	tx, err := store.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	return store.SessionRepository.DeleteExpired(ctx, tx, now.Unix())
+}
+
+var (
+	sessionPurgerMutex sync.Mutex
+	stopSessionPurger  context.CancelFunc
+)
+
+// StartSessionPurger removes expired sessions from the store every interval until ctx is done. A process runs one
+// purger, starting a purger stops the one started before.
+func StartSessionPurger(ctx context.Context, store SessionStore, interval time.Duration) {
+	sessionPurgerMutex.Lock()
+	defer sessionPurgerMutex.Unlock()
+
+	if stopSessionPurger != nil {
+		stopSessionPurger()
+	}
+	ctx, stopSessionPurger = context.WithCancel(ctx)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				_, err := store.DeleteExpired(ctx, now)
+				if err != nil {
+					log.Println("cannot purge expired sessions ", err)
+				}
+			}
+		}
+	}()
+}
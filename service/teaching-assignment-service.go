@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/repository"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var ErrNotTeacher = errors.New("user is not a teacher")
+
+type TeachingAssignmentService interface {
+	Assign(ctx context.Context, codeCourse string, request model.CreateTeachingAssignmentRequest) (model.GetCourseTeacherResponse, error)
+	Unassign(ctx context.Context, codeCourse string, userId int) error
+	FindAllTeacherByCourse(ctx context.Context, codeCourse string) ([]model.GetCourseTeacherResponse, error)
+	FindAllCourseByTeacher(ctx context.Context, userId int) ([]model.GetStudentCourseResponse, error)
+	Teaches(ctx context.Context, userId int, codeCourse string) (bool, error)
+}
+
+type teachingAssignmentService struct {
+	TeachingAssignmentRepository repository.TeachingAssignmentRepository
+	CourseRepository             repository.CourseRepository
+	UserRepository               repository.UserRepository
+	DB                           *sql.DB
+}
+
+func NewTeachingAssignmentService(teachingAssignmentRepository *repository.TeachingAssignmentRepository, courseRepository *repository.CourseRepository, userRepository *repository.UserRepository, db *sql.DB) TeachingAssignmentService {
+	return &teachingAssignmentService{
+		TeachingAssignmentRepository: *teachingAssignmentRepository,
+		CourseRepository:             *courseRepository,
+		UserRepository:               *userRepository,
+		DB:                           db,
+	}
+}
+
+// Assign makes the teacher identified by request.UserId responsible for the course
+func (service *teachingAssignmentService) Assign(ctx context.Context, codeCourse string, request model.CreateTeachingAssignmentRequest) (model.GetCourseTeacherResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetCourseTeacherResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, codeCourse)
+	if err != nil {
+		return model.GetCourseTeacherResponse{}, err
+	}
+
+	user, err := service.UserRepository.GetUserByID(ctx, tx, request.UserId)
+	if err != nil {
+		return model.GetCourseTeacherResponse{}, err
+	}
+	if user.Id == 0 {
+		return model.GetCourseTeacherResponse{}, errors.New("user not found")
+	}
+	if user.Role != entity.RoleTeacher {
+		return model.GetCourseTeacherResponse{}, ErrNotTeacher
+	}
+
+	exists, err := service.TeachingAssignmentRepository.Exists(ctx, tx, user.Id, course.Id)
+	if err != nil {
+		return model.GetCourseTeacherResponse{}, err
+	}
+	if !exists {
+		_, err = service.TeachingAssignmentRepository.Create(ctx, tx, entity.TeachingAssignments{
+			UserId:    user.Id,
+			CourseId:  course.Id,
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			return model.GetCourseTeacherResponse{}, err
+		}
+	}
+
+	return utils.ToCourseTeacherResponse(entity.CourseTeachers{
+		IdUser:       user.Id,
+		UserName:     user.Name,
+		UserUsername: user.Username,
+		UserEmail:    user.Email,
+	}), nil
+}
+
+func (service *teachingAssignmentService) Unassign(ctx context.Context, codeCourse string, userId int) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, codeCourse)
+	if err != nil {
+		return err
+	}
+
+	return service.TeachingAssignmentRepository.Delete(ctx, tx, userId, course.Id)
+}
+
+func (service *teachingAssignmentService) FindAllTeacherByCourse(ctx context.Context, codeCourse string) ([]model.GetCourseTeacherResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return []model.GetCourseTeacherResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, codeCourse)
+	if err != nil {
+		return []model.GetCourseTeacherResponse{}, err
+	}
+
+	teachers, err := service.TeachingAssignmentRepository.FindAllTeacherByCourseId(ctx, tx, course.Id)
+	if err != nil {
+		return []model.GetCourseTeacherResponse{}, err
+	}
+
+	var teacherResponses []model.GetCourseTeacherResponse
+	for _, teacher := range teachers {
+		teacherResponses = append(teacherResponses, utils.ToCourseTeacherResponse(teacher))
+	}
+
+	return teacherResponses, nil
+}
+
+func (service *teachingAssignmentService) FindAllCourseByTeacher(ctx context.Context, userId int) ([]model.GetStudentCourseResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return []model.GetStudentCourseResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	courses, err := service.TeachingAssignmentRepository.FindAllCourseByUserId(ctx, tx, userId)
+	if err != nil {
+		return []model.GetStudentCourseResponse{}, err
+	}
+
+	var courseResponses []model.GetStudentCourseResponse
+	for _, course := range courses {
+		courseResponses = append(courseResponses, utils.ToStudentCourseResponse(course))
+	}
+
+	return courseResponses, nil
+}
+
+// Teaches reports whether the user is assigned to teach the course
+func (service *teachingAssignmentService) Teaches(ctx context.Context, userId int, codeCourse string) (bool, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	course, err := service.CourseRepository.FindByCode(ctx, tx, codeCourse)
+	if err != nil {
+		return false, nil
+	}
+
+	return service.TeachingAssignmentRepository.Exists(ctx, tx, userId, course.Id)
+}
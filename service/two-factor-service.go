@@ -0,0 +1,530 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/repository"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+const (
+	// challengeLifetime is how long the second step of a login can be completed
+	challengeLifetime = 5 * time.Minute
+	// challengeAttempts is how many wrong codes a challenge accepts before the login has to start over
+	challengeAttempts = 5
+	recoveryCodeCount = 10
+)
+
+var (
+	ErrTwoFactorEnabled          = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled      = errors.New("two-factor authentication has not been set up")
+	ErrTwoFactorCode             = errors.New("the two-factor code is incorrect")
+	ErrTwoFactorRequired         = errors.New("two-factor authentication is required for the role")
+	ErrTwoFactorChallengeInvalid = errors.New("the two-factor challenge is invalid or expired")
+	ErrTwoFactorRole             = errors.New("the role does not exist")
+)
+
+type TwoFactorService interface {
+	Status(ctx context.Context, userId int, role int) (model.TwoFactorStatusResponse, error)
+	// Enroll generates a new secret for the user, two-factor authentication is enabled once Confirm accepts a code of it
+	Enroll(ctx context.Context, userId int) (model.TwoFactorEnrollResponse, error)
+	// Confirm enables two-factor authentication and returns the recovery codes, which are only shown this one time.
+	// The user is logged out everywhere, so every session from now on went through the second step.
+	Confirm(ctx context.Context, userId int, code string) (model.RecoveryCodesResponse, error)
+	// Disable turns two-factor authentication off with the password and a code or a recovery code, unless the role
+	// requires it
+	Disable(ctx context.Context, userId int, role int, request model.DisableTwoFactorRequest) error
+	// Challenge starts the second step of the login when the user enabled two-factor authentication or the role
+	// requires it, it reports false when the user can be logged in right away
+	Challenge(ctx context.Context, userId int, role int) (model.TwoFactorChallengeResponse, bool, error)
+	// EnrollChallenge generates a secret for a user who has to set up two-factor authentication to log in
+	EnrollChallenge(ctx context.Context, token string) (model.TwoFactorEnrollResponse, error)
+	// VerifyChallenge completes the login with a code or a recovery code
+	VerifyChallenge(ctx context.Context, request model.VerifyTwoFactorChallengeRequest) (model.TwoFactorLoginResponse, error)
+	ListPolicies(ctx context.Context) ([]model.TwoFactorPolicyResponse, error)
+	// SavePolicy sets whether the role requires two-factor authentication. Requiring it logs out the users of the role
+	// who have not set it up, so they can not keep using the sessions they logged in to without it.
+	SavePolicy(ctx context.Context, role int, required bool) (model.TwoFactorPolicyResponse, error)
+}
+
+type twoFactorService struct {
+	TwoFactorRepository    repository.TwoFactorRepository
+	UserRepository         repository.UserRepository
+	RefreshTokenRepository repository.RefreshTokenRepository
+	SessionStore           SessionStore
+	Issuer                 string
+	DB                     *sql.DB
+}
+
+// NewTwoFactorService names the accounts in authenticator apps after issuer
+func NewTwoFactorService(twoFactorRepository *repository.TwoFactorRepository, userRepository *repository.UserRepository, refreshTokenRepository *repository.RefreshTokenRepository, sessionStore SessionStore, issuer string, db *sql.DB) TwoFactorService {
+	return &twoFactorService{
+		TwoFactorRepository:    *twoFactorRepository,
+		UserRepository:         *userRepository,
+		RefreshTokenRepository: *refreshTokenRepository,
+		SessionStore:           sessionStore,
+		Issuer:                 issuer,
+		DB:                     db,
+	}
+}
+
+func (service *twoFactorService) Status(ctx context.Context, userId int, role int) (model.TwoFactorStatusResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.TwoFactorStatusResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	required, err := service.required(ctx, tx, role)
+	if err != nil {
+		return model.TwoFactorStatusResponse{}, err
+	}
+
+	response := model.TwoFactorStatusResponse{Required: required}
+
+	twoFactor, err := service.TwoFactorRepository.FindByUser(ctx, tx, userId)
+	if err != nil || twoFactor.ConfirmedAt == nil {
+		return response, nil
+	}
+
+	response.Enabled = true
+	response.RecoveryCodesLeft, err = service.TwoFactorRepository.CountRecoveryCodes(ctx, tx, userId)
+	if err != nil {
+		return model.TwoFactorStatusResponse{}, err
+	}
+
+	return response, nil
+}
+
+func (service *twoFactorService) Enroll(ctx context.Context, userId int) (model.TwoFactorEnrollResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.TwoFactorEnrollResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	return service.enroll(ctx, tx, userId)
+}
+
+func (service *twoFactorService) Confirm(ctx context.Context, userId int, code string) (model.RecoveryCodesResponse, error) {
+	codes, err := service.confirmEnrollment(ctx, userId, code)
+	if err != nil {
+		return model.RecoveryCodesResponse{}, err
+	}
+
+	// The sessions are kept by the session store, which works in its own transaction
+	err = service.SessionStore.DeleteByUser(ctx, userId)
+	if err != nil {
+		return model.RecoveryCodesResponse{}, err
+	}
+
+	return model.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// confirmEnrollment enables two-factor authentication and revokes the refresh tokens of the user, which were issued
+// without it. It returns the recovery codes.
+func (service *twoFactorService) confirmEnrollment(ctx context.Context, userId int, code string) ([]string, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	twoFactor, err := service.TwoFactorRepository.FindByUser(ctx, tx, userId)
+	if err != nil {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	if twoFactor.ConfirmedAt != nil {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	now := time.Now()
+	codes, err := service.confirm(ctx, tx, twoFactor, code, now)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.RefreshTokenRepository.RevokeFamiliesByUser(ctx, tx, userId, now.Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (service *twoFactorService) Disable(ctx context.Context, userId int, role int, request model.DisableTwoFactorRequest) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	err = service.UserRepository.CheckPassword(ctx, tx, userId, request.Password)
+	if err != nil {
+		return ErrPasswordIncorrect
+	}
+
+	required, err := service.required(ctx, tx, role)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrTwoFactorRequired
+	}
+
+	twoFactor, err := service.TwoFactorRepository.FindByUser(ctx, tx, userId)
+	if err != nil || twoFactor.ConfirmedAt == nil {
+		return ErrTwoFactorNotEnrolled
+	}
+
+	valid, err := service.verify(ctx, tx, twoFactor, request.Code, time.Now())
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return ErrTwoFactorCode
+	}
+
+	return service.TwoFactorRepository.Delete(ctx, tx, userId)
+}
+
+func (service *twoFactorService) Challenge(ctx context.Context, userId int, role int) (model.TwoFactorChallengeResponse, bool, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.TwoFactorChallengeResponse{}, false, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	required, err := service.required(ctx, tx, role)
+	if err != nil {
+		return model.TwoFactorChallengeResponse{}, false, err
+	}
+
+	twoFactor, err := service.TwoFactorRepository.FindByUser(ctx, tx, userId)
+	enabled := err == nil && twoFactor.ConfirmedAt != nil
+	if !enabled && !required {
+		return model.TwoFactorChallengeResponse{}, false, nil
+	}
+
+	token, err := utils.RandomToken(32)
+	if err != nil {
+		return model.TwoFactorChallengeResponse{}, false, err
+	}
+
+	// Only the hash is stored, the token itself is only in the response
+	now := time.Now()
+	_, err = service.TwoFactorRepository.CreateChallenge(ctx, tx, entity.TwoFactorChallenges{
+		UserId:    userId,
+		TokenHash: utils.HashToken(token),
+		ExpiredAt: now.Add(challengeLifetime).Unix(),
+		CreatedAt: now.Unix(),
+	})
+	if err != nil {
+		return model.TwoFactorChallengeResponse{}, false, err
+	}
+
+	return model.TwoFactorChallengeResponse{
+		ChallengeToken: token,
+		ExpiresIn:      int(challengeLifetime.Seconds()),
+		SetupRequired:  !enabled,
+	}, true, nil
+}
+
+func (service *twoFactorService) EnrollChallenge(ctx context.Context, token string) (model.TwoFactorEnrollResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.TwoFactorEnrollResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	challenge, err := service.challenge(ctx, tx, token, time.Now())
+	if err != nil {
+		return model.TwoFactorEnrollResponse{}, err
+	}
+
+	return service.enroll(ctx, tx, challenge.UserId)
+}
+
+func (service *twoFactorService) VerifyChallenge(ctx context.Context, request model.VerifyTwoFactorChallengeRequest) (model.TwoFactorLoginResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.TwoFactorLoginResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	now := time.Now()
+
+	challenge, err := service.challenge(ctx, tx, request.ChallengeToken, now)
+	if err != nil {
+		return model.TwoFactorLoginResponse{}, err
+	}
+
+	twoFactor, err := service.TwoFactorRepository.FindByUser(ctx, tx, challenge.UserId)
+	if err != nil {
+		return model.TwoFactorLoginResponse{}, ErrTwoFactorNotEnrolled
+	}
+
+	// A user who had to set up two-factor authentication to log in confirms it with the first code
+	var codes []string
+	if twoFactor.ConfirmedAt == nil {
+		codes, err = service.confirm(ctx, tx, twoFactor, request.Code, now)
+	} else {
+		var valid bool
+		valid, err = service.verify(ctx, tx, twoFactor, request.Code, now)
+		if err == nil && !valid {
+			err = ErrTwoFactorCode
+		}
+	}
+	if err == ErrTwoFactorCode {
+		// The transaction is committed on errors as well, so the attempt is counted
+		errAttempt := service.TwoFactorRepository.AddChallengeAttempt(ctx, tx, challenge.Id)
+		if errAttempt != nil {
+			return model.TwoFactorLoginResponse{}, errAttempt
+		}
+	}
+	if err != nil {
+		return model.TwoFactorLoginResponse{}, err
+	}
+
+	unused, err := service.TwoFactorRepository.UseChallenge(ctx, tx, challenge.Id, now.Unix())
+	if err != nil {
+		return model.TwoFactorLoginResponse{}, err
+	}
+	if !unused {
+		return model.TwoFactorLoginResponse{}, ErrTwoFactorChallengeInvalid
+	}
+
+	user, err := service.UserRepository.GetUserByID(ctx, tx, challenge.UserId)
+	if err != nil {
+		return model.TwoFactorLoginResponse{}, err
+	}
+
+	return model.TwoFactorLoginResponse{
+		UserLoginResponse: model.UserLoginResponse{
+			Id:             user.Id,
+			Name:           user.Name,
+			Username:       user.Username,
+			Email:          user.Email,
+			Role:           user.Role,
+			Gender:         user.Gender,
+			DisabilityType: user.DisabilityType,
+		},
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// ListPolicies returns the policy of every role, roles that never had one do not require two-factor authentication
+func (service *twoFactorService) ListPolicies(ctx context.Context) ([]model.TwoFactorPolicyResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	policies, err := service.TwoFactorRepository.FindPolicies(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	required := map[int]bool{}
+	for _, policy := range policies {
+		required[policy.Role] = policy.Required
+	}
+
+	var response []model.TwoFactorPolicyResponse
+	for _, role := range []int{entity.RoleAdmin, entity.RoleStudent, entity.RoleTeacher} {
+		response = append(response, model.TwoFactorPolicyResponse{
+			Role:     role,
+			Required: required[role],
+		})
+	}
+
+	return response, nil
+}
+
+func (service *twoFactorService) SavePolicy(ctx context.Context, role int, required bool) (model.TwoFactorPolicyResponse, error) {
+	if role != entity.RoleAdmin && role != entity.RoleStudent && role != entity.RoleTeacher {
+		return model.TwoFactorPolicyResponse{}, ErrTwoFactorRole
+	}
+
+	userIds, err := service.savePolicy(ctx, role, required)
+	if err != nil {
+		return model.TwoFactorPolicyResponse{}, err
+	}
+
+	// The sessions are kept by the session store, which works in its own transaction
+	for _, userId := range userIds {
+		err = service.SessionStore.DeleteByUser(ctx, userId)
+		if err != nil {
+			return model.TwoFactorPolicyResponse{}, err
+		}
+	}
+
+	return model.TwoFactorPolicyResponse{Role: role, Required: required}, nil
+}
+
+// savePolicy saves the policy of the role. When the role requires two-factor authentication, it revokes the refresh
+// tokens of the users of the role who have not set it up and returns their ids.
+func (service *twoFactorService) savePolicy(ctx context.Context, role int, required bool) ([]int, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	now := time.Now().Unix()
+	err = service.TwoFactorRepository.SavePolicy(ctx, tx, entity.TwoFactorPolicies{
+		Role:      role,
+		Required:  required,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !required {
+		return nil, nil
+	}
+
+	userIds, err := service.TwoFactorRepository.FindUsersWithout(ctx, tx, role)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, userId := range userIds {
+		err = service.RefreshTokenRepository.RevokeFamiliesByUser(ctx, tx, userId, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return userIds, nil
+}
+
+// enroll replaces a secret that has not been confirmed yet, so a lost QR code can be scanned again
+func (service *twoFactorService) enroll(ctx context.Context, tx *sql.Tx, userId int) (model.TwoFactorEnrollResponse, error) {
+	twoFactor, err := service.TwoFactorRepository.FindByUser(ctx, tx, userId)
+	if err == nil && twoFactor.ConfirmedAt != nil {
+		return model.TwoFactorEnrollResponse{}, ErrTwoFactorEnabled
+	}
+
+	user, err := service.UserRepository.GetUserByID(ctx, tx, userId)
+	if err != nil {
+		return model.TwoFactorEnrollResponse{}, err
+	}
+
+	secret, err := utils.NewTOTPSecret()
+	if err != nil {
+		return model.TwoFactorEnrollResponse{}, err
+	}
+
+	err = service.TwoFactorRepository.Save(ctx, tx, entity.TwoFactor{
+		UserId:    userId,
+		Secret:    secret,
+		CreatedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		return model.TwoFactorEnrollResponse{}, err
+	}
+
+	return model.TwoFactorEnrollResponse{
+		Secret: secret,
+		Uri:    utils.TOTPURI(service.Issuer, user.Email, secret),
+	}, nil
+}
+
+// confirm enables two-factor authentication with a first code of the secret and replaces the recovery codes
+func (service *twoFactorService) confirm(ctx context.Context, tx *sql.Tx, twoFactor entity.TwoFactor, code string, now time.Time) ([]string, error) {
+	step, valid := utils.MatchTOTP(twoFactor.Secret, code, now)
+	if !valid {
+		return nil, ErrTwoFactorCode
+	}
+
+	unused, err := service.TwoFactorRepository.UseStep(ctx, tx, twoFactor.UserId, step)
+	if err != nil {
+		return nil, err
+	}
+	if !unused {
+		return nil, ErrTwoFactorCode
+	}
+
+	err = service.TwoFactorRepository.Confirm(ctx, tx, twoFactor.UserId, now.Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.TwoFactorRepository.ReplaceRecoveryCodes(ctx, tx, twoFactor.UserId, hashes, now.Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// verify accepts a code of the secret that has not been used before or a recovery code that has not been used yet
+func (service *twoFactorService) verify(ctx context.Context, tx *sql.Tx, twoFactor entity.TwoFactor, code string, now time.Time) (bool, error) {
+	if step, valid := utils.MatchTOTP(twoFactor.Secret, code, now); valid {
+		return service.TwoFactorRepository.UseStep(ctx, tx, twoFactor.UserId, step)
+	}
+
+	return service.TwoFactorRepository.UseRecoveryCode(ctx, tx, twoFactor.UserId, utils.HashToken(normalizeRecoveryCode(code)), now.Unix())
+}
+
+// challenge finds the challenge of the token as long as it can still be completed
+func (service *twoFactorService) challenge(ctx context.Context, tx *sql.Tx, token string, now time.Time) (entity.TwoFactorChallenges, error) {
+	challenge, err := service.TwoFactorRepository.FindChallengeByHash(ctx, tx, utils.HashToken(token))
+	if err != nil {
+		return entity.TwoFactorChallenges{}, ErrTwoFactorChallengeInvalid
+	}
+	if challenge.UsedAt != nil || now.Unix() >= challenge.ExpiredAt || challenge.Attempts >= challengeAttempts {
+		return entity.TwoFactorChallenges{}, ErrTwoFactorChallengeInvalid
+	}
+
+	return challenge, nil
+}
+
+func (service *twoFactorService) required(ctx context.Context, tx *sql.Tx, role int) (bool, error) {
+	policies, err := service.TwoFactorRepository.FindPolicies(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, policy := range policies {
+		if policy.Role == role {
+			return policy.Required, nil
+		}
+	}
+
+	return false, nil
+}
+
+// newRecoveryCodes returns the recovery codes as they are shown to the user, e.g. 3f9a1-c07b2, and the hashes they are
+// stored as
+func newRecoveryCodes() ([]string, []string, error) {
+	var codes, hashes []string
+	for i := 0; i < recoveryCodeCount; i++ {
+		token, err := utils.RandomToken(5)
+		if err != nil {
+			return nil, nil, err
+		}
+		codes = append(codes, token[:5]+"-"+token[5:])
+		hashes = append(hashes, utils.HashToken(token))
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode accepts the recovery code with or without the dash and in either case
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,387 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/mail"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/repository"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var (
+	ErrImportFile    = errors.New("the file is not a valid CSV")
+	ErrImportColumns = errors.New("the file must have the name, username, email and password columns")
+	ErrImportEmpty   = errors.New("the file has no users to import")
+	ErrImportInvalid = errors.New("some rows are invalid, no user was imported")
+)
+
+// importVerificationLifetime is how long imported users can verify their email, they did not ask for the email so
+// they get longer than users who register themselves
+const importVerificationLifetime = 7 * 24 * time.Hour
+
+type UserImportService interface {
+	Import(ctx context.Context, file io.Reader, dryRun bool) (model.ImportUsersResponse, error)
+}
+
+type userImportService struct {
+	UserRepository              repository.UserRepository
+	EmailVerificationRepository repository.EmailVerificationRepository
+	CourseRepository            repository.CourseRepository
+	Mailer                      *mailer
+	Enroller                    *enroller
+	DB                          *sql.DB
+}
+
+func NewUserImportService(userRepository *repository.UserRepository, emailVerificationRepository *repository.EmailVerificationRepository, courseRepository *repository.CourseRepository, enrollmentRepository *repository.EnrollmentRepository, userCourseRepository *repository.UserCourseRepository, moduleSubmissionRepository *repository.ModuleSubmissionsRepository, userSubmissionRepository *repository.UserSubmissionsRepository, quizRepository *repository.QuizRepository, userQuizRepository *repository.UserQuizRepository, emailOutboxRepository *repository.EmailOutboxRepository, templates *mail.Templates, verificationUrl string, db *sql.DB) UserImportService {
+	return &userImportService{
+		UserRepository:              *userRepository,
+		EmailVerificationRepository: *emailVerificationRepository,
+		CourseRepository:            *courseRepository,
+		Mailer:                      newMailer(emailOutboxRepository, templates, verificationUrl),
+		Enroller:                    newEnroller(enrollmentRepository, userCourseRepository, moduleSubmissionRepository, userSubmissionRepository, quizRepository, userQuizRepository),
+		DB:                          db,
+	}
+}
+
+// importRow is a row of the file with the line it starts on and the values that could not be read
+type importRow struct {
+	Line   int
+	User   model.ImportUserRow
+	Errors []string
+}
+
+// Import checks every row of the CSV with the rules users are registered with and, unless it is a dry run, registers
+// the users and enrolls them in their courses in one transaction. Users are enrolled whatever the enrollment mode of
+// a course, but only between its enrollment dates, and join the waitlist of the courses that are full. Nothing is
+// imported when a row is invalid, the report then lists the errors of each row. The imported users are sent an email
+// to verify their address in the default language.
+func (service *userImportService) Import(ctx context.Context, file io.Reader, dryRun bool) (model.ImportUsersResponse, error) {
+	rows, err := readImportRows(file)
+	if err != nil {
+		return model.ImportUsersResponse{}, err
+	}
+
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.ImportUsersResponse{}, err
+	}
+	// utils.CommitOrRollback commits what was written before an error, the import is committed explicitly once every
+	// row is written and rolled back otherwise
+	defer func(tx *sql.Tx) {
+		_ = tx.Rollback()
+	}(tx)
+
+	response := model.ImportUsersResponse{
+		DryRun: dryRun,
+		Rows:   len(rows),
+		Errors: []model.ImportUserRowError{},
+		Users:  []model.ImportedUserResponse{},
+	}
+
+	now := time.Now()
+	courses := map[string]entity.Courses{}
+	settings := map[string]entity.EnrollmentSettings{}
+	// seats left in each course as the rows are read, -1 for courses without a capacity
+	seats := map[string]int{}
+	usernames := map[string]int{}
+	emails := map[string]int{}
+	for _, row := range rows {
+		errs := append(row.Errors, importRowErrors(row.User)...)
+
+		// Users of the file must not collide with each other either
+		if line, ok := usernames[row.User.Username]; ok && row.User.Username != "" {
+			errs = append(errs, fmt.Sprintf("username is also used on row %v", line))
+		} else {
+			usernames[row.User.Username] = row.Line
+		}
+		if line, ok := emails[row.User.Email]; ok && row.User.Email != "" {
+			errs = append(errs, fmt.Sprintf("email is also used on row %v", line))
+		} else {
+			emails[row.User.Email] = row.Line
+		}
+
+		err := service.UserRepository.CheckRegistered(ctx, tx, row.User.Username, row.User.Email)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+
+		for _, code := range row.User.Courses {
+			if _, ok := courses[code]; ok {
+				continue
+			}
+			course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("course %v not found", code))
+				continue
+			}
+			courses[code] = course
+
+			settings[code] = service.Enroller.settings(ctx, tx, course.Id)
+			left, waitlist, err := service.Enroller.seats(ctx, tx, settings[code])
+			if err != nil {
+				return model.ImportUsersResponse{}, err
+			}
+			// Nobody gets ahead of the students already waitlisted
+			if len(waitlist) > 0 && left > 0 {
+				left = 0
+			}
+			seats[code] = left
+		}
+		for _, code := range row.User.Courses {
+			if _, ok := courses[code]; ok && !enrollmentDatesOpen(settings[code], now) {
+				errs = append(errs, fmt.Sprintf("course %v is not open for enrollment", code))
+			}
+		}
+
+		if len(errs) > 0 {
+			response.Errors = append(response.Errors, model.ImportUserRowError{Row: row.Line, Errors: errs})
+			continue
+		}
+
+		waitlisted := []string{}
+		for _, code := range row.User.Courses {
+			switch {
+			case seats[code] == 0:
+				waitlisted = append(waitlisted, code)
+			case seats[code] > 0:
+				seats[code]--
+			}
+		}
+
+		response.Users = append(response.Users, model.ImportedUserResponse{
+			Row:        row.Line,
+			Name:       row.User.Name,
+			Username:   row.User.Username,
+			Email:      row.User.Email,
+			Role:       row.User.Role,
+			Courses:    row.User.Courses,
+			Waitlisted: waitlisted,
+		})
+	}
+
+	if len(response.Errors) > 0 {
+		response.Users = []model.ImportedUserResponse{}
+		return response, ErrImportInvalid
+	}
+	if dryRun {
+		return response, nil
+	}
+
+	for i, row := range rows {
+		user, err := service.UserRepository.Register(ctx, tx, entity.Users{
+			Name:           row.User.Name,
+			Username:       row.User.Username,
+			Email:          row.User.Email,
+			Password:       row.User.Password,
+			Role:           row.User.Role,
+			Phone:          row.User.Phone,
+			Gender:         row.User.Gender,
+			DisabilityType: row.User.DisabilityType,
+			Birthdate:      row.User.Birthdate,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		})
+		if err != nil {
+			return model.ImportUsersResponse{}, err
+		}
+		response.Users[i].Id = user.Id
+
+		for _, code := range row.User.Courses {
+			err := service.Enroller.enrollOrWaitlist(ctx, tx, settings[code], user.Id, courses[code].Id, now)
+			if err != nil {
+				return model.ImportUsersResponse{}, err
+			}
+		}
+
+		signature, err := service.verification(ctx, tx, user.Email, now)
+		if err != nil {
+			return model.ImportUsersResponse{}, err
+		}
+		err = service.Mailer.queueVerification(ctx, tx, user.Name, user.Email, signature, "")
+		if err != nil {
+			return model.ImportUsersResponse{}, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return model.ImportUsersResponse{}, err
+	}
+	response.Imported = len(rows)
+
+	return response, nil
+}
+
+// verification creates the signature the user verifies the email with, or replaces the one the email already has
+func (service *userImportService) verification(ctx context.Context, tx *sql.Tx, email string, now time.Time) (string, error) {
+	signature, err := utils.RandomToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	verification := entity.EmailVerification{
+		Email:     email,
+		Signature: signature,
+		Expired:   int(now.Add(importVerificationLifetime).Unix()),
+		SentAt:    int(now.Unix()),
+	}
+
+	existing, err := service.EmailVerificationRepository.FindByEmail(ctx, tx, email)
+	if err != nil {
+		return "", err
+	}
+	if existing.Email == "" {
+		_, err = service.EmailVerificationRepository.Create(ctx, tx, verification)
+	} else {
+		_, err = service.EmailVerificationRepository.Update(ctx, tx, verification)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return signature, nil
+}
+
+// readImportRows reads the rows of the CSV by the names in its header. The role defaults to student and the courses
+// are separated by spaces or semicolons.
+func readImportRows(file io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrImportEmpty
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrImportFile, err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		// Spreadsheets often start the file with a byte order mark
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"name", "username", "email", "password"} {
+		if _, ok := columns[name]; !ok {
+			return nil, ErrImportColumns
+		}
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrImportFile, err)
+		}
+
+		var row importRow
+		row.Line, _ = reader.FieldPos(0)
+
+		value := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return record[i]
+		}
+		number := func(column string, fallback int) int {
+			text := strings.TrimSpace(value(column))
+			if text == "" {
+				return fallback
+			}
+			n, err := strconv.Atoi(text)
+			if err != nil {
+				row.Errors = append(row.Errors, fmt.Sprintf("%v must be a number", column))
+				return fallback
+			}
+			return n
+		}
+
+		row.User = model.ImportUserRow{
+			Name:           strings.TrimSpace(value("name")),
+			Username:       strings.TrimSpace(value("username")),
+			Email:          strings.TrimSpace(value("email")),
+			Password:       value("password"),
+			Role:           number("role", entity.RoleStudent),
+			Phone:          strings.TrimSpace(value("phone")),
+			Gender:         number("gender", 0),
+			DisabilityType: number("type_of_disability", 0),
+			Birthdate:      strings.TrimSpace(value("birthdate")),
+		}
+
+		codes := strings.FieldsFunc(value("courses"), func(r rune) bool {
+			return r == ';' || unicode.IsSpace(r)
+		})
+		seen := map[string]bool{}
+		for _, code := range codes {
+			if !seen[code] {
+				seen[code] = true
+				row.User.Courses = append(row.User.Courses, code)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, ErrImportEmpty
+	}
+
+	return rows, nil
+}
+
+// importRowErrors validates the row with the validator of the request bindings and names the columns that fail
+func importRowErrors(row model.ImportUserRow) []string {
+	err := binding.Validator.ValidateStruct(row)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return []string{err.Error()}
+	}
+
+	var errs []string
+	rowType := reflect.TypeOf(row)
+	for _, fieldError := range fieldErrors {
+		column := fieldError.StructField()
+		if field, ok := rowType.FieldByName(fieldError.StructField()); ok {
+			column = field.Tag.Get("csv")
+		}
+
+		switch fieldError.Tag() {
+		case "required":
+			errs = append(errs, fmt.Sprintf("%v is required", column))
+		case "email":
+			errs = append(errs, fmt.Sprintf("%v is not a valid email", column))
+		case "oneof":
+			errs = append(errs, fmt.Sprintf("%v must be one of %v", column, fieldError.Param()))
+		case "datetime":
+			errs = append(errs, fmt.Sprintf("%v must be a date like %v", column, fieldError.Param()))
+		default:
+			errs = append(errs, fmt.Sprintf("%v fails the %v rule", column, fieldError.Tag()))
+		}
+	}
+
+	return errs
+}
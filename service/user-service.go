@@ -3,20 +3,22 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/mail"
 	"github.com/rg-km/final-project-engineering-12/backend/model"
 	"github.com/rg-km/final-project-engineering-12/backend/repository"
 	"github.com/rg-km/final-project-engineering-12/backend/utils"
 )
 
 type UserService interface {
-	RegisterUser(ctx context.Context, user model.UserRegisterResponse, signature string, expired int) (model.UserRegisterResponse, error)
+	RegisterUser(ctx context.Context, user model.UserRegisterResponse, signature string, expired int, languages string) (model.UserRegisterResponse, error)
 	UserLogin(ctx context.Context, user model.GetUserLogin) (model.UserLoginResponse, error)
 	UpdateUserRole(ctx context.Context, id int, role int) (model.UserDetailResponse, error)
-	ListUser(ctx context.Context) ([]model.UserDetailResponse, error)
+	ListUser(ctx context.Context, query model.ListQuery) ([]model.UserDetailResponse, model.ListPage, error)
 	GetUserbyID(ctx context.Context, id int) (model.UserDetailResponse, error)
 	UpdateUser(ctx context.Context, id int, user model.UserDetailResponse) (model.UserDetailResponse, error)
 	DeleteUser(ctx context.Context, id int) error
@@ -25,19 +27,32 @@ type UserService interface {
 type UserServiceImplement struct {
 	userRepository    repository.UserRepository
 	emailVerification repository.EmailVerificationRepository
+	mailer            *mailer
+	requireVerified   bool
 	DB                *sql.DB
 }
 
-func NewUserService(userRepository *repository.UserRepository, db *sql.DB, emailVerification *repository.EmailVerificationRepository) UserServiceImplement {
+var (
+	ErrEmailNotVerified = errors.New("the email is not verified yet")
+	ErrUserNotFound     = errors.New("user not found")
+	ErrRoleChange       = errors.New("the role can not be changed with a profile update")
+)
+
+// NewUserService links the verification emails to verificationUrl, with requireVerified users can only log in once
+// they verified their email
+func NewUserService(userRepository *repository.UserRepository, db *sql.DB, emailVerification *repository.EmailVerificationRepository, emailOutboxRepository *repository.EmailOutboxRepository, templates *mail.Templates, verificationUrl string, requireVerified bool) UserServiceImplement {
 	return UserServiceImplement{
 		userRepository:    *userRepository,
 		emailVerification: *emailVerification,
+		mailer:            newMailer(emailOutboxRepository, templates, verificationUrl),
+		requireVerified:   requireVerified,
 		DB:                db,
 	}
 }
 
-// RegisterUser is used to register new user
-func (service *UserServiceImplement) RegisterUser(ctx *gin.Context, user model.UserRegisterResponse, signature string, expired int) (model.UserRegisterResponse, error) {
+// RegisterUser is used to register new user, the verification email is sent in the first of languages there is a
+// template in
+func (service *UserServiceImplement) RegisterUser(ctx *gin.Context, user model.UserRegisterResponse, signature string, expired int, languages string) (model.UserRegisterResponse, error) {
 	var response model.UserRegisterResponse
 
 	tx, err := service.DB.Begin()
@@ -49,9 +64,12 @@ func (service *UserServiceImplement) RegisterUser(ctx *gin.Context, user model.U
 
 	user.Created_at = time.Now()
 	user.Updated_at = time.Now()
-	user.EmailVerification = time.Now()
+	// The user is verified by opening the link of the verification email
+	user.EmailVerification = nil
+	// Everyone registers as a student, only admins give other roles
+	user.Role = entity.RoleStudent
 
-	err = service.userRepository.Register(ctx, tx, entity.Users{
+	temp, err := service.userRepository.Register(ctx, tx, entity.Users{
 		Name:              user.Name,
 		Username:          user.Username,
 		Email:             user.Email,
@@ -70,11 +88,6 @@ func (service *UserServiceImplement) RegisterUser(ctx *gin.Context, user model.U
 		return model.UserRegisterResponse{}, err
 	}
 
-	temp, err := service.userRepository.GetLastInsertUser(ctx, tx)
-	if err != nil {
-		return model.UserRegisterResponse{}, err
-	}
-
 	response = model.UserRegisterResponse{
 		Id:                temp.Id,
 		Name:              temp.Name,
@@ -102,6 +115,7 @@ func (service *UserServiceImplement) RegisterUser(ctx *gin.Context, user model.U
 		Email:     user.Email,
 		Signature: signature,
 		Expired:   expired,
+		SentAt:    int(user.Created_at.Unix()),
 	}
 	if rows.Email == "" {
 		_, err = service.emailVerification.Create(ctx, tx, emailVerification)
@@ -116,6 +130,11 @@ func (service *UserServiceImplement) RegisterUser(ctx *gin.Context, user model.U
 		}
 	}
 
+	err = service.mailer.queueVerification(ctx, tx, user.Name, user.Email, signature, languages)
+	if err != nil {
+		return model.UserRegisterResponse{}, err
+	}
+
 	return response, nil
 }
 
@@ -136,6 +155,10 @@ func (service *UserServiceImplement) UserLogin(ctx *gin.Context, data model.GetU
 		return model.UserLoginResponse{}, err
 	}
 
+	if service.requireVerified && user.EmailVerification == nil {
+		return model.UserLoginResponse{}, ErrEmailNotVerified
+	}
+
 	response = model.UserLoginResponse{
 		Id:             user.Id,
 		Name:           user.Name,
@@ -227,13 +250,26 @@ func (service *UserServiceImplement) UpdateUser(ctx *gin.Context, id int, user m
 	}
 	defer utils.CommitOrRollback(tx)
 
+	current, err := service.userRepository.GetUserByID(ctx, tx, id)
+	if err != nil {
+		return model.UserDetailResponse{}, err
+	}
+	if current.Id == 0 {
+		return model.UserDetailResponse{}, ErrUserNotFound
+	}
+
+	// Roles are only changed by admins through UpdateUserRole, sending the current role is still accepted
+	if user.Role != 0 && user.Role != current.Role {
+		return model.UserDetailResponse{}, ErrRoleChange
+	}
+	user.Role = current.Role
+
 	user.UpdateAt = time.Now()
 
 	err = service.userRepository.Update(ctx, tx, entity.Users{
 		Id:             id,
 		Name:           user.Name,
 		Username:       user.Username,
-		Role:           user.Role,
 		Phone:          user.Phone,
 		Gender:         user.Gender,
 		DisabilityType: user.DisabilityType,
@@ -266,20 +302,20 @@ func (service *UserServiceImplement) UpdateUser(ctx *gin.Context, id int, user m
 }
 
 // ListUser is used to list all user
-func (service *UserServiceImplement) ListUser(ctx *gin.Context) ([]model.UserDetailResponse, error) {
+func (service *UserServiceImplement) ListUser(ctx *gin.Context, query model.ListQuery) ([]model.UserDetailResponse, model.ListPage, error) {
 	var responses = []model.UserDetailResponse{}
 
 	tx, err := service.DB.Begin()
 
 	if err != nil {
-		return []model.UserDetailResponse{}, err
+		return []model.UserDetailResponse{}, model.ListPage{}, err
 	}
 	defer utils.CommitOrRollback(tx)
 
-	users, err := service.userRepository.ListUser(ctx, tx)
+	users, total, err := service.userRepository.ListUser(ctx, tx, query)
 
 	if err != nil {
-		return nil, err
+		return nil, model.ListPage{}, err
 	}
 
 	for _, user := range users {
@@ -298,7 +334,7 @@ func (service *UserServiceImplement) ListUser(ctx *gin.Context) ([]model.UserDet
 		})
 	}
 
-	return responses, nil
+	return responses, utils.ToListPage(query, len(users), total), nil
 }
 
 // DeleteUser is used to delete user
@@ -3,33 +3,57 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"io"
+	"mime"
+	"path/filepath"
+	"strings"
+	"time"
+
 	"github.com/rg-km/final-project-engineering-12/backend/entity"
 	"github.com/rg-km/final-project-engineering-12/backend/model"
 	"github.com/rg-km/final-project-engineering-12/backend/repository"
+	"github.com/rg-km/final-project-engineering-12/backend/storage"
 	"github.com/rg-km/final-project-engineering-12/backend/utils"
-	"os"
 )
 
+var ErrNoFile = errors.New("no file")
+
 type UserSubmissionsService interface {
-	SubmitFile(ctx context.Context, request model.CreateUserSubmissionsRequest) (model.GetUserSubmissionsResponse, error)
-	UpdateGrade(ctx context.Context, request model.UpdateUserGradeRequest) error
+	SubmitFile(ctx context.Context, request model.CreateUserSubmissionsRequest, file model.UploadFile) (model.GetUserSubmissionsResponse, error)
+	UpdateGrade(ctx context.Context, code string, moduleSubmissionId int, request model.UpdateUserGradeRequest) error
 	FindUserSubmissionByOther(ctx context.Context, userId int, moduleSubmissionsId int) (model.GetUserSubmissionsResponse, error)
-	FindUserSubmissionById(ctx context.Context, id int) (model.GetUserSubmissionsResponse, error)
+	FindUserSubmissionById(ctx context.Context, code string, moduleSubmissionId int, id int) (model.GetUserSubmissionsResponse, error)
+	OpenFile(ctx context.Context, code string, moduleSubmissionId int, id int) (io.ReadCloser, string, error)
+	FileURL(ctx context.Context, code string, moduleSubmissionId int, id int) (model.GetFileURLResponse, error)
+	FindAllAttempts(ctx context.Context, code string, moduleSubmissionId int, id int) ([]model.GetSubmissionAttemptResponse, error)
+	OpenAttempt(ctx context.Context, code string, moduleSubmissionId int, id int, attemptId int) (io.ReadCloser, string, error)
+	SelectGradedAttempt(ctx context.Context, code string, moduleSubmissionId int, request model.SelectGradedAttemptRequest) (model.GetUserSubmissionsResponse, error)
 }
 
 type userSubmissionsService struct {
 	UserSubmissionRepository    repository.UserSubmissionsRepository
+	SubmissionAttemptRepository repository.SubmissionAttemptRepository
 	ModuleSubmissionsRepository repository.ModuleSubmissionsRepository
+	ExtensionRepository         repository.SubmissionExtensionRepository
 	CourseRepository            repository.CourseRepository
 	DB                          *sql.DB
+	Store                       storage.BlobStore
+	URLLifetime                 time.Duration
+	Limits                      storage.UploadLimits
 }
 
-func NewUserSubmissionsService(userSubmissionRepository *repository.UserSubmissionsRepository, moduleSubmissionsRepository *repository.ModuleSubmissionsRepository, courseRepository *repository.CourseRepository, db *sql.DB) UserSubmissionsService {
+func NewUserSubmissionsService(userSubmissionRepository *repository.UserSubmissionsRepository, submissionAttemptRepository *repository.SubmissionAttemptRepository, moduleSubmissionsRepository *repository.ModuleSubmissionsRepository, extensionRepository *repository.SubmissionExtensionRepository, courseRepository *repository.CourseRepository, db *sql.DB, store storage.BlobStore, urlLifetime time.Duration, limits storage.UploadLimits) UserSubmissionsService {
 	return &userSubmissionsService{
 		UserSubmissionRepository:    *userSubmissionRepository,
+		SubmissionAttemptRepository: *submissionAttemptRepository,
 		ModuleSubmissionsRepository: *moduleSubmissionsRepository,
+		ExtensionRepository:         *extensionRepository,
 		CourseRepository:            *courseRepository,
 		DB:                          db,
+		Store:                       store,
+		URLLifetime:                 urlLifetime,
+		Limits:                      limits,
 	}
 }
 
@@ -52,14 +76,14 @@ func (service *userSubmissionsService) FindUserSubmissionByOther(ctx context.Con
 	return utils.ToUserSubmissionsResponse(userSubmission), nil
 }
 
-func (service *userSubmissionsService) FindUserSubmissionById(ctx context.Context, id int) (model.GetUserSubmissionsResponse, error) {
+func (service *userSubmissionsService) FindUserSubmissionById(ctx context.Context, code string, moduleSubmissionId int, id int) (model.GetUserSubmissionsResponse, error) {
 	tx, err := service.DB.Begin()
 	if err != nil {
 		return model.GetUserSubmissionsResponse{}, err
 	}
 	defer utils.CommitOrRollback(tx)
 
-	userSubmission, err := service.UserSubmissionRepository.FindUserSubmissionById(ctx, tx, id)
+	userSubmission, err := service.findInCourse(ctx, tx, code, moduleSubmissionId, id)
 	if err != nil {
 		return model.GetUserSubmissionsResponse{}, err
 	}
@@ -67,32 +91,60 @@ func (service *userSubmissionsService) FindUserSubmissionById(ctx context.Contex
 	return utils.ToUserSubmissionsResponse(userSubmission), nil
 }
 
-func (service *userSubmissionsService) SubmitFile(ctx context.Context, request model.CreateUserSubmissionsRequest) (model.GetUserSubmissionsResponse, error) {
+// SubmitFile checks the file against the rules and the deadline policy of the module submission, stores it under its
+// content address and records it as a new attempt. The user submission points to the latest attempt, earlier attempts
+// are kept.
+func (service *userSubmissionsService) SubmitFile(ctx context.Context, request model.CreateUserSubmissionsRequest, file model.UploadFile) (model.GetUserSubmissionsResponse, error) {
 	tx, err := service.DB.Begin()
 	if err != nil {
 		return model.GetUserSubmissionsResponse{}, err
 	}
 	defer utils.CommitOrRollback(tx)
 
+	course, err := service.CourseRepository.FindByCode(ctx, tx, request.CodeCourse)
+	if err != nil {
+		return model.GetUserSubmissionsResponse{}, err
+	}
+
+	moduleSubmission, err := service.ModuleSubmissionsRepository.FindByModId(ctx, tx, course.Id, request.ModuleSubmissionId)
+	if err != nil {
+		return model.GetUserSubmissionsResponse{}, err
+	}
+
+	extension, err := service.ExtensionRepository.FindByUser(ctx, tx, moduleSubmission.Id, request.UserId)
+	if err != nil {
+		return model.GetUserSubmissionsResponse{}, err
+	}
+	policy := newDeadlinePolicy(moduleSubmission, extensionDeadline(extension))
+	now := time.Now().UTC().Truncate(time.Second)
+	if !policy.Accepts(now) {
+		return model.GetUserSubmissionsResponse{}, ErrSubmissionClosed
+	}
+
+	err = storage.Validate(file.Body, file.Name, file.Size, utils.SplitList(moduleSubmission.AllowedTypes), moduleSubmission.MaxSize, service.Limits)
+	if err != nil {
+		return model.GetUserSubmissionsResponse{}, err
+	}
+
+	key, size, err := storage.ContentKey(file.Body, file.Name)
+	if err != nil {
+		return model.GetUserSubmissionsResponse{}, err
+	}
+
 	newSubmit := entity.UserSubmissions{
 		UserId:             request.UserId,
 		ModuleSubmissionId: request.ModuleSubmissionId,
-		File:               request.File,
+		File:               &key,
 	}
 
 	before, err := service.UserSubmissionRepository.FindUserSubmissionByOther(ctx, tx, newSubmit)
 	if err != nil {
 		return model.GetUserSubmissionsResponse{}, err
 	}
-	if before.File != nil {
-		path, err := utils.GetPath("/assets/", *before.File)
-		if err != nil {
-			return model.GetUserSubmissionsResponse{}, err
-		}
-		err = os.Remove(path)
-		if err != nil {
-			return model.GetUserSubmissionsResponse{}, err
-		}
+
+	err = service.Store.Put(ctx, key, file.Body, size, mime.TypeByExtension(filepath.Ext(key)))
+	if err != nil {
+		return model.GetUserSubmissionsResponse{}, err
 	}
 
 	userSubmission, err := service.UserSubmissionRepository.UpdateFile(ctx, tx, newSubmit)
@@ -100,11 +152,79 @@ func (service *userSubmissionsService) SubmitFile(ctx context.Context, request m
 		return model.GetUserSubmissionsResponse{}, err
 	}
 	userSubmission.Id = before.Id
+	userSubmission.Grade = before.Grade
+	userSubmission.GradedAttemptId = before.GradedAttemptId
+	userSubmission.Feedback = before.Feedback
+
+	hash := strings.TrimSuffix(key, filepath.Ext(key))
+	_, err = service.SubmissionAttemptRepository.Create(ctx, tx, entity.SubmissionAttempts{
+		UserSubmissionId: before.Id,
+		File:             key,
+		Hash:             &hash,
+		Size:             &size,
+		Late:             policy.Late(now),
+		CreatedAt:        now,
+	})
+	if err != nil {
+		return model.GetUserSubmissionsResponse{}, err
+	}
 
 	return utils.ToUserSubmissionsResponse(userSubmission), nil
 }
 
-func (service *userSubmissionsService) UpdateGrade(ctx context.Context, request model.UpdateUserGradeRequest) error {
+// OpenFile returns the content of the file of a user submission together with its key
+func (service *userSubmissionsService) OpenFile(ctx context.Context, code string, moduleSubmissionId int, id int) (io.ReadCloser, string, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return nil, "", err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	userSubmission, err := service.findInCourse(ctx, tx, code, moduleSubmissionId, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if userSubmission.File == nil {
+		return nil, "", ErrNoFile
+	}
+
+	body, err := service.Store.Get(ctx, *userSubmission.File)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return body, *userSubmission.File, nil
+}
+
+// FileURL returns a signed URL that downloads the file of a user submission without authentication for a limited time
+func (service *userSubmissionsService) FileURL(ctx context.Context, code string, moduleSubmissionId int, id int) (model.GetFileURLResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetFileURLResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	userSubmission, err := service.findInCourse(ctx, tx, code, moduleSubmissionId, id)
+	if err != nil {
+		return model.GetFileURLResponse{}, err
+	}
+	if userSubmission.File == nil {
+		return model.GetFileURLResponse{}, ErrNoFile
+	}
+
+	expiresAt := time.Now().Add(service.URLLifetime)
+	url, err := service.Store.SignedURL(ctx, *userSubmission.File, *userSubmission.File, service.URLLifetime)
+	if err != nil {
+		return model.GetFileURLResponse{}, err
+	}
+
+	return model.GetFileURLResponse{
+		Url:       url,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (service *userSubmissionsService) UpdateGrade(ctx context.Context, code string, moduleSubmissionId int, request model.UpdateUserGradeRequest) error {
 	tx, err := service.DB.Begin()
 	if err != nil {
 		return err
@@ -112,11 +232,12 @@ func (service *userSubmissionsService) UpdateGrade(ctx context.Context, request
 	defer utils.CommitOrRollback(tx)
 
 	newUpdate := entity.UserSubmissions{
-		Id:    request.Id,
-		Grade: &request.Grade,
+		Id:       request.Id,
+		Grade:    &request.Grade,
+		Feedback: request.Feedback,
 	}
 
-	_, err = service.UserSubmissionRepository.FindUserSubmissionById(ctx, tx, request.Id)
+	_, err = service.findInCourse(ctx, tx, code, moduleSubmissionId, request.Id)
 	if err != nil {
 		return err
 	}
@@ -128,3 +249,109 @@ func (service *userSubmissionsService) UpdateGrade(ctx context.Context, request
 
 	return nil
 }
+
+// FindAllAttempts lists every file submitted for a user submission, the first attempt comes first
+func (service *userSubmissionsService) FindAllAttempts(ctx context.Context, code string, moduleSubmissionId int, id int) ([]model.GetSubmissionAttemptResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	userSubmission, err := service.findInCourse(ctx, tx, code, moduleSubmissionId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := service.SubmissionAttemptRepository.FindAll(ctx, tx, userSubmission.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	attemptResponses := []model.GetSubmissionAttemptResponse{}
+	for i, attempt := range attempts {
+		// Without a pick of the teacher the latest attempt is graded
+		graded := userSubmission.GradedAttemptId == nil && i == len(attempts)-1 ||
+			userSubmission.GradedAttemptId != nil && *userSubmission.GradedAttemptId == attempt.Id
+		attemptResponses = append(attemptResponses, utils.ToSubmissionAttemptResponse(attempt, graded))
+	}
+
+	return attemptResponses, nil
+}
+
+// OpenAttempt returns the content of the file of one attempt together with its key
+func (service *userSubmissionsService) OpenAttempt(ctx context.Context, code string, moduleSubmissionId int, id int, attemptId int) (io.ReadCloser, string, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return nil, "", err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	userSubmission, err := service.findInCourse(ctx, tx, code, moduleSubmissionId, id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	attempt, err := service.SubmissionAttemptRepository.FindById(ctx, tx, userSubmission.Id, attemptId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	body, err := service.Store.Get(ctx, attempt.File)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return body, attempt.File, nil
+}
+
+// SelectGradedAttempt lets a teacher grade another attempt than the latest one
+func (service *userSubmissionsService) SelectGradedAttempt(ctx context.Context, code string, moduleSubmissionId int, request model.SelectGradedAttemptRequest) (model.GetUserSubmissionsResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return model.GetUserSubmissionsResponse{}, err
+	}
+	defer utils.CommitOrRollback(tx)
+
+	userSubmission, err := service.findInCourse(ctx, tx, code, moduleSubmissionId, request.Id)
+	if err != nil {
+		return model.GetUserSubmissionsResponse{}, err
+	}
+
+	attempt, err := service.SubmissionAttemptRepository.FindById(ctx, tx, userSubmission.Id, request.AttemptId)
+	if err != nil {
+		return model.GetUserSubmissionsResponse{}, err
+	}
+
+	err = service.UserSubmissionRepository.UpdateGradedAttempt(ctx, tx, userSubmission.Id, attempt.Id)
+	if err != nil {
+		return model.GetUserSubmissionsResponse{}, err
+	}
+	userSubmission.GradedAttemptId = &attempt.Id
+
+	return utils.ToUserSubmissionsResponse(userSubmission), nil
+}
+
+// findInCourse only returns the user submission when it belongs to the module submission of the course,
+// so a course in the path can not be used to reach another course's work
+func (service *userSubmissionsService) findInCourse(ctx context.Context, tx *sql.Tx, code string, moduleSubmissionId int, id int) (entity.UserSubmissions, error) {
+	course, err := service.CourseRepository.FindByCode(ctx, tx, code)
+	if err != nil {
+		return entity.UserSubmissions{}, err
+	}
+
+	_, err = service.ModuleSubmissionsRepository.FindByModId(ctx, tx, course.Id, moduleSubmissionId)
+	if err != nil {
+		return entity.UserSubmissions{}, err
+	}
+
+	userSubmission, err := service.UserSubmissionRepository.FindUserSubmissionById(ctx, tx, id)
+	if err != nil {
+		return entity.UserSubmissions{}, err
+	}
+	if userSubmission.ModuleSubmissionId != moduleSubmissionId {
+		return entity.UserSubmissions{}, errors.New("user submission not found")
+	}
+
+	return userSubmission, nil
+}
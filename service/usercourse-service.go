@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"database/sql"
+	"strconv"
 
 	"github.com/rg-km/final-project-engineering-12/backend/entity"
 	"github.com/rg-km/final-project-engineering-12/backend/model"
@@ -11,7 +12,7 @@ import (
 )
 
 type UserCourseService interface {
-	FindAll(ctx context.Context) ([]model.GetUserCourseResponse, error)
+	FindAll(ctx context.Context, query model.ListQuery) ([]model.GetUserCourseResponse, model.ListPage, error)
 	FindAllCourseByUserId(ctx context.Context, userId int) ([]model.GetStudentCourseResponse, error)
 	FindAllUserByCourseId(ctx context.Context, codeCourse string) ([]model.GetUserTeacherCourseResponse, error)
 	FindByUserCourse(ctx context.Context, code1 string, code2 string) (model.GetUserCourseResponse, error)
@@ -26,29 +27,41 @@ type usercourseService struct {
 	CourseRepository           repository.CourseRepository
 	ModuleSubmissionRepository repository.ModuleSubmissionsRepository
 	UserSubmissionRepository   repository.UserSubmissionsRepository
+	RubricRepository           repository.RubricRepository
+	QuizRepository             repository.QuizRepository
+	UserQuizRepository         repository.UserQuizRepository
+	CurriculumRepository       repository.CurriculumRepository
+	ProgressRepository         repository.ProgressRepository
+	Enroller                   *enroller
 	DB                         *sql.DB
 }
 
-func NewUserCourseService(usercourseRepository *repository.UserCourseRepository, courseRepository *repository.CourseRepository, moduleSubmissionRepository *repository.ModuleSubmissionsRepository, userSubmissionRepository *repository.UserSubmissionsRepository, db *sql.DB) UserCourseService {
+func NewUserCourseService(usercourseRepository *repository.UserCourseRepository, courseRepository *repository.CourseRepository, moduleSubmissionRepository *repository.ModuleSubmissionsRepository, userSubmissionRepository *repository.UserSubmissionsRepository, rubricRepository *repository.RubricRepository, quizRepository *repository.QuizRepository, userQuizRepository *repository.UserQuizRepository, curriculumRepository *repository.CurriculumRepository, progressRepository *repository.ProgressRepository, enrollmentRepository *repository.EnrollmentRepository, db *sql.DB) UserCourseService {
 	return &usercourseService{
 		UserCourseRepository:       *usercourseRepository,
 		CourseRepository:           *courseRepository,
 		ModuleSubmissionRepository: *moduleSubmissionRepository,
 		UserSubmissionRepository:   *userSubmissionRepository,
+		RubricRepository:           *rubricRepository,
+		QuizRepository:             *quizRepository,
+		UserQuizRepository:         *userQuizRepository,
+		CurriculumRepository:       *curriculumRepository,
+		ProgressRepository:         *progressRepository,
+		Enroller:                   newEnroller(enrollmentRepository, usercourseRepository, moduleSubmissionRepository, userSubmissionRepository, quizRepository, userQuizRepository),
 		DB:                         db,
 	}
 }
 
-func (service *usercourseService) FindAll(ctx context.Context) ([]model.GetUserCourseResponse, error) {
+func (service *usercourseService) FindAll(ctx context.Context, query model.ListQuery) ([]model.GetUserCourseResponse, model.ListPage, error) {
 	tx, err := service.DB.Begin()
 	if err != nil {
-		return []model.GetUserCourseResponse{}, err
+		return []model.GetUserCourseResponse{}, model.ListPage{}, err
 	}
 	defer utils.CommitOrRollback(tx)
 
-	courses, err := service.UserCourseRepository.FindAll(ctx, tx)
+	courses, total, err := service.UserCourseRepository.FindAll(ctx, tx, query)
 	if err != nil {
-		return []model.GetUserCourseResponse{}, err
+		return []model.GetUserCourseResponse{}, model.ListPage{}, err
 	}
 
 	var usercourseResponses []model.GetUserCourseResponse
@@ -56,24 +69,41 @@ func (service *usercourseService) FindAll(ctx context.Context) ([]model.GetUserC
 		usercourseResponses = append(usercourseResponses, utils.ToUserCourseResponse(usercourse))
 	}
 
-	return usercourseResponses, nil
+	return usercourseResponses, utils.ToListPage(query, len(courses), total), nil
 }
 
-func (service *usercourseService) FindAllCourseByUserId(ctx context.Context, courseId int) ([]model.GetStudentCourseResponse, error) {
+// FindAllCourseByUserId returns the courses of the user with the progress through each of them
+func (service *usercourseService) FindAllCourseByUserId(ctx context.Context, userId int) ([]model.GetStudentCourseResponse, error) {
 	tx, err := service.DB.Begin()
 	if err != nil {
 		return []model.GetStudentCourseResponse{}, err
 	}
 	defer utils.CommitOrRollback(tx)
 
-	courses, err := service.UserCourseRepository.FindAllCourseByUserId(ctx, tx, courseId)
+	courses, err := service.UserCourseRepository.FindAllCourseByUserId(ctx, tx, userId)
 	if err != nil {
 		return []model.GetStudentCourseResponse{}, err
 	}
 
 	var usercourseResponses []model.GetStudentCourseResponse
 	for _, usercourse := range courses {
-		usercourseResponses = append(usercourseResponses, utils.ToStudentCourseResponse(usercourse))
+		items, err := service.CurriculumRepository.FindAllItems(ctx, tx, usercourse.IdCourse)
+		if err != nil {
+			return []model.GetStudentCourseResponse{}, err
+		}
+
+		progresses, err := findAllProgress(ctx, tx, service.ProgressRepository, usercourse.IdCourse)
+		if err != nil {
+			return []model.GetStudentCourseResponse{}, err
+		}
+
+		progress := courseProgress(entity.Courses{Id: usercourse.IdCourse, CodeCourse: usercourse.CourseCode}, items, progresses, userId)
+
+		response := utils.ToStudentCourseResponse(usercourse)
+		response.Progress = progress.Progress
+		response.TimeRemaining = progress.TimeRemaining
+		response.LastVisited = progress.LastVisited
+		usercourseResponses = append(usercourseResponses, response)
 	}
 
 	return usercourseResponses, nil
@@ -131,7 +161,13 @@ func (service *usercourseService) Create(ctx context.Context, request model.Crea
 		CourseId: request.CourseId,
 	}
 
-	array, err := service.UserCourseRepository.FindAll(ctx, tx)
+	array, _, err := service.UserCourseRepository.FindAll(ctx, tx, model.ListQuery{
+		Limit: 1,
+		Filters: map[string]string{
+			"user_id":   strconv.Itoa(usercourses.UserId),
+			"course_id": strconv.Itoa(usercourses.CourseId),
+		},
+	})
 	if err != nil {
 		return model.GetUserCourseResponse{}, err
 	}
@@ -141,22 +177,11 @@ func (service *usercourseService) Create(ctx context.Context, request model.Crea
 		}
 	}
 
-	usercourse, err := service.UserCourseRepository.Create(ctx, tx, usercourses)
+	usercourse, err := service.Enroller.enroll(ctx, tx, usercourses.UserId, usercourses.CourseId)
 	if err != nil {
 		return model.GetUserCourseResponse{}, err
 	}
 
-	// Create User Submission
-	allModuleSubmissions, _ := service.ModuleSubmissionRepository.FindAll(ctx, tx, usercourses.CourseId)
-	if allModuleSubmissions != nil {
-		for _, value := range allModuleSubmissions {
-			err := service.UserSubmissionRepository.OnlyCreate(ctx, tx, request.UserId, value.Id)
-			if err != nil {
-				return model.GetUserCourseResponse{}, err
-			}
-		}
-	}
-
 	return utils.ToUserCourseResponse(usercourse), nil
 }
 
@@ -177,7 +202,7 @@ func (service *usercourseService) Delete(ctx context.Context, code1 int, code2 i
 		return err
 	}
 
-	return nil
+	return service.Enroller.promote(ctx, tx, code2)
 }
 
 func (service *usercourseService) FindAllStudentSubmissions(ctx context.Context, userId int, limit int) ([]model.GetStudentSubmissionsResponse, error) {
@@ -194,7 +219,24 @@ func (service *usercourseService) FindAllStudentSubmissions(ctx context.Context,
 
 	var studentSubmissionsResponses []model.GetStudentSubmissionsResponse
 	for _, studentSubmission := range studentSubmissions {
-		studentSubmissionsResponses = append(studentSubmissionsResponses, utils.ToStudentSubmissionsResponse(studentSubmission))
+		policy := newDeadlinePolicy(entity.ModuleSubmissions{
+			Deadline:      studentSubmission.Deadline,
+			LatePolicy:    studentSubmission.LatePolicy,
+			GracePeriod:   studentSubmission.GracePeriod,
+			PenaltyPerDay: studentSubmission.PenaltyPerDay,
+		}, studentSubmission.ExtendedDeadline)
+		penalty, late := policy.Grade(studentSubmission.Grade, studentSubmission.SubmittedAt)
+
+		scores, err := service.RubricRepository.FindScores(ctx, tx, studentSubmission.IdUserSubmission)
+		if err != nil {
+			return nil, err
+		}
+
+		studentSubmissionResponse := utils.ToStudentSubmissionsResponse(studentSubmission, penalty, late)
+		if len(scores) > 0 {
+			studentSubmissionResponse.Rubric = utils.ToCriterionScoreResponses(scores)
+		}
+		studentSubmissionsResponses = append(studentSubmissionsResponses, studentSubmissionResponse)
 	}
 
 	return studentSubmissionsResponses, nil
@@ -224,7 +266,14 @@ func (service *usercourseService) FindAllTeacherSubmissions(ctx context.Context,
 
 	var teacherSubmissionsResponses []model.GetTeacherSubmissionsResponse
 	for _, studentSubmission := range teacherSubmissions {
-		teacherSubmissionsResponses = append(teacherSubmissionsResponses, utils.ToTeacherSubmissionsResponse(studentSubmission))
+		policy := newDeadlinePolicy(entity.ModuleSubmissions{
+			Deadline:      studentSubmission.Deadline,
+			LatePolicy:    studentSubmission.LatePolicy,
+			GracePeriod:   studentSubmission.GracePeriod,
+			PenaltyPerDay: studentSubmission.PenaltyPerDay,
+		}, studentSubmission.ExtendedDeadline)
+		penalty, late := policy.Grade(studentSubmission.Grade, studentSubmission.SubmittedAt)
+		teacherSubmissionsResponses = append(teacherSubmissionsResponses, utils.ToTeacherSubmissionsResponse(studentSubmission, penalty, late))
 	}
 
 	return teacherSubmissionsResponses, nil
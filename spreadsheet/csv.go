@@ -0,0 +1,30 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// WriteCSV writes rows as comma separated values. Text starting with =, +, - or @ is prefixed with a quote
+// so a spreadsheet opening the file does not run it as a formula.
+func WriteCSV(writer io.Writer, rows [][]interface{}) error {
+	csvWriter := csv.NewWriter(writer)
+	for _, row := range rows {
+		record := make([]string, len(row))
+		for i, cell := range row {
+			record[i] = text(cell)
+			if _, isText := cell.(string); isText && record[i] != "" && strings.ContainsAny(record[i][:1], "=+-@") {
+				record[i] = "'" + record[i]
+			}
+		}
+
+		err := csvWriter.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
@@ -0,0 +1,52 @@
+// Package spreadsheet writes tables as CSV or XLSX downloads. A cell is nil, a string, an int or a float64.
+package spreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+var ErrUnknownFormat = errors.New("unknown export format, use csv or xlsx")
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// ContentType returns the media type of a format
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+
+	return "text/csv; charset=utf-8"
+}
+
+// Write writes rows in the given format, the first row is the header
+func Write(writer io.Writer, format string, sheet string, rows [][]interface{}) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(writer, rows)
+	case FormatXLSX:
+		return WriteXLSX(writer, sheet, rows)
+	}
+
+	return ErrUnknownFormat
+}
+
+func text(cell interface{}) string {
+	switch value := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case int:
+		return strconv.Itoa(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
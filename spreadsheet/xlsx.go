@@ -0,0 +1,109 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%v" sheetId="1" r:id="rId1"/></sheets></workbook>`
+)
+
+// WriteXLSX writes rows as a workbook with a single sheet. Strings are inline strings and numbers numeric cells,
+// so the file opens without shared strings or styles.
+func WriteXLSX(writer io.Writer, sheet string, rows [][]interface{}) error {
+	archive := zip.NewWriter(writer)
+
+	parts := []struct {
+		Name    string
+		Content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, escape(sheetName(sheet)))},
+		{"xl/worksheets/sheet1.xml", worksheet(rows)},
+	}
+	for _, part := range parts {
+		file, err := archive.Create(part.Name)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(file, part.Content)
+		if err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+func worksheet(rows [][]interface{}) string {
+	var sheet strings.Builder
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`)
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, row := range rows {
+		fmt.Fprintf(&sheet, `<row r="%v">`, i+1)
+		for j, cell := range row {
+			reference := fmt.Sprintf("%v%v", column(j), i+1)
+			switch cell.(type) {
+			case nil:
+			case int, float64:
+				fmt.Fprintf(&sheet, `<c r="%v"><v>%v</v></c>`, reference, text(cell))
+			default:
+				fmt.Fprintf(&sheet, `<c r="%v" t="inlineStr"><is><t xml:space="preserve">%v</t></is></c>`, reference, escape(text(cell)))
+			}
+		}
+		sheet.WriteString(`</row>`)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+
+	return sheet.String()
+}
+
+// column returns the letters of the zero based column index, A to Z then AA and so on
+func column(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+
+	return name
+}
+
+// sheetName drops the characters a sheet name cannot hold and keeps it within 31 characters
+func sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" {
+		name = "Sheet1"
+	}
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+
+	return name
+}
+
+func escape(value string) string {
+	var escaped strings.Builder
+	_ = xml.EscapeText(&escaped, []byte(value))
+
+	return escaped.String()
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrBlobNotFound     = errors.New("blob not found")
+	ErrInvalidKey       = errors.New("invalid blob key")
+	ErrInvalidSignature = errors.New("invalid or expired signature")
+)
+
+// validKey only allows flat keys, so a key can never point outside the store
+var (
+	validKey       = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]*$`)
+	validExtension = regexp.MustCompile(`^\.[a-z0-9]{1,9}$`)
+)
+
+// BlobStore keeps uploaded files. Keys are flat names, new files are stored under their ContentKey.
+type BlobStore interface {
+	// Put stores body under key, it does nothing when the key already exists
+	Put(ctx context.Context, key string, body io.ReadSeeker, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL that downloads key as filename without authentication until lifetime has passed
+	SignedURL(ctx context.Context, key string, filename string, lifetime time.Duration) (string, error)
+}
+
+// SignatureVerifier is implemented by the stores whose signed URLs are served by this application
+type SignatureVerifier interface {
+	Verify(key string, filename string, expires int64, signature string) error
+}
+
+// ContentKey returns the content address of body, the hex SHA-256 of its bytes followed by the extension of name.
+// body is rewound afterwards so it can be stored.
+func ContentKey(body io.ReadSeeker, name string) (string, int64, error) {
+	hash := sha256.New()
+	size, err := io.Copy(hash, body)
+	if err != nil {
+		return "", 0, err
+	}
+	_, err = body.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)) + extension(name), size, nil
+}
+
+func extension(name string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	if !validExtension.MatchString(ext) {
+		return ""
+	}
+
+	return ext
+}
+
+func checkKey(key string) error {
+	if !validKey.MatchString(key) {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// New opens the store selected by STORAGE_DRIVER, local files under STORAGE_LOCAL_ROOT are used by default
+func New(configuration config.Config) (BlobStore, error) {
+	switch configuration.Get("STORAGE_DRIVER") {
+	case "", DriverLocal:
+		root := configuration.Get("STORAGE_LOCAL_ROOT")
+		if root == "" {
+			root = "assets"
+		}
+
+		signingKey := []byte(configuration.Get("STORAGE_SIGNING_KEY"))
+		if len(signingKey) == 0 {
+			// Signed URLs then stop working when the application restarts
+			signingKey = make([]byte, 32)
+			_, err := rand.Read(signingKey)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return NewLocalStore(root, signingKey, configuration.Get("STORAGE_PUBLIC_URL"))
+	case DriverS3:
+		return NewS3Store(S3Config{
+			Endpoint:  configuration.Get("S3_ENDPOINT"),
+			Region:    configuration.Get("S3_REGION"),
+			Bucket:    configuration.Get("S3_BUCKET"),
+			AccessKey: configuration.Get("S3_ACCESS_KEY"),
+			SecretKey: configuration.Get("S3_SECRET_KEY"),
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %v", configuration.Get("STORAGE_DRIVER"))
+	}
+}
+
+// URLLifetime is how long signed download URLs stay valid, STORAGE_URL_LIFETIME_MINUTE defaults to 15 minutes
+func URLLifetime(configuration config.Config) time.Duration {
+	return time.Duration(positive(configuration.Get("STORAGE_URL_LIFETIME_MINUTE"), 15)) * time.Minute
+}
+
+// Limits reads UPLOAD_MAX_SIZE_MB (20 by default), UPLOAD_MAX_EXPANDED_SIZE_MB (100) and UPLOAD_MAX_ARCHIVE_FILES (1000)
+func Limits(configuration config.Config) UploadLimits {
+	return UploadLimits{
+		MaxSize:           int64(positive(configuration.Get("UPLOAD_MAX_SIZE_MB"), 20)) << 20,
+		MaxExpandedSize:   int64(positive(configuration.Get("UPLOAD_MAX_EXPANDED_SIZE_MB"), 100)) << 20,
+		MaxArchiveEntries: positive(configuration.Get("UPLOAD_MAX_ARCHIVE_FILES"), 1000),
+	}
+}
+
+func positive(value string, fallback int) int {
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		return fallback
+	}
+
+	return number
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"mime"
+	"os"
+	"path/filepath"
+)
+
+// Imported describes a file Import moved into a store
+type Imported struct {
+	Name string
+	Key  string
+	Size int64
+}
+
+// Import moves every regular file of directory into store under its ContentKey. moved is called once the blob
+// is stored, the file is only removed from directory when moved succeeds, so references can be updated first.
+func Import(ctx context.Context, store BlobStore, directory string, moved func(imported Imported) error) ([]Imported, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	var imported []Imported
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || entry.Name()[0] == '.' {
+			continue
+		}
+
+		path := filepath.Join(directory, entry.Name())
+		file, err := importFile(ctx, store, path)
+		if err != nil {
+			return imported, err
+		}
+		file.Name = entry.Name()
+
+		err = moved(file)
+		if err != nil {
+			return imported, err
+		}
+
+		// A local store may live in directory itself, then a file that is already content-addressed is kept
+		if file.Key != file.Name {
+			err = os.Remove(path)
+			if err != nil {
+				return imported, err
+			}
+		}
+		imported = append(imported, file)
+	}
+
+	return imported, nil
+}
+
+func importFile(ctx context.Context, store BlobStore, path string) (Imported, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Imported{}, err
+	}
+	defer file.Close()
+
+	key, size, err := ContentKey(file, path)
+	if err != nil {
+		return Imported{}, err
+	}
+
+	err = store.Put(ctx, key, file, size, mime.TypeByExtension(filepath.Ext(path)))
+	if err != nil {
+		return Imported{}, err
+	}
+
+	return Imported{Key: key, Size: size}, nil
+}
@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+type localStore struct {
+	Root       string
+	SigningKey []byte
+	BaseURL    string
+}
+
+// NewLocalStore keeps blobs as files in root. Its signed URLs point to /api/files/:key under baseURL,
+// which may be empty to get URLs relative to the API host.
+func NewLocalStore(root string, signingKey []byte, baseURL string) (BlobStore, error) {
+	err := os.MkdirAll(root, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &localStore{
+		Root:       root,
+		SigningKey: signingKey,
+		BaseURL:    baseURL,
+	}, nil
+}
+
+func (store *localStore) Put(ctx context.Context, key string, body io.ReadSeeker, size int64, contentType string) error {
+	exists, err := store.Exists(ctx, key)
+	if err != nil || exists {
+		return err
+	}
+
+	temporary, err := os.CreateTemp(store.Root, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temporary.Name())
+
+	_, err = io.Copy(temporary, body)
+	if closeErr := temporary.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(temporary.Name(), filepath.Join(store.Root, key))
+}
+
+func (store *localStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	err := checkKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filepath.Join(store.Root, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+
+	return file, err
+}
+
+func (store *localStore) Exists(ctx context.Context, key string) (bool, error) {
+	err := checkKey(key)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = os.Stat(filepath.Join(store.Root, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (store *localStore) Delete(ctx context.Context, key string) error {
+	err := checkKey(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filepath.Join(store.Root, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+func (store *localStore) SignedURL(ctx context.Context, key string, filename string, lifetime time.Duration) (string, error) {
+	err := checkKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	expires := time.Now().Add(lifetime).Unix()
+	query := url.Values{}
+	query.Set("filename", filename)
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", store.sign(key, filename, expires))
+
+	return store.BaseURL + "/api/files/" + url.PathEscape(key) + "?" + query.Encode(), nil
+}
+
+// Verify checks a signature made by SignedURL and that it has not expired yet
+func (store *localStore) Verify(key string, filename string, expires int64, signature string) error {
+	if time.Now().Unix() > expires {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(store.sign(key, filename, expires))) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func (store *localStore) sign(key string, filename string, expires int64) string {
+	mac := hmac.New(sha256.New, store.SigningKey)
+	mac.Write([]byte(key + "\n" + filename + "\n" + strconv.FormatInt(expires, 10)))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,309 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Config points the S3 driver at a bucket. Endpoint is the scheme and host of the service,
+// buckets are addressed path-style so MinIO and other S3-compatible servers work as well.
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+type s3Store struct {
+	Config S3Config
+	Client *http.Client
+	now    func() time.Time
+}
+
+func NewS3Store(config S3Config) (BlobStore, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", config.Endpoint)
+	}
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("S3 bucket is not set")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+
+	return &s3Store{
+		Config: config,
+		Client: &http.Client{Timeout: time.Minute},
+		now:    time.Now,
+	}, nil
+}
+
+func (store *s3Store) Put(ctx context.Context, key string, body io.ReadSeeker, size int64, contentType string) error {
+	exists, err := store.Exists(ctx, key)
+	if err != nil || exists {
+		return err
+	}
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, body)
+	if err != nil {
+		return err
+	}
+	_, err = body.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	request, err := store.request(ctx, http.MethodPut, key, ioutil.NopCloser(body), hex.EncodeToString(hash.Sum(nil)))
+	if err != nil {
+		return err
+	}
+	request.ContentLength = size
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	response, err := store.do(request)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+func (store *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	request, err := store.request(ctx, http.MethodGet, key, nil, unsignedPayload)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := store.do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Body, nil
+}
+
+func (store *s3Store) Exists(ctx context.Context, key string) (bool, error) {
+	request, err := store.request(ctx, http.MethodHead, key, nil, unsignedPayload)
+	if err != nil {
+		return false, err
+	}
+
+	response, err := store.do(request)
+	if err == ErrBlobNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, response.Body.Close()
+}
+
+func (store *s3Store) Delete(ctx context.Context, key string) error {
+	request, err := store.request(ctx, http.MethodDelete, key, nil, unsignedPayload)
+	if err != nil {
+		return err
+	}
+
+	response, err := store.do(request)
+	if err == ErrBlobNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return response.Body.Close()
+}
+
+// SignedURL presigns a GET request with AWS Signature Version 4, S3 accepts lifetimes of up to 7 days
+func (store *s3Store) SignedURL(ctx context.Context, key string, filename string, lifetime time.Duration) (string, error) {
+	err := checkKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint, _ := url.Parse(store.Config.Endpoint)
+	now := store.now().UTC()
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", store.Config.AccessKey+"/"+store.scope(now))
+	query.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(lifetime.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if filename != "" {
+		query.Set("response-content-disposition", "attachment; filename=\""+filename+"\"")
+	}
+
+	path := store.path(key)
+	signature := store.signature(now, http.MethodGet, path, query, http.Header{"Host": {endpoint.Host}}, unsignedPayload)
+
+	return store.Config.Endpoint + path + "?" + canonicalQuery(query) + "&X-Amz-Signature=" + signature, nil
+}
+
+func (store *s3Store) path(key string) string {
+	return "/" + uriEncode(store.Config.Bucket, false) + "/" + uriEncode(key, false)
+}
+
+// request builds a request for key signed in the Authorization header, payloadHash is the hex SHA-256 of the body
+func (store *s3Store) request(ctx context.Context, method string, key string, body io.ReadCloser, payloadHash string) (*http.Request, error) {
+	err := checkKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, store.Config.Endpoint+store.path(key), body)
+	if err != nil {
+		return nil, err
+	}
+
+	now := store.now().UTC()
+	request.Header.Set("Host", request.URL.Host)
+	request.Header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signature := store.signature(now, method, store.path(key), url.Values{}, request.Header, payloadHash)
+	request.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%v/%v, SignedHeaders=%v, Signature=%v",
+		store.Config.AccessKey, store.scope(now), signedHeaders(request.Header), signature,
+	))
+	request.Header.Del("Host")
+
+	return request, nil
+}
+
+func (store *s3Store) do(request *http.Request) (*http.Response, error) {
+	response, err := store.Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == http.StatusNotFound {
+		_ = response.Body.Close()
+		return nil, ErrBlobNotFound
+	}
+	if response.StatusCode >= 300 {
+		message, _ := ioutil.ReadAll(io.LimitReader(response.Body, 1024))
+		_ = response.Body.Close()
+		return nil, fmt.Errorf("s3 %v %v: %v %s", request.Method, request.URL.Path, response.Status, bytes.TrimSpace(message))
+	}
+
+	return response, nil
+}
+
+func (store *s3Store) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + store.Config.Region + "/s3/aws4_request"
+}
+
+// signature computes the AWS Signature Version 4 of a request, see
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+func (store *s3Store) signature(now time.Time, method string, path string, query url.Values, header http.Header, payloadHash string) string {
+	canonicalRequest := strings.Join([]string{
+		method,
+		path,
+		canonicalQuery(query),
+		canonicalHeaders(header),
+		signedHeaders(header),
+		payloadHash,
+	}, "\n")
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		now.Format("20060102T150405Z"),
+		store.scope(now),
+		hex.EncodeToString(canonicalHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+store.Config.SecretKey), now.Format("20060102"))
+	key = hmacSHA256(key, store.Config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func canonicalQuery(query url.Values) string {
+	var names []string
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var pairs []string
+	for _, name := range names {
+		for _, value := range query[name] {
+			pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+func canonicalHeaders(header http.Header) string {
+	var builder strings.Builder
+	for _, name := range signedHeaderNames(header) {
+		builder.WriteString(name + ":" + strings.TrimSpace(header.Get(name)) + "\n")
+	}
+
+	return builder.String()
+}
+
+func signedHeaders(header http.Header) string {
+	return strings.Join(signedHeaderNames(header), ";")
+}
+
+func signedHeaderNames(header http.Header) []string {
+	var names []string
+	for name := range header {
+		lower := strings.ToLower(name)
+		if lower == "host" || strings.HasPrefix(lower, "x-amz-") {
+			names = append(names, lower)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// uriEncode percent-encodes everything but the unreserved characters, slashes are kept unless encodeSlash is set
+func uriEncode(value string, encodeSlash bool) string {
+	var builder strings.Builder
+	for _, char := range []byte(value) {
+		switch {
+		case 'A' <= char && char <= 'Z', 'a' <= char && char <= 'z', '0' <= char && char <= '9',
+			char == '-', char == '_', char == '.', char == '~':
+			builder.WriteByte(char)
+		case char == '/' && !encodeSlash:
+			builder.WriteByte(char)
+		default:
+			builder.WriteString(fmt.Sprintf("%%%02X", char))
+		}
+	}
+
+	return builder.String()
+}
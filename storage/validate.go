@@ -0,0 +1,192 @@
+package storage
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrUnknownFileType     = errors.New("unknown file type")
+	ErrFileTooLarge        = errors.New("file is too large")
+	ErrFileTypeNotAllowed  = errors.New("file type is not allowed")
+	ErrFileContentMismatch = errors.New("file content does not match its extension")
+	ErrArchiveTooLarge     = errors.New("archive expands too much")
+)
+
+// FileType groups the extensions and sniffed content types accepted for one kind of upload
+type FileType struct {
+	Name         string
+	Extensions   []string
+	ContentTypes []string
+	// Archive types are zip files, their expanded size is checked
+	Archive bool
+	// Entry is a file a zip archive of this type must contain
+	Entry string
+}
+
+// FileTypes are the types a module submission can restrict its uploads to
+var FileTypes = []FileType{
+	{Name: "pdf", Extensions: []string{".pdf"}, ContentTypes: []string{"application/pdf"}},
+	{Name: "docx", Extensions: []string{".docx"}, ContentTypes: []string{"application/zip"}, Archive: true, Entry: "word/document.xml"},
+	{Name: "zip", Extensions: []string{".zip"}, ContentTypes: []string{"application/zip"}, Archive: true},
+	{Name: "image", Extensions: []string{".png", ".jpg", ".jpeg", ".gif", ".webp"}, ContentTypes: []string{"image/png", "image/jpeg", "image/gif", "image/webp"}},
+}
+
+// UploadLimits bound every upload, whatever its module submission allows
+type UploadLimits struct {
+	MaxSize           int64
+	MaxExpandedSize   int64
+	MaxArchiveEntries int
+}
+
+// ParseFileTypes normalizes the names of file types and rejects the unknown ones
+func ParseFileTypes(names []string) ([]string, error) {
+	var types []string
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := fileTypeByName(name); !ok {
+			return nil, fmt.Errorf("%w %q, use one of %v", ErrUnknownFileType, name, strings.Join(fileTypeNames(), ", "))
+		}
+		if !contains(types, name) {
+			types = append(types, name)
+		}
+	}
+	sort.Strings(types)
+
+	return types, nil
+}
+
+// Validate checks an upload against the allowed file types and the size limit, an empty allow-list accepts any type.
+// The real content type is sniffed so a file can not pass for another type by its extension, and zip archives are
+// expanded to make sure they stay under limits.MaxExpandedSize. body is rewound afterwards.
+func Validate(body io.ReadSeeker, name string, size int64, allowed []string, maxSize int64, limits UploadLimits) error {
+	if maxSize <= 0 || maxSize > limits.MaxSize {
+		maxSize = limits.MaxSize
+	}
+	if size > maxSize {
+		return fmt.Errorf("%w, the limit is %v bytes", ErrFileTooLarge, maxSize)
+	}
+
+	fileType, known := fileTypeByExtension(extension(name))
+	if len(allowed) > 0 && (!known || !contains(allowed, fileType.Name)) {
+		return fmt.Errorf("%w, upload one of %v", ErrFileTypeNotAllowed, strings.Join(allowed, ", "))
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(body, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	contentType := http.DetectContentType(head[:n])
+	if index := strings.Index(contentType, ";"); index >= 0 {
+		contentType = contentType[:index]
+	}
+
+	if known && !contains(fileType.ContentTypes, contentType) {
+		return fmt.Errorf("%w, a %v file contains %v", ErrFileContentMismatch, fileType.Name, contentType)
+	}
+	if (known && fileType.Archive) || contentType == "application/zip" {
+		err = checkArchive(body, size, fileType.Entry, limits)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = body.Seek(0, io.SeekStart)
+	return err
+}
+
+func checkArchive(body io.ReadSeeker, size int64, entry string, limits UploadLimits) error {
+	readerAt, ok := body.(io.ReaderAt)
+	if !ok {
+		_, err := body.Seek(0, io.SeekStart)
+		if err != nil {
+			return err
+		}
+		content, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		readerAt = bytes.NewReader(content)
+	}
+
+	archive, err := zip.NewReader(readerAt, size)
+	if err != nil {
+		return fmt.Errorf("%w, the archive can not be read", ErrFileContentMismatch)
+	}
+	if len(archive.File) > limits.MaxArchiveEntries {
+		return fmt.Errorf("%w, it has more than %v files", ErrArchiveTooLarge, limits.MaxArchiveEntries)
+	}
+
+	// The sizes in the headers can lie, so the entries are expanded until the limit is reached
+	found := entry == ""
+	remaining := limits.MaxExpandedSize
+	for _, file := range archive.File {
+		found = found || file.Name == entry
+
+		content, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("%w, the archive can not be read", ErrFileContentMismatch)
+		}
+		written, err := io.Copy(io.Discard, io.LimitReader(content, remaining+1))
+		content.Close()
+		if err != nil {
+			return fmt.Errorf("%w, the archive can not be read", ErrFileContentMismatch)
+		}
+
+		remaining -= written
+		if remaining < 0 {
+			return fmt.Errorf("%w, the limit is %v bytes", ErrArchiveTooLarge, limits.MaxExpandedSize)
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w, the archive has no %v", ErrFileContentMismatch, entry)
+	}
+
+	return nil
+}
+
+func fileTypeByName(name string) (FileType, bool) {
+	for _, fileType := range FileTypes {
+		if fileType.Name == name {
+			return fileType, true
+		}
+	}
+
+	return FileType{}, false
+}
+
+func fileTypeByExtension(ext string) (FileType, bool) {
+	for _, fileType := range FileTypes {
+		if contains(fileType.Extensions, ext) {
+			return fileType, true
+		}
+	}
+
+	return FileType{}, false
+}
+
+func fileTypeNames() []string {
+	var names []string
+	for _, fileType := range FileTypes {
+		names = append(names, fileType.Name)
+	}
+
+	return names
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,196 @@
+package integration
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+)
+
+var _ = Describe("Account API", func() {
+
+	var (
+		server    *gin.Engine
+		directory string
+		token     string
+		id        int
+	)
+
+	settings := map[string]string{
+		"MAIL_TRANSPORT":              "file",
+		"MAIL_WORKER_INTERVAL_SECOND": "1",
+		"EMAIL_CHANGE_URL":            "https://belajar.example/confirm-email",
+	}
+
+	serve := func(method string, path string, body string) (int, map[string]interface{}) {
+		return setup.Serve(server, token, method, path, body)
+	}
+
+	register := func(username string) {
+		setup.Register(server, "Siswa "+username, username, entity.RoleStudent)
+	}
+
+	login := func(email string, password string) int {
+		code, _ := serve(http.MethodPost, "/api/users/login", `{"email": "`+email+`", "password": "`+password+`"}`)
+		return code
+	}
+
+	// delivered waits until the worker sent every email, so it is not writing while the next request does
+	delivered := func() {
+		db, err := setup.SuiteSetup(config.New("../../.env.test"))
+		Expect(err).NotTo(HaveOccurred())
+		defer db.Close()
+
+		Eventually(func() int {
+			var pending int
+			_ = db.QueryRow(`SELECT COUNT(*) FROM email_outbox WHERE status <> 'sent'`).Scan(&pending)
+			return pending
+		}, 5*time.Second, 100*time.Millisecond).Should(BeZero())
+	}
+
+	// tokens returns the tokens of the confirmation emails the file transport wrote so far
+	tokens := func() []string {
+		names, _ := filepath.Glob(filepath.Join(directory, "*.eml"))
+		var found []string
+		for _, name := range names {
+			content, _ := os.ReadFile(name)
+			if !strings.Contains(string(content), "Subject: Confirm your new email address") {
+				continue
+			}
+			Expect(string(content)).To(ContainSubstring("To: siswaganti@gmail.com"))
+			token := regexp.MustCompile(`https://belajar\.example/confirm-email\?token=([0-9a-f]+)`).FindStringSubmatch(string(content))
+			Expect(token).To(HaveLen(2))
+			found = append(found, token[1])
+		}
+		return found
+	}
+
+	BeforeEach(func() {
+		var err error
+		directory, err = os.MkdirTemp("", "mails")
+		Expect(err).NotTo(HaveOccurred())
+
+		for key, value := range settings {
+			os.Setenv(key, value)
+		}
+		os.Setenv("MAIL_FILE_PATH", directory)
+
+		configuration := config.New("../../.env.test")
+		_, err = setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		server = setup.ModuleSetup(configuration)
+
+		token = ""
+		register("siswaakun")
+		code, response := serve(http.MethodPost, "/api/users/login", `{"email": "siswaakun@gmail.com", "password": "123456ll"}`)
+		Expect(code).To(Equal(http.StatusOK))
+		token = response["token"].(string)
+		id = int(response["data"].(map[string]interface{})["id"].(float64))
+		delivered()
+	})
+
+	AfterEach(func() {
+		for key := range settings {
+			os.Unsetenv(key)
+		}
+		os.Unsetenv("MAIL_FILE_PATH")
+		_ = os.RemoveAll(directory)
+
+		configuration := config.New("../../.env.test")
+		// Replaces the worker writing to the directory
+		setup.ModuleSetup(configuration)
+
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Change password", func() {
+		It("should ask for the current password", func() {
+			code, _ := serve(http.MethodPut, "/api/users/password", `{"current_password": "salah1234", "new_password": "rahasiabaru"}`)
+			Expect(code).To(Equal(http.StatusForbidden))
+			code, _ = serve(http.MethodPut, "/api/users/password", `{"current_password": "123456ll", "new_password": "pendek"}`)
+			Expect(code).To(Equal(http.StatusBadRequest))
+
+			_, response := serve(http.MethodPost, "/api/users/login", `{"email": "siswaakun@gmail.com", "password": "123456ll"}`)
+			refreshToken := response["refresh_token"].(string)
+
+			code, _ = serve(http.MethodPut, "/api/users/password", `{"current_password": "123456ll", "new_password": "rahasiabaru"}`)
+			Expect(code).To(Equal(http.StatusOK))
+
+			// Every session and refresh token from before the change is revoked
+			code, _ = serve(http.MethodPut, "/api/users/password", `{"current_password": "rahasiabaru", "new_password": "rahasialagi"}`)
+			Expect(code).To(Equal(http.StatusUnauthorized))
+			token = ""
+			code, _ = serve(http.MethodPost, "/api/users/token/refresh", `{"refresh_token": "`+refreshToken+`"}`)
+			Expect(code).To(Equal(http.StatusUnauthorized))
+
+			Expect(login("siswaakun@gmail.com", "123456ll")).To(Equal(http.StatusBadRequest))
+			Expect(login("siswaakun@gmail.com", "rahasiabaru")).To(Equal(http.StatusOK))
+		})
+	})
+
+	Describe("Change email", func() {
+		It("should only change the email once the new email is confirmed", func() {
+			register("siswalain")
+			delivered()
+			code, _ := serve(http.MethodPut, "/api/users/email", `{"email": "siswalain@gmail.com", "password": "123456ll"}`)
+			Expect(code).To(Equal(http.StatusConflict))
+			code, _ = serve(http.MethodPut, "/api/users/email", `{"email": "siswaakun@gmail.com", "password": "123456ll"}`)
+			Expect(code).To(Equal(http.StatusBadRequest))
+			code, _ = serve(http.MethodPut, "/api/users/email", `{"email": "siswaganti@gmail.com", "password": "salah1234"}`)
+			Expect(code).To(Equal(http.StatusForbidden))
+
+			code, _ = serve(http.MethodPut, "/api/users/email", `{"email": "siswaganti@gmail.com", "password": "123456ll"}`)
+			Expect(code).To(Equal(http.StatusAccepted))
+			delivered()
+			Expect(tokens()).To(HaveLen(1))
+
+			Expect(login("siswaganti@gmail.com", "123456ll")).To(Equal(http.StatusBadRequest))
+
+			code, _ = serve(http.MethodPost, "/api/users/email/confirm", `{"token": "`+tokens()[0]+`"}`)
+			Expect(code).To(Equal(http.StatusOK))
+			code, _ = serve(http.MethodPost, "/api/users/email/confirm", `{"token": "`+tokens()[0]+`"}`)
+			Expect(code).To(Equal(http.StatusBadRequest))
+
+			Expect(login("siswaakun@gmail.com", "123456ll")).To(Equal(http.StatusBadRequest))
+			Expect(login("siswaganti@gmail.com", "123456ll")).To(Equal(http.StatusOK))
+		})
+	})
+
+	Describe("Update profile", func() {
+		It("should not change the role", func() {
+			code, _ := serve(http.MethodPut, "/api/users/"+strconv.Itoa(id), `{"name": "Siswa Akun", "username": "siswaakun", "role": 1}`)
+			Expect(code).To(Equal(http.StatusForbidden))
+
+			code, response := serve(http.MethodPut, "/api/users/"+strconv.Itoa(id), `{"name": "Siswa Akun", "username": "siswaakun"}`)
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(response["data"].(map[string]interface{})["role"]).To(Equal(float64(2)))
+
+			code, response = serve(http.MethodGet, "/api/users/"+strconv.Itoa(id), "")
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(response["data"].(map[string]interface{})["name"]).To(Equal("Siswa Akun"))
+			Expect(response["data"].(map[string]interface{})["role"]).To(Equal(float64(2)))
+		})
+	})
+})
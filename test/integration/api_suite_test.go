@@ -5,9 +5,22 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
 )
 
 func TestMainApi(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "API Testing")
 }
+
+var _ = BeforeSuite(func() {
+	configuration := config.New("../../.env.test")
+
+	err := setup.FreshDatabase(configuration)
+	Expect(err).NotTo(HaveOccurred())
+
+	db, err := setup.SuiteSetup(configuration)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(db.Close()).To(Succeed())
+})
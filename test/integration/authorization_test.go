@@ -0,0 +1,168 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var _ = Describe("Authorization", func() {
+
+	var (
+		server       *gin.Engine
+		studentToken string
+		adminToken   string
+	)
+
+	registerAndLogin := func(user model.UserRegisterResponse) string {
+		userData, _ := json.Marshal(user)
+		request := httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(string(userData)))
+		request.Header.Add("Content-Type", "application/json")
+
+		writer := httptest.NewRecorder()
+		server.ServeHTTP(writer, request)
+
+		// Users register as students, the other roles are given afterwards
+		Expect(setup.SetRole(config.New("../../.env.test"), user.Email, user.Role)).To(Succeed())
+
+		userLogin, _ := json.Marshal(model.GetUserLogin{Email: user.Email, Password: user.Password})
+		request = httptest.NewRequest(http.MethodPost, "/api/users/login", strings.NewReader(string(userLogin)))
+		request.Header.Add("Content-Type", "application/json")
+
+		writer = httptest.NewRecorder()
+		server.ServeHTTP(writer, request)
+
+		body, _ := io.ReadAll(writer.Result().Body)
+		var responseBody map[string]interface{}
+		_ = json.Unmarshal(body, &responseBody)
+
+		return responseBody["token"].(string)
+	}
+
+	BeforeEach(func() {
+		configuration := config.New("../../.env.test")
+
+		_, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		router := setup.ModuleSetup(configuration)
+		server = router
+
+		studentToken = registerAndLogin(model.UserRegisterResponse{
+			Name:           "murid",
+			Username:       "murid",
+			Email:          "murid@gmail.com",
+			Password:       "123456ll",
+			Role:           2,
+			Phone:          "085156789011",
+			Gender:         1,
+			DisabilityType: 1,
+			Birthdate:      "2002-04-01",
+		})
+		adminToken = registerAndLogin(model.UserRegisterResponse{
+			Name:           "admin",
+			Username:       "admin",
+			Email:          "admin@gmail.com",
+			Password:       "123456ll",
+			Role:           1,
+			Phone:          "085156789011",
+			Gender:         1,
+			DisabilityType: 1,
+			Birthdate:      "2002-04-01",
+		})
+	})
+
+	AfterEach(func() {
+		configuration := config.New("../../.env.test")
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Admin only endpoint", func() {
+		When("a student calls it", func() {
+			It("should return forbidden", func() {
+				request := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+				request.Header.Set("Authorization", studentToken)
+
+				writer := httptest.NewRecorder()
+				server.ServeHTTP(writer, request)
+
+				Expect(writer.Result().StatusCode).To(Equal(http.StatusForbidden))
+			})
+		})
+
+		When("an admin calls it", func() {
+			It("should return ok", func() {
+				request := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+				request.Header.Set("Authorization", adminToken)
+
+				writer := httptest.NewRecorder()
+				server.ServeHTTP(writer, request)
+
+				Expect(writer.Result().StatusCode).To(Equal(http.StatusOK))
+			})
+		})
+
+		When("the token is missing", func() {
+			It("should return unauthorized", func() {
+				request := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+
+				writer := httptest.NewRecorder()
+				server.ServeHTTP(writer, request)
+
+				Expect(writer.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+	Describe("User submission of another student", func() {
+		When("a student reads it", func() {
+			It("should return forbidden", func() {
+				idOwner := setup.Register(server, "Siswa Pemilik", "siswapemilik", entity.RoleStudent)
+				ownerToken := setup.Login(server, "siswapemilik")
+				codeCourse, _ := setup.EnrolledCourse(server, adminToken, idOwner, `{"name": "Fisika","class": "X-1","tools": "Kalkulator","about": "Gerak","description": "Gerak lurus"}`)
+
+				_, response := setup.Serve(server, adminToken, http.MethodPost, "/api/courses/"+codeCourse+"/submissions", `{"name": "Tugas Gerak","description": "GLB","deadline": "2099-06-21T15:21:38+07:00"}`)
+				pathSubmission := fmt.Sprintf("/api/courses/%v/submissions/%v", codeCourse, setup.ID(response))
+
+				code, response := setup.Upload(server, ownerToken, pathSubmission+"/user-submit", "jawaban.txt", "jarak per waktu")
+				Expect(code).To(Equal(http.StatusOK))
+				path, _ := utils.GetPath("/assets/", response["data"].(map[string]interface{})["file"].(string))
+				DeferCleanup(os.Remove, path)
+				pathUserSubmission := fmt.Sprintf("%v/user-submit/%v", pathSubmission, setup.ID(response))
+
+				code, _ = setup.Serve(server, studentToken, http.MethodGet, pathUserSubmission, "")
+				Expect(code).To(Equal(http.StatusForbidden))
+				code, _ = setup.Serve(server, studentToken, http.MethodPost, pathUserSubmission+"/download", "")
+				Expect(code).To(Equal(http.StatusForbidden))
+
+				code, _ = setup.Serve(server, ownerToken, http.MethodGet, pathUserSubmission, "")
+				Expect(code).To(Equal(http.StatusOK))
+				code, _ = setup.Serve(server, adminToken, http.MethodGet, pathUserSubmission, "")
+				Expect(code).To(Equal(http.StatusOK))
+			})
+		})
+	})
+})
@@ -0,0 +1,157 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var _ = Describe("Certificate API", func() {
+
+	var (
+		server     *gin.Engine
+		token      string
+		codeCourse string
+		article    int
+	)
+
+	serve := func(method string, path string, body string) (int, map[string]interface{}) {
+		return setup.Serve(server, token, method, path, body)
+	}
+
+	// graded submits a file to a new module submission and grades it
+	graded := func(grade int) {
+		code, response := serve(http.MethodPost, "/api/courses/"+codeCourse+"/submissions", `{"name": "Laporan","description": "Laporan praktikum","deadline": "2099-06-21"}`)
+		Expect(code).To(Equal(http.StatusOK))
+		pathSubmission := fmt.Sprintf("/api/courses/%v/submissions/%v", codeCourse, setup.ID(response))
+
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("file", "laporan.txt")
+		_, _ = part.Write([]byte(fmt.Sprintf("laporan bernilai %v", grade)))
+		writer.Close()
+
+		request := httptest.NewRequest(http.MethodPost, pathSubmission+"/user-submit", body)
+		request.Header.Add("Content-Type", writer.FormDataContentType())
+		request.Header.Set("Authorization", token)
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+		data := response["data"].(map[string]interface{})
+
+		path, _ := utils.GetPath("/assets/", data["file"].(string))
+		DeferCleanup(os.Remove, path)
+
+		code, _ = serve(http.MethodPatch, fmt.Sprintf("%v/user-submit/%v", pathSubmission, int(data["id"].(float64))), fmt.Sprintf(`{"grade": %v}`, grade))
+		Expect(code).To(Equal(http.StatusOK))
+	}
+
+	BeforeEach(func() {
+		configuration := config.New("../../.env.test")
+
+		_, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		server = setup.ModuleSetup(configuration)
+
+		idUser := setup.Register(server, "Admin Certificate", "admincertificate", entity.RoleAdmin)
+		token = setup.Login(server, "admincertificate")
+		codeCourse, _ = setup.EnrolledCourse(server, token, idUser, `{"name": "Biologi Sel","class": "XI-1","tools": "Mikroskop","about": "Sel","description": "Struktur sel"}`)
+
+		_, response := serve(http.MethodPost, "/api/courses/"+codeCourse+"/articles", `{"name": "Organel sel","content": "Mitokondria","estimate": 10}`)
+		article = setup.ID(response)
+	})
+
+	AfterEach(func() {
+		configuration := config.New("../../.env.test")
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Issue a certificate", func() {
+		It("should refuse students who did not complete every item", func() {
+			code, _ := serve(http.MethodPost, "/api/courses/"+codeCourse+"/certificate", "")
+			Expect(code).To(Equal(http.StatusForbidden))
+
+			code, _ = serve(http.MethodGet, "/api/courses/"+codeCourse+"/certificate", "")
+			Expect(code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should issue one certificate with a verification code and a PDF", func() {
+			serve(http.MethodPost, fmt.Sprintf("/api/courses/%v/articles/%v/complete", codeCourse, article), "")
+
+			code, response := serve(http.MethodPost, "/api/courses/"+codeCourse+"/certificate", "")
+			Expect(code).To(Equal(http.StatusOK))
+			certificate := response["data"].(map[string]interface{})
+			Expect(certificate["code"]).To(MatchRegexp(`^[0-9A-F]{4}-[0-9A-F]{4}-[0-9A-F]{4}$`))
+			Expect(certificate["student_name"]).To(Equal("Admin Certificate"))
+			Expect(certificate["course_name"]).To(Equal("Biologi Sel"))
+			Expect(certificate["score"]).To(BeNil())
+
+			_, response = serve(http.MethodPost, "/api/courses/"+codeCourse+"/certificate", "")
+			Expect(response["data"].(map[string]interface{})["code"]).To(Equal(certificate["code"]))
+
+			request := httptest.NewRequest(http.MethodGet, "/api/courses/"+codeCourse+"/certificate/download", nil)
+			request.Header.Set("Authorization", token)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/pdf"))
+			Expect(recorder.Body.String()).To(HavePrefix("%PDF-"))
+			Expect(recorder.Body.String()).To(ContainSubstring("(Biologi Sel)"))
+			Expect(recorder.Body.String()).To(ContainSubstring(certificate["code"].(string)))
+		})
+
+		It("should refuse students below the passing score", func() {
+			serve(http.MethodPost, fmt.Sprintf("/api/courses/%v/articles/%v/complete", codeCourse, article), "")
+			graded(40)
+
+			code, _ := serve(http.MethodPost, "/api/courses/"+codeCourse+"/certificate", "")
+			Expect(code).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	Describe("Verify a certificate", func() {
+		It("should verify a certificate by its code without logging in", func() {
+			serve(http.MethodPost, fmt.Sprintf("/api/courses/%v/articles/%v/complete", codeCourse, article), "")
+			graded(85)
+			_, response := serve(http.MethodPost, "/api/courses/"+codeCourse+"/certificate", "")
+			certificateCode := response["data"].(map[string]interface{})["code"].(string)
+
+			token = ""
+			code, response := serve(http.MethodGet, "/api/certificates/"+strings.ToLower(certificateCode)+"/verify", "")
+			Expect(code).To(Equal(http.StatusOK))
+			certificate := response["data"].(map[string]interface{})
+			Expect(certificate["code"]).To(Equal(certificateCode))
+			Expect(certificate["code_course"]).To(Equal(codeCourse))
+			Expect(certificate["student_name"]).To(Equal("Admin Certificate"))
+			Expect(certificate["score"]).To(Equal(float64(85)))
+
+			code, _ = serve(http.MethodGet, "/api/certificates/0000-0000-0000/verify", "")
+			Expect(code).To(Equal(http.StatusNotFound))
+		})
+	})
+})
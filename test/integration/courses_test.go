@@ -75,6 +75,12 @@ var _ = Describe("Courses API", func() {
 		usernameUser = responseBodyRegister["data"].(map[string]interface{})["username"].(string)
 		emailUser = responseBodyRegister["data"].(map[string]interface{})["email"].(string)
 
+		// Users register as students, the admin gets its role afterwards
+		err = setup.SetRole(configuration, user.Email, user.Role)
+		if err != nil {
+			panic(err)
+		}
+
 		//Login User
 		userData, _ = json.Marshal(login)
 		requestBody = strings.NewReader(string(userData))
@@ -0,0 +1,167 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+)
+
+var _ = Describe("Curriculum API", func() {
+
+	var (
+		server     *gin.Engine
+		token      string
+		codeCourse string
+		article    int
+		submission int
+		quiz       int
+	)
+
+	serve := func(method string, path string, body string) (int, map[string]interface{}) {
+		return setup.Serve(server, token, method, path, body)
+	}
+
+	// sequence lists the items of the curriculum as type:id in order
+	sequence := func() []string {
+		code, response := serve(http.MethodGet, "/api/courses/"+codeCourse+"/curriculum", "")
+		Expect(code).To(Equal(http.StatusOK))
+
+		var items []string
+		for _, section := range response["data"].(map[string]interface{})["sections"].([]interface{}) {
+			for _, item := range section.(map[string]interface{})["items"].([]interface{}) {
+				items = append(items, fmt.Sprintf("%v:%v", item.(map[string]interface{})["type"], item.(map[string]interface{})["id"]))
+			}
+		}
+
+		return items
+	}
+
+	BeforeEach(func() {
+		configuration := config.New("../../.env.test")
+
+		_, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		server = setup.ModuleSetup(configuration)
+
+		setup.Register(server, "Admin Curriculum", "admincurriculum", entity.RoleAdmin)
+		token = setup.Login(server, "admincurriculum")
+
+		_, response := serve(http.MethodPost, "/api/courses", `{"name": "Biologi","class": "X-3","tools": "Buku","about": "Sel","description": "Struktur sel"}`)
+		codeCourse = response["data"].(map[string]interface{})["code_course"].(string)
+
+		_, response = serve(http.MethodPost, "/api/courses/"+codeCourse+"/articles", `{"name": "Membran sel","content": "Membran","estimate": 10}`)
+		article = setup.ID(response)
+		_, response = serve(http.MethodPost, "/api/courses/"+codeCourse+"/submissions", `{"name": "Laporan sel","description": "Laporan","deadline": "2099-06-21"}`)
+		submission = setup.ID(response)
+		_, response = serve(http.MethodPost, "/api/courses/"+codeCourse+"/quizzes", `{"name": "Kuis sel"}`)
+		quiz = setup.ID(response)
+	})
+
+	AfterEach(func() {
+		configuration := config.New("../../.env.test")
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Get the curriculum", func() {
+		It("should list new modules in the order they were created", func() {
+			Expect(sequence()).To(Equal([]string{
+				fmt.Sprint("article:", article),
+				fmt.Sprint("submission:", submission),
+				fmt.Sprint("quiz:", quiz),
+			}))
+		})
+
+		It("should put new modules at the end of the last section", func() {
+			code, response := serve(http.MethodPost, "/api/courses/"+codeCourse+"/sections", `{"name": "Bab 1"}`)
+			Expect(code).To(Equal(http.StatusOK))
+			section := setup.ID(response)
+
+			_, response = serve(http.MethodPost, "/api/courses/"+codeCourse+"/articles", `{"name": "Inti sel","content": "Inti","estimate": 5}`)
+			second := setup.ID(response)
+
+			_, response = serve(http.MethodGet, "/api/courses/"+codeCourse+"/curriculum", "")
+			sections := response["data"].(map[string]interface{})["sections"].([]interface{})
+			Expect(sections).To(HaveLen(2))
+			Expect(sections[0].(map[string]interface{})["id"]).To(BeNil())
+			Expect(sections[1].(map[string]interface{})["id"]).To(Equal(float64(section)))
+			items := sections[1].(map[string]interface{})["items"].([]interface{})
+			Expect(items).To(HaveLen(1))
+			Expect(items[0].(map[string]interface{})["id"]).To(Equal(float64(second)))
+			Expect(items[0].(map[string]interface{})["name"]).To(Equal("Inti sel"))
+		})
+	})
+
+	Describe("Reorder the curriculum", func() {
+		It("should walk the reordered items across types and sections", func() {
+			_, response := serve(http.MethodPost, "/api/courses/"+codeCourse+"/sections", `{"name": "Bab 1"}`)
+			first := setup.ID(response)
+			_, response = serve(http.MethodPost, "/api/courses/"+codeCourse+"/sections", `{"name": "Bab 2"}`)
+			second := setup.ID(response)
+
+			code, _ := serve(http.MethodPut, "/api/courses/"+codeCourse+"/curriculum", fmt.Sprintf(`{"sections": [
+				{"id": %v, "items": [{"type": "quiz", "id": %v}]},
+				{"id": %v, "items": [{"type": "submission", "id": %v}, {"type": "article", "id": %v}]}
+			]}`, second, quiz, first, submission, article))
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(sequence()).To(Equal([]string{
+				fmt.Sprint("quiz:", quiz),
+				fmt.Sprint("submission:", submission),
+				fmt.Sprint("article:", article),
+			}))
+
+			code, response = serve(http.MethodGet, fmt.Sprintf("/api/courses/%v/curriculum/quiz/%v/next", codeCourse, quiz), "")
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(response["data"]).To(Equal(map[string]interface{}{"type": "submission", "id": float64(submission), "code_course": codeCourse}))
+
+			_, response = serve(http.MethodGet, fmt.Sprintf("/api/courses/%v/curriculum/article/%v/previous", codeCourse, article), "")
+			Expect(response["data"].(map[string]interface{})["type"]).To(Equal("submission"))
+
+			code, _ = serve(http.MethodGet, fmt.Sprintf("/api/courses/%v/curriculum/article/%v/next", codeCourse, article), "")
+			Expect(code).To(Equal(http.StatusNotFound))
+
+			code, _ = serve(http.MethodDelete, fmt.Sprintf("/api/courses/%v/sections/%v", codeCourse, first), "")
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(sequence()).To(Equal([]string{
+				fmt.Sprint("quiz:", quiz),
+				fmt.Sprint("submission:", submission),
+				fmt.Sprint("article:", article),
+			}))
+		})
+
+		It("should refuse an order that leaves out an item", func() {
+			code, _ := serve(http.MethodPut, "/api/courses/"+codeCourse+"/curriculum", fmt.Sprintf(`{"items": [{"type": "quiz", "id": %v}, {"type": "article", "id": %v}]}`, quiz, article))
+			Expect(code).To(Equal(http.StatusBadRequest))
+
+			code, _ = serve(http.MethodPut, "/api/courses/"+codeCourse+"/curriculum", fmt.Sprintf(`{"items": [{"type": "quiz", "id": %v}, {"type": "quiz", "id": %v}, {"type": "article", "id": %v}]}`, quiz, quiz, article))
+			Expect(code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should drop deleted modules from the curriculum", func() {
+			code, _ := serve(http.MethodDelete, fmt.Sprintf("/api/courses/%v/submissions/%v", codeCourse, submission), "")
+			Expect(code).To(Equal(http.StatusOK))
+
+			Expect(sequence()).To(Equal([]string{
+				fmt.Sprint("article:", article),
+				fmt.Sprint("quiz:", quiz),
+			}))
+		})
+	})
+})
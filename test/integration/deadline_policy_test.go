@@ -0,0 +1,232 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var _ = Describe("Deadline Policy", func() {
+
+	var (
+		server     *gin.Engine
+		token      string
+		codeCourse string
+		idUser     int
+	)
+
+	serve := func(method string, path string, body string) (int, map[string]interface{}) {
+		return setup.Serve(server, token, method, path, body)
+	}
+
+	deadline := func(offset time.Duration) string {
+		return time.Now().Add(offset).Format(time.RFC3339)
+	}
+
+	createSubmission := func(deadline string, policy string) int {
+		code, response := serve(http.MethodPost, "/api/courses/"+codeCourse+"/submissions", fmt.Sprintf(`{"name": "Esai","description": "Esai sejarah","deadline": %q,%v}`, deadline, policy))
+		Expect(code).To(Equal(http.StatusOK))
+
+		return int(response["data"].(map[string]interface{})["id"].(float64))
+	}
+
+	submit := func(idSubmission int) (int, map[string]interface{}) {
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("file", "esai.txt")
+		_, _ = part.Write([]byte("esai tentang proklamasi"))
+		writer.Close()
+
+		request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/courses/%v/submissions/%v/user-submit", codeCourse, idSubmission), body)
+		request.Header.Add("Content-Type", writer.FormDataContentType())
+		request.Header.Set("Authorization", token)
+
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+
+		var response map[string]interface{}
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+
+		if recorder.Code == http.StatusOK {
+			path, _ := utils.GetPath("/assets/", response["data"].(map[string]interface{})["file"].(string))
+			DeferCleanup(os.Remove, path)
+		}
+
+		return recorder.Code, response
+	}
+
+	BeforeEach(func() {
+		configuration := config.New("../../.env.test")
+
+		_, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		server = setup.ModuleSetup(configuration)
+
+		idUser = setup.Register(server, "Admin Deadline", "admindeadline", entity.RoleAdmin)
+		token = setup.Login(server, "admindeadline")
+		codeCourse, _ = setup.EnrolledCourse(server, token, idUser, `{"name": "Sejarah","class": "IPS-1","tools": "Buku","about": "Kemerdekaan","description": "Sejarah kemerdekaan"}`)
+	})
+
+	AfterEach(func() {
+		configuration := config.New("../../.env.test")
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Late policy of a module submission", func() {
+		It("should keep the policy", func() {
+			idSubmission := createSubmission(deadline(time.Hour), `"late_policy": "grace", "grace_period": 30`)
+
+			_, response := serve(http.MethodGet, fmt.Sprintf("/api/courses/%v/submissions/%v", codeCourse, idSubmission), "")
+			data := response["data"].(map[string]interface{})
+			Expect(data["late_policy"]).To(Equal("grace"))
+			Expect(data["grace_period"]).To(Equal(float64(30)))
+		})
+
+		It("should reject an unknown policy or a penalty above 100 percent", func() {
+			code, _ := serve(http.MethodPost, "/api/courses/"+codeCourse+"/submissions", `{"name": "Esai","description": "Esai","deadline": "2022-06-21","late_policy": "never"}`)
+			Expect(code).To(Equal(http.StatusBadRequest))
+
+			code, _ = serve(http.MethodPost, "/api/courses/"+codeCourse+"/submissions", `{"name": "Esai","description": "Esai","deadline": "2022-06-21","late_policy": "penalty","penalty_per_day": 101}`)
+			Expect(code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should keep the policy when an update leaves it out", func() {
+			idSubmission := createSubmission(deadline(time.Hour), `"late_policy": "penalty", "penalty_per_day": 15`)
+			path := fmt.Sprintf("/api/courses/%v/submissions/%v", codeCourse, idSubmission)
+
+			code, _ := serve(http.MethodPatch, path, fmt.Sprintf(`{"name": "Esai Baru","description": "Esai sejarah","deadline": %q}`, deadline(2*time.Hour)))
+			Expect(code).To(Equal(http.StatusOK))
+
+			_, response := serve(http.MethodGet, path, "")
+			data := response["data"].(map[string]interface{})
+			Expect(data["name"]).To(Equal("Esai Baru"))
+			Expect(data["late_policy"]).To(Equal("penalty"))
+			Expect(data["penalty_per_day"]).To(Equal(float64(15)))
+		})
+
+		It("should reject a deadline that is not a timestamp or a date", func() {
+			code, _ := serve(http.MethodPost, "/api/courses/"+codeCourse+"/submissions", `{"name": "Esai","description": "Esai","deadline": "tomorrow"}`)
+			Expect(code).To(Equal(http.StatusBadRequest))
+
+			idSubmission := createSubmission(deadline(time.Hour), `"late_policy": "accept"`)
+			code, _ = serve(http.MethodPatch, fmt.Sprintf("/api/courses/%v/submissions/%v", codeCourse, idSubmission), `{"name": "Esai","description": "Esai","deadline": "21/06/2022"}`)
+			Expect(code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("Submit after the deadline", func() {
+		It("should refuse a file once a hard closed submission is past its deadline", func() {
+			code, _ := submit(createSubmission(deadline(time.Hour), `"late_policy": "hard_close"`))
+			Expect(code).To(Equal(http.StatusOK))
+
+			code, _ = submit(createSubmission(deadline(-time.Minute), `"late_policy": "hard_close"`))
+			Expect(code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should take files until the end of the day of a date deadline", func() {
+			today := time.Now().UTC().Format("2006-01-02")
+			idSubmission := createSubmission(today, `"late_policy": "hard_close"`)
+
+			_, response := serve(http.MethodGet, fmt.Sprintf("/api/courses/%v/submissions/%v", codeCourse, idSubmission), "")
+			Expect(response["data"].(map[string]interface{})["deadline"]).To(Equal(today + "T23:59:59Z"))
+			code, _ := submit(idSubmission)
+			Expect(code).To(Equal(http.StatusOK))
+		})
+
+		It("should never close a submission without a deadline", func() {
+			idSubmission := createSubmission("", `"late_policy": "hard_close"`)
+
+			_, response := serve(http.MethodGet, fmt.Sprintf("/api/courses/%v/submissions/%v", codeCourse, idSubmission), "")
+			Expect(response["data"].(map[string]interface{})["deadline"]).To(BeEmpty())
+			code, _ := submit(idSubmission)
+			Expect(code).To(Equal(http.StatusOK))
+		})
+
+		It("should take a late file during the grace period only", func() {
+			code, _ := submit(createSubmission(deadline(-30*time.Minute), `"late_policy": "grace", "grace_period": 60`))
+			Expect(code).To(Equal(http.StatusOK))
+
+			code, _ = submit(createSubmission(deadline(-30*time.Minute), `"late_policy": "grace", "grace_period": 10`))
+			Expect(code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should take points off the grade for every day late", func() {
+			idSubmission := createSubmission(deadline(-36*time.Hour), `"late_policy": "penalty", "penalty_per_day": 10`)
+			_, response := submit(idSubmission)
+			idUserSubmission := int(response["data"].(map[string]interface{})["id"].(float64))
+
+			code, _ := serve(http.MethodPatch, fmt.Sprintf("/api/courses/%v/submissions/%v/user-submit/%v", codeCourse, idSubmission, idUserSubmission), `{"grade": 80}`)
+			Expect(code).To(Equal(http.StatusOK))
+
+			_, response = serve(http.MethodGet, fmt.Sprintf("/api/courses/%v/submissions/%v/get", codeCourse, idSubmission), "")
+			submissions := response["data"].([]interface{})
+			Expect(submissions).To(HaveLen(1))
+			teacherSubmission := submissions[0].(map[string]interface{})
+			Expect(teacherSubmission["raw_grade"]).To(Equal(float64(80)))
+			Expect(teacherSubmission["penalty"]).To(Equal(float64(16)))
+			Expect(teacherSubmission["grade"]).To(Equal(float64(64)))
+			Expect(teacherSubmission["late"]).To(BeTrue())
+
+			_, response = serve(http.MethodGet, "/api/users/submissions", "")
+			studentSubmission := response["data"].([]interface{})[0].(map[string]interface{})
+			Expect(studentSubmission["raw_grade"]).To(Equal(float64(80)))
+			Expect(studentSubmission["grade"]).To(Equal(float64(64)))
+			Expect(studentSubmission["late"]).To(BeTrue())
+		})
+	})
+
+	Describe("Extensions", func() {
+		It("should let a student submit until their own deadline", func() {
+			idSubmission := createSubmission(deadline(-time.Hour), `"late_policy": "hard_close"`)
+			pathExtension := fmt.Sprintf("/api/courses/%v/submissions/%v/extensions/%v", codeCourse, idSubmission, idUser)
+
+			code, _ := serve(http.MethodPut, pathExtension, `{"deadline": "tomorrow"}`)
+			Expect(code).To(Equal(http.StatusBadRequest))
+
+			code, _ = serve(http.MethodPut, pathExtension, fmt.Sprintf(`{"deadline": %q}`, deadline(time.Hour)))
+			Expect(code).To(Equal(http.StatusOK))
+
+			_, response := serve(http.MethodGet, fmt.Sprintf("/api/courses/%v/submissions/%v/extensions", codeCourse, idSubmission), "")
+			extensions := response["data"].([]interface{})
+			Expect(extensions).To(HaveLen(1))
+			Expect(extensions[0].(map[string]interface{})["user_id"]).To(Equal(float64(idUser)))
+
+			code, _ = submit(idSubmission)
+			Expect(code).To(Equal(http.StatusOK))
+
+			_, response = serve(http.MethodGet, fmt.Sprintf("/api/courses/%v/submissions/%v/get", codeCourse, idSubmission), "")
+			Expect(response["data"].([]interface{})[0].(map[string]interface{})["late"]).To(BeFalse())
+
+			code, _ = serve(http.MethodDelete, pathExtension, "")
+			Expect(code).To(Equal(http.StatusOK))
+
+			code, _ = submit(idSubmission)
+			Expect(code).To(Equal(http.StatusForbidden))
+		})
+	})
+})
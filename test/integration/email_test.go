@@ -0,0 +1,176 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/mail"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+)
+
+var _ = Describe("Email API", func() {
+
+	var (
+		server    *gin.Engine
+		directory string
+	)
+
+	serve := func(language string, method string, path string, body string) (int, map[string]interface{}) {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Add("Content-Type", "application/json")
+		request.Header.Set("Accept-Language", language)
+
+		writer := httptest.NewRecorder()
+		server.ServeHTTP(writer, request)
+
+		responseBody, _ := io.ReadAll(writer.Result().Body)
+		var response map[string]interface{}
+		_ = json.Unmarshal(responseBody, &response)
+
+		return writer.Result().StatusCode, response
+	}
+
+	register := func(language string, username string) {
+		user, _ := json.Marshal(model.UserRegisterResponse{Name: "Siswa " + username, Username: username, Email: username + "@gmail.com", Password: "123456ll", Role: 2, Phone: "085156789019", Gender: 1, DisabilityType: 1, Birthdate: "2004-04-01"})
+		code, _ := serve(language, http.MethodPost, "/api/users", string(user))
+		Expect(code).To(Equal(http.StatusCreated))
+	}
+
+	// mails returns the emails the file transport wrote so far
+	mails := func() []string {
+		names, _ := filepath.Glob(filepath.Join(directory, "*.eml"))
+		var contents []string
+		for _, name := range names {
+			content, _ := os.ReadFile(name)
+			contents = append(contents, string(content))
+		}
+		return contents
+	}
+
+	BeforeEach(func() {
+		var err error
+		directory, err = os.MkdirTemp("", "mails")
+		Expect(err).NotTo(HaveOccurred())
+
+		os.Setenv("MAIL_TRANSPORT", "file")
+		os.Setenv("MAIL_FILE_PATH", directory)
+		os.Setenv("MAIL_WORKER_INTERVAL_SECOND", "1")
+		os.Setenv("MAIL_RETRY_DELAY_SECOND", "1")
+		os.Setenv("MAIL_MAX_ATTEMPTS", "2")
+
+		configuration := config.New("../../.env.test")
+		_, err = setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		server = setup.ModuleSetup(configuration)
+	})
+
+	AfterEach(func() {
+		for _, key := range []string{"MAIL_TRANSPORT", "MAIL_FILE_PATH", "MAIL_WORKER_INTERVAL_SECOND", "MAIL_RETRY_DELAY_SECOND", "MAIL_MAX_ATTEMPTS"} {
+			os.Unsetenv(key)
+		}
+		_ = os.RemoveAll(directory)
+
+		configuration := config.New("../../.env.test")
+		// Replaces the worker writing to the directory
+		setup.ModuleSetup(configuration)
+
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Send emails", func() {
+		It("should send the verification email in the language of the user", func() {
+			register("id-ID,id;q=0.9,en;q=0.8", "siswasurel")
+
+			Eventually(mails, 5*time.Second, 100*time.Millisecond).Should(HaveLen(1))
+			email := mails()[0]
+			Expect(email).To(ContainSubstring("To: siswasurel@gmail.com"))
+			Expect(email).To(ContainSubstring("Subject: Verifikasi alamat email kamu"))
+			Expect(email).To(ContainSubstring("Halo Siswa siswasurel"))
+
+			signature := regexp.MustCompile(`signature=([0-9a-f]+)`).FindStringSubmatch(email)
+			Expect(signature).To(HaveLen(2))
+			code, _ := serve("", http.MethodGet, "/api/users/verify?email="+url.QueryEscape("siswasurel@gmail.com")+"&signature="+signature[1], "")
+			Expect(code).To(Equal(http.StatusOK))
+
+			register("fr-FR", "siswamail")
+			Eventually(mails, 5*time.Second, 100*time.Millisecond).Should(HaveLen(2))
+			Expect(mails()).To(ContainElement(And(ContainSubstring("To: siswamail@gmail.com"), ContainSubstring("Subject: Verify your email address"))))
+		})
+
+		It("should not write the emails to the log in production unless asked to", func() {
+			os.Unsetenv("MAIL_TRANSPORT")
+			os.Setenv("APP_ENV", "production")
+			defer os.Setenv("APP_ENV", "test")
+
+			_, err := mail.New(config.New("../../.env.test"))
+			Expect(err).To(HaveOccurred())
+
+			os.Setenv("MAIL_TRANSPORT", mail.TransportLog)
+			_, err = mail.New(config.New("../../.env.test"))
+			Expect(err).NotTo(HaveOccurred())
+
+			os.Setenv("APP_ENV", "test")
+			os.Unsetenv("MAIL_TRANSPORT")
+			_, err = mail.New(config.New("../../.env.test"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should try again later and give up after the last attempt", func() {
+			Expect(os.RemoveAll(directory)).To(Succeed())
+			Expect(os.WriteFile(directory, []byte("not a directory"), 0644)).To(Succeed())
+
+			register("", "siswagagal")
+
+			db, err := setup.SuiteSetup(config.New("../../.env.test"))
+			Expect(err).NotTo(HaveOccurred())
+			defer db.Close()
+
+			outbox := func() (string, int, string) {
+				var status, lastError string
+				var attempts int
+				_ = db.QueryRow(`SELECT status, attempts, COALESCE(last_error, '') FROM email_outbox WHERE recipient = 'siswagagal@gmail.com'`).Scan(&status, &attempts, &lastError)
+				return status, attempts, lastError
+			}
+
+			Eventually(func() int {
+				_, attempts, _ := outbox()
+				return attempts
+			}, 5*time.Second, 100*time.Millisecond).Should(Equal(1))
+			status, _, lastError := outbox()
+			Expect(status).To(Equal("pending"))
+			Expect(lastError).NotTo(BeEmpty())
+
+			Eventually(func() string {
+				status, _, _ := outbox()
+				return status
+			}, 10*time.Second, 100*time.Millisecond).Should(Equal("failed"))
+			_, attempts, _ := outbox()
+			Expect(attempts).To(Equal(2))
+		})
+	})
+})
@@ -0,0 +1,187 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+)
+
+var _ = Describe("Email Verification API", func() {
+
+	var (
+		server    *gin.Engine
+		directory string
+	)
+
+	settings := map[string]string{
+		"MAIL_TRANSPORT":                      "file",
+		"MAIL_WORKER_INTERVAL_SECOND":         "1",
+		"AUTH_REQUIRE_VERIFIED_EMAIL":         "true",
+		"VERIFICATION_URL":                    "https://belajar.example/verify",
+		"VERIFICATION_RESEND_INTERVAL_SECOND": "3600",
+		"VERIFICATION_RESEND_LIMIT":           "3",
+	}
+
+	serve := func(method string, path string, body string) *httptest.ResponseRecorder {
+		return setup.Request(server, "", method, path, body)
+	}
+
+	login := func(username string) int {
+		return serve(http.MethodPost, "/api/users/login", `{"email": "`+username+`@gmail.com", "password": "123456ll"}`).Code
+	}
+
+	resend := func(email string) (int, map[string]interface{}) {
+		writer := serve(http.MethodPost, "/api/users/verify/resend", `{"email": "`+email+`"}`)
+
+		responseBody, _ := io.ReadAll(writer.Result().Body)
+		var response map[string]interface{}
+		_ = json.Unmarshal(responseBody, &response)
+
+		return writer.Code, response
+	}
+
+	// mails returns the emails the file transport wrote so far
+	mails := func() []string {
+		names, _ := filepath.Glob(filepath.Join(directory, "*.eml"))
+		var contents []string
+		for _, name := range names {
+			content, _ := os.ReadFile(name)
+			contents = append(contents, string(content))
+		}
+		return contents
+	}
+
+	signatureOf := func(email string) string {
+		signature := regexp.MustCompile(`https://belajar\.example/verify\?email=[^&]+&(?:amp;)?signature=([0-9a-f]+)`).FindStringSubmatch(email)
+		Expect(signature).To(HaveLen(2))
+		return signature[1]
+	}
+
+	BeforeEach(func() {
+		var err error
+		directory, err = os.MkdirTemp("", "mails")
+		Expect(err).NotTo(HaveOccurred())
+
+		for key, value := range settings {
+			os.Setenv(key, value)
+		}
+		os.Setenv("MAIL_FILE_PATH", directory)
+
+		configuration := config.New("../../.env.test")
+		_, err = setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		server = setup.ModuleSetup(configuration)
+
+		setup.Register(server, "Siswa Baru", "siswabaru", entity.RoleStudent)
+	})
+
+	AfterEach(func() {
+		for key := range settings {
+			os.Unsetenv(key)
+		}
+		os.Unsetenv("MAIL_FILE_PATH")
+		_ = os.RemoveAll(directory)
+
+		configuration := config.New("../../.env.test")
+		// Replaces the worker writing to the directory
+		setup.ModuleSetup(configuration)
+
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Verify before login", func() {
+		It("should refuse the login until the email is verified", func() {
+			Expect(login("siswabaru")).To(Equal(http.StatusForbidden))
+
+			Eventually(mails, 5*time.Second, 100*time.Millisecond).Should(HaveLen(1))
+			signature := signatureOf(mails()[0])
+
+			writer := serve(http.MethodGet, "/api/users/verify?email="+url.QueryEscape("siswabaru@gmail.com")+"&signature="+signature, "")
+			Expect(writer.Code).To(Equal(http.StatusOK))
+
+			Expect(login("siswabaru")).To(Equal(http.StatusOK))
+		})
+	})
+
+	Describe("Resend verification email", func() {
+		It("should send a new link once the resend interval passed", func() {
+			Eventually(mails, 5*time.Second, 100*time.Millisecond).Should(HaveLen(1))
+			first := signatureOf(mails()[0])
+
+			// Too soon after the email of the registration
+			code, _ := resend("siswabaru@gmail.com")
+			Expect(code).To(Equal(http.StatusAccepted))
+			Consistently(mails, 2*time.Second, 200*time.Millisecond).Should(HaveLen(1))
+
+			db, err := setup.SuiteSetup(config.New("../../.env.test"))
+			Expect(err).NotTo(HaveOccurred())
+			defer db.Close()
+			_, err = db.Exec(`UPDATE email_verifications SET sent_at = 0 WHERE email = 'siswabaru@gmail.com'`)
+			Expect(err).NotTo(HaveOccurred())
+
+			code, _ = resend("siswabaru@gmail.com")
+			Expect(code).To(Equal(http.StatusAccepted))
+			Eventually(mails, 5*time.Second, 100*time.Millisecond).Should(HaveLen(2))
+
+			var second string
+			for _, email := range mails() {
+				if signature := signatureOf(email); signature != first {
+					second = signature
+				}
+			}
+			Expect(second).NotTo(BeEmpty())
+
+			writer := serve(http.MethodGet, "/api/users/verify?email="+url.QueryEscape("siswabaru@gmail.com")+"&signature="+first, "")
+			Expect(writer.Code).NotTo(Equal(http.StatusOK))
+			writer = serve(http.MethodGet, "/api/users/verify?email="+url.QueryEscape("siswabaru@gmail.com")+"&signature="+second, "")
+			Expect(writer.Code).To(Equal(http.StatusOK))
+
+			// Verified users get no more emails
+			_, err = db.Exec(`UPDATE email_verifications SET sent_at = 0 WHERE email = 'siswabaru@gmail.com'`)
+			Expect(err).NotTo(HaveOccurred())
+			code, _ = resend("siswabaru@gmail.com")
+			Expect(code).To(Equal(http.StatusAccepted))
+			Consistently(mails, 2*time.Second, 200*time.Millisecond).Should(HaveLen(2))
+		})
+
+		It("should answer unknown emails the same and limit the requests", func() {
+			code, known := resend("siswabaru@gmail.com")
+			Expect(code).To(Equal(http.StatusAccepted))
+			code, unknown := resend("siapa@gmail.com")
+			Expect(code).To(Equal(http.StatusAccepted))
+			Expect(unknown).To(Equal(known))
+
+			code, _ = resend("bukan email")
+			Expect(code).To(Equal(http.StatusBadRequest))
+
+			writer := serve(http.MethodPost, "/api/users/verify/resend", `{"email": "siswabaru@gmail.com"}`)
+			Expect(writer.Code).To(Equal(http.StatusTooManyRequests))
+			Expect(writer.Header().Get("Retry-After")).NotTo(BeEmpty())
+		})
+	})
+})
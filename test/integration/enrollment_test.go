@@ -0,0 +1,191 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+)
+
+var _ = Describe("Enrollment API", func() {
+
+	var (
+		server     *gin.Engine
+		adminToken string
+		codeCourse string
+		path       string
+	)
+
+	serve := func(token string, method string, path string, body string) (int, map[string]interface{}) {
+		return setup.Serve(server, token, method, path, body)
+	}
+
+	data := func(response map[string]interface{}) map[string]interface{} {
+		return response["data"].(map[string]interface{})
+	}
+
+	// student registers a student and returns the token of the student
+	student := func(username string) string {
+		setup.Register(server, "Siswa "+username, username, entity.RoleStudent)
+		return setup.Login(server, username)
+	}
+
+	BeforeEach(func() {
+		configuration := config.New("../../.env.test")
+
+		_, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		server = setup.ModuleSetup(configuration)
+
+		setup.Register(server, "Admin Enrollment", "adminenrollment", entity.RoleAdmin)
+		adminToken = setup.Login(server, "adminenrollment")
+
+		_, response := serve(adminToken, http.MethodPost, "/api/courses", `{"name": "Geografi","class": "X-2","tools": "Peta","about": "Bumi","description": "Lapisan bumi"}`)
+		codeCourse = data(response)["code_course"].(string)
+		path = "/api/courses/" + codeCourse + "/enrollment"
+	})
+
+	AfterEach(func() {
+		configuration := config.New("../../.env.test")
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Enroll in a course", func() {
+		It("should keep courses without settings closed", func() {
+			token := student("siswatutup")
+
+			code, response := serve(token, http.MethodGet, path, "")
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(data(response)["mode"]).To(Equal("closed"))
+			Expect(data(response)["open"]).To(BeFalse())
+			Expect(data(response)["status"]).To(Equal("none"))
+
+			code, _ = serve(token, http.MethodPost, path, "")
+			Expect(code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should check the key, waitlist students when full and promote them when a seat frees up", func() {
+			code, _ := serve(adminToken, http.MethodPut, path+"/settings", `{"mode": "key", "capacity": 1}`)
+			Expect(code).To(Equal(http.StatusBadRequest))
+			code, response := serve(adminToken, http.MethodPut, path+"/settings", `{"mode": "key", "key": "bumi-bulat", "capacity": 1}`)
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(data(response)["key"]).To(Equal("bumi-bulat"))
+
+			code, _ = serve(adminToken, http.MethodPost, "/api/courses/"+codeCourse+"/submissions", `{"name": "Peta buta","description": "Tandai gunung","deadline": "2099-06-21"}`)
+			Expect(code).To(Equal(http.StatusOK))
+
+			first := student("siswapertama")
+			second := student("siswakedua")
+
+			code, _ = serve(first, http.MethodPost, path, `{"key": "bumi-datar"}`)
+			Expect(code).To(Equal(http.StatusForbidden))
+			code, response = serve(first, http.MethodPost, path, `{"key": "bumi-bulat"}`)
+			Expect(code).To(Equal(http.StatusCreated))
+			Expect(data(response)["status"]).To(Equal("enrolled"))
+			Expect(data(response)["seats_left"]).To(Equal(float64(0)))
+
+			code, _ = serve(first, http.MethodPost, path, `{"key": "bumi-bulat"}`)
+			Expect(code).To(Equal(http.StatusConflict))
+
+			code, response = serve(second, http.MethodPost, path, `{"key": "bumi-bulat"}`)
+			Expect(code).To(Equal(http.StatusAccepted))
+			Expect(data(response)["status"]).To(Equal("waitlisted"))
+			Expect(data(response)["position"]).To(Equal(float64(1)))
+
+			_, response = serve(second, http.MethodGet, "/api/users/submissions", "")
+			Expect(response["data"]).To(BeNil())
+
+			code, _ = serve(first, http.MethodDelete, path, "")
+			Expect(code).To(Equal(http.StatusOK))
+
+			_, response = serve(second, http.MethodGet, path, "")
+			Expect(data(response)["status"]).To(Equal("enrolled"))
+
+			_, response = serve(second, http.MethodGet, "/api/users/submissions", "")
+			Expect(response["data"]).To(HaveLen(1))
+		})
+
+		It("should promote the waitlist when the capacity grows", func() {
+			serve(adminToken, http.MethodPut, path+"/settings", `{"mode": "open", "capacity": 1}`)
+
+			first := student("siswasatu")
+			second := student("siswadua")
+			serve(first, http.MethodPost, path, "")
+			serve(second, http.MethodPost, path, "")
+
+			code, response := serve(adminToken, http.MethodGet, path+"/requests", "")
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(response["data"]).To(HaveLen(1))
+
+			_, response = serve(adminToken, http.MethodPut, path+"/settings", `{"mode": "open", "capacity": 2}`)
+			Expect(data(response)["enrolled"]).To(Equal(float64(2)))
+			Expect(data(response)["waitlisted"]).To(Equal(float64(0)))
+		})
+
+		It("should only enroll between the enrollment dates", func() {
+			code, _ := serve(adminToken, http.MethodPut, path+"/settings", `{"mode": "open", "starts_at": "2030-01-01", "ends_at": "2029-01-01"}`)
+			Expect(code).To(Equal(http.StatusBadRequest))
+
+			code, _ = serve(adminToken, http.MethodPut, path+"/settings", `{"mode": "open", "ends_at": "2001-01-01"}`)
+			Expect(code).To(Equal(http.StatusOK))
+
+			code, _ = serve(student("siswatelat"), http.MethodPost, path, "")
+			Expect(code).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	Describe("Approve enrollment requests", func() {
+		It("should wait for a teacher to approve or reject the request", func() {
+			serve(adminToken, http.MethodPut, path+"/settings", `{"mode": "approval"}`)
+
+			accepted := student("siswaditerima")
+			rejected := student("siswaditolak")
+
+			code, response := serve(accepted, http.MethodPost, path, "")
+			Expect(code).To(Equal(http.StatusAccepted))
+			Expect(data(response)["status"]).To(Equal("pending"))
+			serve(rejected, http.MethodPost, path, "")
+
+			_, response = serve(adminToken, http.MethodGet, path+"/requests", "")
+			requests := response["data"].([]interface{})
+			Expect(requests).To(HaveLen(2))
+
+			ids := map[string]int{}
+			for _, request := range requests {
+				ids[request.(map[string]interface{})["username"].(string)] = int(request.(map[string]interface{})["id"].(float64))
+			}
+
+			code, response = serve(adminToken, http.MethodPost, fmt.Sprintf("%v/requests/%v/approve", path, ids["siswaditerima"]), "")
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(data(response)["status"]).To(Equal("enrolled"))
+
+			code, _ = serve(adminToken, http.MethodDelete, fmt.Sprintf("%v/requests/%v", path, ids["siswaditolak"]), "")
+			Expect(code).To(Equal(http.StatusOK))
+
+			_, response = serve(accepted, http.MethodGet, path, "")
+			Expect(data(response)["status"]).To(Equal("enrolled"))
+			_, response = serve(rejected, http.MethodGet, path, "")
+			Expect(data(response)["status"]).To(Equal("none"))
+
+			code, _ = serve(accepted, http.MethodGet, path+"/requests", "")
+			Expect(code).To(Equal(http.StatusForbidden))
+		})
+	})
+})
@@ -0,0 +1,196 @@
+package integration
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var _ = Describe("Gradebook API", func() {
+
+	var (
+		server     *gin.Engine
+		token      string
+		codeCourse string
+	)
+
+	serve := func(method string, path string, body string) (int, map[string]interface{}) {
+		return setup.Serve(server, token, method, path, body)
+	}
+
+	createCategory := func(body string) int {
+		code, response := serve(http.MethodPost, "/api/courses/"+codeCourse+"/grade-categories", body)
+		Expect(code).To(Equal(http.StatusOK))
+
+		return int(response["data"].(map[string]interface{})["id"].(float64))
+	}
+
+	// graded submits a file to a new module submission of the category and grades it
+	graded := func(categoryId int, grade int) {
+		category := "null"
+		if categoryId != 0 {
+			category = fmt.Sprint(categoryId)
+		}
+		code, response := serve(http.MethodPost, "/api/courses/"+codeCourse+"/submissions", fmt.Sprintf(`{"name": "Tugas %v","description": "Tugas","deadline": "2099-06-21","category_id": %v}`, grade, category))
+		Expect(code).To(Equal(http.StatusOK))
+		pathSubmission := fmt.Sprintf("/api/courses/%v/submissions/%v", codeCourse, int(response["data"].(map[string]interface{})["id"].(float64)))
+
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("file", "tugas.txt")
+		_, _ = part.Write([]byte(fmt.Sprintf("tugas bernilai %v", grade)))
+		writer.Close()
+
+		request := httptest.NewRequest(http.MethodPost, pathSubmission+"/user-submit", body)
+		request.Header.Add("Content-Type", writer.FormDataContentType())
+		request.Header.Set("Authorization", token)
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+		data := response["data"].(map[string]interface{})
+
+		path, _ := utils.GetPath("/assets/", data["file"].(string))
+		DeferCleanup(os.Remove, path)
+
+		code, _ = serve(http.MethodPatch, fmt.Sprintf("%v/user-submit/%v", pathSubmission, int(data["id"].(float64))), fmt.Sprintf(`{"grade": %v}`, grade))
+		Expect(code).To(Equal(http.StatusOK))
+	}
+
+	BeforeEach(func() {
+		configuration := config.New("../../.env.test")
+
+		_, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		server = setup.ModuleSetup(configuration)
+
+		idUser := setup.Register(server, "Admin Gradebook", "admingradebook", entity.RoleAdmin)
+		token = setup.Login(server, "admingradebook")
+		codeCourse, _ = setup.EnrolledCourse(server, token, idUser, `{"name": "Matematika","class": "X-1","tools": "Kalkulator","about": "Aljabar","description": "Aljabar linear"}`)
+	})
+
+	AfterEach(func() {
+		configuration := config.New("../../.env.test")
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Grade categories", func() {
+		It("should refuse weights above 100 and categories of another course", func() {
+			createCategory(`{"name": "Tugas", "weight": 60}`)
+
+			code, _ := serve(http.MethodPost, "/api/courses/"+codeCourse+"/grade-categories", `{"name": "Ujian", "weight": 50}`)
+			Expect(code).To(Equal(http.StatusBadRequest))
+
+			code, _ = serve(http.MethodPost, "/api/courses/"+codeCourse+"/submissions", `{"name": "Tugas","description": "Tugas","deadline": "2099-06-21","category_id": 999999}`)
+			Expect(code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("Get the gradebook", func() {
+		It("should weigh the categories and drop the lowest grade", func() {
+			homework := createCategory(`{"name": "Tugas", "weight": 40, "drop_lowest": 1}`)
+			exam := createCategory(`{"name": "Ujian", "weight": 60}`)
+			graded(homework, 50)
+			graded(homework, 90)
+			graded(exam, 80)
+
+			code, response := serve(http.MethodGet, "/api/courses/"+codeCourse+"/gradebook", "")
+			Expect(code).To(Equal(http.StatusOK))
+
+			data := response["data"].(map[string]interface{})
+			Expect(data["assignments"]).To(HaveLen(3))
+			Expect(data["categories"]).To(HaveLen(2))
+			student := data["students"].([]interface{})[0].(map[string]interface{})
+			grades := student["grades"].([]interface{})
+			Expect(grades[0].(map[string]interface{})["dropped"]).To(BeTrue())
+			Expect(grades[1].(map[string]interface{})["dropped"]).To(BeFalse())
+			Expect(student["score"]).To(Equal(float64(84)))
+			Expect(student["letter"]).To(Equal("B"))
+		})
+
+		It("should use the bands of the course and count module submissions without a category", func() {
+			code, _ := serve(http.MethodPut, "/api/courses/"+codeCourse+"/grade-bands", `{"bands": [{"letter": "Lulus", "min_score": 75}, {"letter": "Gagal", "min_score": 0}]}`)
+			Expect(code).To(Equal(http.StatusOK))
+			graded(0, 70)
+			graded(0, 80)
+
+			_, response := serve(http.MethodGet, "/api/courses/"+codeCourse+"/gradebook", "")
+			data := response["data"].(map[string]interface{})
+			Expect(data["categories"].([]interface{})[0].(map[string]interface{})["name"]).To(Equal("Uncategorized"))
+			student := data["students"].([]interface{})[0].(map[string]interface{})
+			Expect(student["score"]).To(Equal(float64(75)))
+			Expect(student["letter"]).To(Equal("Lulus"))
+		})
+	})
+
+	Describe("Export the gradebook", func() {
+		It("should download a csv with a row for every student", func() {
+			graded(createCategory(`{"name": "Tugas", "weight": 100}`), 95)
+
+			request := httptest.NewRequest(http.MethodGet, "/api/courses/"+codeCourse+"/gradebook/export", nil)
+			request.Header.Set("Authorization", token)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Disposition")).To(ContainSubstring("gradebook-" + codeCourse + ".csv"))
+
+			records, err := csv.NewReader(recorder.Body).ReadAll()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(2))
+			Expect(records[0]).To(Equal([]string{"Name", "Username", "Email", "Tugas 95", "Tugas", "Score", "Letter"}))
+			Expect(records[1]).To(Equal([]string{"Admin Gradebook", "admingradebook", "admingradebook@gmail.com", "95", "95", "95", "A"}))
+		})
+
+		It("should download an xlsx workbook and refuse other formats", func() {
+			graded(0, 60)
+
+			request := httptest.NewRequest(http.MethodGet, "/api/courses/"+codeCourse+"/gradebook/export?format=xlsx", nil)
+			request.Header.Set("Authorization", token)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			archive, err := zip.NewReader(bytes.NewReader(recorder.Body.Bytes()), int64(recorder.Body.Len()))
+			Expect(err).NotTo(HaveOccurred())
+			var sheet string
+			for _, file := range archive.File {
+				if file.Name == "xl/worksheets/sheet1.xml" {
+					content, _ := file.Open()
+					read, _ := io.ReadAll(content)
+					sheet = string(read)
+				}
+			}
+			Expect(sheet).To(ContainSubstring("admingradebook@gmail.com"))
+			Expect(sheet).To(ContainSubstring("<v>60</v>"))
+
+			code, _ := serve(http.MethodGet, "/api/courses/"+codeCourse+"/gradebook/export?format=pdf", "")
+			Expect(code).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
@@ -0,0 +1,202 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+)
+
+var _ = Describe("List Query API", func() {
+
+	var (
+		server    *gin.Engine
+		token     string
+		idAdmin   int
+		idCourses []int
+	)
+
+	list := func(path string) (int, map[string]interface{}) {
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		request.Header.Set("Authorization", token)
+
+		writer := httptest.NewRecorder()
+		server.ServeHTTP(writer, request)
+
+		body, _ := io.ReadAll(writer.Result().Body)
+		var responseBody map[string]interface{}
+		_ = json.Unmarshal(body, &responseBody)
+
+		return writer.Result().StatusCode, responseBody
+	}
+
+	BeforeEach(func() {
+		configuration := config.New("../../.env.test")
+
+		_, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		server = setup.ModuleSetup(configuration)
+
+		users := []model.UserRegisterResponse{
+			{Name: "Admin List", Username: "adminlist", Email: "adminlist@gmail.com", Password: "123456ll", Role: 1, Phone: "085156789011", Gender: 1, DisabilityType: 1, Birthdate: "2002-04-01"},
+			{Name: "Student List", Username: "studentlist", Email: "studentlist@gmail.com", Password: "123456ll", Role: 2, Phone: "085156789012", Gender: 1, DisabilityType: 1, Birthdate: "2002-04-01"},
+		}
+		for _, user := range users {
+			userData, _ := json.Marshal(user)
+			request := httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(string(userData)))
+			request.Header.Add("Content-Type", "application/json")
+			writer := httptest.NewRecorder()
+			server.ServeHTTP(writer, request)
+			// Users register as students, the admin gets its role afterwards
+			Expect(setup.SetRole(configuration, user.Email, user.Role)).To(Succeed())
+
+			if user.Role == 1 {
+				body, _ := io.ReadAll(writer.Result().Body)
+				var responseBodyRegister map[string]interface{}
+				_ = json.Unmarshal(body, &responseBodyRegister)
+				idAdmin = int(responseBodyRegister["data"].(map[string]interface{})["id"].(float64))
+			}
+		}
+
+		request := httptest.NewRequest(http.MethodPost, "/api/users/login", strings.NewReader(`{"email": "adminlist@gmail.com", "password": "123456ll"}`))
+		request.Header.Add("Content-Type", "application/json")
+		writer := httptest.NewRecorder()
+		server.ServeHTTP(writer, request)
+
+		body, _ := io.ReadAll(writer.Result().Body)
+		var responseBodyLogin map[string]interface{}
+		_ = json.Unmarshal(body, &responseBodyLogin)
+		token = responseBodyLogin["token"].(string)
+
+		courses := []string{
+			`{"name": "Teknik Komputer Jaringan","class": "TKJ-1","tools": "Router","about": "Jaringan","description": "Jaringan"}`,
+			`{"name": "Administrasi Server","class": "TKJ-1","tools": "Linux","about": "Server","description": "Server"}`,
+			`{"name": "Rekayasa Perangkat Lunak","class": "RPL-1","tools": "XAMPP","about": "Web","description": "Web"}`,
+		}
+		idCourses = nil
+		for _, course := range courses {
+			request := httptest.NewRequest(http.MethodPost, "/api/courses", strings.NewReader(course))
+			request.Header.Add("Content-Type", "application/json")
+			request.Header.Set("Authorization", token)
+			writer := httptest.NewRecorder()
+			server.ServeHTTP(writer, request)
+
+			body, _ := io.ReadAll(writer.Result().Body)
+			var responseBodyCourse map[string]interface{}
+			_ = json.Unmarshal(body, &responseBodyCourse)
+			idCourses = append(idCourses, int(responseBodyCourse["data"].(map[string]interface{})["id"].(float64)))
+		}
+	})
+
+	AfterEach(func() {
+		configuration := config.New("../../.env.test")
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Paginate courses", func() {
+		It("should walk every page with next_cursor and report the total", func() {
+			code, firstPage := list("/api/courses?limit=2&sort=name")
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(firstPage["total"]).To(Equal(float64(3)))
+			Expect(firstPage["data"]).To(HaveLen(2))
+			Expect(firstPage["data"].([]interface{})[0].(map[string]interface{})["name"]).To(Equal("Administrasi Server"))
+			Expect(firstPage["next_cursor"]).NotTo(BeEmpty())
+
+			code, lastPage := list("/api/courses?limit=2&sort=name&cursor=" + url.QueryEscape(firstPage["next_cursor"].(string)))
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(lastPage["data"]).To(HaveLen(1))
+			Expect(lastPage["data"].([]interface{})[0].(map[string]interface{})["name"]).To(Equal("Teknik Komputer Jaringan"))
+			Expect(lastPage).NotTo(HaveKey("next_cursor"))
+		})
+
+		It("should sort descending and filter by class", func() {
+			code, responseBody := list("/api/courses?class=TKJ-1&sort=-name")
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(responseBody["total"]).To(Equal(float64(2)))
+			Expect(responseBody["data"].([]interface{})[0].(map[string]interface{})["name"]).To(Equal("Teknik Komputer Jaringan"))
+		})
+
+		It("should filter by active state", func() {
+			code, responseBody := list("/api/courses?is_active=false")
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(responseBody["total"]).To(Equal(float64(0)))
+		})
+	})
+
+	Describe("Filter users", func() {
+		It("should only return users with the role", func() {
+			code, responseBody := list("/api/users?role=2")
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(responseBody["total"]).To(Equal(float64(1)))
+			Expect(responseBody["data"].([]interface{})[0].(map[string]interface{})["username"]).To(Equal("studentlist"))
+		})
+	})
+
+	Describe("Filter questions", func() {
+		It("should match the tag", func() {
+			questions := []model.CreateQuestionRequest{
+				{UserId: idAdmin, CourseId: idCourses[0], Title: "Subnetting", Tags: "network,ip", Description: "Cara subnetting"},
+				{UserId: idAdmin, CourseId: idCourses[0], Title: "Routing", Tags: "network", Description: "Cara routing"},
+				{UserId: idAdmin, CourseId: idCourses[2], Title: "Golang", Tags: "programming", Description: "Belajar golang"},
+			}
+			for _, question := range questions {
+				questionData, _ := json.Marshal(question)
+				request := httptest.NewRequest(http.MethodPost, "/api/questions/create", strings.NewReader(string(questionData)))
+				request.Header.Add("Content-Type", "application/json")
+				request.Header.Set("Authorization", token)
+				server.ServeHTTP(httptest.NewRecorder(), request)
+			}
+
+			code, responseBody := list("/api/questions/all?tag=Network&sort=title")
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(responseBody["total"]).To(Equal(float64(2)))
+			Expect(responseBody["data"].([]interface{})[0].(map[string]interface{})["title"]).To(Equal("Routing"))
+
+			// The wildcards of LIKE are matched as they are
+			_, responseBody = list("/api/questions/all?tag=" + url.QueryEscape("net%"))
+			Expect(responseBody["total"]).To(Equal(float64(0)))
+			_, responseBody = list("/api/questions/all?tag=n_twork")
+			Expect(responseBody["total"]).To(Equal(float64(0)))
+		})
+	})
+
+	Describe("Reject invalid queries", func() {
+		It("should answer 400 for fields that are not whitelisted or malformed values", func() {
+			code, _ := list("/api/courses?sort=password")
+			Expect(code).To(Equal(http.StatusBadRequest))
+
+			code, _ = list("/api/users?role=admin")
+			Expect(code).To(Equal(http.StatusBadRequest))
+
+			code, _ = list("/api/courses?titel=TKJ")
+			Expect(code).To(Equal(http.StatusBadRequest))
+
+			code, _ = list("/api/answers/all?cursor=not-a-cursor")
+			Expect(code).To(Equal(http.StatusBadRequest))
+
+			code, _ = list("/api/usercourse?limit=0")
+			Expect(code).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
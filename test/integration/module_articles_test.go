@@ -60,6 +60,12 @@ var _ = Describe("Module Articles API", func() {
 		writer := httptest.NewRecorder()
 		server.ServeHTTP(writer, request)
 
+		// Users register as students, the admin gets its role afterwards
+		err = setup.SetRole(configuration, user.Email, user.Role)
+		if err != nil {
+			panic(err)
+		}
+
 		//Login User
 		userData, _ = json.Marshal(login)
 		requestBody = strings.NewReader(string(userData))
@@ -73,6 +73,12 @@ var _ = Describe("Module Submissions API", func() {
 		idUser = int(responseBodyRegister["data"].(map[string]interface{})["id"].(float64))
 		NameUser = responseBodyRegister["data"].(map[string]interface{})["name"].(string)
 
+		// Users register as students, the admin gets its role afterwards
+		err = setup.SetRole(configuration, user.Email, user.Role)
+		if err != nil {
+			panic(err)
+		}
+
 		//Login User
 		userData, _ = json.Marshal(login)
 		requestBody = strings.NewReader(string(userData))
@@ -0,0 +1,162 @@
+package integration
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+)
+
+var _ = Describe("Password Reset API", func() {
+
+	var (
+		server    *gin.Engine
+		directory string
+	)
+
+	settings := map[string]string{
+		"MAIL_TRANSPORT":              "file",
+		"MAIL_WORKER_INTERVAL_SECOND": "1",
+		"PASSWORD_RESET_URL":          "https://belajar.example/reset",
+		"PASSWORD_RESET_LIMIT":        "3",
+	}
+
+	serve := func(method string, path string, token string, body string) (int, map[string]interface{}) {
+		return setup.Serve(server, token, method, path, body)
+	}
+
+	login := func(password string) (int, map[string]interface{}) {
+		return serve(http.MethodPost, "/api/users/login", "", `{"email": "siswalupa@gmail.com", "password": "`+password+`"}`)
+	}
+
+	forgot := func(email string) (int, map[string]interface{}) {
+		return serve(http.MethodPost, "/api/users/password/forgot", "", `{"email": "`+email+`"}`)
+	}
+
+	reset := func(token string, password string) int {
+		status, _ := serve(http.MethodPost, "/api/users/password/reset", "", `{"token": "`+token+`", "password": "`+password+`"}`)
+		return status
+	}
+
+	// tokens returns the tokens of the reset emails the file transport wrote so far, oldest first
+	tokens := func() []string {
+		names, _ := filepath.Glob(filepath.Join(directory, "*.eml"))
+		var found []string
+		for _, name := range names {
+			content, _ := os.ReadFile(name)
+			if !strings.Contains(string(content), "Subject: Reset your password") {
+				continue
+			}
+			token := regexp.MustCompile(`https://belajar\.example/reset\?token=([0-9a-f]+)`).FindStringSubmatch(string(content))
+			Expect(token).To(HaveLen(2))
+			found = append(found, token[1])
+		}
+		return found
+	}
+
+	BeforeEach(func() {
+		var err error
+		directory, err = os.MkdirTemp("", "mails")
+		Expect(err).NotTo(HaveOccurred())
+
+		for key, value := range settings {
+			os.Setenv(key, value)
+		}
+		os.Setenv("MAIL_FILE_PATH", directory)
+
+		configuration := config.New("../../.env.test")
+		_, err = setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		server = setup.ModuleSetup(configuration)
+
+		setup.Register(server, "Siswa Lupa", "siswalupa", entity.RoleStudent)
+	})
+
+	AfterEach(func() {
+		for key := range settings {
+			os.Unsetenv(key)
+		}
+		os.Unsetenv("MAIL_FILE_PATH")
+		_ = os.RemoveAll(directory)
+
+		configuration := config.New("../../.env.test")
+		// Replaces the worker writing to the directory
+		setup.ModuleSetup(configuration)
+
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Reset password", func() {
+		It("should set the new password once and log the user out everywhere", func() {
+			code, session := login("123456ll")
+			Expect(code).To(Equal(http.StatusOK))
+
+			code, known := forgot("siswalupa@gmail.com")
+			Expect(code).To(Equal(http.StatusAccepted))
+			code, unknown := forgot("siapa@gmail.com")
+			Expect(code).To(Equal(http.StatusAccepted))
+			Expect(unknown).To(Equal(known))
+
+			Eventually(tokens, 5*time.Second, 100*time.Millisecond).Should(HaveLen(1))
+			token := tokens()[0]
+
+			Expect(reset(token, "pendek")).To(Equal(http.StatusBadRequest))
+			Expect(reset("bukantoken", "rahasiabaru")).To(Equal(http.StatusBadRequest))
+			Expect(reset(token, "rahasiabaru")).To(Equal(http.StatusOK))
+			Expect(reset(token, "rahasialagi")).To(Equal(http.StatusBadRequest))
+
+			status, _ := serve(http.MethodGet, "/api/userstatus", session["token"].(string), "")
+			Expect(status).To(Equal(http.StatusUnauthorized))
+			status, _ = serve(http.MethodPost, "/api/users/token/refresh", "", `{"refresh_token": "`+session["refresh_token"].(string)+`"}`)
+			Expect(status).To(Equal(http.StatusUnauthorized))
+
+			code, _ = login("123456ll")
+			Expect(code).To(Equal(http.StatusBadRequest))
+			code, _ = login("rahasiabaru")
+			Expect(code).To(Equal(http.StatusOK))
+		})
+
+		It("should refuse expired and replaced links", func() {
+			db, err := setup.SuiteSetup(config.New("../../.env.test"))
+			Expect(err).NotTo(HaveOccurred())
+			defer db.Close()
+
+			forgot("siswalupa@gmail.com")
+			Eventually(tokens, 5*time.Second, 100*time.Millisecond).Should(HaveLen(1))
+			_, err = db.Exec(`UPDATE password_resets SET expired_at = 0`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reset(tokens()[0], "rahasiabaru")).To(Equal(http.StatusBadRequest))
+
+			forgot("siswalupa@gmail.com")
+			Eventually(tokens, 5*time.Second, 100*time.Millisecond).Should(HaveLen(2))
+			forgot("siswalupa@gmail.com")
+			Eventually(tokens, 5*time.Second, 100*time.Millisecond).Should(HaveLen(3))
+			Expect(reset(tokens()[2], "rahasiabaru")).To(Equal(http.StatusOK))
+			Expect(reset(tokens()[1], "rahasialagi")).To(Equal(http.StatusBadRequest))
+
+			code, _ := forgot("siswalupa@gmail.com")
+			Expect(code).To(Equal(http.StatusTooManyRequests))
+		})
+	})
+})
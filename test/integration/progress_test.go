@@ -0,0 +1,136 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+)
+
+var _ = Describe("Progress API", func() {
+
+	var (
+		server     *gin.Engine
+		token      string
+		codeCourse string
+		idCourse   int
+		reading    int
+		exercise   int
+		quiz       int
+	)
+
+	serve := func(method string, path string, body string) (int, map[string]interface{}) {
+		return setup.Serve(server, token, method, path, body)
+	}
+
+	BeforeEach(func() {
+		configuration := config.New("../../.env.test")
+
+		_, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		server = setup.ModuleSetup(configuration)
+
+		idUser := setup.Register(server, "Admin Progress", "adminprogress", entity.RoleAdmin)
+		token = setup.Login(server, "adminprogress")
+		codeCourse, idCourse = setup.EnrolledCourse(server, token, idUser, `{"name": "Kimia","class": "XI-1","tools": "Buku","about": "Atom","description": "Struktur atom"}`)
+
+		_, response := serve(http.MethodPost, "/api/courses/"+codeCourse+"/articles", `{"name": "Model atom","content": "Dalton","estimate": 15}`)
+		reading = setup.ID(response)
+		_, response = serve(http.MethodPost, "/api/courses/"+codeCourse+"/articles", `{"name": "Konfigurasi elektron","content": "Aufbau","estimate": 25}`)
+		exercise = setup.ID(response)
+		_, response = serve(http.MethodPost, "/api/courses/"+codeCourse+"/quizzes", `{"name": "Kuis atom", "questions": [{"type": "true_false", "prompt": "Atom bermuatan netral", "answers": ["true"]}]}`)
+		quiz = setup.ID(response)
+	})
+
+	AfterEach(func() {
+		configuration := config.New("../../.env.test")
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Track the progress of a student", func() {
+		It("should count opened articles as visited and completed items as done", func() {
+			serve(http.MethodGet, fmt.Sprintf("/api/courses/%v/articles/%v", codeCourse, reading), "")
+			serve(http.MethodGet, fmt.Sprintf("/api/courses/%v/articles/%v", codeCourse, reading), "")
+
+			code, response := serve(http.MethodGet, "/api/courses/"+codeCourse+"/progress", "")
+			Expect(code).To(Equal(http.StatusOK))
+			progress := response["data"].(map[string]interface{})
+			Expect(progress["total"]).To(Equal(float64(3)))
+			Expect(progress["progress"]).To(Equal(float64(0)))
+			Expect(progress["time_remaining"]).To(Equal(float64(40)))
+			Expect(progress["last_visited"].(map[string]interface{})["id"]).To(Equal(float64(reading)))
+			Expect(progress["items"].([]interface{})[0].(map[string]interface{})["opens"]).To(Equal(float64(2)))
+
+			code, response = serve(http.MethodPost, fmt.Sprintf("/api/courses/%v/articles/%v/complete", codeCourse, exercise), "")
+			Expect(code).To(Equal(http.StatusOK))
+			progress = response["data"].(map[string]interface{})
+			Expect(progress["completed"]).To(Equal(float64(1)))
+			Expect(progress["progress"]).To(Equal(float64(33)))
+			Expect(progress["time_remaining"]).To(Equal(float64(15)))
+			Expect(progress["last_visited"].(map[string]interface{})["name"]).To(Equal("Konfigurasi elektron"))
+
+			_, response = serve(http.MethodPost, fmt.Sprintf("/api/courses/%v/quizzes/%v/attempts", codeCourse, quiz), "")
+			code, _ = serve(http.MethodPost, fmt.Sprintf("/api/courses/%v/quizzes/%v/attempts/%v/submit", codeCourse, quiz, setup.ID(response)), `{"answers": []}`)
+			Expect(code).To(Equal(http.StatusOK))
+
+			_, response = serve(http.MethodGet, "/api/usercourse/courses", "")
+			courses := response["data"].([]interface{})
+			Expect(courses).To(HaveLen(1))
+			Expect(courses[0].(map[string]interface{})["progress"]).To(Equal(float64(66)))
+			Expect(courses[0].(map[string]interface{})["time_remaining"]).To(Equal(float64(15)))
+			Expect(courses[0].(map[string]interface{})["last_visited"].(map[string]interface{})["id"]).To(Equal(float64(exercise)))
+		})
+
+		It("should refuse articles outside the course and users not enrolled", func() {
+			code, _ := serve(http.MethodPost, fmt.Sprintf("/api/courses/%v/articles/%v/complete", codeCourse, exercise+100), "")
+			Expect(code).To(Equal(http.StatusNotFound))
+
+			_, response := serve(http.MethodPost, "/api/courses", `{"name": "Fisika","class": "XI-1","tools": "Buku","about": "Gerak","description": "Gerak lurus"}`)
+			other := response["data"].(map[string]interface{})["code_course"].(string)
+
+			code, _ = serve(http.MethodGet, "/api/courses/"+other+"/progress", "")
+			Expect(code).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	Describe("Show the progress of the roster", func() {
+		It("should list every enrolled student with their progress", func() {
+			user, _ := json.Marshal(model.UserRegisterResponse{Name: "Student Progress", Username: "stuprog", Email: "studentprogress@gmail.com", Password: "123456ll", Role: 2, Phone: "085156789014", Gender: 1, DisabilityType: 1, Birthdate: "2002-04-01"})
+			_, response := serve(http.MethodPost, "/api/users", string(user))
+			serve(http.MethodPost, "/api/usercourse", fmt.Sprintf(`{"user_id":%v,"course_id":%v}`, setup.ID(response), idCourse))
+
+			serve(http.MethodPost, fmt.Sprintf("/api/courses/%v/articles/%v/complete", codeCourse, reading), "")
+
+			code, response := serve(http.MethodGet, "/api/courses/"+codeCourse+"/progress/students", "")
+			Expect(code).To(Equal(http.StatusOK))
+			students := response["data"].([]interface{})
+			Expect(students).To(HaveLen(2))
+
+			progress := map[string]float64{}
+			for _, student := range students {
+				progress[student.(map[string]interface{})["username"].(string)] = student.(map[string]interface{})["progress"].(float64)
+				Expect(student.(map[string]interface{})).NotTo(HaveKey("items"))
+			}
+			Expect(progress).To(Equal(map[string]float64{"adminprogress": 33, "stuprog": 0}))
+		})
+	})
+})
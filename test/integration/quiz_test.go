@@ -0,0 +1,242 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+)
+
+var _ = Describe("Quiz API", func() {
+
+	var (
+		server     *gin.Engine
+		token      string
+		codeCourse string
+		idCourse   int
+	)
+
+	quiz := `{"name": "Kuis Fisika", "time_limit": 30, "max_attempts": %v, "shuffle_questions": true, "questions": [
+		{"type": "multiple_choice", "prompt": "Satuan gaya", "points": 2, "choices": [{"content": "Newton", "correct": true}, {"content": "Joule"}]},
+		{"type": "multiple_select", "prompt": "Besaran vektor", "points": 2, "choices": [{"content": "Gaya", "correct": true}, {"content": "Kecepatan", "correct": true}, {"content": "Massa"}]},
+		{"type": "true_false", "prompt": "Massa adalah besaran vektor", "answers": ["false"]},
+		{"type": "short_answer", "prompt": "Hukum gerak pertama", "points": 3, "answers": ["hukum inersia", "Hukum Newton I"]},
+		{"type": "numeric", "prompt": "Percepatan gravitasi", "points": 2, "numeric_answer": 9.8, "tolerance": 0.1}
+	]}`
+
+	serve := func(method string, path string, body string) (int, map[string]interface{}) {
+		return setup.Serve(server, token, method, path, body)
+	}
+
+	createQuiz := func(maxAttempts int) string {
+		code, response := serve(http.MethodPost, "/api/courses/"+codeCourse+"/quizzes", fmt.Sprintf(quiz, maxAttempts))
+		Expect(code).To(Equal(http.StatusOK))
+
+		return fmt.Sprintf("/api/courses/%v/quizzes/%v", codeCourse, int(response["data"].(map[string]interface{})["id"].(float64)))
+	}
+
+	// answers answers every question of the attempt, the multiple choice questions with the choice named by right
+	answers := func(attempt map[string]interface{}, right map[string][]string, texts map[string]string) string {
+		var list []string
+		for _, item := range attempt["questions"].([]interface{}) {
+			question := item.(map[string]interface{})
+			var ids []string
+			for _, choice := range question["choices"].([]interface{}) {
+				for _, content := range right[question["prompt"].(string)] {
+					if choice.(map[string]interface{})["content"] == content {
+						ids = append(ids, fmt.Sprint(choice.(map[string]interface{})["id"]))
+					}
+				}
+			}
+			list = append(list, fmt.Sprintf(`{"question_id": %v, "choice_ids": [%v], "text": %q}`, question["id"], strings.Join(ids, ","), texts[question["prompt"].(string)]))
+		}
+
+		return `{"answers": [` + strings.Join(list, ",") + `]}`
+	}
+
+	BeforeEach(func() {
+		configuration := config.New("../../.env.test")
+
+		_, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		server = setup.ModuleSetup(configuration)
+
+		idUser := setup.Register(server, "Admin Quiz", "adminquiz", entity.RoleAdmin)
+		token = setup.Login(server, "adminquiz")
+		codeCourse, idCourse = setup.EnrolledCourse(server, token, idUser, `{"name": "Fisika","class": "X-2","tools": "Buku","about": "Mekanika","description": "Hukum Newton"}`)
+	})
+
+	AfterEach(func() {
+		configuration := config.New("../../.env.test")
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Create a quiz", func() {
+		It("should keep the questions with their answers", func() {
+			path := createQuiz(0)
+
+			code, response := serve(http.MethodGet, path, "")
+			Expect(code).To(Equal(http.StatusOK))
+			data := response["data"].(map[string]interface{})
+			Expect(data["max_score"]).To(Equal(float64(10)))
+			questions := data["questions"].([]interface{})
+			Expect(questions).To(HaveLen(5))
+			Expect(questions[2].(map[string]interface{})["choices"]).To(HaveLen(2))
+			Expect(questions[3].(map[string]interface{})["answers"]).To(HaveLen(2))
+			Expect(questions[3].(map[string]interface{})["choices"]).To(BeEmpty())
+			Expect(questions[4].(map[string]interface{})["numeric_answer"]).To(Equal(9.8))
+		})
+
+		It("should refuse questions that can not be graded", func() {
+			code, _ := serve(http.MethodPost, "/api/courses/"+codeCourse+"/quizzes", `{"name": "Kuis", "questions": [{"type": "multiple_choice", "prompt": "Satuan gaya", "choices": [{"content": "Newton", "correct": true}, {"content": "Dyne", "correct": true}]}]}`)
+			Expect(code).To(Equal(http.StatusBadRequest))
+
+			code, _ = serve(http.MethodPost, "/api/courses/"+codeCourse+"/quizzes", `{"name": "Kuis", "questions": [{"type": "essay", "prompt": "Jelaskan"}]}`)
+			Expect(code).To(Equal(http.StatusBadRequest))
+
+			code, _ = serve(http.MethodPost, "/api/courses/"+codeCourse+"/quizzes", `{"name": "Kuis", "questions": [{"type": "numeric", "prompt": "Gravitasi"}]}`)
+			Expect(code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("Take a quiz", func() {
+		It("should hide the answers and grade every question type on submit", func() {
+			path := createQuiz(0)
+
+			code, response := serve(http.MethodPost, path+"/attempts", "")
+			Expect(code).To(Equal(http.StatusOK))
+			attempt := response["data"].(map[string]interface{})
+			Expect(attempt["deadline"]).NotTo(BeNil())
+			Expect(attempt["questions"]).To(HaveLen(5))
+			for _, question := range attempt["questions"].([]interface{}) {
+				Expect(question.(map[string]interface{})).NotTo(HaveKey("answers"))
+				Expect(question.(map[string]interface{})).NotTo(HaveKey("numeric_answer"))
+				for _, choice := range question.(map[string]interface{})["choices"].([]interface{}) {
+					Expect(choice.(map[string]interface{})).NotTo(HaveKey("correct"))
+				}
+			}
+
+			_, response = serve(http.MethodPost, path+"/attempts", "")
+			Expect(response["data"].(map[string]interface{})["id"]).To(Equal(attempt["id"]))
+
+			body := answers(attempt, map[string][]string{
+				"Satuan gaya":                 {"Newton"},
+				"Besaran vektor":              {"Gaya", "Kecepatan"},
+				"Massa adalah besaran vektor": {"True"},
+			}, map[string]string{
+				"Hukum gerak pertama":  "  hukum   NEWTON i ",
+				"Percepatan gravitasi": "9,75",
+			})
+			code, response = serve(http.MethodPost, fmt.Sprintf("%v/attempts/%v/submit", path, attempt["id"]), body)
+			Expect(code).To(Equal(http.StatusOK))
+			graded := response["data"].(map[string]interface{})
+			Expect(graded["score"]).To(Equal(float64(9)))
+			Expect(graded["max_score"]).To(Equal(float64(10)))
+			Expect(graded["results"]).To(HaveLen(5))
+
+			code, _ = serve(http.MethodPost, fmt.Sprintf("%v/attempts/%v/submit", path, attempt["id"]), body)
+			Expect(code).To(Equal(http.StatusConflict))
+
+			_, response = serve(http.MethodGet, path+"/results", "")
+			results := response["data"].([]interface{})
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].(map[string]interface{})["grade"]).To(Equal(float64(90)))
+			Expect(results[0].(map[string]interface{})["graded_attempt_id"]).To(Equal(attempt["id"]))
+			Expect(results[0].(map[string]interface{})["attempts"]).To(Equal(float64(1)))
+		})
+
+		It("should stop at the attempt limit and keep the questions once started", func() {
+			path := createQuiz(1)
+
+			_, response := serve(http.MethodPost, path+"/attempts", "")
+			attempt := response["data"].(map[string]interface{})
+			code, _ := serve(http.MethodPost, fmt.Sprintf("%v/attempts/%v/submit", path, attempt["id"]), `{"answers": []}`)
+			Expect(code).To(Equal(http.StatusOK))
+
+			code, _ = serve(http.MethodPost, path+"/attempts", "")
+			Expect(code).To(Equal(http.StatusForbidden))
+
+			_, response = serve(http.MethodGet, path+"/attempts", "")
+			attempts := response["data"].([]interface{})
+			Expect(attempts).To(HaveLen(1))
+			Expect(attempts[0].(map[string]interface{})["score"]).To(Equal(float64(0)))
+
+			code, _ = serve(http.MethodPatch, path, `{"name": "Kuis Fisika", "questions": [{"type": "true_false", "prompt": "Gaya adalah besaran vektor", "answers": ["true"]}]}`)
+			Expect(code).To(Equal(http.StatusConflict))
+
+			code, response = serve(http.MethodPatch, path, `{"name": "Kuis Fisika Dasar", "max_attempts": 2}`)
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(response["data"].(map[string]interface{})["questions"]).To(HaveLen(5))
+		})
+		It("should refuse an answer to an unknown question without keeping the other answers", func() {
+			path := createQuiz(0)
+
+			_, response := serve(http.MethodPost, path+"/attempts", "")
+			attempt := response["data"].(map[string]interface{})
+			body := answers(attempt, map[string][]string{"Satuan gaya": {"Newton"}}, nil)
+			unknown := strings.Replace(body, `{"answers": [`, `{"answers": [{"question_id": 999999, "choice_ids": [], "text": ""},`, 1)
+
+			code, _ := serve(http.MethodPost, fmt.Sprintf("%v/attempts/%v/submit", path, attempt["id"]), unknown)
+			Expect(code).To(Equal(http.StatusBadRequest))
+
+			_, response = serve(http.MethodPost, path+"/attempts", "")
+			Expect(response["data"].(map[string]interface{})["id"]).To(Equal(attempt["id"]))
+			code, response = serve(http.MethodPost, fmt.Sprintf("%v/attempts/%v/submit", path, attempt["id"]), body)
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(response["data"].(map[string]interface{})["score"]).To(Equal(float64(2)))
+		})
+	})
+
+	Describe("Delete the owners of a quiz", func() {
+		It("should delete a user with an attempt and a course with a quiz", func() {
+			path := createQuiz(0)
+
+			idStudent := setup.Register(server, "Siswa Quiz", "siswaquiz", entity.RoleStudent)
+			code, _ := serve(http.MethodPost, "/api/usercourse", fmt.Sprintf(`{"user_id": %v, "course_id": %v}`, idStudent, idCourse))
+			Expect(code).To(Equal(http.StatusCreated))
+			code, _ = setup.Serve(server, setup.Login(server, "siswaquiz"), http.MethodPost, path+"/attempts", "")
+			Expect(code).To(Equal(http.StatusOK))
+
+			code, _ = serve(http.MethodDelete, fmt.Sprintf("/api/users/%v", idStudent), "")
+			Expect(code).To(Equal(http.StatusOK))
+
+			code, _ = serve(http.MethodDelete, "/api/courses/"+codeCourse, "")
+			Expect(code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Describe("Navigate between quizzes", func() {
+		It("should go to the next and the previous quiz of the course", func() {
+			first := createQuiz(0)
+			second := createQuiz(0)
+
+			code, response := serve(http.MethodGet, first+"/next", "")
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(second).To(HaveSuffix(fmt.Sprint(response["data"].(map[string]interface{})["id"])))
+
+			_, response = serve(http.MethodGet, second+"/previous", "")
+			Expect(first).To(HaveSuffix(fmt.Sprint(response["data"].(map[string]interface{})["id"])))
+
+			code, _ = serve(http.MethodGet, second+"/next", "")
+			Expect(code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+})
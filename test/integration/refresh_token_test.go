@@ -0,0 +1,160 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+)
+
+var _ = Describe("Refresh Token API", func() {
+
+	var (
+		server       *gin.Engine
+		token        string
+		refreshToken string
+	)
+
+	refresh := func(token string) map[string]interface{} {
+		requestData, _ := json.Marshal(model.RefreshTokenRequest{RefreshToken: token})
+		request := httptest.NewRequest(http.MethodPost, "/api/users/token/refresh", strings.NewReader(string(requestData)))
+		request.Header.Add("Content-Type", "application/json")
+
+		writer := httptest.NewRecorder()
+		server.ServeHTTP(writer, request)
+
+		body, _ := io.ReadAll(writer.Result().Body)
+		var responseBody map[string]interface{}
+		_ = json.Unmarshal(body, &responseBody)
+
+		return responseBody
+	}
+
+	BeforeEach(func() {
+		configuration := config.New("../../.env.test")
+
+		_, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		router := setup.ModuleSetup(configuration)
+		server = router
+
+		// Register User
+		userData, _ := json.Marshal(model.UserRegisterResponse{
+			Name:           "refresh",
+			Username:       "refresh",
+			Email:          "refresh@gmail.com",
+			Password:       "123456ll",
+			Role:           2,
+			Phone:          "085156789011",
+			Gender:         1,
+			DisabilityType: 1,
+			Birthdate:      "2002-04-01",
+		})
+		request := httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(string(userData)))
+		request.Header.Add("Content-Type", "application/json")
+
+		writer := httptest.NewRecorder()
+		server.ServeHTTP(writer, request)
+
+		// Login User
+		userLogin, _ := json.Marshal(model.GetUserLogin{
+			Email:    "refresh@gmail.com",
+			Password: "123456ll",
+		})
+		request = httptest.NewRequest(http.MethodPost, "/api/users/login", strings.NewReader(string(userLogin)))
+		request.Header.Add("Content-Type", "application/json")
+
+		writer = httptest.NewRecorder()
+		server.ServeHTTP(writer, request)
+
+		body, _ := io.ReadAll(writer.Result().Body)
+		var responseBody map[string]interface{}
+		_ = json.Unmarshal(body, &responseBody)
+
+		token = responseBody["token"].(string)
+		refreshToken = responseBody["refresh_token"].(string)
+	})
+
+	AfterEach(func() {
+		configuration := config.New("../../.env.test")
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Refresh Token", func() {
+		When("the refresh token is used once", func() {
+			It("should return a new token pair", func() {
+				responseBody := refresh(refreshToken)
+
+				Expect(int(responseBody["code"].(float64))).To(Equal(http.StatusOK))
+				Expect(responseBody["status"]).To(Equal("Token Refreshed"))
+				Expect(responseBody["token"]).NotTo(BeEmpty())
+				Expect(responseBody["refresh_token"]).NotTo(Equal(refreshToken))
+
+				request := httptest.NewRequest(http.MethodGet, "/api/userstatus", nil)
+				request.Header.Set("Authorization", responseBody["token"].(string))
+
+				writer := httptest.NewRecorder()
+				server.ServeHTTP(writer, request)
+
+				Expect(writer.Result().StatusCode).To(Equal(http.StatusOK))
+			})
+		})
+
+		When("the refresh token is used twice", func() {
+			It("should revoke the whole family", func() {
+				rotated := refresh(refreshToken)
+				Expect(int(rotated["code"].(float64))).To(Equal(http.StatusOK))
+
+				reused := refresh(refreshToken)
+				Expect(int(reused["code"].(float64))).To(Equal(http.StatusUnauthorized))
+
+				afterReuse := refresh(rotated["refresh_token"].(string))
+				Expect(int(afterReuse["code"].(float64))).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		When("the user logged out with the refresh token", func() {
+			It("should return unauthorized", func() {
+				request := httptest.NewRequest(http.MethodPost, "/api/users/logout", strings.NewReader(`{"refresh_token": "`+refreshToken+`"}`))
+				request.Header.Add("Content-Type", "application/json")
+				request.Header.Set("Authorization", token)
+
+				writer := httptest.NewRecorder()
+				server.ServeHTTP(writer, request)
+				Expect(writer.Result().StatusCode).To(Equal(http.StatusOK))
+
+				responseBody := refresh(refreshToken)
+
+				Expect(int(responseBody["code"].(float64))).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		When("the refresh token is unknown", func() {
+			It("should return unauthorized", func() {
+				responseBody := refresh("unknown")
+
+				Expect(int(responseBody["code"].(float64))).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+})
@@ -0,0 +1,191 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var _ = Describe("Rubric API", func() {
+
+	var (
+		server           *gin.Engine
+		token            string
+		pathSubmission   string
+		idUserSubmission int
+	)
+
+	rubric := `{"criteria": [
+		{"name": "Isi", "description": "Ketepatan isi", "levels": [{"name": "Kurang", "points": 10}, {"name": "Baik", "points": 40}]},
+		{"name": "Bahasa", "levels": [{"name": "Kurang", "points": 5}, {"name": "Baik", "points": 20}]}
+	]}`
+
+	serve := func(method string, path string, body string) (int, map[string]interface{}) {
+		return setup.Serve(server, token, method, path, body)
+	}
+
+	// levels returns the ids of the levels of every criterion of the rubric, by criterion and level name
+	levels := func() (map[string]int, map[string]map[string]int) {
+		_, response := serve(http.MethodGet, pathSubmission+"/rubric", "")
+		criteria := map[string]int{}
+		levels := map[string]map[string]int{}
+		for _, item := range response["data"].(map[string]interface{})["criteria"].([]interface{}) {
+			criterion := item.(map[string]interface{})
+			name := criterion["name"].(string)
+			criteria[name] = int(criterion["id"].(float64))
+			levels[name] = map[string]int{}
+			for _, level := range criterion["levels"].([]interface{}) {
+				levels[name][level.(map[string]interface{})["name"].(string)] = int(level.(map[string]interface{})["id"].(float64))
+			}
+		}
+
+		return criteria, levels
+	}
+
+	BeforeEach(func() {
+		configuration := config.New("../../.env.test")
+
+		_, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		server = setup.ModuleSetup(configuration)
+
+		idUser := setup.Register(server, "Admin Rubric", "adminrubric", entity.RoleAdmin)
+		token = setup.Login(server, "adminrubric")
+		codeCourse, _ := setup.EnrolledCourse(server, token, idUser, `{"name": "Bahasa Indonesia","class": "XI-2","tools": "Buku","about": "Menulis","description": "Menulis esai"}`)
+
+		_, response := serve(http.MethodPost, "/api/courses/"+codeCourse+"/submissions", `{"name": "Esai","description": "Esai argumentasi","deadline": "2099-06-21"}`)
+		pathSubmission = fmt.Sprintf("/api/courses/%v/submissions/%v", codeCourse, int(response["data"].(map[string]interface{})["id"].(float64)))
+
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("file", "esai.txt")
+		_, _ = part.Write([]byte("esai argumentasi"))
+		writer.Close()
+
+		request := httptest.NewRequest(http.MethodPost, pathSubmission+"/user-submit", body)
+		request.Header.Add("Content-Type", writer.FormDataContentType())
+		request.Header.Set("Authorization", token)
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+		data := response["data"].(map[string]interface{})
+		idUserSubmission = int(data["id"].(float64))
+
+		path, _ := utils.GetPath("/assets/", data["file"].(string))
+		DeferCleanup(os.Remove, path)
+	})
+
+	AfterEach(func() {
+		configuration := config.New("../../.env.test")
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Save a rubric", func() {
+		It("should keep the criteria in order with their levels", func() {
+			code, response := serve(http.MethodPut, pathSubmission+"/rubric", rubric)
+			Expect(code).To(Equal(http.StatusOK))
+
+			data := response["data"].(map[string]interface{})
+			Expect(data["max_points"]).To(Equal(float64(60)))
+			criteria := data["criteria"].([]interface{})
+			Expect(criteria).To(HaveLen(2))
+			Expect(criteria[0].(map[string]interface{})["name"]).To(Equal("Isi"))
+			Expect(criteria[0].(map[string]interface{})["max_points"]).To(Equal(float64(40)))
+			Expect(criteria[1].(map[string]interface{})["levels"]).To(HaveLen(2))
+		})
+
+		It("should refuse a criterion without levels", func() {
+			code, _ := serve(http.MethodPut, pathSubmission+"/rubric", `{"criteria": [{"name": "Isi", "levels": []}]}`)
+			Expect(code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("Grade with the rubric", func() {
+		It("should compute the grade and show the breakdown to the student", func() {
+			serve(http.MethodPut, pathSubmission+"/rubric", rubric)
+			criteria, levels := levels()
+
+			scores := fmt.Sprintf(`{"feedback": "Argumen kuat", "scores": [
+				{"criterion_id": %v, "level_id": %v, "comment": "Data lengkap"},
+				{"criterion_id": %v, "level_id": %v, "comment": "Banyak salah ketik"}
+			]}`, criteria["Isi"], levels["Isi"]["Baik"], criteria["Bahasa"], levels["Bahasa"]["Kurang"])
+			code, response := serve(http.MethodPut, fmt.Sprintf("%v/user-submit/%v/rubric", pathSubmission, idUserSubmission), scores)
+			Expect(code).To(Equal(http.StatusOK))
+			data := response["data"].(map[string]interface{})
+			Expect(data["total"]).To(Equal(float64(45)))
+			Expect(data["max_points"]).To(Equal(float64(60)))
+
+			_, response = serve(http.MethodGet, fmt.Sprintf("%v/user-submit/%v", pathSubmission, idUserSubmission), "")
+			Expect(response["data"].(map[string]interface{})["grade"]).To(Equal(float64(45)))
+
+			_, response = serve(http.MethodGet, "/api/users/submissions", "")
+			submission := response["data"].([]interface{})[0].(map[string]interface{})
+			Expect(submission["grade"]).To(Equal(float64(45)))
+			Expect(submission["feedback"]).To(Equal("Argumen kuat"))
+			breakdown := submission["rubric"].([]interface{})
+			Expect(breakdown).To(HaveLen(2))
+			Expect(breakdown[1].(map[string]interface{})["level_name"]).To(Equal("Kurang"))
+			Expect(breakdown[1].(map[string]interface{})["comment"]).To(Equal("Banyak salah ketik"))
+		})
+
+		It("should refuse scores that leave out a criterion or use a level of another one", func() {
+			serve(http.MethodPut, pathSubmission+"/rubric", rubric)
+			criteria, levels := levels()
+			path := fmt.Sprintf("%v/user-submit/%v/rubric", pathSubmission, idUserSubmission)
+
+			code, _ := serve(http.MethodPut, path, fmt.Sprintf(`{"scores": [{"criterion_id": %v, "level_id": %v}]}`, criteria["Isi"], levels["Isi"]["Baik"]))
+			Expect(code).To(Equal(http.StatusBadRequest))
+
+			code, _ = serve(http.MethodPut, path, fmt.Sprintf(`{"scores": [{"criterion_id": %v, "level_id": %v}, {"criterion_id": %v, "level_id": %v}]}`,
+				criteria["Isi"], levels["Bahasa"]["Baik"], criteria["Bahasa"], levels["Bahasa"]["Baik"]))
+			Expect(code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should not change a rubric once a submission is scored", func() {
+			serve(http.MethodPut, pathSubmission+"/rubric", rubric)
+			criteria, levels := levels()
+			serve(http.MethodPut, fmt.Sprintf("%v/user-submit/%v/rubric", pathSubmission, idUserSubmission), fmt.Sprintf(`{"scores": [{"criterion_id": %v, "level_id": %v}, {"criterion_id": %v, "level_id": %v}]}`,
+				criteria["Isi"], levels["Isi"]["Kurang"], criteria["Bahasa"], levels["Bahasa"]["Kurang"]))
+
+			code, _ := serve(http.MethodPut, pathSubmission+"/rubric", rubric)
+			Expect(code).To(Equal(http.StatusConflict))
+
+			code, _ = serve(http.MethodDelete, pathSubmission+"/rubric", "")
+			Expect(code).To(Equal(http.StatusOK))
+
+			_, response := serve(http.MethodGet, fmt.Sprintf("%v/user-submit/%v/rubric", pathSubmission, idUserSubmission), "")
+			Expect(response["data"].(map[string]interface{})["scores"]).To(BeEmpty())
+		})
+
+		It("should not show the scores to another student", func() {
+			setup.Register(server, "Siswa Rubrik", "siswarubrik", entity.RoleStudent)
+
+			code, _ := setup.Serve(server, setup.Login(server, "siswarubrik"), http.MethodGet, fmt.Sprintf("%v/user-submit/%v/rubric", pathSubmission, idUserSubmission), "")
+			Expect(code).To(Equal(http.StatusForbidden))
+		})
+	})
+})
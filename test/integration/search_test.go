@@ -0,0 +1,147 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var _ = Describe("Search API", func() {
+
+	var (
+		server       *gin.Engine
+		tokenAdmin   string
+		tokenStudent string
+		codeCourses  []string
+		idArticles   []int
+	)
+
+	serve := func(method string, path string, body string, token string) (int, map[string]interface{}) {
+		return setup.Serve(server, token, method, path, body)
+	}
+
+	BeforeEach(func() {
+		configuration := config.New("../../.env.test")
+		if configuration.Get("DB_CONNECTION") != utils.DriverPostgres && !utils.FTS5 {
+			Skip("SQLite search needs the FTS5 extension, run the tests with -tags sqlite_fts5")
+		}
+
+		_, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		server = setup.ModuleSetup(configuration)
+
+		setup.Register(server, "Admin Search", "adminsearch", entity.RoleAdmin)
+		idStudent := setup.Register(server, "Student Search", "studentsearch", entity.RoleStudent)
+		tokenAdmin = setup.Login(server, "adminsearch")
+		tokenStudent = setup.Login(server, "studentsearch")
+
+		courses := []string{
+			`{"name": "Biologi Dasar","class": "IPA-1","tools": "Mikroskop","about": "Belajar tumbuhan","description": "Mengenal sel"}`,
+			`{"name": "Biologi Lanjut","class": "IPA-2","tools": "Mikroskop","about": "Belajar hewan","description": "Mengenal organ"}`,
+		}
+		codeCourses = nil
+		idArticles = nil
+		var idCourses []int
+		for i, course := range courses {
+			_, response := serve(http.MethodPost, "/api/courses", course, tokenAdmin)
+			data := response["data"].(map[string]interface{})
+			codeCourses = append(codeCourses, data["code_course"].(string))
+			idCourses = append(idCourses, int(data["id"].(float64)))
+
+			article := fmt.Sprintf(`{"name": "Bab %v","content": "Proses fotosintesis terjadi di daun, bagian %v","estimate": 60}`, i+1, i+1)
+			_, response = serve(http.MethodPost, "/api/courses/"+codeCourses[i]+"/articles", article, tokenAdmin)
+			idArticles = append(idArticles, int(response["data"].(map[string]interface{})["id"].(float64)))
+		}
+
+		serve(http.MethodPost, "/api/usercourse", fmt.Sprintf(`{"user_id": %v, "course_id": %v}`, idStudent, idCourses[0]), tokenAdmin)
+	})
+
+	AfterEach(func() {
+		configuration := config.New("../../.env.test")
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Search articles", func() {
+		It("should only show a student the articles of the courses they are enrolled in", func() {
+			code, response := serve(http.MethodGet, "/api/search?q=fotosintesis", "", tokenStudent)
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(response["total"]).To(Equal(float64(1)))
+
+			result := response["data"].([]interface{})[0].(map[string]interface{})
+			Expect(result["type"]).To(Equal("article"))
+			Expect(result["code_course"]).To(Equal(codeCourses[0]))
+			Expect(result["snippet"]).To(ContainSubstring("<mark>fotosintesis</mark>"))
+		})
+
+		It("should show an admin every match", func() {
+			code, response := serve(http.MethodGet, "/api/search?q=fotosintesis&type=article", "", tokenAdmin)
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(response["total"]).To(Equal(float64(2)))
+		})
+
+		It("should keep the index in sync with updates", func() {
+			serve(http.MethodPatch, fmt.Sprintf("/api/courses/%v/articles/%v", codeCourses[0], idArticles[0]), `{"name": "Bab 1","content": "Respirasi sel","estimate": 60}`, tokenAdmin)
+
+			_, response := serve(http.MethodGet, "/api/search?q=fotosintesis", "", tokenStudent)
+			Expect(response["total"]).To(Equal(float64(0)))
+
+			_, response = serve(http.MethodGet, "/api/search?q=respirasi", "", tokenStudent)
+			Expect(response["total"]).To(Equal(float64(1)))
+		})
+
+		It("should escape the text around the marked matches", func() {
+			serve(http.MethodPatch, fmt.Sprintf("/api/courses/%v/articles/%v", codeCourses[0], idArticles[0]), `{"name": "Bab 1","content": "<img src=x onerror=alert(1)> fotosintesis & respirasi","estimate": 60}`, tokenAdmin)
+
+			_, response := serve(http.MethodGet, "/api/search?q=fotosintesis", "", tokenStudent)
+			snippet := response["data"].([]interface{})[0].(map[string]interface{})["snippet"]
+			Expect(snippet).To(ContainSubstring("&lt;img src=x onerror=alert(1)&gt; <mark>fotosintesis</mark> &amp; respirasi"))
+			Expect(snippet).NotTo(ContainSubstring("<img"))
+		})
+	})
+
+	Describe("Search courses", func() {
+		It("should rank the course that matches better first", func() {
+			code, response := serve(http.MethodGet, "/api/search?q=biologi+hewan&type=course", "", tokenStudent)
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(response["total"]).To(Equal(float64(1)))
+			Expect(response["data"].([]interface{})[0].(map[string]interface{})["title"]).To(Equal("Biologi Lanjut"))
+
+			_, response = serve(http.MethodGet, "/api/search?q=biologi&type=course", "", tokenStudent)
+			Expect(response["total"]).To(Equal(float64(2)))
+		})
+	})
+
+	Describe("Invalid search", func() {
+		It("should answer 400 without a query or with an unknown type", func() {
+			code, _ := serve(http.MethodGet, "/api/search?q=", "", tokenStudent)
+			Expect(code).To(Equal(http.StatusBadRequest))
+
+			code, _ = serve(http.MethodGet, "/api/search?q=daun&type=user", "", tokenStudent)
+			Expect(code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should treat query syntax as plain words", func() {
+			code, _ := serve(http.MethodGet, `/api/search?q=%22daun+OR+NEAR(`, "", tokenStudent)
+			Expect(code).To(Equal(http.StatusOK))
+		})
+	})
+})
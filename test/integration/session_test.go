@@ -0,0 +1,132 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+)
+
+var _ = Describe("Session API", func() {
+
+	var (
+		server *gin.Engine
+		token  string
+	)
+
+	BeforeEach(func() {
+		configuration := config.New("../../.env.test")
+
+		_, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		router := setup.ModuleSetup(configuration)
+		server = router
+
+		// Register User
+		userData, _ := json.Marshal(model.UserRegisterResponse{
+			Name:           "session",
+			Username:       "session",
+			Email:          "session@gmail.com",
+			Password:       "123456ll",
+			Role:           2,
+			Phone:          "085156789011",
+			Gender:         1,
+			DisabilityType: 1,
+			Birthdate:      "2002-04-01",
+		})
+		request := httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(string(userData)))
+		request.Header.Add("Content-Type", "application/json")
+
+		writer := httptest.NewRecorder()
+		server.ServeHTTP(writer, request)
+
+		// Login User
+		userLogin, _ := json.Marshal(model.GetUserLogin{
+			Email:    "session@gmail.com",
+			Password: "123456ll",
+		})
+		request = httptest.NewRequest(http.MethodPost, "/api/users/login", strings.NewReader(string(userLogin)))
+		request.Header.Add("Content-Type", "application/json")
+
+		writer = httptest.NewRecorder()
+		server.ServeHTTP(writer, request)
+
+		body, _ := io.ReadAll(writer.Result().Body)
+		var responseBody map[string]interface{}
+		_ = json.Unmarshal(body, &responseBody)
+
+		token = responseBody["token"].(string)
+	})
+
+	AfterEach(func() {
+		configuration := config.New("../../.env.test")
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Session survives a restart", func() {
+		When("a new server is started with the same database", func() {
+			It("should still accept the token", func() {
+				configuration := config.New("../../.env.test")
+				restarted := setup.ModuleSetup(configuration)
+
+				request := httptest.NewRequest(http.MethodGet, "/api/userstatus", nil)
+				request.Header.Set("Authorization", token)
+
+				writer := httptest.NewRecorder()
+				restarted.ServeHTTP(writer, request)
+
+				body, _ := io.ReadAll(writer.Result().Body)
+				var responseBody map[string]interface{}
+				_ = json.Unmarshal(body, &responseBody)
+
+				Expect(int(responseBody["code"].(float64))).To(Equal(http.StatusOK))
+				Expect(responseBody["status"]).To(Equal("User Already Logged In"))
+			})
+		})
+	})
+
+	Describe("Session is revoked on logout", func() {
+		When("the user logs out", func() {
+			It("should reject the token afterwards", func() {
+				request := httptest.NewRequest(http.MethodPost, "/api/users/logout", nil)
+				request.Header.Set("Authorization", token)
+
+				writer := httptest.NewRecorder()
+				server.ServeHTTP(writer, request)
+				Expect(writer.Result().StatusCode).To(Equal(http.StatusOK))
+
+				request = httptest.NewRequest(http.MethodGet, "/api/userstatus", nil)
+				request.Header.Set("Authorization", token)
+
+				writer = httptest.NewRecorder()
+				server.ServeHTTP(writer, request)
+
+				body, _ := io.ReadAll(writer.Result().Body)
+				var responseBody map[string]interface{}
+				_ = json.Unmarshal(body, &responseBody)
+
+				Expect(int(responseBody["code"].(float64))).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+})
@@ -0,0 +1,246 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/storage"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var _ = Describe("Storage", func() {
+
+	var (
+		server             *gin.Engine
+		token              string
+		codeCourse         string
+		idModuleSubmission int
+	)
+
+	serve := func(method string, path string, body string, token string) (int, map[string]interface{}) {
+		return setup.Serve(server, token, method, path, body)
+	}
+
+	submit := func(name string, content string) map[string]interface{} {
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("file", name)
+		_, _ = part.Write([]byte(content))
+		writer.Close()
+
+		request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/courses/%v/submissions/%v/user-submit", codeCourse, idModuleSubmission), body)
+		request.Header.Add("Content-Type", writer.FormDataContentType())
+		request.Header.Set("Authorization", token)
+
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+
+		responseBody, _ := io.ReadAll(recorder.Result().Body)
+		var response map[string]interface{}
+		_ = json.Unmarshal(responseBody, &response)
+		Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+
+		return response["data"].(map[string]interface{})
+	}
+
+	removeAsset := func(file string) {
+		path, err := utils.GetPath("/assets/", file)
+		if err != nil {
+			panic(err)
+		}
+		_ = os.Remove(path)
+	}
+
+	BeforeEach(func() {
+		configuration := config.New("../../.env.test")
+
+		_, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		server = setup.ModuleSetup(configuration)
+
+		idUser := setup.Register(server, "Admin Storage", "adminstorage", entity.RoleAdmin)
+		token = setup.Login(server, "adminstorage")
+		codeCourse, _ = setup.EnrolledCourse(server, token, idUser, `{"name": "Teknik Komputer Jaringan","class": "TKJ-3","tools": "Router","about": "Tower","description": "Tower"}`)
+
+		_, response := serve(http.MethodPost, "/api/courses/"+codeCourse+"/submissions", `{"name": "Tugas Jaringan","description": "Topologi","deadline": "2022-06-21T15:21:38+07:00"}`, token)
+		idModuleSubmission = setup.ID(response)
+	})
+
+	AfterEach(func() {
+		configuration := config.New("../../.env.test")
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Local store", func() {
+		It("should store the upload under its content address and serve it through a signed URL", func() {
+			data := submit("topologi.txt", "star topology")
+			file := data["file"].(string)
+			defer removeAsset(file)
+			Expect(file).To(MatchRegexp(`^[0-9a-f]{64}\.txt$`))
+
+			code, response := serve(http.MethodGet, fmt.Sprintf("/api/courses/%v/submissions/%v/user-submit/%v/download-url", codeCourse, idModuleSubmission, int(data["id"].(float64))), "", token)
+			Expect(code).To(Equal(http.StatusOK))
+			signedURL := response["data"].(map[string]interface{})["url"].(string)
+			Expect(signedURL).To(HavePrefix("/api/files/" + file + "?"))
+
+			request := httptest.NewRequest(http.MethodGet, signedURL, nil)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Body.String()).To(Equal("star topology"))
+
+			request = httptest.NewRequest(http.MethodGet, strings.Replace(signedURL, "filename=", "filename=x", 1), nil)
+			recorder = httptest.NewRecorder()
+			server.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should not sign a URL for the file of another student", func() {
+			data := submit("topologi.txt", "mesh topology")
+			defer removeAsset(data["file"].(string))
+
+			setup.Register(server, "Siswa Storage", "siswastorage", entity.RoleStudent)
+			code, _ := serve(http.MethodGet, fmt.Sprintf("/api/courses/%v/submissions/%v/user-submit/%v/download-url", codeCourse, idModuleSubmission, int(data["id"].(float64))), "", setup.Login(server, "siswastorage"))
+			Expect(code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should keep the previous file when it is replaced", func() {
+			first := submit("topologi.txt", "bus topology")["file"].(string)
+			second := submit("topologi.txt", "ring topology")["file"].(string)
+			defer removeAsset(first)
+			defer removeAsset(second)
+
+			Expect(second).NotTo(Equal(first))
+			path, _ := utils.GetPath("/assets/", first)
+			Expect(path).To(BeAnExistingFile())
+			path, _ = utils.GetPath("/assets/", second)
+			Expect(path).To(BeAnExistingFile())
+		})
+	})
+
+	Describe("S3 store", func() {
+		It("should put, read and delete objects and presign downloads", func() {
+			var (
+				mutex   sync.Mutex
+				objects = map[string][]byte{}
+			)
+			fake := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				if request.Header.Get("Authorization") == "" && request.URL.Query().Get("X-Amz-Signature") == "" {
+					writer.WriteHeader(http.StatusForbidden)
+					return
+				}
+
+				object, exists := objects[request.URL.Path]
+				switch request.Method {
+				case http.MethodPut:
+					objects[request.URL.Path], _ = io.ReadAll(request.Body)
+				case http.MethodDelete:
+					delete(objects, request.URL.Path)
+					writer.WriteHeader(http.StatusNoContent)
+				default:
+					if !exists {
+						writer.WriteHeader(http.StatusNotFound)
+						return
+					}
+					if disposition := request.URL.Query().Get("response-content-disposition"); disposition != "" {
+						writer.Header().Set("Content-Disposition", disposition)
+					}
+					_, _ = writer.Write(object)
+				}
+			}))
+			defer fake.Close()
+
+			store, err := storage.NewS3Store(storage.S3Config{Endpoint: fake.URL, Region: "us-east-1", Bucket: "teenager", AccessKey: "access", SecretKey: "secret"})
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx := context.Background()
+			content := strings.NewReader("mesh topology")
+			key, size, err := storage.ContentKey(content, "Topologi.TXT")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key).To(HaveSuffix(".txt"))
+
+			Expect(store.Put(ctx, key, content, size, "text/plain")).To(Succeed())
+			Expect(objects).To(HaveKey("/teenager/" + key))
+			Expect(store.Exists(ctx, key)).To(BeTrue())
+
+			body, err := store.Get(ctx, key)
+			Expect(err).NotTo(HaveOccurred())
+			read, _ := io.ReadAll(body)
+			body.Close()
+			Expect(string(read)).To(Equal("mesh topology"))
+
+			signedURL, err := store.SignedURL(ctx, key, "topologi.txt", time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			response, err := http.Get(signedURL)
+			Expect(err).NotTo(HaveOccurred())
+			read, _ = io.ReadAll(response.Body)
+			response.Body.Close()
+			Expect(string(read)).To(Equal("mesh topology"))
+			Expect(response.Header.Get("Content-Disposition")).To(ContainSubstring("topologi.txt"))
+
+			Expect(store.Delete(ctx, key)).To(Succeed())
+			Expect(store.Exists(ctx, key)).To(BeFalse())
+			_, err = store.Get(ctx, key)
+			Expect(err).To(MatchError(storage.ErrBlobNotFound))
+		})
+	})
+
+	Describe("Import", func() {
+		It("should move the files of a directory into the store", func() {
+			directory, err := os.MkdirTemp("", "assets")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(directory)
+			Expect(os.WriteFile(filepath.Join(directory, "FgJabagUEEmyOZEXaOrw.csv"), []byte("a,b"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(directory, ".gitkeep"), nil, 0644)).To(Succeed())
+
+			root, err := os.MkdirTemp("", "blobs")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(root)
+			store, err := storage.NewLocalStore(root, []byte("secret"), "")
+			Expect(err).NotTo(HaveOccurred())
+
+			var moved []string
+			imported, err := storage.Import(context.Background(), store, directory, func(imported storage.Imported) error {
+				moved = append(moved, imported.Name)
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(moved).To(Equal([]string{"FgJabagUEEmyOZEXaOrw.csv"}))
+			Expect(imported[0].Size).To(Equal(int64(3)))
+			Expect(filepath.Join(directory, "FgJabagUEEmyOZEXaOrw.csv")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(root, imported[0].Key)).To(BeAnExistingFile())
+		})
+	})
+})
@@ -0,0 +1,179 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var _ = Describe("Submission Attempts API", func() {
+
+	var (
+		server           *gin.Engine
+		token            string
+		pathSubmission   string
+		idCourse         int
+		idUserSubmission int
+	)
+
+	serve := func(method string, path string, body string) (int, map[string]interface{}) {
+		return setup.Serve(server, token, method, path, body)
+	}
+
+	submit := func(content string) {
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("file", "jawaban.txt")
+		_, _ = part.Write([]byte(content))
+		writer.Close()
+
+		request := httptest.NewRequest(http.MethodPost, pathSubmission+"/user-submit", body)
+		request.Header.Add("Content-Type", writer.FormDataContentType())
+		request.Header.Set("Authorization", token)
+
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var response map[string]interface{}
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+		data := response["data"].(map[string]interface{})
+		idUserSubmission = int(data["id"].(float64))
+
+		path, _ := utils.GetPath("/assets/", data["file"].(string))
+		DeferCleanup(os.Remove, path)
+	}
+
+	BeforeEach(func() {
+		configuration := config.New("../../.env.test")
+
+		_, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		server = setup.ModuleSetup(configuration)
+
+		idUser := setup.Register(server, "Admin Attempt", "adminattempt", entity.RoleAdmin)
+		token = setup.Login(server, "adminattempt")
+		var codeCourse string
+		codeCourse, idCourse = setup.EnrolledCourse(server, token, idUser, `{"name": "Kimia","class": "IPA-1","tools": "Tabung","about": "Reaksi","description": "Reaksi kimia"}`)
+
+		_, response := serve(http.MethodPost, "/api/courses/"+codeCourse+"/submissions", `{"name": "Laporan","description": "Titrasi","deadline": "2022-06-21"}`)
+		pathSubmission = fmt.Sprintf("/api/courses/%v/submissions/%v", codeCourse, int(response["data"].(map[string]interface{})["id"].(float64)))
+
+		submit("percobaan pertama")
+		submit("percobaan kedua")
+	})
+
+	AfterEach(func() {
+		configuration := config.New("../../.env.test")
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("List attempts", func() {
+		It("should keep every attempt and grade the latest by default", func() {
+			code, response := serve(http.MethodGet, fmt.Sprintf("%v/user-submit/%v/attempts", pathSubmission, idUserSubmission), "")
+			Expect(code).To(Equal(http.StatusOK))
+
+			attempts := response["data"].([]interface{})
+			Expect(attempts).To(HaveLen(2))
+			first := attempts[0].(map[string]interface{})
+			second := attempts[1].(map[string]interface{})
+			Expect(first["size"]).To(Equal(float64(len("percobaan pertama"))))
+			Expect(first["file"]).To(Equal(first["hash"].(string) + ".txt"))
+			Expect(first["late"]).To(BeTrue())
+			Expect(first["graded"]).To(BeFalse())
+			Expect(second["graded"]).To(BeTrue())
+		})
+	})
+
+	Describe("Download an attempt", func() {
+		It("should return the file of that attempt", func() {
+			_, response := serve(http.MethodGet, fmt.Sprintf("%v/user-submit/%v/attempts", pathSubmission, idUserSubmission), "")
+			idAttempt := int(response["data"].([]interface{})[0].(map[string]interface{})["id"].(float64))
+
+			request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("%v/user-submit/%v/attempts/%v/download", pathSubmission, idUserSubmission, idAttempt), nil)
+			request.Header.Set("Authorization", token)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, request)
+
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Body.String()).To(Equal("percobaan pertama"))
+			Expect(recorder.Header().Get("Content-Disposition")).To(ContainSubstring(".txt"))
+		})
+	})
+
+	Describe("Pick the graded attempt", func() {
+		It("should grade the attempt the teacher picked", func() {
+			_, response := serve(http.MethodGet, fmt.Sprintf("%v/user-submit/%v/attempts", pathSubmission, idUserSubmission), "")
+			idAttempt := int(response["data"].([]interface{})[0].(map[string]interface{})["id"].(float64))
+
+			code, response := serve(http.MethodPatch, fmt.Sprintf("%v/user-submit/%v/graded-attempt", pathSubmission, idUserSubmission), fmt.Sprintf(`{"attempt_id": %v}`, idAttempt))
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(response["data"].(map[string]interface{})["graded_attempt_id"]).To(Equal(float64(idAttempt)))
+
+			_, response = serve(http.MethodGet, fmt.Sprintf("%v/user-submit/%v/attempts", pathSubmission, idUserSubmission), "")
+			attempts := response["data"].([]interface{})
+			Expect(attempts[0].(map[string]interface{})["graded"]).To(BeTrue())
+			Expect(attempts[1].(map[string]interface{})["graded"]).To(BeFalse())
+
+			_, response = serve(http.MethodGet, fmt.Sprintf("%v/user-submit/%v", pathSubmission, idUserSubmission), "")
+			Expect(response["data"].(map[string]interface{})["graded_attempt_id"]).To(Equal(float64(idAttempt)))
+		})
+
+		It("should refuse an attempt of another submission", func() {
+			code, _ := serve(http.MethodPatch, fmt.Sprintf("%v/user-submit/%v/graded-attempt", pathSubmission, idUserSubmission), `{"attempt_id": 999999}`)
+			Expect(code).NotTo(Equal(http.StatusOK))
+
+			code, _ = serve(http.MethodPatch, fmt.Sprintf("%v/user-submit/%v/graded-attempt", pathSubmission, idUserSubmission), `{}`)
+			Expect(code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("Attempts of another student", func() {
+		It("should forbid a student to list or download them", func() {
+			idOwner := setup.Register(server, "Siswa Pertama", "siswapertama", entity.RoleStudent)
+			serve(http.MethodPost, "/api/usercourse", fmt.Sprintf(`{"user_id":%v,"course_id":%v}`, idOwner, idCourse))
+			ownerToken := setup.Login(server, "siswapertama")
+			setup.Register(server, "Siswa Kedua", "siswakedua", entity.RoleStudent)
+			otherToken := setup.Login(server, "siswakedua")
+
+			code, response := setup.Upload(server, ownerToken, pathSubmission+"/user-submit", "jawaban.txt", "percobaan siswa")
+			Expect(code).To(Equal(http.StatusOK))
+			path, _ := utils.GetPath("/assets/", response["data"].(map[string]interface{})["file"].(string))
+			DeferCleanup(os.Remove, path)
+			pathAttempts := fmt.Sprintf("%v/user-submit/%v/attempts", pathSubmission, setup.ID(response))
+
+			code, response = setup.Serve(server, ownerToken, http.MethodGet, pathAttempts, "")
+			Expect(code).To(Equal(http.StatusOK))
+			idAttempt := int(response["data"].([]interface{})[0].(map[string]interface{})["id"].(float64))
+
+			code, _ = setup.Serve(server, otherToken, http.MethodGet, pathAttempts, "")
+			Expect(code).To(Equal(http.StatusForbidden))
+			code, _ = setup.Serve(server, otherToken, http.MethodPost, fmt.Sprintf("%v/%v/download", pathAttempts, idAttempt), "")
+			Expect(code).To(Equal(http.StatusForbidden))
+		})
+	})
+})
@@ -0,0 +1,219 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+)
+
+var _ = Describe("Teacher", func() {
+
+	var (
+		server       *gin.Engine
+		adminToken   string
+		teacherToken string
+		teacherId    int
+		ownCourse    string
+		otherCourse  string
+	)
+
+	registerAndLogin := func(user model.UserRegisterResponse) map[string]interface{} {
+		userData, _ := json.Marshal(user)
+		request := httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(string(userData)))
+		request.Header.Add("Content-Type", "application/json")
+
+		writer := httptest.NewRecorder()
+		server.ServeHTTP(writer, request)
+
+		// Users register as students, the other roles are given afterwards
+		Expect(setup.SetRole(config.New("../../.env.test"), user.Email, user.Role)).To(Succeed())
+
+		userLogin, _ := json.Marshal(model.GetUserLogin{Email: user.Email, Password: user.Password})
+		request = httptest.NewRequest(http.MethodPost, "/api/users/login", strings.NewReader(string(userLogin)))
+		request.Header.Add("Content-Type", "application/json")
+
+		writer = httptest.NewRecorder()
+		server.ServeHTTP(writer, request)
+
+		body, _ := io.ReadAll(writer.Result().Body)
+		var responseBody map[string]interface{}
+		_ = json.Unmarshal(body, &responseBody)
+
+		return responseBody
+	}
+
+	createCourse := func(name string) string {
+		requestBody := strings.NewReader(fmt.Sprintf(`{"name": "%v","class": "TKJ-3","tools": "Router","about": "about","description": "description"}`, name))
+		request := httptest.NewRequest(http.MethodPost, "/api/courses", requestBody)
+		request.Header.Add("Content-Type", "application/json")
+		request.Header.Set("Authorization", adminToken)
+
+		writer := httptest.NewRecorder()
+		server.ServeHTTP(writer, request)
+
+		body, _ := io.ReadAll(writer.Result().Body)
+		var responseBody map[string]interface{}
+		_ = json.Unmarshal(body, &responseBody)
+
+		return responseBody["data"].(map[string]interface{})["code_course"].(string)
+	}
+
+	createArticle := func(token string, codeCourse string) *http.Response {
+		requestBody := strings.NewReader(`{"name": "Article 1","content": "Content","estimate": 10}`)
+		request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/courses/%v/articles", codeCourse), requestBody)
+		request.Header.Add("Content-Type", "application/json")
+		request.Header.Set("Authorization", token)
+
+		writer := httptest.NewRecorder()
+		server.ServeHTTP(writer, request)
+
+		return writer.Result()
+	}
+
+	BeforeEach(func() {
+		configuration := config.New("../../.env.test")
+
+		_, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		router := setup.ModuleSetup(configuration)
+		server = router
+
+		admin := registerAndLogin(model.UserRegisterResponse{
+			Name:           "admin",
+			Username:       "admin",
+			Email:          "admin@gmail.com",
+			Password:       "123456ll",
+			Role:           1,
+			Phone:          "085156789011",
+			Gender:         1,
+			DisabilityType: 1,
+			Birthdate:      "2002-04-01",
+		})
+		adminToken = admin["token"].(string)
+
+		teacher := registerAndLogin(model.UserRegisterResponse{
+			Name:           "guru",
+			Username:       "guru",
+			Email:          "guru@gmail.com",
+			Password:       "123456ll",
+			Role:           3,
+			Phone:          "085156789011",
+			Gender:         1,
+			DisabilityType: 1,
+			Birthdate:      "2002-04-01",
+		})
+		teacherToken = teacher["token"].(string)
+		teacherId = int(teacher["data"].(map[string]interface{})["id"].(float64))
+
+		ownCourse = createCourse("Teknik Komputer Jaringan")
+		otherCourse = createCourse("Rekayasa Perangkat Lunak")
+
+		requestBody := strings.NewReader(fmt.Sprintf(`{"user_id": %v}`, teacherId))
+		request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/courses/%v/teachers", ownCourse), requestBody)
+		request.Header.Add("Content-Type", "application/json")
+		request.Header.Set("Authorization", adminToken)
+
+		writer := httptest.NewRecorder()
+		server.ServeHTTP(writer, request)
+		Expect(writer.Result().StatusCode).To(Equal(http.StatusOK))
+	})
+
+	AfterEach(func() {
+		configuration := config.New("../../.env.test")
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Manage articles", func() {
+		When("the teacher teaches the course", func() {
+			It("should create the article", func() {
+				response := createArticle(teacherToken, ownCourse)
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+			})
+		})
+
+		When("the teacher does not teach the course", func() {
+			It("should return forbidden", func() {
+				response := createArticle(teacherToken, otherCourse)
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+		})
+
+		When("an admin manages any course", func() {
+			It("should create the article", func() {
+				response := createArticle(adminToken, otherCourse)
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+			})
+		})
+	})
+
+	Describe("See rosters", func() {
+		It("should only list the students of the teacher's own course", func() {
+			request := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/courses/%v/users", ownCourse), nil)
+			request.Header.Set("Authorization", teacherToken)
+
+			writer := httptest.NewRecorder()
+			server.ServeHTTP(writer, request)
+			Expect(writer.Result().StatusCode).To(Equal(http.StatusOK))
+
+			request = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/courses/%v/users", otherCourse), nil)
+			request.Header.Set("Authorization", teacherToken)
+
+			writer = httptest.NewRecorder()
+			server.ServeHTTP(writer, request)
+			Expect(writer.Result().StatusCode).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	Describe("Teaching assignments", func() {
+		It("should list the courses the teacher teaches", func() {
+			request := httptest.NewRequest(http.MethodGet, "/api/teachers/courses", nil)
+			request.Header.Set("Authorization", teacherToken)
+
+			writer := httptest.NewRecorder()
+			server.ServeHTTP(writer, request)
+
+			body, _ := io.ReadAll(writer.Result().Body)
+			var responseBody map[string]interface{}
+			_ = json.Unmarshal(body, &responseBody)
+
+			Expect(writer.Result().StatusCode).To(Equal(http.StatusOK))
+			courses := responseBody["data"].([]interface{})
+			Expect(courses).To(HaveLen(1))
+			Expect(courses[0].(map[string]interface{})["course_code"]).To(Equal(ownCourse))
+		})
+
+		It("should not let a teacher assign themselves to another course", func() {
+			requestBody := strings.NewReader(fmt.Sprintf(`{"user_id": %v}`, teacherId))
+			request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/courses/%v/teachers", otherCourse), requestBody)
+			request.Header.Add("Content-Type", "application/json")
+			request.Header.Set("Authorization", teacherToken)
+
+			writer := httptest.NewRecorder()
+			server.ServeHTTP(writer, request)
+
+			Expect(writer.Result().StatusCode).To(Equal(http.StatusForbidden))
+		})
+	})
+})
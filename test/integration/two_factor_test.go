@@ -0,0 +1,205 @@
+package integration
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var _ = Describe("Two-Factor API", func() {
+
+	var server *gin.Engine
+
+	serve := func(method string, path string, token string, body string) (int, map[string]interface{}) {
+		return setup.Serve(server, token, method, path, body)
+	}
+
+	login := func(email string) (int, map[string]interface{}) {
+		return serve(http.MethodPost, "/api/users/login", "", `{"email": "`+email+`", "password": "123456ll"}`)
+	}
+
+	data := func(response map[string]interface{}) map[string]interface{} {
+		return response["data"].(map[string]interface{})
+	}
+
+	// code returns the code of the secret, offset time steps away from now
+	code := func(secret string, offset int64) string {
+		totp, err := utils.TOTPCode(secret, utils.TOTPStep(time.Now())+offset)
+		Expect(err).NotTo(HaveOccurred())
+		return totp
+	}
+
+	verify := func(challenge string, code string) (int, map[string]interface{}) {
+		return serve(http.MethodPost, "/api/users/login/2fa", "", `{"challenge_token": "`+challenge+`", "code": "`+code+`"}`)
+	}
+
+	BeforeEach(func() {
+		configuration := config.New("../../.env.test")
+		_, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		server = setup.ModuleSetup(configuration)
+
+		setup.Register(server, "Admin Dua", "admindua", entity.RoleAdmin)
+		setup.Register(server, "Siswa Dua", "siswadua", entity.RoleStudent)
+	})
+
+	AfterEach(func() {
+		configuration := config.New("../../.env.test")
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Enabled by the user", func() {
+		It("should ask for a code or a recovery code at login", func() {
+			status, response := login("siswadua@gmail.com")
+			Expect(status).To(Equal(http.StatusOK))
+			token := response["token"].(string)
+			refreshToken := response["refresh_token"].(string)
+
+			status, response = serve(http.MethodPost, "/api/users/2fa/enroll", token, "")
+			Expect(status).To(Equal(http.StatusOK))
+			secret := data(response)["secret"].(string)
+			Expect(data(response)["otpauth_uri"]).To(HavePrefix("otpauth://totp/Teenager:siswadua@gmail.com?"))
+
+			status, _ = serve(http.MethodPost, "/api/users/2fa/confirm", token, `{"code": "`+code(secret, 10)+`"}`)
+			Expect(status).To(Equal(http.StatusForbidden))
+			confirmed := code(secret, 0)
+			status, response = serve(http.MethodPost, "/api/users/2fa/confirm", token, `{"code": "`+confirmed+`"}`)
+			Expect(status).To(Equal(http.StatusOK))
+			recoveryCodes := data(response)["recovery_codes"].([]interface{})
+			Expect(recoveryCodes).To(HaveLen(10))
+
+			// The sessions and refresh tokens from before two-factor authentication was enabled are revoked
+			status, _ = serve(http.MethodGet, "/api/users/2fa", token, "")
+			Expect(status).To(Equal(http.StatusUnauthorized))
+			status, _ = serve(http.MethodPost, "/api/users/token/refresh", "", `{"refresh_token": "`+refreshToken+`"}`)
+			Expect(status).To(Equal(http.StatusUnauthorized))
+
+			status, response = login("siswadua@gmail.com")
+			Expect(status).To(Equal(http.StatusAccepted))
+			Expect(response["token"]).To(BeNil())
+			Expect(data(response)["setup_required"]).To(BeFalse())
+			challenge := data(response)["challenge_token"].(string)
+
+			// The code used to confirm can not be used again
+			status, _ = verify(challenge, confirmed)
+			Expect(status).To(Equal(http.StatusForbidden))
+			status, response = verify(challenge, strings.ToUpper(recoveryCodes[0].(string)))
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(response["token"]).NotTo(BeEmpty())
+			Expect(response["refresh_token"]).NotTo(BeEmpty())
+			Expect(data(response)["email"]).To(Equal("siswadua@gmail.com"))
+			token = response["token"].(string)
+
+			status, response = serve(http.MethodGet, "/api/users/2fa", token, "")
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(data(response)["enabled"]).To(BeTrue())
+			Expect(data(response)["recovery_codes_left"]).To(Equal(float64(9)))
+			status, _ = verify(challenge, recoveryCodes[1].(string))
+			Expect(status).To(Equal(http.StatusUnauthorized))
+
+			_, response = login("siswadua@gmail.com")
+			challenge = data(response)["challenge_token"].(string)
+			status, _ = verify(challenge, recoveryCodes[0].(string))
+			Expect(status).To(Equal(http.StatusForbidden))
+
+			status, _ = serve(http.MethodPost, "/api/users/2fa/disable", token, `{"password": "salah1234", "code": "`+recoveryCodes[1].(string)+`"}`)
+			Expect(status).To(Equal(http.StatusForbidden))
+			status, _ = serve(http.MethodPost, "/api/users/2fa/disable", token, `{"password": "123456ll", "code": "`+recoveryCodes[1].(string)+`"}`)
+			Expect(status).To(Equal(http.StatusOK))
+
+			status, _ = login("siswadua@gmail.com")
+			Expect(status).To(Equal(http.StatusOK))
+		})
+
+		It("should give up on a challenge after five wrong codes", func() {
+			_, response := login("siswadua@gmail.com")
+			token := response["token"].(string)
+			_, response = serve(http.MethodPost, "/api/users/2fa/enroll", token, "")
+			secret := data(response)["secret"].(string)
+			status, _ := serve(http.MethodPost, "/api/users/2fa/confirm", token, `{"code": "`+code(secret, -1)+`"}`)
+			Expect(status).To(Equal(http.StatusOK))
+
+			_, response = login("siswadua@gmail.com")
+			challenge := data(response)["challenge_token"].(string)
+			for i := 0; i < 5; i++ {
+				status, _ = verify(challenge, code(secret, 10))
+				Expect(status).To(Equal(http.StatusForbidden))
+			}
+			status, _ = verify(challenge, code(secret, 0))
+			Expect(status).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Describe("Required for a role", func() {
+		It("should make admins set up two-factor authentication at login", func() {
+			status, response := login("siswadua@gmail.com")
+			Expect(status).To(Equal(http.StatusOK))
+			status, _ = serve(http.MethodPut, "/api/users/2fa/policies/1", response["token"].(string), `{"required": true}`)
+			Expect(status).To(Equal(http.StatusForbidden))
+
+			status, response = login("admindua@gmail.com")
+			Expect(status).To(Equal(http.StatusOK))
+			token := response["token"].(string)
+			refreshToken := response["refresh_token"].(string)
+			status, _ = serve(http.MethodPut, "/api/users/2fa/policies/4", token, `{"required": true}`)
+			Expect(status).To(Equal(http.StatusBadRequest))
+			status, _ = serve(http.MethodPut, "/api/users/2fa/policies/1", token, `{"required": true}`)
+			Expect(status).To(Equal(http.StatusOK))
+
+			// Admins without two-factor authentication are logged out and can not refresh their token
+			status, _ = serve(http.MethodGet, "/api/users/2fa/policies", token, "")
+			Expect(status).To(Equal(http.StatusUnauthorized))
+			status, _ = serve(http.MethodPost, "/api/users/token/refresh", "", `{"refresh_token": "`+refreshToken+`"}`)
+			Expect(status).To(Equal(http.StatusUnauthorized))
+
+			status, response = login("admindua@gmail.com")
+			Expect(status).To(Equal(http.StatusAccepted))
+			Expect(data(response)["setup_required"]).To(BeTrue())
+			challenge := data(response)["challenge_token"].(string)
+
+			status, _ = verify(challenge, "123456")
+			Expect(status).To(Equal(http.StatusBadRequest))
+			status, response = serve(http.MethodPost, "/api/users/login/2fa/enroll", "", `{"challenge_token": "`+challenge+`"}`)
+			Expect(status).To(Equal(http.StatusOK))
+			secret := data(response)["secret"].(string)
+
+			status, response = verify(challenge, code(secret, 0))
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(data(response)["recovery_codes"]).To(HaveLen(10))
+			token = response["token"].(string)
+			recoveryCode := data(response)["recovery_codes"].([]interface{})[0].(string)
+
+			status, response = serve(http.MethodGet, "/api/users/2fa/policies", token, "")
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(response["data"]).To(HaveLen(3))
+			Expect(response["data"].([]interface{})[0]).To(Equal(map[string]interface{}{"role": float64(1), "required": true}))
+
+			status, _ = serve(http.MethodPost, "/api/users/2fa/disable", token, `{"password": "123456ll", "code": "`+recoveryCode+`"}`)
+			Expect(status).To(Equal(http.StatusForbidden))
+
+			// Students are not affected by the policy of admins
+			status, _ = login("siswadua@gmail.com")
+			Expect(status).To(Equal(http.StatusOK))
+		})
+	})
+})
@@ -0,0 +1,184 @@
+package integration
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
+)
+
+var _ = Describe("Upload Validation", func() {
+
+	var (
+		server     *gin.Engine
+		token      string
+		codeCourse string
+	)
+
+	pdf := []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\n%%EOF\n")
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00")
+
+	archive := func(files map[string][]byte) []byte {
+		buffer := new(bytes.Buffer)
+		writer := zip.NewWriter(buffer)
+		for name, content := range files {
+			entry, _ := writer.Create(name)
+			_, _ = entry.Write(content)
+		}
+		writer.Close()
+
+		return buffer.Bytes()
+	}
+
+	serve := func(method string, path string, body string) (int, map[string]interface{}) {
+		return setup.Serve(server, token, method, path, body)
+	}
+
+	createSubmission := func(rules string) int {
+		code, response := serve(http.MethodPost, "/api/courses/"+codeCourse+"/submissions", `{"name": "Laporan","description": "Laporan praktikum","deadline": "2022-06-21T15:21:38+07:00",`+rules+`}`)
+		Expect(code).To(Equal(http.StatusOK))
+
+		return int(response["data"].(map[string]interface{})["id"].(float64))
+	}
+
+	upload := func(idSubmission int, name string, content []byte) (int, map[string]interface{}) {
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("file", name)
+		_, _ = part.Write(content)
+		writer.Close()
+
+		request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/courses/%v/submissions/%v/user-submit", codeCourse, idSubmission), body)
+		request.Header.Add("Content-Type", writer.FormDataContentType())
+		request.Header.Set("Authorization", token)
+
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+
+		responseBody, _ := io.ReadAll(recorder.Result().Body)
+		var response map[string]interface{}
+		_ = json.Unmarshal(responseBody, &response)
+
+		if recorder.Code == http.StatusOK {
+			path, _ := utils.GetPath("/assets/", response["data"].(map[string]interface{})["file"].(string))
+			DeferCleanup(os.Remove, path)
+		}
+
+		return recorder.Code, response
+	}
+
+	BeforeEach(func() {
+		configuration := config.New("../../.env.test")
+
+		_, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		server = setup.ModuleSetup(configuration)
+
+		idUser := setup.Register(server, "Admin Upload", "adminupload", entity.RoleAdmin)
+		token = setup.Login(server, "adminupload")
+		codeCourse, _ = setup.EnrolledCourse(server, token, idUser, `{"name": "Fisika","class": "IPA-1","tools": "Kalkulator","about": "Gerak","description": "Gerak lurus"}`)
+	})
+
+	AfterEach(func() {
+		configuration := config.New("../../.env.test")
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Upload rules of a module submission", func() {
+		It("should keep the allowed types and the size limit", func() {
+			idSubmission := createSubmission(`"allowed_types": ["PDF", "image", "pdf"], "max_size": 1024`)
+
+			code, response := serve(http.MethodGet, fmt.Sprintf("/api/courses/%v/submissions/%v", codeCourse, idSubmission), "")
+			Expect(code).To(Equal(http.StatusOK))
+			data := response["data"].(map[string]interface{})
+			Expect(data["allowed_types"]).To(Equal([]interface{}{"image", "pdf"}))
+			Expect(data["max_size"]).To(Equal(float64(1024)))
+		})
+
+		It("should reject unknown file types", func() {
+			code, _ := serve(http.MethodPost, "/api/courses/"+codeCourse+"/submissions", `{"name": "Laporan","description": "Laporan","deadline": "2022-06-21T15:21:38+07:00","allowed_types": ["exe"]}`)
+			Expect(code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("Submit a file", func() {
+		It("should accept a file of an allowed type", func() {
+			idSubmission := createSubmission(`"allowed_types": ["pdf"]`)
+
+			code, response := upload(idSubmission, "laporan.PDF", pdf)
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(response["data"].(map[string]interface{})["file"]).To(HaveSuffix(".pdf"))
+		})
+
+		It("should answer 415 for a type that is not allowed", func() {
+			idSubmission := createSubmission(`"allowed_types": ["pdf"]`)
+
+			code, response := upload(idSubmission, "foto.png", png)
+			Expect(code).To(Equal(http.StatusUnsupportedMediaType))
+			Expect(response["status"]).To(ContainSubstring("upload one of pdf"))
+		})
+
+		It("should answer 415 when the content does not match the extension", func() {
+			idSubmission := createSubmission(`"allowed_types": ["pdf", "docx"]`)
+
+			code, response := upload(idSubmission, "laporan.pdf", png)
+			Expect(code).To(Equal(http.StatusUnsupportedMediaType))
+			Expect(response["status"]).To(ContainSubstring("image/png"))
+
+			code, _ = upload(idSubmission, "laporan.docx", archive(map[string][]byte{"readme.txt": []byte("not a document")}))
+			Expect(code).To(Equal(http.StatusUnsupportedMediaType))
+
+			code, _ = upload(idSubmission, "laporan.docx", archive(map[string][]byte{"word/document.xml": []byte("<w:document/>")}))
+			Expect(code).To(Equal(http.StatusOK))
+		})
+
+		It("should answer 413 for a file above the size limit", func() {
+			idSubmission := createSubmission(`"max_size": 64`)
+
+			code, _ := upload(idSubmission, "laporan.pdf", append(pdf, bytes.Repeat([]byte(" "), 64)...))
+			Expect(code).To(Equal(http.StatusRequestEntityTooLarge))
+		})
+
+		It("should answer 422 for a zip archive that expands too much", func() {
+			os.Setenv("UPLOAD_MAX_EXPANDED_SIZE_MB", "1")
+			defer os.Unsetenv("UPLOAD_MAX_EXPANDED_SIZE_MB")
+			server = setup.ModuleSetup(config.New("../../.env.test"))
+
+			idSubmission := createSubmission(`"allowed_types": ["zip"]`)
+			bomb := archive(map[string][]byte{"zeros.txt": make([]byte, 2<<20)})
+			Expect(len(bomb)).To(BeNumerically("<", 64<<10))
+
+			code, response := upload(idSubmission, "tugas.zip", bomb)
+			Expect(code).To(Equal(http.StatusUnprocessableEntity))
+			Expect(response["status"]).To(ContainSubstring("archive expands too much"))
+
+			code, _ = upload(idSubmission, "tugas.zip", archive(map[string][]byte{"tugas.txt": []byte("jawaban")}))
+			Expect(code).To(Equal(http.StatusOK))
+		})
+	})
+})
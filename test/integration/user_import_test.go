@@ -0,0 +1,185 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/test/setup"
+)
+
+var _ = Describe("User Import API", func() {
+
+	var (
+		server     *gin.Engine
+		adminToken string
+		codeCourse string
+	)
+
+	serve := func(token string, method string, path string, body string) (int, map[string]interface{}) {
+		return setup.Serve(server, token, method, path, body)
+	}
+
+	upload := func(token string, path string, csv string) (int, map[string]interface{}) {
+		body := &bytes.Buffer{}
+		form := multipart.NewWriter(body)
+		part, _ := form.CreateFormFile("file", "users.csv")
+		_, _ = part.Write([]byte(csv))
+		_ = form.Close()
+
+		request := httptest.NewRequest(http.MethodPost, path, body)
+		request.Header.Add("Content-Type", form.FormDataContentType())
+		request.Header.Set("Authorization", token)
+
+		writer := httptest.NewRecorder()
+		server.ServeHTTP(writer, request)
+
+		responseBody, _ := io.ReadAll(writer.Result().Body)
+		var response map[string]interface{}
+		_ = json.Unmarshal(responseBody, &response)
+
+		return writer.Result().StatusCode, response
+	}
+
+	data := func(response map[string]interface{}) map[string]interface{} {
+		return response["data"].(map[string]interface{})
+	}
+
+	login := func(email string) int {
+		code, _ := serve("", http.MethodPost, "/api/users/login", fmt.Sprintf(`{"email": "%v", "password": "rahasia123"}`, email))
+		return code
+	}
+
+	BeforeEach(func() {
+		configuration := config.New("../../.env.test")
+
+		_, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+
+		server = setup.ModuleSetup(configuration)
+
+		setup.Register(server, "Admin Import", "adminimport", entity.RoleAdmin)
+		adminToken = setup.Login(server, "adminimport")
+
+		_, response := serve(adminToken, http.MethodPost, "/api/courses", `{"name": "Sejarah","class": "XI-1","tools": "Buku","about": "Masa lalu","description": "Kerajaan nusantara"}`)
+		codeCourse = data(response)["code_course"].(string)
+	})
+
+	AfterEach(func() {
+		configuration := config.New("../../.env.test")
+		db, err := setup.SuiteSetup(configuration)
+		if err != nil {
+			panic(err)
+		}
+		defer db.Close()
+
+		err = setup.TearDownTest(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("Import users", func() {
+		It("should report every invalid row and import nothing", func() {
+			csv := "name,username,email,password,role,courses\n" +
+				"Siswa Satu,siswasatu,siswasatu@gmail.com,rahasia123,2," + codeCourse + "\n" +
+				"Siswa Dua,siswadua,adminimport@gmail.com,rahasia123,2,\n" +
+				",siswatiga,bukan-email,rahasia123,9,TIDAKADA\n" +
+				"Siswa Empat,siswasatu,siswaempat@gmail.com,rahasia123,dua,\n"
+
+			code, response := upload(adminToken, "/api/users/import", csv)
+			Expect(code).To(Equal(http.StatusUnprocessableEntity))
+			Expect(data(response)["rows"]).To(Equal(float64(4)))
+			Expect(data(response)["imported"]).To(Equal(float64(0)))
+
+			errs := data(response)["errors"].([]interface{})
+			Expect(errs).To(HaveLen(3))
+			Expect(errs[0].(map[string]interface{})["row"]).To(Equal(float64(3)))
+			Expect(errs[0].(map[string]interface{})["errors"]).To(ConsistOf("email has been registered"))
+			Expect(errs[1].(map[string]interface{})["errors"]).To(ConsistOf("name is required", "email is not a valid email", "role must be one of 1 2 3", "course TIDAKADA not found"))
+			Expect(errs[2].(map[string]interface{})["errors"]).To(ConsistOf("role must be a number", "username is also used on row 2"))
+
+			Expect(login("siswasatu@gmail.com")).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should only report on a dry run and import every row in one go", func() {
+			csv := "name,username,email,password,role,phone,gender,type_of_disability,birthdate,courses\n" +
+				"Siswa Satu,siswasatu,siswasatu@gmail.com,rahasia123,,0851,1,1,2005-01-02," + codeCourse + "\n" +
+				"Guru Satu,gurusatu,gurusatu@gmail.com,rahasia123,3,0852,2,1,1990-03-04,\n"
+
+			code, response := upload(adminToken, "/api/users/import?dry_run=true", csv)
+			Expect(code).To(Equal(http.StatusOK))
+			Expect(data(response)["dry_run"]).To(BeTrue())
+			Expect(data(response)["users"]).To(HaveLen(2))
+			Expect(data(response)["imported"]).To(Equal(float64(0)))
+			Expect(login("siswasatu@gmail.com")).To(Equal(http.StatusBadRequest))
+
+			code, response = upload(adminToken, "/api/users/import", csv)
+			Expect(code).To(Equal(http.StatusCreated))
+			Expect(data(response)["imported"]).To(Equal(float64(2)))
+			users := data(response)["users"].([]interface{})
+			Expect(users[0].(map[string]interface{})["role"]).To(Equal(float64(2)))
+			Expect(users[0].(map[string]interface{})["id"]).NotTo(BeNil())
+
+			_, response = serve("", http.MethodPost, "/api/users/login", `{"email": "siswasatu@gmail.com", "password": "rahasia123"}`)
+			token := response["token"].(string)
+			_, response = serve(token, http.MethodGet, "/api/courses/"+codeCourse+"/enrollment", "")
+			Expect(data(response)["status"]).To(Equal("enrolled"))
+
+			code, response = upload(adminToken, "/api/users/import", csv)
+			Expect(code).To(Equal(http.StatusUnprocessableEntity))
+			Expect(data(response)["errors"]).To(HaveLen(2))
+		})
+
+		It("should waitlist users once a course is full and refuse courses outside their enrollment dates", func() {
+			code, _ := serve(adminToken, http.MethodPut, "/api/courses/"+codeCourse+"/enrollment/settings", `{"mode": "key", "key": "masa-lalu", "capacity": 1}`)
+			Expect(code).To(Equal(http.StatusOK))
+
+			csv := "name,username,email,password,role,courses\n" +
+				"Siswa Satu,siswasatu,siswasatu@gmail.com,rahasia123,2," + codeCourse + "\n" +
+				"Siswa Dua,siswadua,siswadua@gmail.com,rahasia123,2," + codeCourse + "\n"
+
+			_, response := upload(adminToken, "/api/users/import?dry_run=true", csv)
+			users := data(response)["users"].([]interface{})
+			Expect(users[0].(map[string]interface{})["waitlisted"]).To(BeEmpty())
+			Expect(users[1].(map[string]interface{})["waitlisted"]).To(ConsistOf(codeCourse))
+
+			code, _ = upload(adminToken, "/api/users/import", csv)
+			Expect(code).To(Equal(http.StatusCreated))
+
+			for username, status := range map[string]string{"siswasatu": "enrolled", "siswadua": "waitlisted"} {
+				_, response = serve("", http.MethodPost, "/api/users/login", `{"email": "`+username+`@gmail.com", "password": "rahasia123"}`)
+				_, response = serve(response["token"].(string), http.MethodGet, "/api/courses/"+codeCourse+"/enrollment", "")
+				Expect(data(response)["status"]).To(Equal(status))
+			}
+
+			serve(adminToken, http.MethodPut, "/api/courses/"+codeCourse+"/enrollment/settings", `{"mode": "open", "ends_at": "2001-01-01"}`)
+			code, response = upload(adminToken, "/api/users/import", "name,username,email,password,role,courses\nSiswa Tiga,siswatiga,siswatiga@gmail.com,rahasia123,2,"+codeCourse+"\n")
+			Expect(code).To(Equal(http.StatusUnprocessableEntity))
+			Expect(data(response)["errors"].([]interface{})[0].(map[string]interface{})["errors"]).To(ConsistOf("course " + codeCourse + " is not open for enrollment"))
+		})
+
+		It("should refuse files without the user columns and users who are not admins", func() {
+			code, _ := upload(adminToken, "/api/users/import", "name,email\nSiswa,siswa@gmail.com\n")
+			Expect(code).To(Equal(http.StatusBadRequest))
+
+			_, response := upload(adminToken, "/api/users/import", "name,username,email,password,role\nSiswa Satu,siswasatu,siswasatu@gmail.com,rahasia123,2\n")
+			Expect(data(response)["imported"]).To(Equal(float64(1)))
+
+			_, response = serve("", http.MethodPost, "/api/users/login", `{"email": "siswasatu@gmail.com", "password": "rahasia123"}`)
+			code, _ = upload(response["token"].(string), "/api/users/import", "name,username,email,password\n")
+			Expect(code).To(Equal(http.StatusForbidden))
+		})
+	})
+})
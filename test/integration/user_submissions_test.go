@@ -76,6 +76,12 @@ var _ = Describe("User Submissions API", func() {
 
 		idUser = int(responseBodyRegister["data"].(map[string]interface{})["id"].(float64))
 
+		// Users register as students, the admin gets its role afterwards
+		err = setup.SetRole(configuration, user.Email, user.Role)
+		if err != nil {
+			panic(err)
+		}
+
 		//Login User
 		userData, _ = json.Marshal(login)
 		requestBody = strings.NewReader(string(userData))
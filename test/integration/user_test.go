@@ -100,6 +100,9 @@ var _ = Describe("User Course API", func() {
 				writer := httptest.NewRecorder()
 				server.ServeHTTP(writer, request)
 
+				// Users register as students, the admin gets its role afterwards
+				Expect(setup.SetRole(config.New("../../.env.test"), user[0].Email, user[0].Role)).To(Succeed())
+
 				//Login User
 				userData, _ = json.Marshal(login[0])
 				requestBody = strings.NewReader(string(userData))
@@ -140,6 +143,9 @@ var _ = Describe("User Course API", func() {
 				writer := httptest.NewRecorder()
 				server.ServeHTTP(writer, request)
 
+				// Users register as students, the admin gets its role afterwards
+				Expect(setup.SetRole(config.New("../../.env.test"), user[1].Email, user[1].Role)).To(Succeed())
+
 				//Login User
 				userData, _ = json.Marshal(login[1])
 				requestBody = strings.NewReader(string(userData))
@@ -180,6 +186,9 @@ var _ = Describe("User Course API", func() {
 				writer := httptest.NewRecorder()
 				server.ServeHTTP(writer, request)
 
+				// Users register as students, the admin gets its role afterwards
+				Expect(setup.SetRole(config.New("../../.env.test"), user[0].Email, user[0].Role)).To(Succeed())
+
 				//Login User
 				userData, _ = json.Marshal(login[0])
 				requestBody = strings.NewReader(string(userData))
@@ -237,6 +246,9 @@ var _ = Describe("User Course API", func() {
 				writer := httptest.NewRecorder()
 				server.ServeHTTP(writer, request)
 
+				// Users register as students, the admin gets its role afterwards
+				Expect(setup.SetRole(config.New("../../.env.test"), user[1].Email, user[1].Role)).To(Succeed())
+
 				//Login User
 				userData, _ = json.Marshal(login[1])
 				requestBody = strings.NewReader(string(userData))
@@ -157,12 +157,15 @@ var _ = Describe("User API", func() {
 				Expect(userResponse["username"]).To(Equal("admin"))
 				Expect(userResponse["email"]).To(Equal("admin@gmail.com"))
 				Expect(userResponse["password"]).ToNot(BeNil())
-				Expect(userResponse["role"]).To(Equal(float64(1)))
+				// The role of the request is ignored, everyone registers as a student
+				Expect(userResponse["role"]).To(Equal(float64(2)))
 				Expect(userResponse["phone"]).To(Equal("8121212121212"))
 				Expect(userResponse["gender"]).To(Equal(float64(2)))
 				Expect(userResponse["type_of_disability"]).To(Equal(float64(1)))
 				Expect(userResponse["birthdate"]).To(Equal("2002-04-01"))
 
+				Expect(setup.SetRole(config.New("../../.env.test"), user[1].Email, user[1].Role)).To(Succeed())
+
 				userLogin, _ := json.Marshal(login[1])
 				requestBodyLogin := strings.NewReader(string(userLogin))
 				requestLogin := httptest.NewRequest(http.MethodPost, "/api/users/login", requestBodyLogin)
@@ -207,6 +210,9 @@ var _ = Describe("User API", func() {
 				writer := httptest.NewRecorder()
 				server.ServeHTTP(writer, request)
 
+				// Users register as students, the admin gets its role afterwards
+				Expect(setup.SetRole(config.New("../../.env.test"), user[1].Email, user[1].Role)).To(Succeed())
+
 				//Login User
 				userLogin, _ := json.Marshal(login[1])
 				requestBodyLogin := strings.NewReader(string(userLogin))
@@ -390,6 +396,9 @@ var _ = Describe("User API", func() {
 				writerAdmin := httptest.NewRecorder()
 				server.ServeHTTP(writerAdmin, requestAdmin)
 
+				// Users register as students, the admin gets its role afterwards
+				Expect(setup.SetRole(config.New("../../.env.test"), user[1].Email, user[1].Role)).To(Succeed())
+
 				//Login User
 				userLogin, _ := json.Marshal(login[1])
 				requestBodyLogin := strings.NewReader(string(userLogin))
@@ -502,6 +511,9 @@ var _ = Describe("User API", func() {
 				writerAdmin := httptest.NewRecorder()
 				server.ServeHTTP(writerAdmin, requestAdmin)
 
+				// Users register as students, the admin gets its role afterwards
+				Expect(setup.SetRole(config.New("../../.env.test"), user[1].Email, user[1].Role)).To(Succeed())
+
 				//Login User
 				userLogin, _ := json.Marshal(login[1])
 				requestBodyLogin := strings.NewReader(string(userLogin))
@@ -567,6 +579,9 @@ var _ = Describe("User API", func() {
 				writerAdmin := httptest.NewRecorder()
 				server.ServeHTTP(writerAdmin, requestAdmin)
 
+				// Users register as students, the admin gets its role afterwards
+				Expect(setup.SetRole(config.New("../../.env.test"), user[1].Email, user[1].Role)).To(Succeed())
+
 				//Login User
 				userLogin, _ := json.Marshal(login[1])
 				requestBodyLogin := strings.NewReader(string(userLogin))
@@ -632,6 +647,9 @@ var _ = Describe("User API", func() {
 				writerAdmin := httptest.NewRecorder()
 				server.ServeHTTP(writerAdmin, requestAdmin)
 
+				// Users register as students, the admin gets its role afterwards
+				Expect(setup.SetRole(config.New("../../.env.test"), user[1].Email, user[1].Role)).To(Succeed())
+
 				//Login User
 				adminLogin, _ := json.Marshal(login[1])
 				requestBodyLogin := strings.NewReader(string(adminLogin))
@@ -735,13 +753,13 @@ var _ = Describe("User API", func() {
 						CourseId:    idCourse1,
 						Name:        "Tugas Biologi",
 						Description: "ini form untuk submissions biologi",
-						Deadline:    utils.TimeNow().Add(2 * time.Hour).Format("12:34:56"),
+						Deadline:    utils.TimeNow().Add(2 * time.Hour).Format(time.RFC3339),
 					},
 					{
 						CourseId:    idCourse2,
 						Name:        "Tugas Matematika",
 						Description: "ini form untuk submissions matematika",
-						Deadline:    utils.TimeNow().Add(2 * time.Hour).Format("12:34:56"),
+						Deadline:    utils.TimeNow().Add(2 * time.Hour).Format(time.RFC3339),
 					},
 				}
 
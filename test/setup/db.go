@@ -1,17 +1,67 @@
 package setup
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
+	"os"
 
 	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/migration"
+	"github.com/rg-km/final-project-engineering-12/backend/utils"
 )
 
+// FreshDatabase empties the test database so the next SuiteSetup builds it again from the migrations
+func FreshDatabase(configuration config.Config) error {
+	if configuration.Get("DB_CONNECTION") == utils.DriverPostgres {
+		db, err := sql.Open(utils.DriverPostgres, config.DataSourceName(configuration))
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		_, err = db.Exec(`DROP SCHEMA public CASCADE; CREATE SCHEMA public;`)
+		return err
+	}
+
+	err := os.Remove(config.DataSourceName(configuration))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// SetRole gives the user with the email the role. Users register as students, the tests create admins and teachers
+// with it before they log in.
+func SetRole(configuration config.Config, email string, role int) error {
+	db, err := SuiteSetup(configuration)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	query := `UPDATE users SET role = ? WHERE email = ?`
+	if configuration.Get("DB_CONNECTION") == utils.DriverPostgres {
+		query = `UPDATE users SET role = $1 WHERE email = $2`
+	}
+	_, err = db.Exec(query, role, email)
+	return err
+}
+
+// SuiteSetup opens the test database and brings its schema up to date
 func SuiteSetup(configuration config.Config) (*sql.DB, error) {
 	driver := configuration.Get("DB_CONNECTION")
-	databaseName := configuration.Get("DB_DATABASE")
-	dsn := fmt.Sprintf("./%v.db", databaseName)
-	db, err := sql.Open(driver, dsn)
+	db, err := sql.Open(driver, config.DataSourceName(configuration))
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := migration.NewMigrator(db, driver)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = migrator.Up(context.Background())
 	if err != nil {
 		return nil, err
 	}
@@ -56,6 +106,126 @@ func TearDownTest(db *sql.DB) error {
 	if err != nil {
 		return err
 	}
+	_, err = db.Exec(`DELETE FROM sessions;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM refresh_tokens;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM refresh_token_families;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM teaching_assignments;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM submission_attempts;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM submission_extensions;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM rubric_scores;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM rubric_levels;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM rubric_criteria;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM enrollment_requests;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM enrollment_settings;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM certificates;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM email_outbox;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM password_resets;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM email_changes;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM two_factor_challenges;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM recovery_codes;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM two_factor;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM two_factor_policies;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM learning_progress;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM curriculum_items;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM course_sections;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM quiz_responses;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM user_quizzes;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM quiz_attempts;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM quiz_choices;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM quiz_questions;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM quizzes;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM grade_bands;`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM grade_categories;`)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
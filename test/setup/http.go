@@ -0,0 +1,116 @@
+package setup
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rg-km/final-project-engineering-12/backend/config"
+	"github.com/rg-km/final-project-engineering-12/backend/entity"
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+)
+
+// Password is the password of every user created with Register
+const Password = "123456ll"
+
+// EnvFile is the configuration of the tests, relative to the test packages
+const EnvFile = "../../.env.test"
+
+// Request sends a JSON request to the server, the token is only sent when there is one
+func Request(server *gin.Engine, token string, method string, path string, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Add("Content-Type", "application/json")
+	if token != "" {
+		request.Header.Set("Authorization", token)
+	}
+
+	writer := httptest.NewRecorder()
+	server.ServeHTTP(writer, request)
+
+	return writer
+}
+
+// Serve sends a JSON request like Request and decodes the JSON the server answers
+func Serve(server *gin.Engine, token string, method string, path string, body string) (int, map[string]interface{}) {
+	writer := Request(server, token, method, path, body)
+
+	responseBody, _ := io.ReadAll(writer.Result().Body)
+	var response map[string]interface{}
+	_ = json.Unmarshal(responseBody, &response)
+
+	return writer.Result().StatusCode, response
+}
+
+// Upload sends the content as the file of a multipart form like the browser does for an upload
+func Upload(server *gin.Engine, token string, path string, name string, content string) (int, map[string]interface{}) {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", name)
+	_, _ = part.Write([]byte(content))
+	writer.Close()
+
+	request := httptest.NewRequest(http.MethodPost, path, body)
+	request.Header.Add("Content-Type", writer.FormDataContentType())
+	request.Header.Set("Authorization", token)
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+
+	var response map[string]interface{}
+	_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+
+	return recorder.Code, response
+}
+
+// Register creates a user of the role with the email username@gmail.com and returns its id
+func Register(server *gin.Engine, name string, username string, role int) int {
+	user, _ := json.Marshal(model.UserRegisterResponse{Name: name, Username: username, Email: username + "@gmail.com", Password: Password, Role: role, Phone: "085156789011", Gender: 1, DisabilityType: 1, Birthdate: "2002-04-01"})
+	code, response := Serve(server, "", http.MethodPost, "/api/users", string(user))
+	if code != http.StatusCreated {
+		panic(fmt.Sprintf("register %v: %v %v", username, code, response))
+	}
+	if role != entity.RoleStudent {
+		err := SetRole(config.New(EnvFile), username+"@gmail.com", role)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	return ID(response)
+}
+
+// Login returns the token of a user created with Register
+func Login(server *gin.Engine, username string) string {
+	code, response := Serve(server, "", http.MethodPost, "/api/users/login", `{"email": "`+username+`@gmail.com", "password": "`+Password+`"}`)
+	if code != http.StatusOK {
+		panic(fmt.Sprintf("login %v: %v %v", username, code, response))
+	}
+
+	return response["token"].(string)
+}
+
+// EnrolledCourse creates the course as the user of token and enrolls the user with userId in it, it returns the code
+// and the id of the course
+func EnrolledCourse(server *gin.Engine, token string, userId int, course string) (string, int) {
+	code, response := Serve(server, token, http.MethodPost, "/api/courses", course)
+	if code != http.StatusOK {
+		panic(fmt.Sprintf("create course: %v %v", code, response))
+	}
+	codeCourse := response["data"].(map[string]interface{})["code_course"].(string)
+	idCourse := ID(response)
+
+	Serve(server, token, http.MethodPost, "/api/usercourse", fmt.Sprintf(`{"user_id":%v,"course_id":%v}`, userId, idCourse))
+
+	return codeCourse, idCourse
+}
+
+// ID returns the id of the data of a response
+func ID(response map[string]interface{}) int {
+	return int(response["data"].(map[string]interface{})["id"].(float64))
+}
@@ -59,6 +59,12 @@ var _ = Describe("User Course API", func() {
 
 		writer := httptest.NewRecorder()
 		server.ServeHTTP(writer, request)
+		// Users register as students, the admin gets its role afterwards
+		err = setup.SetRole(configuration, user.Email, user.Role)
+		if err != nil {
+			panic(err)
+		}
+
 		//Login User
 		userData, _ = json.Marshal(login)
 		requestBody = strings.NewReader(string(userData))
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	DriverSQLite   = "sqlite3"
+	DriverPostgres = "postgres"
+)
+
+// Rebind rewrites the ? placeholders of query into the placeholder style of the driver
+func Rebind(driver string, query string) string {
+	if driver != DriverPostgres {
+		return query
+	}
+
+	var builder strings.Builder
+	position := 0
+	for _, char := range query {
+		if char == '?' {
+			position++
+			builder.WriteString("$" + strconv.Itoa(position))
+			continue
+		}
+		builder.WriteRune(char)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,7 @@
+//go:build sqlite_fts5
+// +build sqlite_fts5
+
+package utils
+
+// FTS5 reports whether the SQLite driver was built with the FTS5 extension (go build -tags sqlite_fts5)
+const FTS5 = true
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"encoding/base64"
+	"strconv"
+
+	"github.com/rg-km/final-project-engineering-12/backend/model"
+)
+
+// EncodeCursor turns an offset into the opaque cursor handed to clients
+func EncodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// DecodeCursor reads the offset back from a cursor made by EncodeCursor
+func DecodeCursor(cursor string) (int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, model.ErrInvalidListQuery
+	}
+
+	offset, err := strconv.Atoi(string(decoded))
+	if err != nil || offset < 0 {
+		return 0, model.ErrInvalidListQuery
+	}
+
+	return offset, nil
+}
+
+// ToListPage builds the page metadata for count rows returned by query out of total
+func ToListPage(query model.ListQuery, count int, total int) model.ListPage {
+	page := model.ListPage{Total: total}
+	if next := query.Offset + count; count > 0 && next < total {
+		page.NextCursor = EncodeCursor(next)
+	}
+
+	return page
+}
@@ -46,11 +46,26 @@ func ToModuleSubmissionsNextPreviousResponse(ModSubs entity.NextPreviousModuleSu
 
 func ToModuleSubmissionsResponse(modsub entity.ModuleSubmissions) model.GetModuleSubmissionsResponse {
 	return model.GetModuleSubmissionsResponse{
-		Id:          modsub.Id,
-		CourseId:    modsub.CourseId,
-		Name:        modsub.Name,
-		Description: modsub.Description,
-		Deadline:    modsub.Deadline.String(),
+		Id:            modsub.Id,
+		CourseId:      modsub.CourseId,
+		Name:          modsub.Name,
+		Description:   modsub.Description,
+		Deadline:      FormatDeadline(modsub.Deadline),
+		AllowedTypes:  SplitList(modsub.AllowedTypes),
+		MaxSize:       modsub.MaxSize,
+		LatePolicy:    modsub.LatePolicy,
+		GracePeriod:   modsub.GracePeriod,
+		PenaltyPerDay: modsub.PenaltyPerDay,
+		CategoryId:    modsub.CategoryId,
+	}
+}
+
+func ToExtensionResponse(extension entity.StudentExtensions) model.GetExtensionResponse {
+	return model.GetExtensionResponse{
+		UserId:    extension.UserId,
+		UserName:  extension.UserName,
+		Deadline:  extension.Deadline,
+		CreatedAt: extension.CreatedAt,
 	}
 }
 
@@ -61,6 +76,21 @@ func ToUserSubmissionsResponse(userSubmission entity.UserSubmissions) model.GetU
 		ModuleSubmissionId: userSubmission.ModuleSubmissionId,
 		File:               userSubmission.File,
 		Grade:              userSubmission.Grade,
+		GradedAttemptId:    userSubmission.GradedAttemptId,
+		Feedback:           userSubmission.Feedback,
+	}
+}
+
+func ToSubmissionAttemptResponse(attempt entity.SubmissionAttempts, graded bool) model.GetSubmissionAttemptResponse {
+	return model.GetSubmissionAttemptResponse{
+		Id:               attempt.Id,
+		UserSubmissionId: attempt.UserSubmissionId,
+		File:             attempt.File,
+		Hash:             attempt.Hash,
+		Size:             attempt.Size,
+		Late:             attempt.Late,
+		Graded:           graded,
+		CreatedAt:        attempt.CreatedAt,
 	}
 }
 
@@ -129,23 +159,302 @@ func ToAnswerResponse(answer entity.Answers) model.GetAnswerResponse {
 	}
 }
 
-func ToStudentSubmissionsResponse(submission entity.StudentSubmissions) model.GetStudentSubmissionsResponse {
+func ToStudentSubmissionsResponse(submission entity.StudentSubmissions, penalty *int, late bool) model.GetStudentSubmissionsResponse {
 	return model.GetStudentSubmissionsResponse{
 		IdModuleSubmission:   submission.IdModuleSubmission,
 		NameCourse:           submission.CourseName,
 		CodeCourse:           submission.CodeCourse,
 		NameModuleSubmission: submission.ModuleSubmissionName,
-		Grade:                submission.Grade,
+		Grade:                penalizedGrade(submission.Grade, penalty),
+		RawGrade:             submission.Grade,
+		Penalty:              penalty,
+		Late:                 late,
+		SubmittedAt:          submission.SubmittedAt,
 		File:                 submission.File,
+		Feedback:             submission.Feedback,
 	}
 }
 
-func ToTeacherSubmissionsResponse(submission entity.TeacherSubmissions) model.GetTeacherSubmissionsResponse {
+func ToTeacherSubmissionsResponse(submission entity.TeacherSubmissions, penalty *int, late bool) model.GetTeacherSubmissionsResponse {
 	return model.GetTeacherSubmissionsResponse{
 		IdUserSubmission:     submission.IdUserSubmission,
 		UserName:             submission.UserName,
 		ModuleSubmissionName: submission.ModuleSubmissionName,
-		Grade:                submission.Grade,
+		Grade:                penalizedGrade(submission.Grade, penalty),
+		RawGrade:             submission.Grade,
+		Penalty:              penalty,
+		Late:                 late,
+		SubmittedAt:          submission.SubmittedAt,
 		File:                 submission.File,
 	}
 }
+
+func penalizedGrade(grade *int, penalty *int) *int {
+	if grade == nil || penalty == nil {
+		return grade
+	}
+
+	penalized := *grade - *penalty
+	return &penalized
+}
+
+func ToCourseTeacherResponse(teacher entity.CourseTeachers) model.GetCourseTeacherResponse {
+	return model.GetCourseTeacherResponse{
+		IdUser:       teacher.IdUser,
+		UserName:     teacher.UserName,
+		UserUsername: teacher.UserUsername,
+		UserEmail:    teacher.UserEmail,
+	}
+}
+
+// ToSearchResultResponse turns the rank, where lower is better, into a score where higher is better
+func ToSearchResultResponse(result entity.SearchResults) model.SearchResultResponse {
+	return model.SearchResultResponse{
+		Type:       result.Type,
+		Id:         result.Id,
+		CodeCourse: result.CodeCourse,
+		Title:      result.Title,
+		Snippet:    result.Snippet,
+		Score:      -result.Rank,
+	}
+}
+
+// ToRubricResponse groups the levels under their criterion, levels come with the lowest points first
+func ToRubricResponse(moduleSubmissionId int, criteria []entity.RubricCriteria, levels []entity.RubricLevels) model.GetRubricResponse {
+	rubric := model.GetRubricResponse{
+		ModuleSubmissionId: moduleSubmissionId,
+		Criteria:           []model.GetRubricCriterionResponse{},
+	}
+	for _, criterion := range criteria {
+		criterionResponse := model.GetRubricCriterionResponse{
+			Id:          criterion.Id,
+			Name:        criterion.Name,
+			Description: criterion.Description,
+			Levels:      []model.GetRubricLevelResponse{},
+		}
+		for _, level := range levels {
+			if level.CriterionId != criterion.Id {
+				continue
+			}
+			criterionResponse.Levels = append(criterionResponse.Levels, model.GetRubricLevelResponse{
+				Id:          level.Id,
+				Name:        level.Name,
+				Description: level.Description,
+				Points:      level.Points,
+			})
+			if level.Points > criterionResponse.MaxPoints {
+				criterionResponse.MaxPoints = level.Points
+			}
+		}
+		rubric.MaxPoints += criterionResponse.MaxPoints
+		rubric.Criteria = append(rubric.Criteria, criterionResponse)
+	}
+
+	return rubric
+}
+
+func ToRubricScoresResponse(userSubmission entity.UserSubmissions, feedback *string, scores []entity.CriterionScores) model.GetRubricScoresResponse {
+	response := model.GetRubricScoresResponse{
+		UserSubmissionId: userSubmission.Id,
+		UserId:           userSubmission.UserId,
+		Feedback:         feedback,
+		Scores:           ToCriterionScoreResponses(scores),
+	}
+	for _, score := range scores {
+		response.Total += score.Points
+		response.MaxPoints += score.MaxPoints
+	}
+
+	return response
+}
+
+func ToCriterionScoreResponses(scores []entity.CriterionScores) []model.GetCriterionScoreResponse {
+	responses := []model.GetCriterionScoreResponse{}
+	for _, score := range scores {
+		responses = append(responses, model.GetCriterionScoreResponse{
+			CriterionId:   score.CriterionId,
+			CriterionName: score.CriterionName,
+			LevelId:       score.LevelId,
+			LevelName:     score.LevelName,
+			Points:        score.Points,
+			MaxPoints:     score.MaxPoints,
+			Comment:       score.Comment,
+		})
+	}
+
+	return responses
+}
+
+func ToGradeCategoryResponse(category entity.GradeCategories) model.GetGradeCategoryResponse {
+	return model.GetGradeCategoryResponse{
+		Id:         category.Id,
+		Name:       category.Name,
+		Weight:     category.Weight,
+		DropLowest: category.DropLowest,
+	}
+}
+
+func ToGradeBandResponses(bands []entity.GradeBands) []model.GetGradeBandResponse {
+	responses := []model.GetGradeBandResponse{}
+	for _, band := range bands {
+		responses = append(responses, model.GetGradeBandResponse{
+			Letter:   band.Letter,
+			MinScore: band.MinScore,
+		})
+	}
+
+	return responses
+}
+
+func ToQuizResponse(quiz entity.Quizzes, maxScore int, questions []model.GetQuizQuestionResponse) model.GetQuizResponse {
+	return model.GetQuizResponse{
+		Id:               quiz.Id,
+		CourseId:         quiz.CourseId,
+		Name:             quiz.Name,
+		Description:      quiz.Description,
+		TimeLimit:        quiz.TimeLimit,
+		MaxAttempts:      quiz.MaxAttempts,
+		ShuffleQuestions: quiz.ShuffleQuestions,
+		MaxScore:         maxScore,
+		Questions:        questions,
+	}
+}
+
+// ToQuizQuestionResponse only shows the answers with withAnswers, the accepted short answers are never choices
+func ToQuizQuestionResponse(question entity.QuizQuestions, choices []entity.QuizChoices, withAnswers bool) model.GetQuizQuestionResponse {
+	response := model.GetQuizQuestionResponse{
+		Id:      question.Id,
+		Type:    question.Type,
+		Prompt:  question.Prompt,
+		Points:  question.Points,
+		Choices: []model.GetQuizChoiceResponse{},
+	}
+	if withAnswers && question.Type == entity.QuestionNumeric {
+		tolerance := question.Tolerance
+		response.NumericAnswer = question.NumericAnswer
+		response.Tolerance = &tolerance
+	}
+
+	for _, choice := range choices {
+		if question.Type == entity.QuestionShortAnswer {
+			if withAnswers {
+				response.Answers = append(response.Answers, choice.Content)
+			}
+			continue
+		}
+
+		choiceResponse := model.GetQuizChoiceResponse{
+			Id:      choice.Id,
+			Content: choice.Content,
+		}
+		if withAnswers {
+			correct := choice.Correct
+			choiceResponse.Correct = &correct
+		}
+		response.Choices = append(response.Choices, choiceResponse)
+	}
+
+	return response
+}
+
+func ToQuizNextPreviousResponse(quiz entity.NextPreviousQuizzes) model.GetNextPreviousQuizzesResponse {
+	return model.GetNextPreviousQuizzesResponse{
+		Id:         quiz.Id,
+		CodeCourse: quiz.CodeCourse,
+	}
+}
+
+func ToQuizAttemptResponse(attempt entity.QuizAttempts, questions []model.GetQuizQuestionResponse, responses []entity.QuizResponses) model.GetQuizAttemptResponse {
+	response := model.GetQuizAttemptResponse{
+		Id:          attempt.Id,
+		QuizId:      attempt.QuizId,
+		StartedAt:   attempt.StartedAt,
+		Deadline:    attempt.Deadline,
+		SubmittedAt: attempt.SubmittedAt,
+		Score:       attempt.Score,
+		MaxScore:    attempt.MaxScore,
+		Questions:   questions,
+	}
+	for _, quizResponse := range responses {
+		response.Results = append(response.Results, model.GetQuizResultResponse{
+			QuestionId: quizResponse.QuestionId,
+			Answer:     quizResponse.Answer,
+			Correct:    quizResponse.Correct,
+			Points:     quizResponse.Points,
+		})
+	}
+
+	return response
+}
+
+func ToUserQuizResponse(result entity.UserQuizResults) model.GetUserQuizResponse {
+	return model.GetUserQuizResponse{
+		UserId:          result.UserId,
+		Name:            result.UserName,
+		Username:        result.UserUsername,
+		Grade:           result.Grade,
+		GradedAttemptId: result.GradedAttemptId,
+		Attempts:        result.Attempts,
+	}
+}
+
+func ToCourseSectionResponse(section entity.CourseSections) model.GetCourseSectionResponse {
+	return model.GetCourseSectionResponse{
+		Id:       &section.Id,
+		Name:     section.Name,
+		Position: section.Position,
+		Items:    []model.GetCurriculumItemResponse{},
+	}
+}
+
+func ToCurriculumItemResponse(item entity.CurriculumItems) model.GetCurriculumItemResponse {
+	return model.GetCurriculumItemResponse{
+		Type:     item.Type,
+		Id:       item.ItemId,
+		Name:     item.Name,
+		Position: item.Position,
+	}
+}
+
+func ToCurriculumNextPreviousResponse(item entity.CurriculumItems, code string) model.GetNextPreviousCurriculumResponse {
+	return model.GetNextPreviousCurriculumResponse{
+		Type:       item.Type,
+		Id:         item.ItemId,
+		CodeCourse: code,
+	}
+}
+
+func ToCertificateResponse(certificate entity.Certificates, verifyUrl string) model.GetCertificateResponse {
+	return model.GetCertificateResponse{
+		Code:        certificate.Code,
+		CodeCourse:  certificate.CodeCourse,
+		CourseName:  certificate.CourseName,
+		StudentName: certificate.StudentName,
+		Score:       certificate.Score,
+		IssuedAt:    certificate.IssuedAt,
+		VerifyUrl:   verifyUrl,
+	}
+}
+
+func ToEnrollmentSettingsResponse(settings entity.EnrollmentSettings, code string) model.GetEnrollmentSettingsResponse {
+	return model.GetEnrollmentSettingsResponse{
+		CodeCourse: code,
+		Mode:       settings.Mode,
+		Key:        settings.Key,
+		Capacity:   settings.Capacity,
+		StartsAt:   settings.StartsAt,
+		EndsAt:     settings.EndsAt,
+	}
+}
+
+func ToEnrollmentRequestResponse(request entity.EnrollmentRequests, position *int) model.GetEnrollmentRequestResponse {
+	return model.GetEnrollmentRequestResponse{
+		Id:        request.Id,
+		UserId:    request.UserId,
+		Name:      request.Name,
+		Username:  request.Username,
+		Status:    request.Status,
+		Position:  position,
+		CreatedAt: request.CreatedAt,
+	}
+}
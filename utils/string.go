@@ -1,7 +1,11 @@
 package utils
 
 import (
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"math/rand"
+	"strings"
 	"time"
 )
 
@@ -16,3 +20,28 @@ func RandomString(n int) string {
 	}
 	return string(b)
 }
+
+// RandomToken returns a hex encoded string built from n bytes of crypto/rand.
+// Use it instead of RandomString for anything that must not be guessable.
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := cryptorand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// HashToken returns the hex encoded SHA-256 of a token so it can be stored and looked up
+// without keeping the token itself in the database.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// SplitList splits a comma separated column, an empty column gives an empty list
+func SplitList(list string) []string {
+	if list == "" {
+		return []string{}
+	}
+	return strings.Split(list, ",")
+}
@@ -8,8 +8,37 @@ func TimeNow() time.Time {
 	return timeNow
 }
 
+// ParseTime reads an RFC 3339 timestamp or a plain date, anything else gives the zero time
 func ParseTime(times string) time.Time {
+	timeNow, err := time.Parse(time.RFC3339, times)
+	if err == nil {
+		return timeNow
+	}
+
 	format := "2006-01-02"
-	timeNow, _ := time.Parse(format, times)
+	timeNow, _ = time.Parse(format, times)
 	return timeNow
 }
+
+// ParseDeadline reads a deadline like ParseTime, a plain date is the last second of that day
+func ParseDeadline(times string) time.Time {
+	deadline, err := time.Parse(time.RFC3339, times)
+	if err == nil {
+		return deadline
+	}
+
+	format := "2006-01-02"
+	deadline, err = time.Parse(format, times)
+	if err != nil {
+		return time.Time{}
+	}
+	return deadline.Add(24*time.Hour - time.Second)
+}
+
+// FormatDeadline writes a deadline as RFC 3339, the zero time of a submission without deadline is empty
+func FormatDeadline(deadline time.Time) string {
+	if deadline.IsZero() {
+		return ""
+	}
+	return deadline.Format(time.RFC3339)
+}